@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/services"
+	"online_subscription_service/internal/storage"
+	"online_subscription_service/internal/storage/postgres"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// createAdminUsage — справка по подкоманде create-admin.
+const createAdminUsage = `usage: server [--config_path=PATH] create-admin --email EMAIL [--tenant UUID] [--platform]
+
+Создаёт локальную учётную запись с ролью admin. Пароль берётся из переменной
+окружения ADMIN_PASSWORD, а если она не задана — из первой строки стандартного ввода.
+
+flags:
+  --email     адрес электронной почты администратора
+  --tenant    организация (по умолчанию — организация по умолчанию)
+  --platform  добавить роль platform_admin (администратор платформы)
+`
+
+// createAdminArgs — аргументы подкоманды create-admin.
+type createAdminArgs struct {
+	email    string
+	tenant   uuid.UUID
+	platform bool
+}
+
+// runCreateAdmin — выполняет подкоманду create-admin и возвращает код завершения процесса.
+func runCreateAdmin(cfg *config.Config, args []string) int {
+	parsed, err := parseCreateAdmin(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprint(os.Stderr, createAdminUsage)
+		return 2
+	}
+
+	password, err := readPassword()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DB.ConnectTimeout)
+	defer cancel()
+
+	pool, err := postgres.Connect(ctx, cfg.DB)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer pool.Close()
+
+	users := services.NewUsersService(storage.NewUsersStorage(pool), nil, cfg.Auth)
+	user, err := users.CreateAdmin(ctx, parsed.tenant, models.CreateTenantAdminRequest{Email: parsed.email, Password: password}, parsed.platform)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Printf("id:      %s\ntenant:  %s\nemail:   %s\nroles:   %s\n", user.ID, user.TenantID, user.Email, strings.Join(user.Roles, ","))
+	return 0
+}
+
+// parseCreateAdmin — разбирает аргументы подкоманды create-admin до подключения к БД.
+func parseCreateAdmin(args []string) (createAdminArgs, error) {
+	parsed := createAdminArgs{tenant: models.DefaultTenantID}
+
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {}
+	fs.StringVar(&parsed.email, "email", "", "")
+	tenant := fs.String("tenant", "", "")
+	fs.BoolVar(&parsed.platform, "platform", false, "")
+	if err := fs.Parse(args); err != nil {
+		return parsed, err
+	}
+
+	if fs.NArg() > 0 {
+		return parsed, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if parsed.email == "" {
+		return parsed, errors.New("--email is required")
+	}
+	if *tenant != "" {
+		id, err := uuid.Parse(*tenant)
+		if err != nil {
+			return parsed, errors.New("--tenant must be a UUID")
+		}
+		parsed.tenant = id
+	}
+
+	return parsed, nil
+}
+
+// readPassword — читает пароль из ADMIN_PASSWORD или из первой строки стандартного ввода.
+func readPassword() (string, error) {
+	if password := os.Getenv("ADMIN_PASSWORD"); password != "" {
+		return password, nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return "", errors.New("password is empty")
+	}
+
+	return line, nil
+}
@@ -6,10 +6,10 @@ import (
 	_ "online_subscription_service/docs"
 	"online_subscription_service/internal/app"
 	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/lib/logger"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 )
 
 /*
@@ -20,10 +20,13 @@ import (
  */
 
 // main является точкой входа в приложение.
-//  1. Загружает конфигурацию.
+//  1. Загружает конфигурацию и настраивает логгер; подкоманды migrate и
+//     create-admin выполняют операцию с миграциями или создают администратора
+//     и завершают работу.
 //  2. Инициализирует и запускает приложение.
-//  3. Ожидает сигнала завершения (SIGINT или SIGTERM).
-//  4. Корректно завершает работу приложения с таймаутом в 10 секунд.
+//  3. Ожидает сигнала завершения (SIGINT или SIGTERM) или аварийного завершения компонента.
+//  4. Корректно завершает работу приложения за время shutdown.timeout
+//     и выходит с ненулевым кодом, если при запуске или остановке были ошибки.
 //
 
 // @title           Online Subscriptions Swagger Api
@@ -31,22 +34,71 @@ import (
 // @description     API documentation example
 // @host            localhost:8080
 // @BasePath /api/v1
+//
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT в формате "Bearer <token>"
+//
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description API-ключ сервиса
 func main() {
+	os.Exit(run())
+}
+
+// run — запускает приложение и возвращает код завершения процесса:
+// 0 — при штатной остановке, 1 — если приложение не запустилось, аварийно
+// прекратило работу или не смогло корректно освободить ресурсы.
+func run() int {
 	cfg := config.MustLoad()
 
-	app := app.New(cfg)
+	slog.SetDefault(logger.New(cfg.Log))
 
-	go app.MustRun()
+	switch cmd, args := subcommand(); cmd {
+	case "":
+	case "migrate":
+		return runMigrate(cfg, args)
+	case "create-admin":
+		return runCreateAdmin(cfg, args)
+	default:
+		slog.Error("unknown command", slog.String("command", cmd))
+		return 2
+	}
+
+	app, err := app.New(context.Background(), cfg)
+	if err != nil {
+		slog.Error("failed to init application", slog.String("error", err.Error()))
+		return 1
+	}
+
+	if err := app.Start(context.Background()); err != nil {
+		slog.Error("failed to start application", slog.String("error", err.Error()))
+		return 1
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
-	<-quit
-	slog.Info("shutdown initiated...")
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	code := 0
+	select {
+	case sig := <-quit:
+		slog.Info("shutdown initiated...", slog.String("signal", sig.String()))
+	case err := <-app.Failed():
+		slog.Error("application failed", slog.String("error", err.Error()))
+		code = 1
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Shutdown.Timeout)
 	defer cancel()
 
 	// Останавливаем приложение.
-	app.Stop(shutdownCtx)
+	if err := app.Stop(shutdownCtx); err != nil {
+		slog.Error("shutdown completed with errors", slog.String("error", err.Error()))
+		return 1
+	}
+
 	slog.Info("server stopped...")
+	return code
 }
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/storage/postgres"
+	"os"
+	"strconv"
+)
+
+// migrateUsage — справка по подкоманде migrate.
+const migrateUsage = `usage: server [--config_path=PATH] migrate <command>
+
+commands:
+  up              применить все непримененные миграции
+  down [N]        откатить N последних миграций (по умолчанию 1)
+  goto VERSION    перейти к указанной версии схемы
+  status          показать применённую и последнюю доступную версии
+  force VERSION   установить версию и снять признак «грязной» миграции без выполнения SQL
+`
+
+// runMigrate — выполняет подкоманду migrate и возвращает код завершения процесса.
+func runMigrate(cfg *config.Config, args []string) int {
+	op, err := parseMigrate(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DB.ConnectTimeout)
+	defer cancel()
+
+	pool, err := postgres.Connect(ctx, cfg.DB)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer pool.Close()
+
+	m := postgres.NewMigrator(pool)
+	if op != nil {
+		if err := op(ctx, m); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	if err := printMigrationStatus(ctx, m); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// migrateOp — операция с миграциями.
+type migrateOp func(ctx context.Context, m *postgres.Migrator) error
+
+// parseMigrate — разбирает аргументы подкоманды migrate до подключения к БД.
+// Для команды status возвращает nil: выводится только состояние схемы.
+func parseMigrate(args []string) (migrateOp, error) {
+	if len(args) == 0 {
+		return nil, errors.New("command is required")
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "up":
+		return func(ctx context.Context, m *postgres.Migrator) error {
+			return m.Up(ctx)
+		}, nil
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n <= 0 {
+				return nil, errors.New("N must be a positive number")
+			}
+			steps = n
+		}
+		return func(ctx context.Context, m *postgres.Migrator) error {
+			return m.Down(ctx, steps)
+		}, nil
+	case "goto":
+		if len(args) != 1 {
+			return nil, errors.New("VERSION is required")
+		}
+		version, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return nil, errors.New("VERSION must be a non-negative number")
+		}
+		return func(ctx context.Context, m *postgres.Migrator) error {
+			return m.Goto(ctx, uint(version))
+		}, nil
+	case "force":
+		if len(args) != 1 {
+			return nil, errors.New("VERSION is required")
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil || version < -1 {
+			return nil, errors.New("VERSION must be a number not less than -1")
+		}
+		return func(ctx context.Context, m *postgres.Migrator) error {
+			return m.Force(ctx, version)
+		}, nil
+	case "status":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// printMigrationStatus — выводит текущее состояние схемы.
+func printMigrationStatus(ctx context.Context, m *postgres.Migrator) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("version: %d\ndirty:   %t\nlatest:  %d\n", status.Version, status.Dirty, status.Latest)
+	if status.Pending() {
+		fmt.Println("pending migrations: yes")
+	}
+
+	return nil
+}
+
+// subcommand — возвращает подкоманду и её аргументы из аргументов командной строки,
+// оставшихся после разбора флагов.
+func subcommand() (string, []string) {
+	args := flag.Args()
+	if len(args) == 0 {
+		return "", nil
+	}
+	return args[0], args[1:]
+}
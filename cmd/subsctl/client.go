@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/pkg/client"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// requestTimeout — максимальное время выполнения одного запроса к API.
+const requestTimeout = 30 * time.Second
+
+// apiClient — клиент API для subsctl.
+// Операции с подписками выполняет pkg/client, а вход и обновление токенов —
+// сам apiClient: при истёкшем access-токене он один раз обновляет его по refresh-токену.
+type apiClient struct {
+	subs     *client.Client
+	http     *http.Client
+	profile  *profile
+	onTokens func() error // сохраняет профиль после обновления токенов
+}
+
+// newAPIClient — конструктор apiClient.
+func newAPIClient(p *profile, onTokens func() error) (*apiClient, error) {
+	if p.URL == "" {
+		return nil, errors.New("api url is not set: use -url or the url field of the profile")
+	}
+
+	hc := &http.Client{Timeout: requestTimeout}
+	opts := []client.Option{
+		client.WithHTTPClient(hc),
+		client.WithUserAgent("subsctl"),
+		client.WithTokenSource(client.TokenSourceFunc(func(context.Context) (string, error) {
+			return p.Token, nil
+		})),
+	}
+	if p.APIKey != "" {
+		opts = append(opts, client.WithAPIKey(p.APIKey))
+	}
+	if p.TenantID != "" {
+		tenantID, err := uuid.Parse(p.TenantID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tenant id %q: %w", p.TenantID, err)
+		}
+		opts = append(opts, client.WithTenant(tenantID))
+	}
+
+	subs, err := client.New(p.URL, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiClient{
+		subs:     subs,
+		http:     hc,
+		profile:  p,
+		onTokens: onTokens,
+	}, nil
+}
+
+// ListSubscriptions — возвращает все подписки, доступные вызывающей стороне.
+func (c *apiClient) ListSubscriptions(ctx context.Context) ([]models.Subs, error) {
+	var subs []models.Subs
+	err := c.authorized(ctx, func() error {
+		subs = subs[:0]
+		for sub, err := range c.subs.Subscriptions(ctx, client.ListOptions{}) {
+			if err != nil {
+				return err
+			}
+			subs = append(subs, sub)
+		}
+		return nil
+	})
+	return subs, err
+}
+
+// GetSubscription — возвращает подписку по UUID.
+func (c *apiClient) GetSubscription(ctx context.Context, id uuid.UUID) (sub models.Subs, err error) {
+	err = c.authorized(ctx, func() error {
+		sub, err = c.subs.GetSubscription(ctx, id)
+		return err
+	})
+	return sub, err
+}
+
+// CreateSubscription — создаёт подписку и возвращает её UUID.
+func (c *apiClient) CreateSubscription(ctx context.Context, req models.AddSubRequest) (id uuid.UUID, err error) {
+	err = c.authorized(ctx, func() error {
+		id, err = c.subs.CreateSubscription(ctx, req)
+		return err
+	})
+	return id, err
+}
+
+// EditSubscription — изменяет поля подписки, переданные в запросе.
+func (c *apiClient) EditSubscription(ctx context.Context, id uuid.UUID, req models.EditSubRequest) error {
+	return c.authorized(ctx, func() error {
+		return c.subs.EditSubscription(ctx, id, req)
+	})
+}
+
+// DeleteSubscription — удаляет подписку.
+func (c *apiClient) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return c.authorized(ctx, func() error {
+		return c.subs.DeleteSubscription(ctx, id)
+	})
+}
+
+// Price — рассчитывает стоимость подписки пользователя за период.
+func (c *apiClient) Price(ctx context.Context, req models.PricePeriodRequest) (price int, err error) {
+	err = c.authorized(ctx, func() error {
+		price, err = c.subs.Price(ctx, req)
+		return err
+	})
+	return price, err
+}
+
+// Login — выполняет вход по паролю и сохраняет полученные токены в профиле.
+func (c *apiClient) Login(ctx context.Context, req models.LoginRequest) error {
+	var tokens models.TokenPair
+	if err := c.send(ctx, "/auth/login", req, &tokens); err != nil {
+		return err
+	}
+
+	c.profile.Token, c.profile.RefreshToken = tokens.AccessToken, tokens.RefreshToken
+	return c.onTokens()
+}
+
+// authorized — выполняет call; если access-токен истёк, обновляет его и повторяет call.
+func (c *apiClient) authorized(ctx context.Context, call func() error) error {
+	err := call()
+	if !errors.Is(err, client.ErrUnauthorized) || c.profile.RefreshToken == "" || c.profile.APIKey != "" {
+		return err
+	}
+
+	if err := c.refresh(ctx); err != nil {
+		return fmt.Errorf("session expired, run subsctl login: %w", err)
+	}
+
+	return call()
+}
+
+// refresh — обменивает refresh-токен на новую пару токенов.
+func (c *apiClient) refresh(ctx context.Context) error {
+	var tokens models.TokenPair
+	req := models.RefreshRequest{RefreshToken: c.profile.RefreshToken}
+	if err := c.send(ctx, "/auth/refresh", req, &tokens); err != nil {
+		return err
+	}
+
+	c.profile.Token, c.profile.RefreshToken = tokens.AccessToken, tokens.RefreshToken
+	return c.onTokens()
+}
+
+// send — выполняет POST-запрос к эндпоинтам аутентификации, которые не входят в pkg/client,
+// и декодирует JSON-ответ в out.
+func (c *apiClient) send(ctx context.Context, path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	u := strings.TrimRight(c.profile.URL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &client.Error{StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-ID")}
+
+		var errResp models.ErrorResponse
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		if json.Unmarshal(data, &errResp) == nil && errResp.Error != "" {
+			apiErr.Message = errResp.Error
+		} else {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return apiErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
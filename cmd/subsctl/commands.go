@@ -0,0 +1,552 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"online_subscription_service/internal/domain/models"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// command — одна команда subsctl вместе с её флагами и окружением.
+type command struct {
+	name   string
+	opts   *options
+	flags  *flag.FlagSet
+	stdin  io.Reader
+	stdout io.Writer
+	f      commandFlags
+}
+
+// commandFlags — значения флагов отдельных команд.
+type commandFlags struct {
+	name     string
+	price    int
+	userID   string
+	start    string
+	end      string
+	csv      string
+	from     string
+	to       string
+	email    string
+	password string
+	use      bool
+}
+
+// run — разбирает флаги команды и выполняет её.
+func (c *command) run(ctx context.Context, args []string) error {
+	handlers := map[string]func(ctx context.Context) error{
+		"list":      c.list,
+		"get":       c.get,
+		"create":    c.create,
+		"edit":      c.edit,
+		"delete":    c.delete,
+		"price":     c.price,
+		"login":     c.login,
+		"configure": c.configure,
+	}
+
+	handler, ok := handlers[c.name]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", errUsage, c.name)
+	}
+
+	c.declare()
+
+	if err := c.flags.Parse(args); err != nil {
+		return err
+	}
+	if !validFormat(c.opts.output) {
+		return fmt.Errorf("%w: unknown output format %q", errUsage, c.opts.output)
+	}
+
+	return handler(ctx)
+}
+
+// declare — регистрирует флаги, специфичные для команды.
+func (c *command) declare() {
+	fs := c.flags
+	switch c.name {
+	case "create":
+		fs.StringVar(&c.f.name, "name", "", "название сервиса")
+		fs.IntVar(&c.f.price, "price", 0, "стоимость в месяц, руб.")
+		fs.StringVar(&c.f.userID, "user-id", "", "UUID пользователя")
+		fs.StringVar(&c.f.csv, "csv", "", "CSV-файл с подписками (- — стандартный ввод)")
+	case "edit":
+		fs.StringVar(&c.f.name, "name", "", "название сервиса")
+		fs.IntVar(&c.f.price, "price", 0, "стоимость в месяц, руб.")
+		fs.StringVar(&c.f.userID, "user-id", "", "UUID пользователя")
+		fs.StringVar(&c.f.start, "start", "", "дата начала, YYYY-MM-DD")
+		fs.StringVar(&c.f.end, "end", "", "дата окончания, YYYY-MM-DD")
+	case "price":
+		fs.StringVar(&c.f.from, "from", "", "начало периода, YYYY-MM-DD")
+		fs.StringVar(&c.f.to, "to", "", "конец периода, YYYY-MM-DD")
+		fs.StringVar(&c.f.userID, "user-id", "", "UUID пользователя")
+		fs.StringVar(&c.f.name, "name", "", "название сервиса")
+	case "login":
+		fs.StringVar(&c.f.email, "email", "", "адрес электронной почты")
+		fs.StringVar(&c.f.password, "password", "", "пароль (если не указан, читается из стандартного ввода)")
+	case "configure":
+		fs.BoolVar(&c.f.use, "use", false, "сделать профиль текущим")
+	}
+}
+
+// session — загружает профиль, применяет переопределения из флагов и создаёт клиента API.
+func (c *command) session() (*profilesFile, *profile, *apiClient, error) {
+	pf, err := loadProfiles(c.opts.configPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	_, stored := pf.get(c.opts.profile)
+
+	// Переопределения из флагов не должны попадать в файл при сохранении токенов,
+	// поэтому клиент работает с копией профиля.
+	p := *stored
+	if c.opts.url != "" {
+		p.URL = c.opts.url
+	}
+	if c.opts.token != "" {
+		p.Token, p.RefreshToken = c.opts.token, ""
+	}
+	if c.opts.apiKey != "" {
+		p.APIKey = c.opts.apiKey
+	}
+	if c.opts.tenant != "" {
+		p.TenantID = c.opts.tenant
+	}
+
+	client, err := newAPIClient(&p, func() error {
+		stored.Token, stored.RefreshToken = p.Token, p.RefreshToken
+		return pf.save(c.opts.configPath)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return pf, &p, client, nil
+}
+
+// client — создаёт клиента API для текущего профиля.
+func (c *command) client() (*apiClient, error) {
+	_, _, client, err := c.session()
+	return client, err
+}
+
+// printer — возвращает printer для выбранного формата вывода.
+func (c *command) printer() printer {
+	return printer{format: c.opts.output, w: c.stdout}
+}
+
+// idArg — возвращает единственный позиционный аргумент команды — UUID подписки.
+func (c *command) idArg() (uuid.UUID, error) {
+	if c.flags.NArg() != 1 {
+		return uuid.Nil, fmt.Errorf("%w: %s expects exactly one subscription id", errUsage, c.name)
+	}
+
+	id, err := uuid.Parse(c.flags.Arg(0))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: invalid subscription id %q", errUsage, c.flags.Arg(0))
+	}
+
+	return id, nil
+}
+
+// noArgs — проверяет, что команде не переданы позиционные аргументы.
+func (c *command) noArgs() error {
+	if c.flags.NArg() != 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUsage, c.flags.Arg(0))
+	}
+	return nil
+}
+
+// visited — возвращает множество флагов команды, явно указанных пользователем.
+func (c *command) visited() map[string]bool {
+	set := map[string]bool{}
+	c.flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+func (c *command) list(ctx context.Context) error {
+	if err := c.noArgs(); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	subs, err := client.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.printer().subs(subs)
+}
+
+func (c *command) get(ctx context.Context) error {
+	id, err := c.idArg()
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	sub, err := client.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.printer().subs([]models.Subs{sub})
+}
+
+func (c *command) create(ctx context.Context) error {
+	if err := c.noArgs(); err != nil {
+		return err
+	}
+
+	var requests []models.AddSubRequest
+	if c.f.csv != "" {
+		if c.visited()["name"] || c.visited()["price"] {
+			return fmt.Errorf("%w: -csv cannot be combined with -name or -price", errUsage)
+		}
+
+		in := c.stdin
+		if c.f.csv != "-" {
+			f, err := os.Open(c.f.csv)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+
+		var err error
+		if requests, err = readSubsCSV(in); err != nil {
+			return err
+		}
+	} else {
+		if c.f.name == "" {
+			return fmt.Errorf("%w: -name is required", errUsage)
+		}
+
+		req := models.AddSubRequest{Name: c.f.name, Price: c.f.price}
+		if c.f.userID != "" {
+			id, err := uuid.Parse(c.f.userID)
+			if err != nil {
+				return fmt.Errorf("%w: invalid -user-id", errUsage)
+			}
+			req.UserID = id
+		}
+		requests = append(requests, req)
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	// Подписки из CSV создаются по одной; ошибка в строке не прерывает загрузку остальных,
+	// а попадает в вывод и в код завершения.
+	type created struct {
+		Line  int    `json:"line"`
+		ID    string `json:"id,omitempty"`
+		Error string `json:"error,omitempty"`
+	}
+
+	var (
+		results []created
+		rows    [][]string
+		failed  int
+	)
+	for i, req := range requests {
+		res := created{Line: i + 1}
+		if c.f.csv != "" {
+			res.Line = i + 2 // первая строка CSV — заголовок
+		}
+
+		id, err := client.CreateSubscription(ctx, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			res.Error = err.Error()
+			failed++
+		} else {
+			res.ID = id.String()
+		}
+
+		results = append(results, res)
+		rows = append(rows, []string{strconv.Itoa(res.Line), res.ID, res.Error})
+	}
+
+	if err := c.printer().print([]string{"line", "id", "error"}, rows, results); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d subscriptions were not created", failed, len(requests))
+	}
+
+	return nil
+}
+
+// readSubsCSV — читает подписки из CSV с заголовком service_name,price[,user_id].
+// Порядок колонок определяется заголовком.
+func readSubsCSV(in io.Reader) ([]models.AddSubRequest, error) {
+	r := csv.NewReader(in)
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv: empty input")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("csv: %w", err)
+	}
+
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"service_name", "price"} {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("csv: missing column %q", name)
+		}
+	}
+
+	var requests []models.AddSubRequest
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+
+		price, err := strconv.Atoi(strings.TrimSpace(record[cols["price"]]))
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: invalid price %q", line, record[cols["price"]])
+		}
+
+		req := models.AddSubRequest{Name: strings.TrimSpace(record[cols["service_name"]]), Price: price}
+		if i, ok := cols["user_id"]; ok && strings.TrimSpace(record[i]) != "" {
+			if req.UserID, err = uuid.Parse(strings.TrimSpace(record[i])); err != nil {
+				return nil, fmt.Errorf("csv line %d: invalid user_id %q", line, record[i])
+			}
+		}
+
+		requests = append(requests, req)
+	}
+
+	if len(requests) == 0 {
+		return nil, errors.New("csv: no subscriptions")
+	}
+
+	return requests, nil
+}
+
+func (c *command) edit(ctx context.Context) error {
+	id, err := c.idArg()
+	if err != nil {
+		return err
+	}
+
+	var req models.EditSubRequest
+	changed := c.visited()
+	if changed["name"] {
+		req.Name = &c.f.name
+	}
+	if changed["price"] {
+		req.Price = &c.f.price
+	}
+	if changed["user-id"] {
+		userID, err := uuid.Parse(c.f.userID)
+		if err != nil {
+			return fmt.Errorf("%w: invalid -user-id", errUsage)
+		}
+		req.UserID = &userID
+	}
+	if changed["start"] {
+		if req.StartDate, err = parseDate("start", c.f.start); err != nil {
+			return err
+		}
+	}
+	if changed["end"] {
+		if req.EndDate, err = parseDate("end", c.f.end); err != nil {
+			return err
+		}
+	}
+	if req == (models.EditSubRequest{}) {
+		return fmt.Errorf("%w: nothing to change", errUsage)
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	if err := client.EditSubscription(ctx, id, req); err != nil {
+		return err
+	}
+
+	sub, err := client.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.printer().subs([]models.Subs{sub})
+}
+
+func (c *command) delete(ctx context.Context) error {
+	id, err := c.idArg()
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteSubscription(ctx, id)
+}
+
+func (c *command) price(ctx context.Context) error {
+	if err := c.noArgs(); err != nil {
+		return err
+	}
+
+	from, err := parseDate("from", c.f.from)
+	if err != nil {
+		return err
+	}
+	to, err := parseDate("to", c.f.to)
+	if err != nil {
+		return err
+	}
+	userID, err := uuid.Parse(c.f.userID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid -user-id", errUsage)
+	}
+	if c.f.name == "" {
+		return fmt.Errorf("%w: -name is required", errUsage)
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	req := models.PricePeriodRequest{From: *from, To: *to, UserID: userID, Name: c.f.name}
+	price, err := client.Price(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return c.printer().print(
+		[]string{"service_name", "user_id", "from", "to", "price"},
+		[][]string{{c.f.name, userID.String(), c.f.from, c.f.to, strconv.Itoa(price)}},
+		map[string]any{"price": price},
+	)
+}
+
+func (c *command) login(ctx context.Context) error {
+	if err := c.noArgs(); err != nil {
+		return err
+	}
+	if c.f.email == "" {
+		return fmt.Errorf("%w: -email is required", errUsage)
+	}
+
+	password := c.f.password
+	if password == "" {
+		line, err := bufio.NewReader(c.stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	// Вход по паролю заменяет API-ключ профиля на пару токенов.
+	client.profile.APIKey = ""
+	req := models.LoginRequest{Email: c.f.email, Password: password}
+	if client.profile.TenantID != "" {
+		tenantID, err := uuid.Parse(client.profile.TenantID)
+		if err != nil {
+			return fmt.Errorf("invalid tenant id %q: %w", client.profile.TenantID, err)
+		}
+		req.TenantID = &tenantID
+	}
+	if err := client.Login(ctx, req); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.stdout, "logged in, tokens saved to", c.opts.configPath)
+	return nil
+}
+
+// configure — сохраняет переданные флагами параметры подключения в профиле.
+func (c *command) configure(context.Context) error {
+	if err := c.noArgs(); err != nil {
+		return err
+	}
+
+	pf, err := loadProfiles(c.opts.configPath)
+	if err != nil {
+		return err
+	}
+
+	name, p := pf.get(c.opts.profile)
+	if c.opts.url != "" {
+		p.URL = c.opts.url
+	}
+	if c.opts.token != "" {
+		p.Token, p.RefreshToken = c.opts.token, ""
+	}
+	if c.opts.apiKey != "" {
+		p.APIKey = c.opts.apiKey
+	}
+	if c.opts.tenant != "" {
+		p.TenantID = c.opts.tenant
+	}
+	if c.f.use || pf.Current == "" {
+		pf.Current = name
+	}
+
+	if err := pf.save(c.opts.configPath); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stdout, "profile %q saved to %s\n", name, c.opts.configPath)
+	return nil
+}
+
+// parseDate — разбирает дату в формате YYYY-MM-DD из флага name.
+func parseDate(name, value string) (*time.Time, error) {
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: -%s must be a date in YYYY-MM-DD format", errUsage, name)
+	}
+	return &t, nil
+}
@@ -0,0 +1,122 @@
+// Команда subsctl — консольная утилита для управления подписками через HTTP API сервиса.
+//
+// Параметры подключения (адрес API, токен или API-ключ, организация) хранятся
+// в профилях в файле $SUBSCTL_CONFIG (по умолчанию <каталог конфигурации>/subsctl/config.yaml)
+// и могут быть переопределены флагами или переменными окружения SUBSCTL_URL,
+// SUBSCTL_TOKEN и SUBSCTL_API_KEY.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `usage: subsctl [flags] <command> [command flags] [args]
+
+commands:
+  list                                   список подписок
+  get ID                                 подписка по ID
+  create -name NAME -price N [-user-id U]
+                                         создать подписку
+  create -csv FILE|-                     создать подписки из CSV (service_name,price[,user_id])
+  edit ID [-name] [-price] [-user-id] [-start] [-end]
+                                         изменить подписку
+  delete ID                              удалить подписку
+  price -from DATE -to DATE -user-id U -name NAME
+                                         стоимость подписки за период
+  login -email EMAIL [-password P]       войти по паролю и сохранить токены в профиле
+  configure [-url] [-token] [-api-key] [-tenant] [-use]
+                                         сохранить параметры подключения в профиле
+
+flags (допустимы и до, и после команды):
+`
+
+// errUsage — команда вызвана с неверными аргументами.
+var errUsage = errors.New("invalid arguments")
+
+// options — общие флаги всех команд.
+type options struct {
+	configPath string
+	profile    string
+	output     string
+	url        string
+	token      string
+	apiKey     string
+	tenant     string
+}
+
+// register — добавляет общие флаги в набор флагов команды.
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.configPath, "config", o.configPath, "файл профилей")
+	fs.StringVar(&o.profile, "profile", o.profile, "имя профиля (по умолчанию текущий)")
+	fs.StringVar(&o.output, "o", o.output, "формат вывода: table, json или csv")
+	fs.StringVar(&o.url, "url", o.url, "адрес API, например http://localhost:8080/api/v1 (SUBSCTL_URL)")
+	fs.StringVar(&o.token, "token", o.token, "bearer-токен (SUBSCTL_TOKEN)")
+	fs.StringVar(&o.apiKey, "api-key", o.apiKey, "API-ключ (SUBSCTL_API_KEY)")
+	fs.StringVar(&o.tenant, "tenant", o.tenant, "организация (X-Tenant-ID)")
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run — разбирает аргументы, выполняет команду и возвращает код завершения:
+// 0 — успех, 1 — ошибка выполнения, 2 — неверные аргументы.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts := &options{
+		configPath: defaultConfigPath(),
+		output:     outputTable,
+		url:        os.Getenv("SUBSCTL_URL"),
+		token:      os.Getenv("SUBSCTL_TOKEN"),
+		apiKey:     os.Getenv("SUBSCTL_API_KEY"),
+	}
+
+	global := flag.NewFlagSet("subsctl", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		global.PrintDefaults()
+	}
+	opts.register(global)
+
+	if err := global.Parse(args); err != nil {
+		return 2
+	}
+	if global.NArg() == 0 {
+		global.Usage()
+		return 2
+	}
+
+	cmd := &command{
+		name:   global.Arg(0),
+		opts:   opts,
+		stdin:  stdin,
+		stdout: stdout,
+		flags:  flag.NewFlagSet("subsctl "+global.Arg(0), flag.ContinueOnError),
+	}
+	cmd.flags.SetOutput(stderr)
+	opts.register(cmd.flags)
+
+	err := cmd.run(ctx, global.Args()[1:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 2
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(stderr, "subsctl:", err)
+		global.Usage()
+		return 2
+	default:
+		fmt.Fprintln(stderr, "subsctl:", err)
+		return 1
+	}
+}
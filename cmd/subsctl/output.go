@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"online_subscription_service/internal/domain/models"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Форматы вывода.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+)
+
+// subsHeader — заголовок таблицы подписок; совпадает с именами полей JSON.
+var subsHeader = []string{"id", "service_name", "price", "user_id", "start_date", "end_date"}
+
+// printer — выводит результаты команд в выбранном формате.
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+// validFormat — проверяет, что формат вывода поддерживается.
+func validFormat(format string) bool {
+	switch format {
+	case outputTable, outputJSON, outputCSV:
+		return true
+	default:
+		return false
+	}
+}
+
+// subs — выводит список подписок.
+func (p printer) subs(subs []models.Subs) error {
+	if subs == nil {
+		subs = []models.Subs{}
+	}
+
+	rows := make([][]string, 0, len(subs))
+	for _, s := range subs {
+		end := ""
+		if s.EndDate != nil {
+			end = s.EndDate.Format(time.DateOnly)
+		}
+		rows = append(rows, []string{
+			s.ID.String(), s.Name, strconv.Itoa(s.Price), s.UserID.String(), s.StartDate.Format(time.DateOnly), end,
+		})
+	}
+
+	return p.print(subsHeader, rows, subs)
+}
+
+// print — выводит таблицу header/rows или value в формате JSON.
+func (p printer) print(header []string, rows [][]string, value any) error {
+	switch p.format {
+	case outputJSON:
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	case outputCSV:
+		w := csv.NewWriter(p.w)
+		if err := w.Write(header); err != nil {
+			return err
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return err
+		}
+		return w.Error()
+	default:
+		w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.ToUpper(strings.Join(header, "\t")))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultProfile — имя профиля, используемого, если другой не выбран.
+const defaultProfile = "default"
+
+// profilesFile — файл с профилями subsctl.
+// Профиль хранит адрес API и учётные данные для одного окружения (local, staging, prod).
+type profilesFile struct {
+	Current  string              `yaml:"current"`
+	Profiles map[string]*profile `yaml:"profiles"`
+}
+
+// profile — параметры подключения к одному окружению.
+type profile struct {
+	URL          string `yaml:"url"`                     // Базовый адрес API, например http://localhost:8080/api/v1
+	Token        string `yaml:"token,omitempty"`         // Bearer-токен (access-токен)
+	RefreshToken string `yaml:"refresh_token,omitempty"` // Refresh-токен, сохраняется командой login
+	APIKey       string `yaml:"api_key,omitempty"`       // API-ключ сервиса (альтернатива токену)
+	TenantID     string `yaml:"tenant_id,omitempty"`     // Организация (заголовок X-Tenant-ID)
+}
+
+// defaultConfigPath — путь к файлу профилей: $SUBSCTL_CONFIG или
+// <каталог конфигурации пользователя>/subsctl/config.yaml.
+func defaultConfigPath() string {
+	if path := os.Getenv("SUBSCTL_CONFIG"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "subsctl.yaml"
+	}
+	return filepath.Join(dir, "subsctl", "config.yaml")
+}
+
+// loadProfiles — читает файл профилей; отсутствующий файл считается пустым.
+func loadProfiles(path string) (*profilesFile, error) {
+	pf := &profilesFile{Profiles: map[string]*profile{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return pf, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, pf); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles %s: %w", path, err)
+	}
+	if pf.Profiles == nil {
+		pf.Profiles = map[string]*profile{}
+	}
+
+	return pf, nil
+}
+
+// save — записывает файл профилей с правами только для владельца,
+// так как в нём хранятся токены и API-ключи.
+func (pf *profilesFile) save(path string) error {
+	data, err := yaml.Marshal(pf)
+	if err != nil {
+		return fmt.Errorf("failed to encode profiles: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config dir: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write profiles: %w", err)
+	}
+
+	return nil
+}
+
+// get — возвращает профиль с указанным именем (или текущий), создавая его при отсутствии.
+func (pf *profilesFile) get(name string) (string, *profile) {
+	if name == "" {
+		name = pf.Current
+	}
+	if name == "" {
+		name = defaultProfile
+	}
+
+	p, ok := pf.Profiles[name]
+	if !ok {
+		p = &profile{}
+		pf.Profiles[name] = p
+	}
+
+	return name, p
+}
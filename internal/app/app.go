@@ -2,39 +2,275 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/auth"
 	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/graph"
+	"online_subscription_service/internal/grpc"
 	"online_subscription_service/internal/handlers"
+	"online_subscription_service/internal/handlers/middleware"
+	"online_subscription_service/internal/health"
 	"online_subscription_service/internal/http"
+	"online_subscription_service/internal/lib/events"
+	"online_subscription_service/internal/lib/ratelimit"
+	"online_subscription_service/internal/lib/tracing"
+	"online_subscription_service/internal/metrics"
 	"online_subscription_service/internal/services"
 	"online_subscription_service/internal/storage"
 	"online_subscription_service/internal/storage/postgres"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
-// App — основной контейнер приложения, оборачивающий HTTP-сервер.
+// App — основной контейнер приложения.
+// Управляет жизненным циклом компонентов: HTTP- и gRPC-серверов, пула подключений к БД,
+// трассировки и фоновых обработчиков.
 type App struct {
-	*http.Server
+	lifecycle *Lifecycle
 }
 
 // New — конструктор приложения.
-// Выполняет инициализацию всех компонентов приложения.
-func New(cfg *config.Config) *App {
-	ctx := context.Background()
+// Выполняет инициализацию всех компонентов приложения и регистрирует их
+// в менеджере жизненного цикла в порядке запуска.
+// Если инициализация не удалась, освобождает уже созданные ресурсы и возвращает ошибку.
+func New(ctx context.Context, cfg *config.Config) (_ *App, err error) {
+	lifecycle := NewLifecycle()
+	defer func() {
+		if err != nil {
+			if releaseErr := lifecycle.Release(context.Background()); releaseErr != nil {
+				err = errors.Join(err, releaseErr)
+			}
+		}
+	}()
+
+	// Настройка трассировки OpenTelemetry. Останавливается последней,
+	// чтобы отправить спаны, созданные при остановке остальных компонентов.
+	shutdownTracing, err := tracing.New(ctx, cfg.Tracing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init tracing: %w", err)
+	}
+	lifecycle.Append(Component{Name: "tracing", Stop: shutdownTracing})
 
 	e := echo.New()
+	e.IPExtractor, err = middleware.IPExtractor(cfg.RateLimit.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure rate limit: %w", err)
+	}
 
 	srv := http.New(ctx, cfg, e)
 
 	// Подключение к базе данных PostgreSQL.
-	db := postgres.New(ctx, cfg)
+	db, err := postgres.New(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	lifecycle.Append(Component{
+		Name: "database",
+		Stop: func(ctx context.Context) error { return postgres.Close(ctx, db) },
+	})
+
+	// Проверки готовности: доступность БД и актуальность схемы; фоновые обработчики
+	// (outbox, доставка вебхуков, импорт) регистрируют свои проверки при создании.
+	latestMigration, err := postgres.LatestMigrationVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init health checks: %w", err)
+	}
+	checker := health.New()
+	checker.Register("database", db.Ping)
+	checker.Register("migrations", postgres.MigrationCheck(db, latestMigration))
 
 	// Создание хранилища подписок и сервиса для работы с ними.
 	subscriptionsStorage := storage.NewSubsStorage(db)
-	subscriptionsService := services.NewSubsService(subscriptionsStorage)
+	subscriptionsService := services.NewSubsService(subscriptionsStorage, cfg.Quota, cfg.Outbox)
+
+	// Публикация событий об изменении подписок из outbox. Останавливается после
+	// HTTP- и gRPC-серверов и фоновых импортов, чтобы опубликовать события последних
+	// изменений, а публикатор закрывается после обработчика.
+	// Если включены вебхуки, события также ставятся в очередь доставки на них,
+	// а доставка останавливается раньше публикации.
+	var webhooksService *services.WebhooksService
+	if cfg.Outbox.Enabled {
+		publisher, err := events.New(cfg.Outbox)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init outbox publisher: %w", err)
+		}
+		lifecycle.Append(Component{Name: "event publisher", Stop: publisher.Close})
+
+		var dispatcher *services.WebhookDispatcher
+		if cfg.Webhooks.Enabled {
+			webhooksStorage := storage.NewWebhooksStorage(db)
+			webhooksService = services.NewWebhooksService(webhooksStorage)
+			dispatcher = services.NewWebhookDispatcher(webhooksStorage, cfg.Webhooks)
+			publisher = events.Fanout(publisher, dispatcher)
+		}
+
+		relay := services.NewOutboxRelay(storage.NewOutboxStorage(db), publisher, cfg.Outbox)
+		lifecycle.Append(Component{Name: "outbox relay", Start: relay.Start, Stop: relay.Stop})
+		checker.Register("outbox relay", relay.Check)
+
+		if dispatcher != nil {
+			lifecycle.Append(Component{Name: "webhook delivery", Start: dispatcher.Start, Stop: dispatcher.Stop})
+			checker.Register("webhook delivery", dispatcher.Check)
+		}
+	} else if cfg.Webhooks.Enabled {
+		return nil, errors.New("webhooks require outbox to be enabled")
+	}
+
+	// Импорт подписок из файлов. Фоновые импорты останавливаются после HTTP-сервера,
+	// чтобы новые задачи уже не поступали, и до закрытия пула подключений к БД.
+	// При запуске задачи, брошенные завершившимися аварийно экземплярами, отмечаются неудавшимися.
+	importService := services.NewImportService(storage.NewImportsStorage(db), subscriptionsStorage, cfg.Import, cfg.Quota, cfg.Outbox)
+	lifecycle.Append(Component{Name: "import jobs", Start: importService.Start, Stop: importService.Stop})
+	checker.Register("import jobs", importService.Check)
+
+	// Выгрузка начислений по подпискам в журналы ledger, hledger и beancount.
+	journalService, err := services.NewJournalService(subscriptionsStorage, cfg.Ledger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Приём вебхуков провайдеров подписок, изменяющих связанные подписки.
+	var integrationsService *services.IntegrationsService
+	if cfg.Integrations.Enabled {
+		integrationsService, err = services.NewIntegrationsService(storage.NewIntegrationsStorage(db), subscriptionsService, cfg.Integrations)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init integrations: %w", err)
+		}
+	}
+
+	// Регистрация метрик пула подключений и бизнес-метрик, а также
+	// административного сервера метрик, если для него указан отдельный порт.
+	if cfg.Metrics.Enabled {
+		metrics.RegisterPool(db)
+		metrics.RegisterBusiness(subscriptionsStorage)
+
+		if cfg.Metrics.Port != 0 {
+			adminE := echo.New()
+			adminE.GET(cfg.Metrics.Path, echo.WrapHandler(metrics.Handler()))
+			metricsSrv := http.NewMetrics(ctx, cfg, adminE)
+			lifecycle.Append(Component{
+				Name:  "metrics server",
+				Start: func(context.Context) error { return metricsSrv.Start(lifecycle.Fail) },
+				Stop:  metricsSrv.Stop,
+			})
+		}
+	}
+
+	// Создание хранилища и сервиса API-ключей для межсервисного доступа.
+	apiKeysService := services.NewAPIKeysService(storage.NewAPIKeysStorage(db))
+
+	// Загрузка ключей проверки JWT, если аутентификация включена.
+	var verifier *auth.Verifier
+	if cfg.Auth.Enabled {
+		verifier, err = auth.NewVerifier(cfg.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init auth: %w", err)
+		}
+	}
+
+	// Локальные учётные записи выдают токены, подписанные HMAC-секретом.
+	var usersService *services.UsersService
+	if cfg.Auth.Enabled && cfg.Auth.LocalAccounts {
+		issuer, err := auth.NewIssuer(cfg.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init local accounts: %w", err)
+		}
+		usersService = services.NewUsersService(storage.NewUsersStorage(db), issuer, cfg.Auth)
+	}
+
+	// Схема GraphQL API поверх сервиса подписок.
+	graphqlSchema, err := graph.NewSchema(subscriptionsService)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init graphql schema: %w", err)
+	}
+
+	// Организации; через сервис HTTP- и gRPC-запросы определяют и проверяют организацию.
+	tenantsService := services.NewTenantsService(storage.NewTenantsStorage(db))
+
+	// Ограничители частоты запросов, общие для HTTP- и gRPC-API.
+	rateLimits := ratelimit.NewGroups(cfg.RateLimit)
 
 	// Регистрация HTTP-эндпоинтов через Handlers.
-	handlers.New(e).SetUpHandlers(subscriptionsService)
+	handlers.New(e, cfg).SetUpHandlers(handlers.Services{
+		Subscriptions: subscriptionsService,
+		Imports:       importService,
+		Journal:       journalService,
+		APIKeys:       apiKeysService,
+		Users:         usersService,
+		Tenants:       tenantsService,
+		Webhooks:      webhooksService,
+		Integrations:  integrationsService,
+		Health:        checker,
+		GraphQL:       graphqlSchema,
+		RateLimits:    rateLimits,
+	}, verifier)
+
+	lifecycle.Append(Component{
+		Name:  "http server",
+		Start: func(context.Context) error { return srv.Start(lifecycle.Fail) },
+		Stop:  srv.Stop,
+	})
+
+	// gRPC API на отдельном порту, использующий тот же сервис подписок.
+	if cfg.GRPC.Enabled {
+		grpcSrv := grpc.New(cfg, subscriptionsService, apiKeysService, tenantsService, verifier, rateLimits)
+		lifecycle.Append(Component{
+			Name:  "grpc server",
+			Start: func(context.Context) error { return grpcSrv.Start(lifecycle.Fail) },
+			Stop:  grpcSrv.Stop,
+		})
+	}
+
+	// Останавливается первой: переводит /readyz в 503 и даёт балансировщику
+	// время перестать направлять запросы, прежде чем HTTP- и gRPC-серверы закроют порты.
+	lifecycle.Append(Component{
+		Name: "readiness",
+		Stop: func(ctx context.Context) error {
+			checker.SetShuttingDown()
+			return sleep(ctx, cfg.Shutdown.Delay)
+		},
+	})
+
+	return &App{lifecycle: lifecycle}, nil
+}
+
+// Start — запускает компоненты приложения в порядке регистрации.
+// Если какой-либо компонент не запустился, уже запущенные останавливаются.
+func (a *App) Start(ctx context.Context) error {
+	return a.lifecycle.Start(ctx)
+}
+
+// Failed — канал, в который поступает ошибка, если компонент аварийно
+// прекратил работу после запуска (например, HTTP-сервер).
+func (a *App) Failed() <-chan error {
+	return a.lifecycle.Failed()
+}
+
+// Stop — останавливает компоненты в обратном порядке: переводит проверку
+// готовности в состояние «не готов», останавливает HTTP- и gRPC-серверы, дожидается
+// фоновых импортов и текущих доставок на вебхуки, публикует оставшиеся события outbox
+// и закрывает публикатор, закрывает пул подключений к БД и отправляет накопленные
+// спаны трассировки.
+// Возвращает все ошибки, возникшие при остановке.
+func (a *App) Stop(ctx context.Context) error {
+	return a.lifecycle.Stop(ctx)
+}
+
+// sleep — ждёт указанное время или отмены контекста.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
 
-	return &App{srv}
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
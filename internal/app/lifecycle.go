@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Component — компонент приложения с управляемым жизненным циклом
+// (HTTP-сервер, пул подключений к БД, фоновый обработчик, публикатор событий).
+type Component struct {
+	Name string
+	// Start — запускает компонент. Не должен блокироваться: длительная работа
+	// выполняется в фоне, а её аварийное завершение передаётся через Lifecycle.Fail.
+	Start func(ctx context.Context) error
+	// Stop — останавливает компонент, не превышая срок, заданный контекстом.
+	Stop func(ctx context.Context) error
+}
+
+// Lifecycle — менеджер жизненного цикла компонентов.
+// Запускает компоненты в порядке регистрации и останавливает в обратном порядке,
+// так что каждый компонент останавливается раньше тех, от которых он зависит.
+type Lifecycle struct {
+	components []Component
+	started    int
+
+	failOnce sync.Once
+	failed   chan error
+}
+
+// NewLifecycle — конструктор Lifecycle.
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{failed: make(chan error, 1)}
+}
+
+// Append — регистрирует компонент. Start и Stop могут быть nil.
+func (l *Lifecycle) Append(c Component) {
+	l.components = append(l.components, c)
+}
+
+// Start — запускает компоненты по порядку.
+// Если компонент не запустился, уже запущенные компоненты останавливаются
+// в обратном порядке, а ошибка возвращается вызывающей стороне.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	for _, c := range l.components {
+		if c.Start != nil {
+			slog.Info("starting component", slog.String("component", c.Name))
+			if err := c.Start(ctx); err != nil {
+				err = fmt.Errorf("failed to start %s: %w", c.Name, err)
+				if stopErr := l.Stop(ctx); stopErr != nil {
+					err = errors.Join(err, stopErr)
+				}
+				return err
+			}
+		}
+		l.started++
+	}
+
+	return nil
+}
+
+// Stop — останавливает запущенные компоненты в обратном порядке.
+// Ошибка одного компонента не прерывает остановку остальных: все ошибки
+// логируются и возвращаются вместе. Если срок контекста истёк, оставшимся
+// компонентам всё равно даётся возможность освободить ресурсы.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	var errs []error
+
+	for i := l.started - 1; i >= 0; i-- {
+		c := l.components[i]
+		if c.Stop == nil {
+			continue
+		}
+
+		slog.Info("stopping component", slog.String("component", c.Name))
+		if err := c.Stop(ctx); err != nil {
+			slog.Error("failed to stop component", slog.String("component", c.Name), slog.String("error", err.Error()))
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", c.Name, err))
+		}
+	}
+	l.started = 0
+
+	return errors.Join(errs...)
+}
+
+// Release — освобождает ресурсы компонентов, которые были созданы,
+// но не запускались (например, пул подключений к БД), если собрать
+// приложение не удалось. Вызывается до Start.
+func (l *Lifecycle) Release(ctx context.Context) error {
+	l.started = len(l.components)
+	return l.Stop(ctx)
+}
+
+// Fail — сообщает об аварийном завершении фоновой работы компонента.
+// Учитывается только первая ошибка.
+func (l *Lifecycle) Fail(err error) {
+	l.failOnce.Do(func() {
+		l.failed <- err
+	})
+}
+
+// Failed — канал, в который поступает первая ошибка, переданная через Fail.
+func (l *Lifecycle) Failed() <-chan error {
+	return l.failed
+}
@@ -0,0 +1,181 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// recorder — записывает порядок запуска и остановки компонентов.
+type recorder struct {
+	calls []string
+}
+
+// component — компонент, который записывает свои вызовы и возвращает ошибку,
+// если его имя есть в failStart или failStop.
+func (r *recorder) component(name string, failStart, failStop []string) Component {
+	return Component{
+		Name: name,
+		Start: func(context.Context) error {
+			r.calls = append(r.calls, "start "+name)
+			if slices.Contains(failStart, name) {
+				return errors.New("start error")
+			}
+			return nil
+		},
+		Stop: func(context.Context) error {
+			r.calls = append(r.calls, "stop "+name)
+			if slices.Contains(failStop, name) {
+				return errors.New("stop error")
+			}
+			return nil
+		},
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	names := []string{"db", "publisher", "server"}
+
+	tests := []struct {
+		name       string
+		failStart  []string
+		failStop   []string
+		release    bool // вместо Start и Stop вызывается Release
+		wantCalls  []string
+		wantErrors []string // подстроки ошибки Start или Release/Stop
+	}{
+		{
+			name: "start in order, stop in reverse",
+			wantCalls: []string{
+				"start db", "start publisher", "start server",
+				"stop server", "stop publisher", "stop db",
+			},
+		},
+		{
+			name:      "failed start rolls back started components",
+			failStart: []string{"server"},
+			wantCalls: []string{
+				"start db", "start publisher", "start server",
+				"stop publisher", "stop db",
+			},
+			wantErrors: []string{"failed to start server: start error"},
+		},
+		{
+			name:       "failed first start stops nothing",
+			failStart:  []string{"db"},
+			wantCalls:  []string{"start db"},
+			wantErrors: []string{"failed to start db"},
+		},
+		{
+			name:      "rollback errors are joined with the start error",
+			failStart: []string{"server"},
+			failStop:  []string{"db"},
+			wantCalls: []string{
+				"start db", "start publisher", "start server",
+				"stop publisher", "stop db",
+			},
+			wantErrors: []string{"failed to start server", "failed to stop db"},
+		},
+		{
+			name:     "all stop errors are collected",
+			failStop: []string{"server", "db"},
+			wantCalls: []string{
+				"start db", "start publisher", "start server",
+				"stop server", "stop publisher", "stop db",
+			},
+			wantErrors: []string{"failed to stop server", "failed to stop db"},
+		},
+		{
+			name:       "release stops components that never started",
+			release:    true,
+			failStop:   []string{"publisher"},
+			wantCalls:  []string{"stop server", "stop publisher", "stop db"},
+			wantErrors: []string{"failed to stop publisher"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			l := NewLifecycle()
+			for _, name := range names {
+				l.Append(r.component(name, tt.failStart, tt.failStop))
+			}
+			ctx := context.Background()
+
+			var err error
+			if tt.release {
+				err = l.Release(ctx)
+			} else if err = l.Start(ctx); err == nil {
+				err = l.Stop(ctx)
+			}
+
+			if !slices.Equal(r.calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", r.calls, tt.wantCalls)
+			}
+			if (err != nil) != (len(tt.wantErrors) > 0) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErrors)
+			}
+			for _, want := range tt.wantErrors {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %v, want it to contain %q", err, want)
+				}
+			}
+
+			// Повторная остановка не останавливает компоненты ещё раз.
+			calls := len(r.calls)
+			if err := l.Stop(ctx); err != nil || len(r.calls) != calls {
+				t.Errorf("second Stop() = %v, calls %v", err, r.calls[calls:])
+			}
+		})
+	}
+}
+
+func TestLifecycleNilHooks(t *testing.T) {
+	r := &recorder{}
+	l := NewLifecycle()
+	l.Append(Component{Name: "config"})
+	l.Append(r.component("server", nil, nil))
+	l.Append(Component{Name: "tracer", Stop: func(context.Context) error {
+		r.calls = append(r.calls, "stop tracer")
+		return nil
+	}})
+
+	if err := l.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := l.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	// Компонент без Start считается запущенным и останавливается вместе с остальными.
+	want := []string{"start server", "stop tracer", "stop server"}
+	if !slices.Equal(r.calls, want) {
+		t.Errorf("calls = %v, want %v", r.calls, want)
+	}
+}
+
+func TestLifecycleFail(t *testing.T) {
+	l := NewLifecycle()
+	first := errors.New("server crashed")
+
+	l.Fail(first)
+	l.Fail(errors.New("publisher crashed"))
+
+	select {
+	case err := <-l.Failed():
+		if !errors.Is(err, first) {
+			t.Errorf("Failed() = %v, want %v", err, first)
+		}
+	default:
+		t.Fatal("Failed() is empty after Fail")
+	}
+
+	select {
+	case err := <-l.Failed():
+		t.Errorf("Failed() = %v, want only the first error", err)
+	default:
+	}
+}
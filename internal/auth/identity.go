@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// RoleAdmin — роль администратора организации, которой разрешены все операции в её пределах.
+	RoleAdmin = "admin"
+	// RolePlatformAdmin — роль администратора платформы: управляет организациями
+	// и может работать в любой из них, указав заголовок X-Tenant-ID.
+	RolePlatformAdmin = "platform_admin"
+)
+
+// Разрешения (scopes), которые выдаются API-ключам.
+const (
+	ScopeSubscriptionsRead  = "subscriptions:read"
+	ScopeSubscriptionsWrite = "subscriptions:write"
+	ScopeReportsRead        = "reports:read"
+	ScopeWebhooksManage     = "webhooks:manage"
+)
+
+// Scopes — список всех известных разрешений.
+var Scopes = []string{ScopeSubscriptionsRead, ScopeSubscriptionsWrite, ScopeReportsRead, ScopeWebhooksManage}
+
+// Identity — описание вызывающей стороны, прошедшей аутентификацию.
+// Содержит субъект токена, UUID пользователя, организацию и список ролей.
+// Для API-ключей заполняются KeyID и Scopes, а UserID остаётся пустым.
+type Identity struct {
+	Subject  string
+	UserID   uuid.UUID
+	TenantID uuid.UUID
+	Roles    []string
+	KeyID    uuid.UUID
+	Scopes   []string
+}
+
+// Anonymous — личность запросов в режиме с отключённой аутентификацией:
+// администратор платформы, что сохраняет поведение открытого API.
+var Anonymous = Identity{Subject: "anonymous", Roles: []string{RoleAdmin, RolePlatformAdmin}}
+
+// IsAPIKey — проверяет, что вызывающая сторона аутентифицирована API-ключом.
+func (i Identity) IsAPIKey() bool {
+	return i.KeyID != uuid.Nil
+}
+
+// HasScope — проверяет наличие разрешения.
+// Пользователи ограничиваются ролями и владением подписками, поэтому
+// разрешения проверяются только для API-ключей.
+func (i Identity) HasScope(scope string) bool {
+	if !i.IsAPIKey() {
+		return true
+	}
+	return slices.Contains(i.Scopes, scope)
+}
+
+// HasRole — проверяет наличие у вызывающей стороны указанной роли.
+func (i Identity) HasRole(role string) bool {
+	return slices.Contains(i.Roles, role)
+}
+
+// IsAdmin — проверяет, является ли вызывающая сторона администратором организации.
+func (i Identity) IsAdmin() bool {
+	return i.HasRole(RoleAdmin)
+}
+
+// IsPlatformAdmin — проверяет, является ли вызывающая сторона администратором платформы.
+func (i Identity) IsPlatformAdmin() bool {
+	return i.HasRole(RolePlatformAdmin)
+}
+
+type identityKey struct{}
+
+// WithIdentity — возвращает копию контекста с сохранённой личностью вызывающей стороны.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext — извлекает личность вызывающей стороны из контекста.
+// Второе значение равно false, если запрос не прошёл аутентификацию.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"fmt"
+	"online_subscription_service/internal/config"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Issuer — выпускает короткоживущие access-токены для локальных учётных записей.
+// Токены подписываются HMAC-секретом из конфигурации, поэтому их принимает Verifier.
+type Issuer struct {
+	secret   []byte
+	issuer   string
+	audience string
+	ttl      time.Duration
+}
+
+// NewIssuer — конструктор Issuer.
+// Возвращает ошибку, если HMAC-секрет не задан, короче 32 байт или является заглушкой.
+func NewIssuer(cfg config.AuthConfig) (*Issuer, error) {
+	if err := checkHMACSecret(cfg.HMACSecret); err != nil {
+		return nil, fmt.Errorf("cannot issue tokens: %w", err)
+	}
+
+	return &Issuer{
+		secret:   []byte(cfg.HMACSecret),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		ttl:      cfg.AccessTokenTTL,
+	}, nil
+}
+
+// TTL — возвращает время жизни access-токена.
+func (i *Issuer) TTL() time.Duration {
+	return i.ttl
+}
+
+// Issue — выпускает подписанный access-токен для пользователя.
+func (i *Issuer) Issue(id Identity) (string, error) {
+	now := time.Now()
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   id.UserID.String(),
+			Issuer:    i.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(i.ttl)),
+		},
+		TenantID: id.TenantID.String(),
+		Roles:    id.Roles,
+	}
+	if i.audience != "" {
+		claims.Audience = jwt.ClaimStrings{i.audience}
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/config"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// clockSkew — допустимое расхождение часов при проверке exp/nbf/iat.
+const clockSkew = 30 * time.Second
+
+// ErrInvalidToken — токен не прошёл проверку подписи или стандартных утверждений.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims — утверждения, которые сервис читает из JWT.
+// Идентификатор пользователя берётся из user_id, а при его отсутствии — из sub.
+// Организация берётся из tenant_id; если он не указан, она определяется middleware Tenant.
+type Claims struct {
+	jwt.RegisteredClaims
+	UserID   string   `json:"user_id,omitempty"`
+	TenantID string   `json:"tenant_id,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+}
+
+// Verifier — проверяет bearer-токены и извлекает из них личность вызывающей стороны.
+// Поддерживает HMAC-секрет, а также публичные ключи RSA/ECDSA из PEM- или JWKS-файла.
+type Verifier struct {
+	keys   keySet
+	parser *jwt.Parser
+}
+
+// NewVerifier — конструктор Verifier.
+// Загружает ключи, указанные в конфигурации, и возвращает ошибку,
+// если не настроен ни один ключ, HMAC-секрет слишком короткий или является
+// заглушкой, либо файл ключей не удалось прочитать.
+func NewVerifier(cfg config.AuthConfig) (*Verifier, error) {
+	var ks keySet
+
+	if cfg.HMACSecret != "" {
+		if err := checkHMACSecret(cfg.HMACSecret); err != nil {
+			return nil, err
+		}
+		ks.add("", []byte(cfg.HMACSecret))
+	}
+
+	if cfg.PublicKeyFile != "" {
+		key, err := loadPEMFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		ks.add("", key)
+	}
+
+	if cfg.JWKSFile != "" {
+		if err := loadJWKSFile(cfg.JWKSFile, &ks); err != nil {
+			return nil, err
+		}
+	}
+
+	if ks.empty() {
+		return nil, errors.New("no token verification keys configured")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(ks.methods()),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return &Verifier{
+		keys:   ks,
+		parser: jwt.NewParser(opts...),
+	}, nil
+}
+
+// Verify — проверяет подпись и утверждения токена.
+// Возвращает личность вызывающей стороны или ошибку, обёрнутую в ErrInvalidToken.
+func (v *Verifier) Verify(token string) (Identity, error) {
+	var claims Claims
+
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.keyFunc); err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	userID := claims.UserID
+	if userID == "" {
+		userID = claims.Subject
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: user id is not a valid uuid", ErrInvalidToken)
+	}
+
+	var tenantID uuid.UUID
+	if claims.TenantID != "" {
+		tenantID, err = uuid.Parse(claims.TenantID)
+		if err != nil {
+			return Identity{}, fmt.Errorf("%w: tenant id is not a valid uuid", ErrInvalidToken)
+		}
+	}
+
+	return Identity{
+		Subject:  claims.Subject,
+		UserID:   id,
+		TenantID: tenantID,
+		Roles:    claims.Roles,
+	}, nil
+}
+
+// keyFunc — подбирает ключи проверки по kid и алгоритму подписи токена.
+func (v *Verifier) keyFunc(t *jwt.Token) (any, error) {
+	candidates := v.keys.fallback
+	if kid, ok := t.Header["kid"].(string); ok && kid != "" {
+		key, found := v.keys.byKID[kid]
+		if !found {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		candidates = []any{key}
+	}
+
+	var set jwt.VerificationKeySet
+	for _, key := range candidates {
+		if keyMatchesMethod(key, t.Method) {
+			set.Keys = append(set.Keys, key)
+		}
+	}
+
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("no key for signing method %s", t.Method.Alg())
+	}
+
+	return set, nil
+}
+
+// methods — возвращает алгоритмы подписи, допустимые для загруженных ключей.
+func (ks *keySet) methods() []string {
+	var hmac, rsaKeys, ecKeys bool
+
+	check := func(key any) {
+		switch key.(type) {
+		case []byte:
+			hmac = true
+		case *rsa.PublicKey:
+			rsaKeys = true
+		case *ecdsa.PublicKey:
+			ecKeys = true
+		}
+	}
+
+	for _, key := range ks.fallback {
+		check(key)
+	}
+	for _, key := range ks.byKID {
+		check(key)
+	}
+
+	var methods []string
+	if hmac {
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+	if rsaKeys {
+		methods = append(methods, "RS256", "RS384", "RS512", "PS256", "PS384", "PS512")
+	}
+	if ecKeys {
+		methods = append(methods, "ES256", "ES384", "ES512")
+	}
+
+	return methods
+}
+
+// keyMatchesMethod — проверяет, подходит ли ключ для алгоритма подписи.
+func keyMatchesMethod(key any, method jwt.SigningMethod) bool {
+	switch method.(type) {
+	case *jwt.SigningMethodHMAC:
+		_, ok := key.([]byte)
+		return ok
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, ok := key.(*rsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodECDSA:
+		_, ok := key.(*ecdsa.PublicKey)
+		return ok
+	default:
+		return false
+	}
+}
@@ -0,0 +1,366 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"online_subscription_service/internal/config"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// writeFile — записывает data во временный файл и возвращает его путь.
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// pemPublicKey — кодирует публичный ключ в PEM (PKIX).
+func pemPublicKey(t *testing.T, key any) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// b64 — кодирует байты в base64url без выравнивания, как в JWK.
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// sign — подписывает токен с утверждениями claims и, если kid не пуст, заголовком kid.
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return s
+}
+
+func TestVerifierVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ecdsa key: %v", err)
+	}
+	otherEC, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ecdsa key: %v", err)
+	}
+
+	rsaPEM := pemPublicKey(t, &rsaKey.PublicKey)
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "EC",
+		"kid": "ec-1",
+		"use": "sig",
+		"crv": "P-256",
+		"x":   b64(ecKey.X.FillBytes(make([]byte, 32))),
+		"y":   b64(ecKey.Y.FillBytes(make([]byte, 32))),
+	}}})
+	if err != nil {
+		t.Fatalf("failed to encode jwks: %v", err)
+	}
+
+	v, err := NewVerifier(config.AuthConfig{
+		HMACSecret:    testSecret,
+		PublicKeyFile: writeFile(t, "key.pem", rsaPEM),
+		JWKSFile:      writeFile(t, "jwks.json", jwks),
+		Issuer:        "https://issuer.example",
+		Audience:      "subscriptions",
+	})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+
+	userID, tenantID := uuid.New(), uuid.New()
+	now := time.Now()
+	claims := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub":       userID.String(),
+			"tenant_id": tenantID.String(),
+			"roles":     []string{RoleAdmin},
+			"iss":       "https://issuer.example",
+			"aud":       "subscriptions",
+			"exp":       now.Add(time.Minute).Unix(),
+		}
+		if change != nil {
+			change(c)
+		}
+		return c
+	}
+
+	hs256 := sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims(nil))
+
+	tests := []struct {
+		name    string
+		token   string
+		want    Identity
+		wantErr bool
+	}{
+		{
+			name:  "hmac",
+			token: hs256,
+			want:  Identity{Subject: userID.String(), UserID: userID, TenantID: tenantID, Roles: []string{RoleAdmin}},
+		},
+		{
+			name:  "rsa key from pem",
+			token: sign(t, jwt.SigningMethodRS256, rsaKey, "", claims(nil)),
+			want:  Identity{Subject: userID.String(), UserID: userID, TenantID: tenantID, Roles: []string{RoleAdmin}},
+		},
+		{
+			name:  "rsa-pss key from pem",
+			token: sign(t, jwt.SigningMethodPS256, rsaKey, "", claims(nil)),
+			want:  Identity{Subject: userID.String(), UserID: userID, TenantID: tenantID, Roles: []string{RoleAdmin}},
+		},
+		{
+			name:  "ecdsa key from jwks by kid",
+			token: sign(t, jwt.SigningMethodES256, ecKey, "ec-1", claims(nil)),
+			want:  Identity{Subject: userID.String(), UserID: userID, TenantID: tenantID, Roles: []string{RoleAdmin}},
+		},
+		{
+			name:  "user_id takes precedence over sub",
+			token: sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims(func(c jwt.MapClaims) { c["sub"] = "service"; c["user_id"] = userID.String() })),
+			want:  Identity{Subject: "service", UserID: userID, TenantID: tenantID, Roles: []string{RoleAdmin}},
+		},
+		{
+			name:  "expired within clock skew",
+			token: sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims(func(c jwt.MapClaims) { c["exp"] = now.Add(-clockSkew / 2).Unix() })),
+			want:  Identity{Subject: userID.String(), UserID: userID, TenantID: tenantID, Roles: []string{RoleAdmin}},
+		},
+		{
+			name:    "wrong hmac secret",
+			token:   sign(t, jwt.SigningMethodHS256, []byte("fedcba9876543210fedcba9876543210"), "", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "tampered payload",
+			token:   hs256[:len(hs256)-4] + "AAAA",
+			wantErr: true,
+		},
+		{
+			name:    "signed by another ecdsa key",
+			token:   sign(t, jwt.SigningMethodES256, otherEC, "ec-1", claims(nil)),
+			wantErr: true,
+		},
+		{
+			// Классическая подмена алгоритма: публичный ключ RSA используется как HMAC-секрет.
+			name:    "hmac signed with rsa public key",
+			token:   sign(t, jwt.SigningMethodHS256, rsaPEM, "", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "unsigned token",
+			token:   sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "kid of ecdsa key with rsa algorithm",
+			token:   sign(t, jwt.SigningMethodRS256, rsaKey, "ec-1", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "unknown kid",
+			token:   sign(t, jwt.SigningMethodES256, ecKey, "ec-2", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "missing exp",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims(func(c jwt.MapClaims) { delete(c, "exp") })),
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims(func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() })),
+			wantErr: true,
+		},
+		{
+			name:    "issuer mismatch",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims(func(c jwt.MapClaims) { c["iss"] = "https://other.example" })),
+			wantErr: true,
+		},
+		{
+			name:    "missing issuer",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims(func(c jwt.MapClaims) { delete(c, "iss") })),
+			wantErr: true,
+		},
+		{
+			name:    "audience mismatch",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims(func(c jwt.MapClaims) { c["aud"] = []string{"billing"} })),
+			wantErr: true,
+		},
+		{
+			name:    "subject is not a uuid",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims(func(c jwt.MapClaims) { c["sub"] = "alice" })),
+			wantErr: true,
+		},
+		{
+			name:    "tenant is not a uuid",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims(func(c jwt.MapClaims) { c["tenant_id"] = "acme" })),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Verify(tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if got.Subject != tt.want.Subject || got.UserID != tt.want.UserID || got.TenantID != tt.want.TenantID ||
+				!slices.Equal(got.Roles, tt.want.Roles) {
+				t.Errorf("Verify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifierAcceptsIssuedTokens(t *testing.T) {
+	cfg := config.AuthConfig{HMACSecret: testSecret, Issuer: "subscriptions", Audience: "api", AccessTokenTTL: time.Minute}
+
+	issuer, err := NewIssuer(cfg)
+	if err != nil {
+		t.Fatalf("NewIssuer() error = %v", err)
+	}
+	v, err := NewVerifier(cfg)
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+
+	want := Identity{UserID: uuid.New(), TenantID: uuid.New(), Roles: []string{RoleAdmin}}
+	token, err := issuer.Issue(want)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	got, err := v.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if got.UserID != want.UserID || got.TenantID != want.TenantID || !slices.Equal(got.Roles, want.Roles) {
+		t.Errorf("Verify() = %+v, want %+v", got, want)
+	}
+}
+
+func TestKeyMatchesMethod(t *testing.T) {
+	rsaKey := &rsa.PublicKey{}
+	ecKey := &ecdsa.PublicKey{}
+	hmacKey := []byte(testSecret)
+
+	tests := []struct {
+		method jwt.SigningMethod
+		key    any
+		want   bool
+	}{
+		{method: jwt.SigningMethodHS256, key: hmacKey, want: true},
+		{method: jwt.SigningMethodHS512, key: hmacKey, want: true},
+		{method: jwt.SigningMethodHS256, key: rsaKey, want: false},
+		{method: jwt.SigningMethodHS256, key: ecKey, want: false},
+		{method: jwt.SigningMethodRS256, key: rsaKey, want: true},
+		{method: jwt.SigningMethodPS256, key: rsaKey, want: true},
+		{method: jwt.SigningMethodRS256, key: hmacKey, want: false},
+		{method: jwt.SigningMethodRS256, key: ecKey, want: false},
+		{method: jwt.SigningMethodES256, key: ecKey, want: true},
+		{method: jwt.SigningMethodES256, key: rsaKey, want: false},
+		{method: jwt.SigningMethodES256, key: hmacKey, want: false},
+		{method: jwt.SigningMethodEdDSA, key: ecKey, want: false},
+		{method: jwt.SigningMethodNone, key: hmacKey, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := keyMatchesMethod(tt.key, tt.method); got != tt.want {
+			t.Errorf("keyMatchesMethod(%T, %s) = %v, want %v", tt.key, tt.method.Alg(), got, tt.want)
+		}
+	}
+}
+
+func TestKeySetMethods(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []any
+		want []string
+	}{
+		{name: "hmac only", keys: []any{[]byte(testSecret)}, want: []string{"HS256", "HS384", "HS512"}},
+		{name: "rsa only", keys: []any{&rsa.PublicKey{}}, want: []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}},
+		{name: "ecdsa only", keys: []any{&ecdsa.PublicKey{}}, want: []string{"ES256", "ES384", "ES512"}},
+		{
+			name: "hmac and ecdsa",
+			keys: []any{&ecdsa.PublicKey{}, []byte(testSecret)},
+			want: []string{"HS256", "HS384", "HS512", "ES256", "ES384", "ES512"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ks keySet
+			for i, key := range tt.keys {
+				kid := ""
+				if i%2 == 1 {
+					kid = "kid"
+				}
+				ks.add(kid, key)
+			}
+			if got := ks.methods(); !slices.Equal(got, tt.want) {
+				t.Errorf("methods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewVerifierErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.AuthConfig
+	}{
+		{name: "no keys", cfg: config.AuthConfig{}},
+		{name: "short hmac secret", cfg: config.AuthConfig{HMACSecret: "short-secret"}},
+		{name: "placeholder hmac secret", cfg: config.AuthConfig{HMACSecret: "change-me-change-me-change-me-change-me"}},
+		{name: "missing pem file", cfg: config.AuthConfig{PublicKeyFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		{name: "missing jwks file", cfg: config.AuthConfig{JWKSFile: filepath.Join(t.TempDir(), "missing.json")}},
+		{name: "jwks without signing keys", cfg: config.AuthConfig{JWKSFile: writeFile(t, "empty.json", []byte(`{"keys":[]}`))}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewVerifier(tt.cfg); err == nil {
+				t.Error("NewVerifier() error = nil, want error")
+			}
+		})
+	}
+}
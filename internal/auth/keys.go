@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// minHMACSecretLength — минимальная длина HMAC-секрета в байтах (256 бит для HS256).
+const minHMACSecretLength = 32
+
+// placeholderSecrets — фрагменты заглушек из примеров и шаблонов. Секрет, содержащий
+// любой из них, считается незаменённой заглушкой.
+var placeholderSecrets = []string{
+	"change-me",
+	"changeme",
+	"change_me",
+	"change-this",
+	"changethis",
+	"replace-me",
+	"replaceme",
+	"your-256-bit-secret",
+	"local-dev-secret",
+}
+
+// checkHMACSecret — проверяет, что HMAC-секрет задан, не короче minHMACSecretLength
+// байт и не является известной заглушкой.
+func checkHMACSecret(secret string) error {
+	if secret == "" {
+		return errors.New("hmac secret is empty")
+	}
+
+	normalized := strings.ToLower(strings.TrimSpace(secret))
+	for _, placeholder := range placeholderSecrets {
+		if strings.Contains(normalized, placeholder) {
+			return errors.New("hmac secret is a placeholder value")
+		}
+	}
+
+	if len(secret) < minHMACSecretLength {
+		return fmt.Errorf("hmac secret must be at least %d bytes, got %d", minHMACSecretLength, len(secret))
+	}
+
+	return nil
+}
+
+// keySet — набор ключей для проверки подписи токенов.
+// Ключи с идентификатором (kid) берутся из JWKS, ключ без идентификатора
+// используется для токенов, в заголовке которых kid не указан.
+type keySet struct {
+	byKID    map[string]any
+	fallback []any
+}
+
+// add — добавляет ключ в набор.
+func (ks *keySet) add(kid string, key any) {
+	if kid == "" {
+		ks.fallback = append(ks.fallback, key)
+		return
+	}
+
+	if ks.byKID == nil {
+		ks.byKID = make(map[string]any)
+	}
+	ks.byKID[kid] = key
+}
+
+// empty — проверяет, что в наборе нет ни одного ключа.
+func (ks *keySet) empty() bool {
+	return len(ks.byKID) == 0 && len(ks.fallback) == 0
+}
+
+// loadPEMFile — читает публичный ключ RSA/ECDSA или сертификат из PEM-файла.
+func loadPEMFile(path string) (any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("public key file does not contain PEM data")
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		return checkPublicKey(cert.PublicKey)
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA public key: %w", err)
+		}
+		return key, nil
+	default:
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		return checkPublicKey(key)
+	}
+}
+
+// checkPublicKey — проверяет, что тип ключа поддерживается.
+func checkPublicKey(key any) (any, error) {
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// jwk — ключ в формате JSON Web Key (RFC 7517).
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// loadJWKSFile — читает набор ключей из локального JWKS-файла.
+// Ключи с назначением, отличным от подписи (use != "sig"), пропускаются.
+func loadJWKSFile(path string, ks *keySet) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read jwks file: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("failed to parse jwks file: %w", err)
+	}
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return fmt.Errorf("jwk %q: %w", k.Kid, err)
+		}
+		ks.add(k.Kid, key)
+	}
+
+	return nil
+}
+
+// publicKey — конвертирует JWK в ключ из стандартной библиотеки.
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return nil, fmt.Errorf("invalid symmetric key: %w", err)
+		}
+		return secret, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// decodeBigInt — декодирует число из base64url без выравнивания.
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key parameter: %w", err)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckHMACSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{name: "random", secret: testSecret},
+		{name: "empty", secret: "", wantErr: true},
+		{name: "one byte short", secret: testSecret[:31], wantErr: true},
+		{name: "placeholder", secret: "change-me", wantErr: true},
+		{name: "long placeholder", secret: "please-CHANGE-ME-before-deploying-to-production", wantErr: true},
+		{name: "padded placeholder", secret: "  ChangeMe  ", wantErr: true},
+		{name: "jwt.io example", secret: "your-256-bit-secret-your-256-bit-secret", wantErr: true},
+		{name: "former local config", secret: "local-dev-secret-local-dev-secret", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkHMACSecret(tt.secret)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkHMACSecret(%q) error = %v, wantErr %v", tt.secret, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadPEMFile(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ecdsa key: %v", err)
+	}
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ed25519 key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "issuer"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &ecKey.PublicKey, ecKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    any
+		wantErr string
+	}{
+		{name: "pkix rsa", data: pemPublicKey(t, &rsaKey.PublicKey), want: &rsaKey.PublicKey},
+		{name: "pkix ecdsa", data: pemPublicKey(t, &ecKey.PublicKey), want: &ecKey.PublicKey},
+		{
+			name: "pkcs1 rsa",
+			data: pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)}),
+			want: &rsaKey.PublicKey,
+		},
+		{name: "certificate", data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), want: &ecKey.PublicKey},
+		{name: "not pem", data: []byte("ssh-rsa AAAA"), wantErr: "does not contain PEM data"},
+		{name: "unsupported ed25519", data: pemPublicKey(t, edKey), wantErr: "unsupported public key type"},
+		{name: "broken der", data: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte{1, 2, 3}}), wantErr: "failed to parse public key"},
+		{name: "broken certificate", data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1, 2, 3}}), wantErr: "failed to parse certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadPEMFile(writeFile(t, "key.pem", tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadPEMFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadPEMFile() error = %v", err)
+			}
+			if !got.(interface{ Equal(crypto.PublicKey) bool }).Equal(tt.want) {
+				t.Errorf("loadPEMFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadJWKSFile(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ecdsa key: %v", err)
+	}
+
+	rsaJWK := `{"kty":"RSA","kid":"rsa-1","n":"` + b64(rsaKey.N.Bytes()) + `","e":"` + b64(big.NewInt(int64(rsaKey.E)).Bytes()) + `"}`
+	ecJWK := `{"kty":"EC","kid":"ec-1","use":"sig","crv":"P-384","x":"` + b64(ecKey.X.Bytes()) + `","y":"` + b64(ecKey.Y.Bytes()) + `"}`
+	octJWK := `{"kty":"oct","kid":"hs-1","k":"` + b64([]byte(testSecret)) + `"}`
+
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name: "rsa, ecdsa and symmetric keys",
+			data: `{"keys":[` + rsaJWK + `,` + ecJWK + `,` + octJWK + `]}`,
+			want: map[string]any{"rsa-1": &rsaKey.PublicKey, "ec-1": &ecKey.PublicKey, "hs-1": []byte(testSecret)},
+		},
+		{
+			name: "encryption keys are skipped",
+			data: `{"keys":[` + rsaJWK + `,{"kty":"RSA","kid":"enc-1","use":"enc","n":"AQAB","e":"AQAB"}]}`,
+			want: map[string]any{"rsa-1": &rsaKey.PublicKey},
+		},
+		{name: "not json", data: `keys`, wantErr: "failed to parse jwks file"},
+		{name: "unsupported key type", data: `{"keys":[{"kty":"OKP","kid":"ed-1","crv":"Ed25519","x":"AQAB"}]}`, wantErr: `jwk "ed-1": unsupported key type`},
+		{name: "unsupported curve", data: `{"keys":[{"kty":"EC","kid":"ec-2","crv":"secp256k1","x":"AQAB","y":"AQAB"}]}`, wantErr: "unsupported curve"},
+		{name: "broken modulus", data: `{"keys":[{"kty":"RSA","kid":"rsa-2","n":"***","e":"AQAB"}]}`, wantErr: "invalid key parameter"},
+		{name: "broken symmetric key", data: `{"keys":[{"kty":"oct","kid":"hs-2","k":"***"}]}`, wantErr: "invalid symmetric key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ks keySet
+			err := loadJWKSFile(writeFile(t, "jwks.json", []byte(tt.data)), &ks)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadJWKSFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadJWKSFile() error = %v", err)
+			}
+
+			if len(ks.byKID) != len(tt.want) || len(ks.fallback) != 0 {
+				t.Fatalf("loaded keys = %v (fallback %v), want %v", ks.byKID, ks.fallback, tt.want)
+			}
+			for kid, want := range tt.want {
+				got, ok := ks.byKID[kid]
+				if !ok {
+					t.Errorf("key %q not loaded", kid)
+					continue
+				}
+				switch want := want.(type) {
+				case []byte:
+					if secret, _ := got.([]byte); !bytes.Equal(secret, want) {
+						t.Errorf("key %q = %v, want %v", kid, got, want)
+					}
+				default:
+					if !got.(interface{ Equal(crypto.PublicKey) bool }).Equal(want) {
+						t.Errorf("key %q = %v, want %v", kid, got, want)
+					}
+				}
+			}
+		})
+	}
+}
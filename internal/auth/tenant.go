@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"errors"
+	"online_subscription_service/internal/domain/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrForeignTenant — вызывающая сторона запросила чужую организацию, не будучи администратором платформы.
+var ErrForeignTenant = errors.New("access to another tenant is forbidden")
+
+// ResolveTenant — определяет организацию запроса по личности и запрошенной организации
+// (заголовок X-Tenant-ID или метаданные x-tenant-id; uuid.Nil — не указана).
+//
+// Правила:
+//   - выбрать организацию, отличную от организации из токена или API-ключа, может только
+//     администратор платформы; остальным запрошенная организация должна совпадать с ней;
+//   - если организация не указана ни в токене, ни в запросе, используется организация
+//     по умолчанию; токен без организации не позволяет выбрать организацию запросом.
+//
+// Существование организации проверяет вызывающая сторона.
+func ResolveTenant(id Identity, requested uuid.UUID) (Identity, error) {
+	if id.TenantID == uuid.Nil {
+		id.TenantID = models.DefaultTenantID
+		if requested == uuid.Nil {
+			return id, nil
+		}
+	}
+
+	if requested != uuid.Nil && requested != id.TenantID {
+		if !id.IsPlatformAdmin() {
+			return Identity{}, ErrForeignTenant
+		}
+		id.TenantID = requested
+	}
+
+	return id, nil
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"errors"
+	"online_subscription_service/internal/domain/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestResolveTenant(t *testing.T) {
+	own, other := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name      string
+		id        Identity
+		requested uuid.UUID
+		want      uuid.UUID
+		wantErr   error
+	}{
+		{name: "token tenant", id: Identity{TenantID: own}, want: own},
+		{name: "same tenant requested", id: Identity{TenantID: own}, requested: own, want: own},
+		{name: "foreign tenant requested by user", id: Identity{TenantID: own}, requested: other, wantErr: ErrForeignTenant},
+		{name: "foreign tenant requested by tenant admin", id: Identity{TenantID: own, Roles: []string{RoleAdmin}}, requested: other, wantErr: ErrForeignTenant},
+		{name: "foreign tenant requested by api key", id: Identity{TenantID: own, KeyID: uuid.New()}, requested: other, wantErr: ErrForeignTenant},
+		{name: "foreign tenant requested by platform admin", id: Identity{TenantID: own, Roles: []string{RolePlatformAdmin}}, requested: other, want: other},
+		{name: "no tenant falls back to default", id: Identity{}, want: models.DefaultTenantID},
+		{name: "no tenant with default requested", id: Identity{}, requested: models.DefaultTenantID, want: models.DefaultTenantID},
+		{name: "no tenant cannot pick tenant", id: Identity{}, requested: other, wantErr: ErrForeignTenant},
+		{name: "no tenant admin cannot pick tenant", id: Identity{Roles: []string{RoleAdmin}}, requested: other, wantErr: ErrForeignTenant},
+		{name: "no tenant platform admin picks tenant", id: Identity{Roles: []string{RolePlatformAdmin}}, requested: other, want: other},
+		{name: "anonymous picks tenant", id: Anonymous, requested: other, want: other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveTenant(tt.id, tt.requested)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveTenant() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.TenantID != tt.want {
+				t.Errorf("ResolveTenant() tenant = %s, want %s", got.TenantID, tt.want)
+			}
+		})
+	}
+}
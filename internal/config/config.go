@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"io/fs"
 	"log"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
@@ -11,28 +14,242 @@ import (
 
 // Config содержит все конфигурационные параметры сервиса.
 type Config struct {
-	Env  string `yaml:"env" env-default:"local"`
-	Port int    `yaml:"port"`
-	Host string `yaml:"host"`
-	DB   DBConfig
+	Env          string `yaml:"env" env-default:"local"`
+	Port         int    `yaml:"port"`
+	Host         string `yaml:"host"`
+	DB           DBConfig
+	Auth         AuthConfig         `yaml:"auth"`
+	RateLimit    RateLimitConfig    `yaml:"rate_limit"`
+	Quota        QuotaConfig        `yaml:"quota"`
+	Log          LogConfig          `yaml:"log"`
+	Metrics      MetricsConfig      `yaml:"metrics"`
+	Tracing      TracingConfig      `yaml:"tracing"`
+	Shutdown     ShutdownConfig     `yaml:"shutdown"`
+	GRPC         GRPCConfig         `yaml:"grpc"`
+	Import       ImportConfig       `yaml:"import"`
+	Ledger       LedgerConfig       `yaml:"ledger"`
+	Outbox       OutboxConfig       `yaml:"outbox"`
+	Webhooks     WebhooksConfig     `yaml:"webhooks"`
+	Integrations IntegrationsConfig `yaml:"integrations"`
 }
 
 // DBConfig определяет параметры подключения к базе данных.
+// Если задан DSN, он используется вместо отдельных параметров подключения;
+// иначе переменные окружения PGHOST, PGPORT, PGUSER, PGPASSWORD, PGDATABASE
+// и PGSSLMODE, если они заданы, переопределяют соответствующие параметры.
 type DBConfig struct {
+	DSN      string `env:"DB_DSN" yaml:"dsn"`              // Полная строка подключения (URL или key=value)
 	DBPort   string `env:"DB_PORT" yaml:"db_port"`         // Порт БД
 	SSLMode  string `env:"SSL_MODE" yaml:"ssl_mode" `      // Режим SSL-соединения
 	Username string `env:"DB_USER" yaml:"username"`        // Имя пользователя БД
 	Password string `env:"DB_PASSWORD" yaml:"db_password"` // Пароль БД (загружается из переменной окружения)
 	DBName   string `env:"DB_NAME" yaml:"db_name"`         // Имя базы данных (может быть переопределено через ENV)
 	DBHost   string `env:"DB_HOST" yaml:"db_host"`         // Адрес хоста БД
+
+	AutoMigrate bool `env:"DB_AUTO_MIGRATE" yaml:"auto_migrate" env-default:"true"` // Применять миграции при запуске сервиса
+
+	MaxConns          int32         `env:"DB_MAX_CONNS" yaml:"max_conns" env-default:"10"`                        // Максимальный размер пула
+	MinConns          int32         `env:"DB_MIN_CONNS" yaml:"min_conns" env-default:"0"`                         // Минимальное число поддерживаемых подключений
+	MaxConnLifetime   time.Duration `env:"DB_MAX_CONN_LIFETIME" yaml:"max_conn_lifetime" env-default:"1h"`        // Максимальное время жизни подключения
+	MaxConnIdleTime   time.Duration `env:"DB_MAX_CONN_IDLE_TIME" yaml:"max_conn_idle_time" env-default:"30m"`     // Максимальное время простоя подключения
+	StatementTimeout  time.Duration `env:"DB_STATEMENT_TIMEOUT" yaml:"statement_timeout" env-default:"30s"`       // statement_timeout для каждого подключения (0 — без ограничения)
+	ConnectTimeout    time.Duration `env:"DB_CONNECT_TIMEOUT" yaml:"connect_timeout" env-default:"1m"`            // Общий срок подключения и миграций при запуске
+	RetryInitialDelay time.Duration `env:"DB_RETRY_INITIAL_DELAY" yaml:"retry_initial_delay" env-default:"500ms"` // Пауза перед первой повторной попыткой подключения
+	RetryMaxDelay     time.Duration `env:"DB_RETRY_MAX_DELAY" yaml:"retry_max_delay" env-default:"10s"`           // Максимальная пауза между попытками подключения
+}
+
+// AuthConfig определяет параметры аутентификации запросов по JWT.
+// Для проверки подписи используется HMAC-секрет и/или публичные ключи
+// RSA/ECDSA из PEM-файла или локального JWKS-файла.
+type AuthConfig struct {
+	Enabled       bool     `env:"AUTH_ENABLED" yaml:"enabled"`                 // Включает проверку токенов
+	HMACSecret    string   `env:"AUTH_HMAC_SECRET" yaml:"hmac_secret"`         // Секрет для HS256/HS384/HS512
+	PublicKeyFile string   `env:"AUTH_PUBLIC_KEY_FILE" yaml:"public_key_file"` // PEM-файл с публичным ключом или сертификатом
+	JWKSFile      string   `env:"AUTH_JWKS_FILE" yaml:"jwks_file"`             // Локальный JWKS-файл
+	Issuer        string   `env:"AUTH_ISSUER" yaml:"issuer"`                   // Ожидаемый iss (не проверяется, если пуст)
+	Audience      string   `env:"AUTH_AUDIENCE" yaml:"audience"`               // Ожидаемый aud (не проверяется, если пуст)
+	PublicPaths   []string `yaml:"public_paths" env-default:"/swagger/"`       // Префиксы путей, доступных без токена
+
+	LocalAccounts     bool          `env:"AUTH_LOCAL_ACCOUNTS" yaml:"local_accounts"`                          // Включает вход по паролю (нужен hmac_secret)
+	AllowRegistration bool          `env:"AUTH_ALLOW_REGISTRATION" yaml:"allow_registration"`                  // Разрешает самостоятельную регистрацию
+	AccessTokenTTL    time.Duration `env:"AUTH_ACCESS_TOKEN_TTL" yaml:"access_token_ttl" env-default:"15m"`    // Время жизни access-токена
+	RefreshTokenTTL   time.Duration `env:"AUTH_REFRESH_TOKEN_TTL" yaml:"refresh_token_ttl" env-default:"720h"` // Время жизни refresh-токена
+}
+
+// RateLimitConfig определяет ограничения частоты запросов.
+// Лимит считается отдельно для каждого клиента (API-ключа, пользователя или IP-адреса)
+// и для каждой группы маршрутов; для групп, не указанных в Groups, используется Default.
+// IP-адрес клиента берётся из X-Forwarded-For, только если запрос пришёл от прокси
+// из TrustedProxies; иначе используется адрес соединения.
+type RateLimitConfig struct {
+	Enabled        bool                 `env:"RATE_LIMIT_ENABLED" yaml:"enabled"`                 // Включает ограничение частоты запросов
+	Default        RateLimit            `yaml:"default"`                                          // Лимит по умолчанию
+	Groups         map[string]RateLimit `yaml:"groups"`                                           // Лимиты групп маршрутов (subscriptions, auth, ...)
+	TrustedProxies []string             `env:"RATE_LIMIT_TRUSTED_PROXIES" yaml:"trusted_proxies"` // Подсети (CIDR) или адреса прокси, которым доверяется X-Forwarded-For (пусто — IP соединения)
+}
+
+// RateLimit — параметры корзины токенов.
+type RateLimit struct {
+	Rate  float64 `yaml:"rate" env-default:"10"`  // Скорость пополнения, запросов в секунду
+	Burst int     `yaml:"burst" env-default:"20"` // Максимальное число запросов подряд
+}
+
+// QuotaConfig определяет квоты на данные пользователей.
+type QuotaConfig struct {
+	MaxSubscriptionsPerUser int `env:"QUOTA_MAX_SUBSCRIPTIONS_PER_USER" yaml:"max_subscriptions_per_user"` // Максимум активных подписок у пользователя (0 — без ограничений)
+}
+
+// LogConfig определяет параметры логирования.
+type LogConfig struct {
+	Level  string `env:"LOG_LEVEL" yaml:"level" env-default:"info"`   // Уровень: debug, info, warn, error
+	Format string `env:"LOG_FORMAT" yaml:"format" env-default:"text"` // Формат вывода: text или json
+}
+
+// MetricsConfig определяет параметры экспорта метрик в формате Prometheus.
+// Если Port не задан, метрики отдаются основным HTTP-сервером и, как и остальные
+// маршруты, требуют аутентификации, если путь не добавлен в auth.public_paths.
+type MetricsConfig struct {
+	Enabled bool   `env:"METRICS_ENABLED" yaml:"enabled"`                  // Включает сбор и экспорт метрик
+	Path    string `env:"METRICS_PATH" yaml:"path" env-default:"/metrics"` // Путь эндпоинта метрик
+	Port    int    `env:"METRICS_PORT" yaml:"port"`                        // Отдельный административный порт (0 — основной сервер)
+}
+
+// GRPCConfig определяет параметры gRPC API, работающего рядом с REST API на отдельном порту.
+type GRPCConfig struct {
+	Enabled    bool `env:"GRPC_ENABLED" yaml:"enabled"`                          // Включает gRPC-сервер
+	Port       int  `env:"GRPC_PORT" yaml:"port" env-default:"50051"`            // Порт gRPC-сервера
+	Reflection bool `env:"GRPC_REFLECTION" yaml:"reflection" env-default:"true"` // Включает gRPC reflection (grpcurl, Postman)
+}
+
+// ImportConfig определяет параметры импорта подписок из CSV- и XLSX-файлов.
+// Файлы, в которых не больше SyncRows строк, импортируются в рамках запроса,
+// файлы большего размера — фоновой задачей, прогресс которой можно запросить отдельно.
+type ImportConfig struct {
+	MaxFileSize int64 `env:"IMPORT_MAX_FILE_SIZE" yaml:"max_file_size" env-default:"10485760"` // Максимальный размер файла в байтах
+	MaxRows     int   `env:"IMPORT_MAX_ROWS" yaml:"max_rows" env-default:"100000"`             // Максимум строк с данными в файле
+	SyncRows    int   `env:"IMPORT_SYNC_ROWS" yaml:"sync_rows" env-default:"1000"`             // Порог, после которого импорт выполняется в фоне
+	Workers     int   `env:"IMPORT_WORKERS" yaml:"workers" env-default:"2"`                    // Число одновременно выполняемых фоновых импортов
+}
+
+// LedgerConfig определяет счета и валюту проводок при выгрузке начислений по подпискам
+// в журнал ledger, hledger или beancount. Расход по услуге, для которой нет счёта в Accounts,
+// записывается на подсчёт ExpenseAccount с названием услуги (Expenses:Subscriptions:Netflix).
+type LedgerConfig struct {
+	ExpenseAccount string            `env:"LEDGER_EXPENSE_ACCOUNT" yaml:"expense_account" env-default:"Expenses:Subscriptions"` // Родительский счёт расходов на подписки
+	PaymentAccount string            `env:"LEDGER_PAYMENT_ACCOUNT" yaml:"payment_account" env-default:"Liabilities:CreditCard"` // Счёт, с которого оплачиваются подписки
+	Currency       string            `env:"LEDGER_CURRENCY" yaml:"currency" env-default:"RUB"`                                  // Валюта цен подписок
+	Accounts       map[string]string `yaml:"accounts"`                                                                          // Счета расходов отдельных услуг (название услуги → счёт)
+}
+
+// OutboxConfig определяет параметры публикации событий об изменении подписок.
+// События записываются в таблицу outbox в одной транзакции с изменением и публикуются
+// фоновым обработчиком в NATS, в файл или в stdout (по одному JSON-объекту на событие).
+// Неудачная публикация повторяется с экспоненциально растущей паузой.
+type OutboxConfig struct {
+	Enabled           bool          `env:"OUTBOX_ENABLED" yaml:"enabled"`                                          // Включает запись и публикацию событий
+	Publisher         string        `env:"OUTBOX_PUBLISHER" yaml:"publisher" env-default:"stdout"`                 // Публикатор: nats, file, stdout или none
+	PollInterval      time.Duration `env:"OUTBOX_POLL_INTERVAL" yaml:"poll_interval" env-default:"1s"`             // Пауза между проверками новых событий
+	BatchSize         int           `env:"OUTBOX_BATCH_SIZE" yaml:"batch_size" env-default:"100"`                  // Максимум событий, публикуемых за один проход
+	RetryInitialDelay time.Duration `env:"OUTBOX_RETRY_INITIAL_DELAY" yaml:"retry_initial_delay" env-default:"1s"` // Пауза перед первой повторной публикацией
+	RetryMaxDelay     time.Duration `env:"OUTBOX_RETRY_MAX_DELAY" yaml:"retry_max_delay" env-default:"5m"`         // Максимальная пауза между повторными публикациями
+	Retention         time.Duration `env:"OUTBOX_RETENTION" yaml:"retention" env-default:"168h"`                   // Срок хранения опубликованных событий (0 — не удалять)
+	File              string        `env:"OUTBOX_FILE" yaml:"file" env-default:"events.jsonl"`                     // Файл для публикатора file
+	NATS              NATSConfig    `yaml:"nats"`                                                                  // Параметры публикатора nats
+}
+
+// NATSConfig определяет параметры публикации событий в NATS.
+// Событие публикуется в subject "<subject_prefix>.<тип события>", например
+// "events.subscription.created", с заголовком Nats-Msg-Id, равным ID события.
+type NATSConfig struct {
+	URL           string        `env:"OUTBOX_NATS_URL" yaml:"url" env-default:"nats://127.0.0.1:4222"`        // Адрес сервера NATS (несколько — через запятую)
+	SubjectPrefix string        `env:"OUTBOX_NATS_SUBJECT_PREFIX" yaml:"subject_prefix" env-default:"events"` // Префикс subject событий
+	JetStream     bool          `env:"OUTBOX_NATS_JETSTREAM" yaml:"jetstream"`                                // Публиковать в JetStream с подтверждением и дедупликацией
+	Timeout       time.Duration `env:"OUTBOX_NATS_TIMEOUT" yaml:"timeout" env-default:"5s"`                   // Срок подключения и подтверждения публикации
+}
+
+// WebhooksConfig определяет параметры доставки событий на вебхуки организаций.
+// События из outbox ставятся в очередь доставки на каждый подходящий вебхук и отправляются
+// POST-запросами, подписанными HMAC-SHA256. Неудачная доставка повторяется с экспоненциально
+// растущей паузой, а после max_attempts попыток повторяется только вручную.
+// Требует включённой секции outbox.
+type WebhooksConfig struct {
+	Enabled              bool          `env:"WEBHOOKS_ENABLED" yaml:"enabled"`                                           // Включает вебхуки и их доставку
+	Workers              int           `env:"WEBHOOKS_WORKERS" yaml:"workers" env-default:"4"`                           // Количество одновременных запросов
+	PollInterval         time.Duration `env:"WEBHOOKS_POLL_INTERVAL" yaml:"poll_interval" env-default:"1s"`              // Пауза между проверками новых доставок
+	BatchSize            int           `env:"WEBHOOKS_BATCH_SIZE" yaml:"batch_size" env-default:"50"`                    // Максимум доставок, выбираемых за один проход
+	Timeout              time.Duration `env:"WEBHOOKS_TIMEOUT" yaml:"timeout" env-default:"10s"`                         // Срок ответа получателя
+	MaxAttempts          int           `env:"WEBHOOKS_MAX_ATTEMPTS" yaml:"max_attempts" env-default:"10"`                // Попыток доставки, после которых она прекращается
+	RetryInitialDelay    time.Duration `env:"WEBHOOKS_RETRY_INITIAL_DELAY" yaml:"retry_initial_delay" env-default:"10s"` // Пауза перед первой повторной попыткой
+	RetryMaxDelay        time.Duration `env:"WEBHOOKS_RETRY_MAX_DELAY" yaml:"retry_max_delay" env-default:"1h"`          // Максимальная пауза между попытками
+	Retention            time.Duration `env:"WEBHOOKS_RETENTION" yaml:"retention" env-default:"720h"`                    // Срок хранения завершённых доставок (0 — не удалять)
+	AllowPrivateNetworks bool          `env:"WEBHOOKS_ALLOW_PRIVATE_NETWORKS" yaml:"allow_private_networks"`             // Разрешает адреса loopback, частных и других внутренних сетей
+}
+
+// IntegrationsConfig определяет параметры приёма вебхуков провайдеров подписок.
+// Провайдер присылает события (продление, отмена, изменение цены) на
+// POST /api/v1/integrations/<имя провайдера>/webhook; события применяются к подпискам,
+// связанным с идентификаторами подписок у провайдера.
+type IntegrationsConfig struct {
+	Enabled         bool                      `env:"INTEGRATIONS_ENABLED" yaml:"enabled"`                                    // Включает приём вебхуков провайдеров
+	MaxBodySize     int64                     `env:"INTEGRATIONS_MAX_BODY_SIZE" yaml:"max_body_size" env-default:"1048576"`  // Максимальный размер тела вебхука в байтах
+	ProcessingLease time.Duration             `env:"INTEGRATIONS_PROCESSING_LEASE" yaml:"processing_lease" env-default:"5m"` // Срок, после которого незавершённая обработка события может быть повторена
+	Providers       map[string]ProviderConfig `yaml:"providers"`                                                             // Провайдеры по имени в пути запроса
+}
+
+// ProviderConfig определяет адаптер провайдера, организацию, которой принадлежат его учётные
+// данные, и проверку подписи его вебхуков. События провайдера применяются только к подпискам
+// этой организации. Подпись — HMAC-SHA256 от тела запроса или, если задан timestamp_header, от строки
+// "<timestamp>.<тело>" в hex с префиксом signature_prefix.
+type ProviderConfig struct {
+	Adapter         string          `yaml:"adapter"`          // Адаптер: generic
+	TenantID        string          `yaml:"tenant_id"`        // Организация провайдера (пусто — организация по умолчанию)
+	Secret          string          `yaml:"secret"`           // Секрет подписи
+	SecretEnv       string          `yaml:"secret_env"`       // Переменная окружения с секретом подписи (имеет приоритет над secret)
+	SignatureHeader string          `yaml:"signature_header"` // Заголовок подписи (по умолчанию X-Webhook-Signature)
+	SignaturePrefix string          `yaml:"signature_prefix"` // Префикс подписи (по умолчанию sha256=)
+	TimestampHeader string          `yaml:"timestamp_header"` // Заголовок с временем отправки в секундах Unix (пусто — время не подписывается)
+	Tolerance       time.Duration   `yaml:"tolerance"`        // Допустимое расхождение времени отправки (по умолчанию 5m)
+	Mapping         ProviderMapping `yaml:"mapping"`          // Сопоставление полей события для адаптера generic
+}
+
+// ProviderMapping определяет, где в JSON-теле вебхука находятся поля события.
+// Пути записываются через точку, элементы массивов — по индексу: "data.items.0.id".
+type ProviderMapping struct {
+	Events          string            `yaml:"events"`           // Путь к массиву событий (пусто — тело и есть событие)
+	EventID         string            `yaml:"event_id"`         // Путь к ID события (по умолчанию id)
+	EventType       string            `yaml:"event_type"`       // Путь к типу события (по умолчанию type)
+	SubscriptionRef string            `yaml:"subscription_ref"` // Путь к ID подписки у провайдера (по умолчанию subscription_id)
+	Price           string            `yaml:"price"`            // Путь к новой цене (по умолчанию price)
+	EndDate         string            `yaml:"end_date"`         // Путь к дате окончания периода (по умолчанию end_date)
+	OccurredAt      string            `yaml:"occurred_at"`      // Путь ко времени события (по умолчанию occurred_at)
+	Types           map[string]string `yaml:"types"`            // Типы событий провайдера: renewed, cancelled или price_changed
+}
+
+// TracingConfig определяет параметры трассировки OpenTelemetry.
+// Спаны экспортируются по OTLP/HTTP, в stdout или в файл (по одному JSON-объекту на спан).
+type TracingConfig struct {
+	Enabled     bool    `env:"TRACING_ENABLED" yaml:"enabled"`                                                     // Включает трассировку
+	Exporter    string  `env:"TRACING_EXPORTER" yaml:"exporter" env-default:"stdout"`                              // Экспортёр: otlp, stdout или file
+	Endpoint    string  `env:"TRACING_OTLP_ENDPOINT" yaml:"endpoint"`                                              // Адрес OTLP/HTTP-коллектора (host:port)
+	Insecure    bool    `env:"TRACING_OTLP_INSECURE" yaml:"insecure"`                                              // Отправлять спаны в коллектор без TLS
+	File        string  `env:"TRACING_FILE" yaml:"file" env-default:"traces.jsonl"`                                // Файл для экспортёра file
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" yaml:"sample_ratio" env-default:"1"`                           // Доля записываемых трасс (от 0 до 1)
+	ServiceName string  `env:"TRACING_SERVICE_NAME" yaml:"service_name" env-default:"online_subscription_service"` // Имя сервиса в трассах
+}
+
+// ShutdownConfig определяет параметры корректного завершения работы.
+type ShutdownConfig struct {
+	Timeout time.Duration `env:"SHUTDOWN_TIMEOUT" yaml:"timeout" env-default:"10s"` // Общий срок остановки всех компонентов
+	Delay   time.Duration `env:"SHUTDOWN_DELAY" yaml:"delay" env-default:"0s"`      // Пауза между переводом /readyz в 503 и остановкой HTTP-сервера
 }
 
 // MustLoad загружает конфигурацию из файла или завершает работу при ошибке.
 // Функция ищет путь к конфигурационному файлу через флаги командной строки
 // или переменные окружения. Если путь не указан - вызывает панику.
 func MustLoad() *Config {
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Env file does not exist: ", err.Error())
+	// .env необязателен: в контейнере переменные окружения передаются снаружи.
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatal("failed to load env file: ", err.Error())
 	}
 
 	path := fetchConfigPath()
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey — API-ключ для межсервисного доступа.
+// Сам ключ не хранится, в базе данных лежит только его хэш.
+type APIKey struct {
+	ID        uuid.UUID  `json:"id"`
+	TenantID  uuid.UUID  `json:"tenant_id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+// APIKeyDTO — DTO API-ключа для передачи между сервисным слоем и хранилищем.
+type APIKeyDTO struct {
+	ID        uuid.UUID
+	TenantID  uuid.UUID
+	Name      string
+	Prefix    string
+	KeyHash   string
+	Scopes    []string
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+}
+
+// CreateAPIKeyRequest — структура запроса на создание API-ключа через HTTP.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreatedAPIKey — созданный API-ключ вместе с его значением.
+// Значение ключа возвращается только один раз, при создании.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// ToAPIKey — конвертирует APIKeyDTO в модель для ответа API.
+func (k *APIKeyDTO) ToAPIKey() APIKey {
+	return APIKey{
+		ID:        k.ID,
+		TenantID:  k.TenantID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		CreatedAt: k.CreatedAt,
+		ExpiresAt: k.ExpiresAt,
+		RevokedAt: k.RevokedAt,
+	}
+}
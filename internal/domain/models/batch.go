@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxBatchOperations — максимальное число операций в одном пакетном запросе.
+const MaxBatchOperations = 1000
+
+// BatchOp — вид операции пакетного запроса.
+type BatchOp string
+
+const (
+	BatchCreate BatchOp = "create" // создать подписку
+	BatchUpdate BatchOp = "update" // изменить поля подписки id
+	BatchDelete BatchOp = "delete" // удалить подписку id
+)
+
+// BatchMode — режим выполнения пакетного запроса.
+type BatchMode string
+
+const (
+	BatchAtomic     BatchMode = "atomic"      // все операции в одной транзакции: либо все, либо ни одной
+	BatchBestEffort BatchMode = "best_effort" // каждая операция в своей транзакции независимо от остальных
+)
+
+// BatchItemStatus — итог отдельной операции пакетного запроса.
+type BatchItemStatus string
+
+const (
+	BatchItemOK         BatchItemStatus = "ok"          // операция выполнена и сохранена
+	BatchItemFailed     BatchItemStatus = "failed"      // операция завершилась ошибкой
+	BatchItemRolledBack BatchItemStatus = "rolled_back" // операция выполнена, но отменена вместе с транзакцией
+	BatchItemSkipped    BatchItemStatus = "skipped"     // операция не выполнялась, так как транзакция уже отменена
+)
+
+// BatchOperation — операция пакетного запроса. Для create обязательны service_name и price,
+// start_date по умолчанию — текущий момент; для update указываются только изменяемые поля.
+type BatchOperation struct {
+	Op        BatchOp    `json:"op"`
+	ID        *uuid.UUID `json:"id,omitempty"` // подписка для update и delete
+	Name      *string    `json:"service_name,omitempty"`
+	Price     *int       `json:"price,omitempty"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	StartDate *time.Time `json:"start_date,omitempty"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+}
+
+// BatchRequest — пакетный запрос на создание, изменение и удаление подписок.
+type BatchRequest struct {
+	Mode       BatchMode        `json:"mode"` // atomic (по умолчанию) или best_effort
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchItemResult — итог операции пакетного запроса с тем же индексом.
+type BatchItemResult struct {
+	Index  int             `json:"index"`
+	Op     BatchOp         `json:"op"`
+	ID     *uuid.UUID      `json:"id,omitempty"` // созданная, изменённая или удалённая подписка
+	Status BatchItemStatus `json:"status"`
+	Code   int             `json:"code,omitempty"` // HTTP-статус ошибки операции
+	Error  string          `json:"error,omitempty"`
+	Err    error           `json:"-"`
+}
+
+// BatchResult — итог пакетного запроса.
+type BatchResult struct {
+	Mode      BatchMode         `json:"mode"`
+	Committed bool              `json:"committed"` // сохранена ли хотя бы часть изменений
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
+
+// Методы конвертации
+
+// ToSubsDTO — конвертирует операцию create в DTO новой подписки.
+// Если дата начала не указана, устанавливает её на текущий момент.
+func (op *BatchOperation) ToSubsDTO() SubsDTO {
+	sub := SubsDTO{
+		StartDate: time.Now(),
+		EndDate:   op.EndDate,
+	}
+	if op.Name != nil {
+		sub.Name = *op.Name
+	}
+	if op.Price != nil {
+		sub.Price = *op.Price
+	}
+	if op.UserID != nil {
+		sub.UserID = *op.UserID
+	}
+	if op.StartDate != nil {
+		sub.StartDate = *op.StartDate
+	}
+	return sub
+}
+
+// ToSubsUpdateDTO — конвертирует операцию update в DTO обновления.
+func (op *BatchOperation) ToSubsUpdateDTO() SubsUpdateDTO {
+	return SubsUpdateDTO{
+		Name:      op.Name,
+		Price:     op.Price,
+		UserID:    op.UserID,
+		StartDate: op.StartDate,
+		EndDate:   op.EndDate,
+	}
+}
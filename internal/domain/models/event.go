@@ -0,0 +1,72 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType — вид события об изменении подписки.
+type EventType string
+
+const (
+	EventSubsCreated       EventType = "subscription.created"        // подписка создана
+	EventSubsUpdated       EventType = "subscription.updated"        // поля подписки изменены
+	EventSubsDeleted       EventType = "subscription.deleted"        // подписка удалена
+	EventSubsStatusChanged EventType = "subscription.status_changed" // изменение перевело подписку в другой статус
+)
+
+// EventTypes — список всех типов событий об изменении подписок.
+var EventTypes = []EventType{EventSubsCreated, EventSubsUpdated, EventSubsDeleted, EventSubsStatusChanged}
+
+// SubsStatus — статус подписки на момент времени, определяемый её датами.
+type SubsStatus string
+
+const (
+	SubsScheduled SubsStatus = "scheduled" // подписка ещё не началась
+	SubsActive    SubsStatus = "active"    // подписка действует
+	SubsEnded     SubsStatus = "ended"     // подписка закончилась
+)
+
+// Status — статус подписки в момент at.
+func (sub SubsDTO) Status(at time.Time) SubsStatus {
+	switch {
+	case sub.StartDate.After(at):
+		return SubsScheduled
+	case sub.EndDate != nil && sub.EndDate.Before(at):
+		return SubsEnded
+	default:
+		return SubsActive
+	}
+}
+
+// Event — событие об изменении подписки, публикуемое через outbox.
+// Получатель может получить событие повторно и должен распознавать повторы по ID;
+// события одной подписки доставляются в порядке Sequence.
+type Event struct {
+	ID             uuid.UUID       `json:"id"`
+	Sequence       int64           `json:"sequence"` // порядковый номер в outbox, присваивается при записи
+	Type           EventType       `json:"type"`
+	TenantID       uuid.UUID       `json:"tenant_id"`
+	SubscriptionID uuid.UUID       `json:"subscription_id"`
+	OccurredAt     time.Time       `json:"occurred_at"`
+	Data           json.RawMessage `json:"data"` // SubsEventData
+}
+
+// SubsEventData — данные события об изменении подписки.
+// Subscription — состояние подписки после изменения (для deleted — перед удалением),
+// Previous — до изменения (только для updated), From и To — статусы до и после
+// изменения (только для status_changed).
+type SubsEventData struct {
+	Subscription Subs       `json:"subscription"`
+	Previous     *Subs      `json:"previous,omitempty"`
+	From         SubsStatus `json:"from,omitempty"`
+	To           SubsStatus `json:"to,omitempty"`
+}
+
+// OutboxEvent — событие, ожидающее публикации, с числом неудачных попыток.
+type OutboxEvent struct {
+	Event
+	Attempts int
+}
@@ -0,0 +1,22 @@
+package models
+
+// Статусы проверок состояния сервиса.
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+// HealthReport — отчёт о состоянии сервиса.
+// Status равен "ok", только если все проверки прошли успешно.
+type HealthReport struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck — результат отдельной проверки состояния.
+type HealthCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
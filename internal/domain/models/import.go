@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportStatus — состояние задачи импорта подписок.
+type ImportStatus string
+
+const (
+	ImportPending   ImportStatus = "pending"   // ожидает свободного обработчика
+	ImportRunning   ImportStatus = "running"   // строки проверяются и записываются
+	ImportSucceeded ImportStatus = "succeeded" // импорт (или проверка при dry run) завершён
+	ImportFailed    ImportStatus = "failed"    // есть ошибки в строках или импорт прерван
+)
+
+// ImportMapping — названия колонок файла, из которых берутся поля подписки.
+// Пустое значение означает колонку с названием самого поля (service_name, price, ...).
+type ImportMapping struct {
+	ServiceName string `json:"service_name"`
+	Price       string `json:"price"`
+	UserID      string `json:"user_id"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+}
+
+// ImportOptions — параметры импорта подписок из файла.
+type ImportOptions struct {
+	Format     string        // формат файла: csv или xlsx
+	Delimiter  rune          // разделитель полей CSV (по умолчанию запятая)
+	Mapping    ImportMapping // соответствие полей колонкам
+	DateFormat string        // формат дат, например MM-YYYY или DD.MM.YYYY (пустой — любой поддерживаемый)
+	DryRun     bool          // только проверить строки, ничего не записывая
+}
+
+// ImportRowError — ошибка проверки строки файла.
+type ImportRowError struct {
+	Row    int    `json:"row"`              // номер строки в файле, начиная с 1
+	Column string `json:"column,omitempty"` // колонка с ошибкой, если она одна
+	Error  string `json:"error"`
+}
+
+// ImportJob — задача импорта подписок и её прогресс.
+type ImportJob struct {
+	ID            uuid.UUID        `json:"id"`
+	TenantID      uuid.UUID        `json:"-"`
+	UserID        uuid.UUID        `json:"-"` // пользователь, запустивший импорт (пустой для API-ключа)
+	Status        ImportStatus     `json:"status"`
+	DryRun        bool             `json:"dry_run"`
+	TotalRows     int              `json:"total_rows"`     // строк с данными в файле
+	ProcessedRows int              `json:"processed_rows"` // проверенных строк
+	ImportedRows  int              `json:"imported_rows"`  // записанных подписок
+	ErrorCount    int              `json:"error_count"`    // всего ошибок в строках
+	Errors        []ImportRowError `json:"errors"`         // первые ошибки в строках
+	Error         string           `json:"error,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	FinishedAt    *time.Time       `json:"finished_at"`
+}
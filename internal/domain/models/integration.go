@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProviderEventType — вид события провайдера, которое применяется к связанной подписке.
+type ProviderEventType string
+
+const (
+	ProviderRenewed      ProviderEventType = "renewed"       // подписка продлена до EndDate
+	ProviderCancelled    ProviderEventType = "cancelled"     // подписка отменена с даты EndDate
+	ProviderPriceChanged ProviderEventType = "price_changed" // цена подписки изменилась на Price
+)
+
+// ProviderEvent — событие провайдера о подписке, разобранное адаптером провайдера.
+// Type содержит исходный тип события, если его не удалось сопоставить с ProviderEventType.
+type ProviderEvent struct {
+	ID         string            // идентификатор события у провайдера для распознавания повторов
+	Type       ProviderEventType // тип события
+	ExternalID string            // идентификатор подписки у провайдера
+	Price      *int              // новая цена (для price_changed)
+	EndDate    *time.Time        // конец оплаченного периода (для renewed) или дата окончания (для cancelled)
+	OccurredAt *time.Time        // время события у провайдера
+}
+
+// InboundStatus — результат обработки события провайдера.
+type InboundStatus string
+
+const (
+	InboundProcessing InboundStatus = "processing" // событие обрабатывается
+	InboundApplied    InboundStatus = "applied"    // подписка изменена
+	InboundUnchanged  InboundStatus = "unchanged"  // подписка уже в состоянии, описанном событием
+	InboundIgnored    InboundStatus = "ignored"    // событие не относится к связанной подписке или не поддерживается
+	InboundFailed     InboundStatus = "failed"     // событие не обработано и будет принято повторно
+	InboundDuplicate  InboundStatus = "duplicate"  // событие уже обработано или обрабатывается
+)
+
+// InboundEventResult — результат обработки одного события провайдера.
+type InboundEventResult struct {
+	EventID        string            `json:"event_id"`
+	Type           ProviderEventType `json:"type"`
+	ExternalID     string            `json:"external_id"`
+	SubscriptionID *uuid.UUID        `json:"subscription_id,omitempty"`
+	Status         InboundStatus     `json:"status"`
+	Error          string            `json:"error,omitempty"`
+}
+
+// InboundWebhookResult — результат обработки вебхука провайдера.
+type InboundWebhookResult struct {
+	Events []InboundEventResult `json:"events"`
+}
+
+// SubscriptionLink — связь подписки с её идентификатором у провайдера,
+// по которой события провайдера применяются к подписке.
+type SubscriptionLink struct {
+	Provider       string    `json:"provider"`
+	ExternalID     string    `json:"external_id"`
+	TenantID       uuid.UUID `json:"-"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// LinkSubscriptionRequest — структура запроса на связывание подписки с провайдером через HTTP.
+type LinkSubscriptionRequest struct {
+	ExternalID string `json:"external_id"`
+}
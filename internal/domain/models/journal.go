@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JournalOptions — параметры выгрузки начислений по подпискам в бухгалтерский журнал.
+type JournalOptions struct {
+	From           time.Time         // первый день периода
+	To             time.Time         // последний день периода
+	UserID         uuid.UUID         // пустой — вызывающий пользователь
+	ServiceName    string            // пустое — все услуги
+	Accounts       map[string]string // счета расходов услуг в дополнение к настройкам (название услуги → счёт)
+	PaymentAccount string            // пустой — счёт оплаты из настроек
+	Currency       string            // пустая — валюта из настроек
+}
+
+// JournalEntry — начисление по подписке за один расчётный период.
+type JournalEntry struct {
+	Date           time.Time // дата списания — первый день расчётного периода
+	PeriodEnd      time.Time // последний день расчётного периода
+	SubscriptionID uuid.UUID
+	ServiceName    string
+	Amount         int
+	Currency       string
+	Account        string // счёт расходов на услугу
+	PaymentAccount string // счёт, с которого оплачивается подписка
+}
@@ -37,7 +37,47 @@ type SubsUpdateDTO struct {
 	EndDate   *time.Time `json:"end_date"`
 }
 
+// SubsFilter — условия выборки списка подписок. Пустые поля не ограничивают выборку.
+type SubsFilter struct {
+	IDs         []uuid.UUID // подписки с перечисленными UUID
+	UserID      *uuid.UUID
+	UserIDs     []uuid.UUID // подписки любого из перечисленных пользователей
+	ServiceName string      // точное название сервиса
+	Page
+}
+
+// DefaultPageLimit и MaxPageLimit — размер страницы списка подписок по умолчанию
+// (если limit не указан) и наибольший допустимый.
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+// Page — страница списка: не более Limit записей, начиная с Offset.
+// Для хранилища нулевой Limit означает выборку без ограничения; сервисный слой
+// заменяет его размером страницы по умолчанию.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// SubsStats — сводные показатели действующих подписок организации.
+type SubsStats struct {
+	TenantID     uuid.UUID
+	Active       int // количество действующих подписок
+	MonthlySpend int // суммарная месячная стоимость действующих подписок
+}
+
+// SubsSummary — сводка по подпискам: сколько их всего, сколько действует на текущую
+// дату и какова месячная стоимость действующих.
+type SubsSummary struct {
+	Count        int
+	Active       int
+	MonthlySpend int // суммарная месячная стоимость действующих подписок, руб.
+}
+
 // AddSubRequest — структура запроса на создание подписки через HTTP.
+// Если UserID не указан, подписка создаётся для вызывающего пользователя.
 type AddSubRequest struct {
 	Name   string    `json:"service_name"`
 	Price  int       `json:"price"`
@@ -61,8 +101,42 @@ type PricePeriodRequest struct {
 	Name   string    `json:"service_name" query:"service_name"`
 }
 
+// SubsSearchRequest — структура запроса поиска подписок через HTTP.
+// Те же условия, что у списка подписок, но списки UUID передаются в теле запроса.
+type SubsSearchRequest struct {
+	IDs         []uuid.UUID `json:"ids"`
+	UserIDs     []uuid.UUID `json:"user_ids"`
+	ServiceName string      `json:"service_name"`
+	Limit       int         `json:"limit"` // размер страницы: 0 — 100 (или все ids), не больше 1000
+	Offset      int         `json:"offset"`
+}
+
+// BulkPriceRequest — структура запроса стоимости подписок на услугу за период
+// для нескольких пользователей. Даты передаются в формате YYYY-MM-DD.
+type BulkPriceRequest struct {
+	From        string      `json:"from" example:"2025-01-01"`
+	To          string      `json:"to" example:"2025-12-31"`
+	ServiceName string      `json:"service_name"`
+	UserIDs     []uuid.UUID `json:"user_ids"`
+}
+
+// BulkPriceResponse — стоимость подписок за период по пользователям.
+type BulkPriceResponse struct {
+	Prices map[uuid.UUID]int `json:"prices"`
+}
+
 // Методы конвертации
 
+// ToSubsFilter — конвертирует SubsSearchRequest в фильтр списка подписок.
+func (s *SubsSearchRequest) ToSubsFilter() SubsFilter {
+	return SubsFilter{
+		IDs:         s.IDs,
+		UserIDs:     s.UserIDs,
+		ServiceName: s.ServiceName,
+		Page:        Page{Limit: s.Limit, Offset: s.Offset},
+	}
+}
+
 // ToSubsDTO — конвертирует AddSubRequest в DTO для хранения в сервисном слое.
 // Устанавливает дату начала подписки на текущий момент.
 func (s *AddSubRequest) ToSubsDTO() *SubsDTO {
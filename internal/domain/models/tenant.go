@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTenantID — организация по умолчанию, создаваемая миграцией.
+// К ней относятся данные, созданные до появления организаций, и запросы без указания организации.
+var DefaultTenantID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Tenant — организация (арендатор), в пределах которой изолированы пользователи и подписки.
+type Tenant struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	DefaultCurrency string    `json:"default_currency"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// CreateTenantRequest — структура запроса на создание организации через HTTP.
+type CreateTenantRequest struct {
+	Name            string `json:"name"`
+	DefaultCurrency string `json:"default_currency"`
+}
+
+// EditTenantRequest — структура запроса на изменение настроек организации. Все поля опциональны.
+type EditTenantRequest struct {
+	Name            *string `json:"name"`
+	DefaultCurrency *string `json:"default_currency"`
+}
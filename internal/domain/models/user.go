@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User — локальная учётная запись пользователя.
+type User struct {
+	ID        uuid.UUID `json:"id"`
+	TenantID  uuid.UUID `json:"tenant_id"`
+	Email     string    `json:"email"`
+	Roles     []string  `json:"roles"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserDTO — DTO учётной записи для передачи между сервисным слоем и хранилищем.
+type UserDTO struct {
+	ID           uuid.UUID
+	TenantID     uuid.UUID
+	Email        string
+	PasswordHash string
+	Roles        []string
+	CreatedAt    time.Time
+}
+
+// RefreshTokenDTO — DTO refresh-токена. Значение токена не хранится, только его хэш.
+type RefreshTokenDTO struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// RegisterRequest — структура запроса на самостоятельную регистрацию через HTTP.
+// Пользователь регистрируется в организации по умолчанию.
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// CreateTenantAdminRequest — структура запроса на создание администратора организации через HTTP.
+type CreateTenantAdminRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginRequest — структура запроса на вход через HTTP.
+// TenantID — организация учётной записи; пустой — организация по умолчанию.
+type LoginRequest struct {
+	TenantID *uuid.UUID `json:"tenant_id,omitempty"`
+	Email    string     `json:"email"`
+	Password string     `json:"password"`
+}
+
+// RefreshRequest — структура запроса с refresh-токеном (обновление токенов и выход).
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ChangePasswordRequest — структура запроса на смену пароля через HTTP.
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// TokenPair — пара токенов, выдаваемая при входе и обновлении.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// ToUser — конвертирует UserDTO в модель для ответа API.
+func (u *UserDTO) ToUser() User {
+	return User{
+		ID:        u.ID,
+		TenantID:  u.TenantID,
+		Email:     u.Email,
+		Roles:     u.Roles,
+		CreatedAt: u.CreatedAt,
+	}
+}
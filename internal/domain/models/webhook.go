@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook — адрес, на который доставляются события об изменении подписок организации.
+// Секрет подписи не возвращается в ответах API, кроме ответа на создание.
+type Webhook struct {
+	ID          uuid.UUID   `json:"id"`
+	TenantID    uuid.UUID   `json:"tenant_id"`
+	URL         string      `json:"url"`
+	EventTypes  []EventType `json:"event_types"` // пустой список — все типы событий
+	Description string      `json:"description"`
+	Enabled     bool        `json:"enabled"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// WebhookDTO — DTO вебхука для передачи между сервисным слоем и хранилищем.
+type WebhookDTO struct {
+	ID          uuid.UUID
+	TenantID    uuid.UUID
+	URL         string
+	EventTypes  []EventType
+	Secret      string
+	Description string
+	Enabled     bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CreateWebhookRequest — структура запроса на регистрацию вебхука через HTTP.
+// Если секрет не указан, он генерируется; Enabled по умолчанию равен true.
+type CreateWebhookRequest struct {
+	URL         string      `json:"url"`
+	EventTypes  []EventType `json:"event_types"`
+	Secret      string      `json:"secret"`
+	Description string      `json:"description"`
+	Enabled     *bool       `json:"enabled"`
+}
+
+// EditWebhookRequest — структура запроса на изменение вебхука через HTTP.
+// Изменяются только переданные поля.
+type EditWebhookRequest struct {
+	URL         *string      `json:"url"`
+	EventTypes  *[]EventType `json:"event_types"`
+	Secret      *string      `json:"secret"`
+	Description *string      `json:"description"`
+	Enabled     *bool        `json:"enabled"`
+}
+
+// CreatedWebhook — зарегистрированный вебхук вместе с секретом подписи.
+// Секрет возвращается только один раз, при создании.
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// ToWebhook — конвертирует WebhookDTO в модель для ответа API.
+func (w *WebhookDTO) ToWebhook() Webhook {
+	return Webhook{
+		ID:          w.ID,
+		TenantID:    w.TenantID,
+		URL:         w.URL,
+		EventTypes:  w.EventTypes,
+		Description: w.Description,
+		Enabled:     w.Enabled,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+}
+
+// DeliveryStatus — состояние доставки события на вебхук.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"   // ожидает отправки или повторной попытки
+	DeliverySucceeded DeliveryStatus = "succeeded" // получатель ответил кодом 2xx
+	DeliveryDead      DeliveryStatus = "dead"      // попытки исчерпаны, доставка повторяется только вручную
+)
+
+// WebhookDelivery — доставка события на вебхук и результат последней попытки.
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      EventType       `json:"event_type"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"` // только для pending
+	ResponseStatus *int            `json:"response_status"` // HTTP-статус ответа на последнюю попытку
+	LastError      string          `json:"last_error,omitempty"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"` // тело запроса — событие Event
+	CreatedAt      time.Time       `json:"created_at"`
+	FinishedAt     *time.Time      `json:"finished_at"`
+}
+
+// DeliveryFilter — параметры выборки журнала доставок вебхука.
+type DeliveryFilter struct {
+	Status DeliveryStatus // пустой — все доставки
+	Limit  int
+	Offset int
+}
+
+// PendingDelivery — доставка, которую нужно выполнить, вместе с адресом и секретом вебхука.
+type PendingDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
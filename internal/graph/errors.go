@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/services"
+)
+
+// resolverError — ошибка резолвера с машиночитаемым кодом в extensions.code ответа.
+type resolverError struct {
+	err  error
+	code string
+}
+
+func (e *resolverError) Error() string {
+	return e.err.Error()
+}
+
+func (e *resolverError) Unwrap() error {
+	return e.err
+}
+
+// Extensions — дополнительные сведения об ошибке в ответе GraphQL.
+func (e *resolverError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+// wrapError — добавляет к ошибке сервисного слоя код по тем же правилам,
+// что и response.Status для HTTP API.
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code := "INTERNAL"
+	switch {
+	case errors.Is(err, services.ErrUnauthenticated), errors.Is(err, services.ErrInvalidAPIKey):
+		code = "UNAUTHENTICATED"
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrQuotaExceeded):
+		code = "FORBIDDEN"
+	case errors.Is(err, services.ErrNotFound):
+		code = "NOT_FOUND"
+	case errors.Is(err, services.ErrInvalidInput):
+		code = "BAD_REQUEST"
+	}
+
+	return &resolverError{err: err, code: code}
+}
+
+// invalidInput — ошибка разбора аргумента запроса.
+func invalidInput(format string, args ...any) error {
+	return wrapError(fmt.Errorf("%w: %s", services.ErrInvalidInput, fmt.Sprintf(format, args...)))
+}
+
+// requireScope — проверяет, что у вызывающей стороны есть разрешение scope
+// (ограничивает только API-ключи, см. auth.Identity.HasScope).
+func requireScope(ctx context.Context, scope string) error {
+	id, ok := auth.FromContext(ctx)
+	if !ok {
+		return wrapError(services.ErrUnauthenticated)
+	}
+	if !id.HasScope(scope) {
+		return wrapError(fmt.Errorf("%w: missing scope %s", services.ErrForbidden, scope))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"online_subscription_service/internal/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Ограничения запросов, защищающие сервис от слишком дорогих запросов.
+const (
+	maxDepth       = 8
+	maxParallelism = 10
+)
+
+// SubsService — операции с подписками, которые использует GraphQL API.
+// Реализуется services.SubsService.
+type SubsService interface {
+	AddSubscription(ctx context.Context, sub models.SubsDTO) (uuid.UUID, error)
+	GetSubscription(ctx context.Context, uuid uuid.UUID) (models.Subs, error)
+	EditSubscription(ctx context.Context, uuid uuid.UUID, sub models.SubsUpdateDTO) error
+	GetAllSubscriptions(ctx context.Context, filter models.SubsFilter) ([]models.Subs, error)
+	GetPricesWithPeriod(ctx context.Context, from, to time.Time, userIDs []uuid.UUID, name string) (map[uuid.UUID]int, error)
+	GetSubscriptionsByUsers(ctx context.Context, userIDs []uuid.UUID, name string, page models.Page) (map[uuid.UUID][]models.Subs, error)
+	GetSubscriptionsSummary(ctx context.Context, filter models.SubsFilter) (models.SubsSummary, error)
+	GetSubscriptionsSummaries(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]models.SubsSummary, error)
+	RemoveSubscription(ctx context.Context, uuid uuid.UUID) error
+}
+
+// NewSchema — разбирает схему и связывает её с резолверами поверх subs.
+// Перед выполнением запроса в контекст нужно положить загрузчики (см. WithLoaders).
+func NewSchema(subs SubsService) (*graphql.Schema, error) {
+	return graphql.ParseSchema(schemaSDL, &rootResolver{r: &resolver{subs: subs}},
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism),
+	)
+}
@@ -0,0 +1,188 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/services"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaderWait — время, в течение которого загрузчик собирает ключи в один пакет.
+const loaderWait = 2 * time.Millisecond
+
+// maxBatchSize — наибольшее число ключей в пакете загрузчика. Ключи сверх него
+// загружаются следующими пакетами, поэтому запрос к хранилищу не растёт вместе с ответом.
+const maxBatchSize = 100
+
+// subsKey — ключ загрузки страницы подписок пользователя на сервис (пустое название — на все сервисы).
+type subsKey struct {
+	userID      uuid.UUID
+	serviceName string
+	page        models.Page
+}
+
+// priceKey — ключ загрузки стоимости подписок пользователя за период.
+type priceKey struct {
+	userID      uuid.UUID
+	from, to    time.Time
+	serviceName string
+}
+
+// loaders — загрузчики одного GraphQL-запроса. Поля вложенных объектов (например,
+// подписки и стоимость каждого из users) собираются в пакеты и читаются из хранилища
+// одним запросом на пакет вместо запроса на каждый объект.
+type loaders struct {
+	subsByUser    *dataloader.Loader[subsKey, []models.Subs]
+	priceByUser   *dataloader.Loader[priceKey, int]
+	summaryByUser *dataloader.Loader[uuid.UUID, models.SubsSummary]
+}
+
+type loadersKey struct{}
+
+// WithLoaders — возвращает контекст с новыми загрузчиками для одного запроса.
+// Загрузчики кэшируют результаты, поэтому их нельзя переиспользовать между запросами.
+func WithLoaders(ctx context.Context, subs SubsService) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders(subs))
+}
+
+// loadersFrom — возвращает загрузчики запроса; без WithLoaders создаёт новые.
+func loadersFrom(ctx context.Context, subs SubsService) *loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*loaders); ok {
+		return l
+	}
+	return newLoaders(subs)
+}
+
+// newLoaders — создаёт загрузчики поверх subs.
+func newLoaders(subs SubsService) *loaders {
+	return &loaders{
+		subsByUser: dataloader.NewBatchedLoader(
+			func(ctx context.Context, keys []subsKey) []*dataloader.Result[[]models.Subs] {
+				return batchSubs(ctx, subs, keys)
+			},
+			dataloader.WithWait[subsKey, []models.Subs](loaderWait),
+			dataloader.WithBatchCapacity[subsKey, []models.Subs](maxBatchSize),
+		),
+		priceByUser: dataloader.NewBatchedLoader(
+			func(ctx context.Context, keys []priceKey) []*dataloader.Result[int] {
+				return batchPrices(ctx, subs, keys)
+			},
+			dataloader.WithWait[priceKey, int](loaderWait),
+			dataloader.WithBatchCapacity[priceKey, int](maxBatchSize),
+		),
+		summaryByUser: dataloader.NewBatchedLoader(
+			func(ctx context.Context, keys []uuid.UUID) []*dataloader.Result[models.SubsSummary] {
+				return batchSummaries(ctx, subs, keys)
+			},
+			dataloader.WithWait[uuid.UUID, models.SubsSummary](loaderWait),
+			dataloader.WithBatchCapacity[uuid.UUID, models.SubsSummary](maxBatchSize),
+		),
+	}
+}
+
+// subsGroup — ключи подписок, которые загружаются одним запросом.
+type subsGroup struct {
+	serviceName string
+	page        models.Page
+}
+
+// batchSubs — загружает подписки пакета пользователей: один запрос к сервису
+// на каждую различающуюся комбинацию названия сервиса и страницы в пакете.
+func batchSubs(ctx context.Context, subs SubsService, keys []subsKey) []*dataloader.Result[[]models.Subs] {
+	groups := map[subsGroup][]uuid.UUID{}
+	for _, k := range keys {
+		g := subsGroup{serviceName: k.serviceName, page: k.page}
+		groups[g] = append(groups[g], k.userID)
+	}
+
+	found := map[subsGroup]map[uuid.UUID][]models.Subs{}
+	failed := map[subsGroup]map[uuid.UUID]error{}
+	for g, userIDs := range groups {
+		found[g], failed[g] = loadByUsers(ctx, userIDs, func(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID][]models.Subs, error) {
+			return subs.GetSubscriptionsByUsers(ctx, userIDs, g.serviceName, g.page)
+		})
+	}
+
+	results := make([]*dataloader.Result[[]models.Subs], len(keys))
+	for i, k := range keys {
+		g := subsGroup{serviceName: k.serviceName, page: k.page}
+		results[i] = &dataloader.Result[[]models.Subs]{Data: found[g][k.userID], Error: failed[g][k.userID]}
+	}
+	return results
+}
+
+// priceGroup — ключи стоимости, которые вычисляются одним запросом.
+type priceGroup struct {
+	from, to    time.Time
+	serviceName string
+}
+
+// batchPrices — вычисляет стоимость для пакета пользователей: один запрос к сервису
+// на каждую различающуюся комбинацию периода и названия сервиса в пакете.
+func batchPrices(ctx context.Context, subs SubsService, keys []priceKey) []*dataloader.Result[int] {
+	groups := map[priceGroup][]uuid.UUID{}
+	for _, k := range keys {
+		g := priceGroup{from: k.from, to: k.to, serviceName: k.serviceName}
+		groups[g] = append(groups[g], k.userID)
+	}
+
+	prices := map[priceGroup]map[uuid.UUID]int{}
+	failed := map[priceGroup]map[uuid.UUID]error{}
+	for g, userIDs := range groups {
+		prices[g], failed[g] = loadByUsers(ctx, userIDs, func(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+			return subs.GetPricesWithPeriod(ctx, g.from, g.to, userIDs, g.serviceName)
+		})
+	}
+
+	results := make([]*dataloader.Result[int], len(keys))
+	for i, k := range keys {
+		g := priceGroup{from: k.from, to: k.to, serviceName: k.serviceName}
+		results[i] = &dataloader.Result[int]{Data: prices[g][k.userID], Error: failed[g][k.userID]}
+	}
+	return results
+}
+
+// batchSummaries — считает сводку по подпискам пакета пользователей одним запросом к сервису.
+func batchSummaries(ctx context.Context, subs SubsService, keys []uuid.UUID) []*dataloader.Result[models.SubsSummary] {
+	summaries, failed := loadByUsers(ctx, keys, subs.GetSubscriptionsSummaries)
+
+	results := make([]*dataloader.Result[models.SubsSummary], len(keys))
+	for i, k := range keys {
+		results[i] = &dataloader.Result[models.SubsSummary]{Data: summaries[k], Error: failed[k]}
+	}
+	return results
+}
+
+// loadByUsers — загружает данные пакета пользователей одним вызовом load.
+// Сервис отклоняет весь пакет, если вызывающей стороне доступны не все его пользователи;
+// тогда пользователи загружаются по одному, чтобы ошибка досталась только недоступным.
+// Остальные ошибки относятся ко всем пользователям пакета.
+func loadByUsers[V any](ctx context.Context, userIDs []uuid.UUID, load func(context.Context, []uuid.UUID) (map[uuid.UUID]V, error)) (map[uuid.UUID]V, map[uuid.UUID]error) {
+	failed := map[uuid.UUID]error{}
+
+	found, err := load(ctx, userIDs)
+	if err == nil {
+		return found, failed
+	}
+	if !errors.Is(err, services.ErrForbidden) || len(userIDs) == 1 {
+		for _, userID := range userIDs {
+			failed[userID] = err
+		}
+		return nil, failed
+	}
+
+	found = make(map[uuid.UUID]V, len(userIDs))
+	for _, userID := range userIDs {
+		one, err := load(ctx, []uuid.UUID{userID})
+		if err != nil {
+			failed[userID] = err
+			continue
+		}
+		found[userID] = one[userID]
+	}
+	return found, failed
+}
@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/services"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// fakeSubsService — сервис подписок, который, как services.SubsService, отклоняет
+// весь пакет, если в нём есть пользователь, не входящий в allowed.
+type fakeSubsService struct {
+	SubsService
+	allowed []uuid.UUID
+	subs    map[uuid.UUID][]models.Subs
+	calls   [][]uuid.UUID
+}
+
+func (f *fakeSubsService) GetSubscriptionsByUsers(_ context.Context, userIDs []uuid.UUID, _ string, _ models.Page) (map[uuid.UUID][]models.Subs, error) {
+	f.calls = append(f.calls, userIDs)
+	for _, userID := range userIDs {
+		if !slices.Contains(f.allowed, userID) {
+			return nil, services.ErrForbidden
+		}
+	}
+	found := map[uuid.UUID][]models.Subs{}
+	for _, userID := range userIDs {
+		found[userID] = f.subs[userID]
+	}
+	return found, nil
+}
+
+func TestBatchSubs(t *testing.T) {
+	own, other, third := uuid.New(), uuid.New(), uuid.New()
+	subs := map[uuid.UUID][]models.Subs{
+		own:   {{ID: uuid.New(), UserID: own}},
+		other: {{ID: uuid.New(), UserID: other}},
+		third: {{ID: uuid.New(), UserID: third}},
+	}
+	page := models.Page{Limit: 10}
+
+	tests := []struct {
+		name      string
+		allowed   []uuid.UUID
+		keys      []subsKey
+		wantErr   []bool // ошибка ожидается у ключа с тем же индексом
+		wantCalls int
+	}{
+		{
+			name:      "all users accessible in one call",
+			allowed:   []uuid.UUID{own, other, third},
+			keys:      []subsKey{{userID: own, page: page}, {userID: other, page: page}, {userID: third, page: page}},
+			wantErr:   []bool{false, false, false},
+			wantCalls: 1,
+		},
+		{
+			name:      "foreign user fails only its key",
+			allowed:   []uuid.UUID{own},
+			keys:      []subsKey{{userID: own, page: page}, {userID: other, page: page}},
+			wantErr:   []bool{false, true},
+			wantCalls: 3,
+		},
+		{
+			name:      "different pages are loaded separately",
+			allowed:   []uuid.UUID{own, other},
+			keys:      []subsKey{{userID: own, page: page}, {userID: other, page: models.Page{Limit: 10, Offset: 10}}},
+			wantErr:   []bool{false, false},
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeSubsService{allowed: tt.allowed, subs: subs}
+
+			results := batchSubs(context.Background(), svc, tt.keys)
+
+			if len(results) != len(tt.keys) {
+				t.Fatalf("got %d results, want %d", len(results), len(tt.keys))
+			}
+			for i, k := range tt.keys {
+				if tt.wantErr[i] {
+					if !errors.Is(results[i].Error, services.ErrForbidden) {
+						t.Errorf("key %d error = %v, want ErrForbidden", i, results[i].Error)
+					}
+					continue
+				}
+				if results[i].Error != nil {
+					t.Errorf("key %d error = %v", i, results[i].Error)
+				}
+				if !slices.Equal(results[i].Data, subs[k.userID]) {
+					t.Errorf("key %d data = %v, want %v", i, results[i].Data, subs[k.userID])
+				}
+			}
+			if len(svc.calls) != tt.wantCalls {
+				t.Errorf("service calls = %v, want %d", svc.calls, tt.wantCalls)
+			}
+		})
+	}
+}
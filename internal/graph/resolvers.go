@@ -0,0 +1,420 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// resolver — общее состояние резолверов схемы.
+type resolver struct {
+	subs SubsService
+}
+
+// rootResolver — корневой резолвер схемы. Запросы и мутации разрешаются отдельными
+// резолверами: метод Subscription корневого резолвера graphql-go принял бы за
+// резолвер операций подписки, а у Query есть поле subscription.
+type rootResolver struct {
+	r *resolver
+}
+
+// Query — резолвер операций чтения.
+func (root *rootResolver) Query() *queryResolver {
+	return &queryResolver{root.r}
+}
+
+// Mutation — резолвер операций изменения.
+func (root *rootResolver) Mutation() *mutationResolver {
+	return &mutationResolver{root.r}
+}
+
+// queryResolver — резолвер полей типа Query.
+type queryResolver struct {
+	*resolver
+}
+
+// mutationResolver — резолвер полей типа Mutation.
+type mutationResolver struct {
+	*resolver
+}
+
+// subscriptionFilter — аргумент SubscriptionFilter.
+type subscriptionFilter struct {
+	UserID      *graphql.ID
+	ServiceName *string
+}
+
+// toModel — конвертирует фильтр и страницу в models.SubsFilter.
+func (f *subscriptionFilter) toModel(limit, offset *int32) (models.SubsFilter, error) {
+	var filter models.SubsFilter
+	if f != nil {
+		if f.UserID != nil {
+			userID, err := parseID("userId", *f.UserID)
+			if err != nil {
+				return filter, err
+			}
+			filter.UserID = &userID
+		}
+		if f.ServiceName != nil {
+			filter.ServiceName = *f.ServiceName
+		}
+	}
+	if limit != nil {
+		filter.Limit = int(*limit)
+	}
+	if offset != nil {
+		filter.Offset = int(*offset)
+	}
+	return filter, nil
+}
+
+// Subscription — подписка по ID; null, если она не найдена.
+func (r *queryResolver) Subscription(ctx context.Context, args struct{ ID graphql.ID }) (*subscriptionResolver, error) {
+	if err := requireScope(ctx, auth.ScopeSubscriptionsRead); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := r.subs.GetSubscription(ctx, id)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return r.subscription(sub), nil
+}
+
+// Subscriptions — подписки, подходящие под фильтр.
+func (r *queryResolver) Subscriptions(ctx context.Context, args struct {
+	Filter *subscriptionFilter
+	Limit  *int32
+	Offset *int32
+}) ([]*subscriptionResolver, error) {
+	if err := requireScope(ctx, auth.ScopeSubscriptionsRead); err != nil {
+		return nil, err
+	}
+
+	filter, err := args.Filter.toModel(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := r.subs.GetAllSubscriptions(ctx, filter)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return r.subscriptions(subs), nil
+}
+
+// User — пользователь по ID. Доступ к его данным проверяется при разрешении полей.
+func (r *queryResolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	if err := requireScope(ctx, auth.ScopeSubscriptionsRead); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userResolver{r: r.resolver, id: id}, nil
+}
+
+// Users — несколько пользователей, не больше maxBatchSize.
+func (r *queryResolver) Users(ctx context.Context, args struct{ IDs []graphql.ID }) ([]*userResolver, error) {
+	if err := requireScope(ctx, auth.ScopeSubscriptionsRead); err != nil {
+		return nil, err
+	}
+	if len(args.IDs) > maxBatchSize {
+		return nil, invalidInput("at most %d ids can be requested at once", maxBatchSize)
+	}
+
+	users := make([]*userResolver, 0, len(args.IDs))
+	for _, raw := range args.IDs {
+		id, err := parseID("ids", raw)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, &userResolver{r: r.resolver, id: id})
+	}
+
+	return users, nil
+}
+
+// Summary — сводка по подпискам, подходящим под фильтр.
+func (r *queryResolver) Summary(ctx context.Context, args struct{ Filter *subscriptionFilter }) (*summaryResolver, error) {
+	if err := requireScope(ctx, auth.ScopeSubscriptionsRead); err != nil {
+		return nil, err
+	}
+
+	filter, err := args.Filter.toModel(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := r.subs.GetSubscriptionsSummary(ctx, filter)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &summaryResolver{summary}, nil
+}
+
+// addSubscriptionInput — аргумент AddSubscriptionInput.
+type addSubscriptionInput struct {
+	ServiceName string
+	Price       int32
+	UserID      *graphql.ID
+}
+
+// AddSubscription — создаёт подписку и возвращает её.
+func (r *mutationResolver) AddSubscription(ctx context.Context, args struct{ Input addSubscriptionInput }) (*subscriptionResolver, error) {
+	if err := requireScope(ctx, auth.ScopeSubscriptionsWrite); err != nil {
+		return nil, err
+	}
+
+	req := models.AddSubRequest{Name: args.Input.ServiceName, Price: int(args.Input.Price)}
+	if args.Input.UserID != nil {
+		userID, err := parseID("userId", *args.Input.UserID)
+		if err != nil {
+			return nil, err
+		}
+		req.UserID = userID
+	}
+
+	id, err := r.subs.AddSubscription(ctx, *req.ToSubsDTO())
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return r.reload(ctx, id)
+}
+
+// editSubscriptionInput — аргумент EditSubscriptionInput.
+type editSubscriptionInput struct {
+	ServiceName *string
+	Price       *int32
+	UserID      *graphql.ID
+	StartDate   *Date
+	EndDate     *Date
+}
+
+// EditSubscription — изменяет заданные поля подписки и возвращает её.
+func (r *mutationResolver) EditSubscription(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input editSubscriptionInput
+}) (*subscriptionResolver, error) {
+	if err := requireScope(ctx, auth.ScopeSubscriptionsWrite); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	in := args.Input
+	upd := models.SubsUpdateDTO{Name: in.ServiceName}
+	if in.Price != nil {
+		price := int(*in.Price)
+		upd.Price = &price
+	}
+	if in.UserID != nil {
+		userID, err := parseID("userId", *in.UserID)
+		if err != nil {
+			return nil, err
+		}
+		upd.UserID = &userID
+	}
+	if in.StartDate != nil {
+		upd.StartDate = &in.StartDate.Time
+	}
+	if in.EndDate != nil {
+		upd.EndDate = &in.EndDate.Time
+	}
+
+	if err := r.subs.EditSubscription(ctx, id, upd); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return r.reload(ctx, id)
+}
+
+// RemoveSubscription — удаляет подписку.
+func (r *mutationResolver) RemoveSubscription(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	if err := requireScope(ctx, auth.ScopeSubscriptionsWrite); err != nil {
+		return false, err
+	}
+
+	id, err := parseID("id", args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.subs.RemoveSubscription(ctx, id); err != nil {
+		return false, wrapError(err)
+	}
+
+	return true, nil
+}
+
+// reload — читает подписку после мутации, чтобы вернуть её актуальное состояние.
+func (r *resolver) reload(ctx context.Context, id uuid.UUID) (*subscriptionResolver, error) {
+	sub, err := r.subs.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return r.subscription(sub), nil
+}
+
+func (r *resolver) subscription(sub models.Subs) *subscriptionResolver {
+	return &subscriptionResolver{r: r, sub: sub}
+}
+
+func (r *resolver) subscriptions(subs []models.Subs) []*subscriptionResolver {
+	list := make([]*subscriptionResolver, 0, len(subs))
+	for _, sub := range subs {
+		list = append(list, r.subscription(sub))
+	}
+	return list
+}
+
+// subscriptionResolver — резолвер типа Subscription.
+type subscriptionResolver struct {
+	r   *resolver
+	sub models.Subs
+}
+
+func (s *subscriptionResolver) ID() graphql.ID {
+	return graphql.ID(s.sub.ID.String())
+}
+
+func (s *subscriptionResolver) ServiceName() string {
+	return s.sub.Name
+}
+
+func (s *subscriptionResolver) Price() int32 {
+	return int32(s.sub.Price)
+}
+
+func (s *subscriptionResolver) User() *userResolver {
+	return &userResolver{r: s.r, id: s.sub.UserID}
+}
+
+func (s *subscriptionResolver) StartDate() Date {
+	return Date{s.sub.StartDate}
+}
+
+func (s *subscriptionResolver) EndDate() *Date {
+	if s.sub.EndDate == nil {
+		return nil
+	}
+	return &Date{*s.sub.EndDate}
+}
+
+// userResolver — резолвер типа User. Подписки и стоимость загружаются через
+// загрузчики запроса, поэтому список пользователей не порождает запрос на каждого.
+type userResolver struct {
+	r  *resolver
+	id uuid.UUID
+}
+
+func (u *userResolver) ID() graphql.ID {
+	return graphql.ID(u.id.String())
+}
+
+// Subscriptions — страница подписок пользователя. Страницы пользователей с одинаковыми
+// аргументами загружаются одним запросом.
+func (u *userResolver) Subscriptions(ctx context.Context, args struct {
+	ServiceName *string
+	Limit       *int32
+	Offset      *int32
+}) ([]*subscriptionResolver, error) {
+	key := subsKey{userID: u.id}
+	if args.ServiceName != nil {
+		key.serviceName = *args.ServiceName
+	}
+	if args.Limit != nil {
+		key.page.Limit = int(*args.Limit)
+	}
+	if args.Offset != nil {
+		key.page.Offset = int(*args.Offset)
+	}
+	if key.page.Offset < 0 || key.page.Limit < 0 || key.page.Limit > models.MaxPageLimit {
+		return nil, invalidInput("limit must be between 0 and %d, offset must not be negative", models.MaxPageLimit)
+	}
+
+	subs, err := loadersFrom(ctx, u.r.subs).subsByUser.Load(ctx, key)()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return u.r.subscriptions(subs), nil
+}
+
+// Price — стоимость подписок пользователя на сервис за период.
+func (u *userResolver) Price(ctx context.Context, args struct {
+	From        Date
+	To          Date
+	ServiceName string
+}) (int32, error) {
+	if err := requireScope(ctx, auth.ScopeReportsRead); err != nil {
+		return 0, err
+	}
+
+	key := priceKey{userID: u.id, from: args.From.Time, to: args.To.Time, serviceName: args.ServiceName}
+	price, err := loadersFrom(ctx, u.r.subs).priceByUser.Load(ctx, key)()
+	if err != nil {
+		return 0, wrapError(err)
+	}
+
+	return int32(price), nil
+}
+
+// Summary — сводка по подпискам пользователя.
+func (u *userResolver) Summary(ctx context.Context) (*summaryResolver, error) {
+	summary, err := loadersFrom(ctx, u.r.subs).summaryByUser.Load(ctx, u.id)()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &summaryResolver{summary}, nil
+}
+
+// summaryResolver — резолвер типа Summary.
+type summaryResolver struct {
+	summary models.SubsSummary
+}
+
+func (s *summaryResolver) Count() int32 {
+	return int32(s.summary.Count)
+}
+
+func (s *summaryResolver) Active() int32 {
+	return int32(s.summary.Active)
+}
+
+func (s *summaryResolver) MonthlySpend() int32 {
+	return int32(s.summary.MonthlySpend)
+}
+
+// parseID — разбирает UUID из аргумента field.
+func parseID(field string, id graphql.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, invalidInput("invalid %s", field)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,38 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Date — скаляр GraphQL для дат в формате YYYY-MM-DD.
+type Date struct {
+	time.Time
+}
+
+// ImplementsGraphQLType — связывает тип со скаляром Date схемы.
+func (Date) ImplementsGraphQLType(name string) bool {
+	return name == "Date"
+}
+
+// UnmarshalGraphQL — разбирает дату из аргумента запроса.
+func (d *Date) UnmarshalGraphQL(input any) error {
+	s, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("date must be a string in YYYY-MM-DD format, got %T", input)
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return fmt.Errorf("date must be in YYYY-MM-DD format: %w", err)
+	}
+
+	d.Time = t
+	return nil
+}
+
+// MarshalJSON — выводит дату в формате YYYY-MM-DD.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(time.DateOnly))
+}
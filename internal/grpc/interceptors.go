@@ -0,0 +1,358 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/lib/ratelimit"
+	"online_subscription_service/internal/services"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Ключи метаданных запроса; совпадают с заголовками HTTP API в нижнем регистре.
+const (
+	metadataAuthorization = "authorization"
+	metadataAPIKey        = "x-api-key"
+	metadataTenantID      = "x-tenant-id"
+	metadataRequestID     = "x-request-id"
+
+	metadataRateLimitLimit     = "ratelimit-limit"
+	metadataRateLimitRemaining = "ratelimit-remaining"
+	metadataRateLimitReset     = "ratelimit-reset"
+	metadataRetryAfter         = "retry-after"
+)
+
+// maxRequestIDLength — максимальная длина принимаемого от клиента x-request-id.
+const maxRequestIDLength = 128
+
+// publicServicePrefix — сервисы, доступные без аутентификации (gRPC reflection).
+const publicServicePrefix = "/grpc.reflection."
+
+// serverStream — поток с заменённым контекстом, чтобы перехватчики могли
+// передать обработчику логгер и личность вызывающей стороны.
+type serverStream struct {
+	grpcgo.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// recoverUnary — перехватчик, превращающий панику обработчика в ошибку Internal.
+func recoverUnary(ctx context.Context, req any, info *grpcgo.UnaryServerInfo, handler grpcgo.UnaryHandler) (_ any, err error) {
+	defer recoverPanic(ctx, &err)
+	return handler(ctx, req)
+}
+
+// recoverStream — потоковый вариант recoverUnary.
+func recoverStream(srv any, ss grpcgo.ServerStream, info *grpcgo.StreamServerInfo, handler grpcgo.StreamHandler) (err error) {
+	defer recoverPanic(ss.Context(), &err)
+	return handler(srv, ss)
+}
+
+// recoverPanic — логирует панику со стеком и подменяет ошибку вызова.
+func recoverPanic(ctx context.Context, errp *error) {
+	if r := recover(); r != nil {
+		logger.FromContext(ctx).Error("panic in grpc handler",
+			slog.Any("panic", r),
+			slog.String("stack", string(debug.Stack())),
+		)
+		*errp = status.Error(codes.Internal, "internal error")
+	}
+}
+
+// logUnary — перехватчик, назначающий вызову идентификатор (x-request-id),
+// создающий логгер вызова и записывающий его итог: код, длительность и ошибку.
+func logUnary(ctx context.Context, req any, info *grpcgo.UnaryServerInfo, handler grpcgo.UnaryHandler) (any, error) {
+	ctx = withRequestLogger(ctx, info.FullMethod)
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logCompleted(ctx, start, err)
+
+	return resp, err
+}
+
+// logStream — потоковый вариант logUnary.
+func logStream(srv any, ss grpcgo.ServerStream, info *grpcgo.StreamServerInfo, handler grpcgo.StreamHandler) error {
+	ctx := withRequestLogger(ss.Context(), info.FullMethod)
+
+	start := time.Now()
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	logCompleted(ctx, start, err)
+
+	return err
+}
+
+// withRequestLogger — сохраняет в контексте логгер вызова с идентификатором запроса,
+// методом и trace_id, а идентификатор возвращает клиенту в заголовке ответа.
+func withRequestLogger(ctx context.Context, method string) context.Context {
+	rid := firstMetadata(ctx, metadataRequestID)
+	if !validRequestID(rid) {
+		rid = uuid.NewString()
+	}
+	_ = grpcgo.SetHeader(ctx, metadata.Pairs(metadataRequestID, rid))
+
+	ctx = logger.With(ctx,
+		slog.String("request_id", rid),
+		slog.String("rpc", method),
+	)
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		ctx = logger.With(ctx, slog.String("trace_id", sc.TraceID().String()))
+	}
+
+	return ctx
+}
+
+// logCompleted — записывает итог вызова; уровень зависит от кода ответа.
+func logCompleted(ctx context.Context, start time.Time, err error) {
+	code := status.Code(err)
+
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.String("code", code.String()),
+		slog.Duration("latency", time.Since(start)),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		attrs = append(attrs, slog.String("remote_addr", p.Addr.String()))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	logger.FromContext(ctx).LogAttrs(ctx, level, "rpc completed", attrs...)
+}
+
+// validRequestID — проверяет, что идентификатор непустой, не слишком длинный
+// и состоит только из печатных ASCII-символов без пробелов.
+func validRequestID(rid string) bool {
+	if rid == "" || len(rid) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(rid); i++ {
+		if rid[i] <= ' ' || rid[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// apiKeyAuthenticator — проверяет значение API-ключа.
+type apiKeyAuthenticator interface {
+	Authenticate(ctx context.Context, raw string) (auth.Identity, error)
+}
+
+// tenantResolver — определяет организацию вызова и проверяет её существование.
+type tenantResolver interface {
+	ResolveTenant(ctx context.Context, id auth.Identity, requested uuid.UUID) (auth.Identity, error)
+}
+
+// authenticator — перехватчик аутентификации и авторизации вызовов.
+// Правила совпадают с middleware HTTP API: API-ключ из x-api-key, иначе bearer-токен
+// из authorization; организация определяется через auth.ResolveTenant, а
+// разрешения API-ключей проверяются по таблице methodScopes. Методы, которых нет
+// в таблице, API-ключам недоступны.
+type authenticator struct {
+	keys     apiKeyAuthenticator
+	tenants  tenantResolver
+	verifier *auth.Verifier // nil — аутентификация отключена
+}
+
+// unary — перехватчик аутентификации для унарных вызовов.
+func (a *authenticator) unary(ctx context.Context, req any, info *grpcgo.UnaryServerInfo, handler grpcgo.UnaryHandler) (any, error) {
+	ctx, err := a.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// stream — перехватчик аутентификации для потоковых вызовов.
+func (a *authenticator) stream(srv any, ss grpcgo.ServerStream, info *grpcgo.StreamServerInfo, handler grpcgo.StreamHandler) error {
+	ctx, err := a.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorize — определяет личность и организацию вызывающей стороны, проверяет
+// разрешение на метод и возвращает контекст с личностью и дополненным логгером.
+func (a *authenticator) authorize(ctx context.Context, method string) (context.Context, error) {
+	if strings.HasPrefix(method, publicServicePrefix) {
+		return ctx, nil
+	}
+
+	id, err := a.identify(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var requested uuid.UUID
+	if raw := firstMetadata(ctx, metadataTenantID); raw != "" {
+		if requested, err = uuid.Parse(raw); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid "+metadataTenantID+" metadata")
+		}
+	}
+
+	if id, err = a.tenants.ResolveTenant(ctx, id, requested); err != nil {
+		if errors.Is(err, auth.ErrForeignTenant) || errors.Is(err, services.ErrTenantNotFound) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, statusError(err)
+	}
+
+	scope, ok := methodScopes[method]
+	if !ok && id.IsAPIKey() {
+		// Новый метод без разрешения в таблице не должен стать доступен любому ключу.
+		return nil, status.Error(codes.PermissionDenied, "method is not available to api keys")
+	}
+	if ok && !id.HasScope(scope) {
+		return nil, status.Error(codes.PermissionDenied, "missing scope "+scope)
+	}
+
+	attrs := []any{
+		slog.String("subject", id.Subject),
+		slog.String("tenant_id", id.TenantID.String()),
+	}
+	if id.UserID != uuid.Nil {
+		attrs = append(attrs, slog.String("user_id", id.UserID.String()))
+	}
+	if id.IsAPIKey() {
+		attrs = append(attrs, slog.String("api_key_id", id.KeyID.String()))
+	}
+	ctx = logger.With(ctx, attrs...)
+
+	return auth.WithIdentity(ctx, id), nil
+}
+
+// identify — аутентифицирует вызов по API-ключу или bearer-токену.
+func (a *authenticator) identify(ctx context.Context) (auth.Identity, error) {
+	if raw := firstMetadata(ctx, metadataAPIKey); raw != "" {
+		id, err := a.keys.Authenticate(ctx, raw)
+		if errors.Is(err, services.ErrInvalidAPIKey) {
+			return auth.Identity{}, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if err != nil {
+			return auth.Identity{}, statusError(err)
+		}
+		return id, nil
+	}
+
+	if a.verifier == nil {
+		return auth.Anonymous, nil
+	}
+
+	scheme, token, found := strings.Cut(firstMetadata(ctx, metadataAuthorization), " ")
+	token = strings.TrimSpace(token)
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return auth.Identity{}, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	id, err := a.verifier.Verify(token)
+	if err != nil {
+		logger.FromContext(ctx).Warn("rejected token", slog.String("error", err.Error()))
+		return auth.Identity{}, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return id, nil
+}
+
+// subscriptionsServicePrefix — методы сервиса подписок; их вызовы ограничиваются
+// лимитом группы subscriptions, общим с маршрутами /api/v1/subscriptions.
+const subscriptionsServicePrefix = "/subscriptions.v1.SubscriptionsService/"
+
+// rateLimiter — перехватчик ограничения частоты вызовов. Использует те же ограничители
+// и ключи клиентов, что и HTTP API, поэтому клиент не может обойти лимит, переключившись
+// с одного API на другое. Вызовы остальных сервисов (reflection) ограничиваются лимитом
+// группы grpc.
+type rateLimiter struct {
+	limits *ratelimit.Groups
+}
+
+// unary — перехватчик ограничения частоты для унарных вызовов.
+func (r *rateLimiter) unary(ctx context.Context, req any, info *grpcgo.UnaryServerInfo, handler grpcgo.UnaryHandler) (any, error) {
+	if err := r.allow(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// stream — перехватчик ограничения частоты для потоковых вызовов; поток считается одним вызовом.
+func (r *rateLimiter) stream(srv any, ss grpcgo.ServerStream, info *grpcgo.StreamServerInfo, handler grpcgo.StreamHandler) error {
+	if err := r.allow(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// allow — списывает вызов из корзины клиента и передаёт состояние лимита в заголовках
+// ответа ratelimit-*; при превышении лимита возвращает ResourceExhausted и retry-after.
+func (r *rateLimiter) allow(ctx context.Context, method string) error {
+	group := "grpc"
+	if strings.HasPrefix(method, subscriptionsServicePrefix) {
+		group = "subscriptions"
+	}
+
+	limiter := r.limits.Limiter(group)
+	if limiter == nil {
+		return nil
+	}
+
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+
+	id, ok := auth.FromContext(ctx)
+	res := limiter.Allow(ratelimit.ClientKey(id, ok, ip))
+
+	md := metadata.Pairs(
+		metadataRateLimitLimit, strconv.Itoa(res.Limit),
+		metadataRateLimitRemaining, strconv.Itoa(res.Remaining),
+		metadataRateLimitReset, ratelimit.Seconds(res.Reset),
+	)
+	if !res.Allowed {
+		md.Set(metadataRetryAfter, ratelimit.Seconds(res.RetryAfter))
+	}
+	_ = grpcgo.SetHeader(ctx, md)
+
+	if !res.Allowed {
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return nil
+}
+
+// firstMetadata — возвращает первое значение ключа из входящих метаданных.
+func firstMetadata(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
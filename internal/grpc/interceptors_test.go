@@ -0,0 +1,208 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/ratelimit"
+	"online_subscription_service/internal/services"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// testHMACSecret — секрет подписи токенов в тестах аутентификации.
+const testHMACSecret = "0123456789abcdef0123456789abcdef"
+
+// fakeKeys — API-ключи в памяти: значение ключа → личность сервиса.
+type fakeKeys map[string]auth.Identity
+
+func (k fakeKeys) Authenticate(_ context.Context, raw string) (auth.Identity, error) {
+	if raw == "sk_broken" {
+		return auth.Identity{}, errors.New("database is unavailable")
+	}
+	id, ok := k[raw]
+	if !ok {
+		return auth.Identity{}, services.ErrInvalidAPIKey
+	}
+	return id, nil
+}
+
+// fakeTenants — организации, существующие в тестах аутентификации.
+type fakeTenants []uuid.UUID
+
+func (t fakeTenants) ResolveTenant(_ context.Context, id auth.Identity, requested uuid.UUID) (auth.Identity, error) {
+	id, err := auth.ResolveTenant(id, requested)
+	if err != nil {
+		return id, err
+	}
+	if !slices.Contains(t, id.TenantID) {
+		return auth.Identity{}, services.ErrTenantNotFound
+	}
+	return id, nil
+}
+
+func TestAuthenticatorAuthorize(t *testing.T) {
+	tenant, other, missing := uuid.New(), uuid.New(), uuid.New()
+	reader := auth.Identity{Subject: "key-reader", KeyID: uuid.New(), TenantID: tenant, Scopes: []string{auth.ScopeSubscriptionsRead}}
+	user := auth.Identity{UserID: uuid.New(), TenantID: tenant}
+	admin := auth.Identity{UserID: uuid.New(), TenantID: tenant, Roles: []string{auth.RolePlatformAdmin}}
+	stranger := auth.Identity{UserID: uuid.New(), TenantID: missing}
+
+	cfg := config.AuthConfig{HMACSecret: testHMACSecret, AccessTokenTTL: time.Minute}
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+	issuer, err := auth.NewIssuer(cfg)
+	if err != nil {
+		t.Fatalf("NewIssuer() error = %v", err)
+	}
+	bearer := func(id auth.Identity) string {
+		token, err := issuer.Issue(id)
+		if err != nil {
+			t.Fatalf("Issue() error = %v", err)
+		}
+		return "Bearer " + token
+	}
+
+	const (
+		getMethod     = "/subscriptions.v1.SubscriptionsService/GetSubscription"
+		addMethod     = "/subscriptions.v1.SubscriptionsService/AddSubscription"
+		unknownMethod = "/subscriptions.v1.SubscriptionsService/ExportEverything"
+	)
+
+	tests := []struct {
+		name       string
+		noAuth     bool // аутентификация отключена
+		method     string
+		md         []string
+		want       codes.Code
+		wantTenant uuid.UUID
+	}{
+		{name: "reflection is public", method: "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", want: codes.OK},
+		{name: "api key with scope", method: getMethod, md: []string{metadataAPIKey, "sk_reader"}, want: codes.OK, wantTenant: tenant},
+		{name: "api key without scope", method: addMethod, md: []string{metadataAPIKey, "sk_reader"}, want: codes.PermissionDenied},
+		{name: "api key on unmapped method", method: unknownMethod, md: []string{metadataAPIKey, "sk_reader"}, want: codes.PermissionDenied},
+		{name: "invalid api key", method: getMethod, md: []string{metadataAPIKey, "sk_unknown"}, want: codes.Unauthenticated},
+		{name: "api key store error", method: getMethod, md: []string{metadataAPIKey, "sk_broken"}, want: codes.Internal},
+		{name: "api key wins over bearer", method: addMethod, md: []string{metadataAPIKey, "sk_reader", metadataAuthorization, bearer(user)}, want: codes.PermissionDenied},
+		{name: "bearer user", method: addMethod, md: []string{metadataAuthorization, bearer(user)}, want: codes.OK, wantTenant: tenant},
+		{name: "bearer user on unmapped method", method: unknownMethod, md: []string{metadataAuthorization, bearer(user)}, want: codes.OK, wantTenant: tenant},
+		{name: "lowercase scheme", method: getMethod, md: []string{metadataAuthorization, "bearer " + bearer(user)[len("Bearer "):]}, want: codes.OK, wantTenant: tenant},
+		{name: "missing credentials", method: getMethod, want: codes.Unauthenticated},
+		{name: "basic scheme", method: getMethod, md: []string{metadataAuthorization, "Basic dXNlcjpwYXNz"}, want: codes.Unauthenticated},
+		{name: "invalid token", method: getMethod, md: []string{metadataAuthorization, "Bearer not-a-jwt"}, want: codes.Unauthenticated},
+		{name: "foreign tenant", method: getMethod, md: []string{metadataAuthorization, bearer(user), metadataTenantID, other.String()}, want: codes.PermissionDenied},
+		{name: "platform admin switches tenant", method: getMethod, md: []string{metadataAuthorization, bearer(admin), metadataTenantID, other.String()}, want: codes.OK, wantTenant: other},
+		{name: "invalid tenant metadata", method: getMethod, md: []string{metadataAuthorization, bearer(user), metadataTenantID, "acme"}, want: codes.InvalidArgument},
+		{name: "unknown tenant", method: getMethod, md: []string{metadataAuthorization, bearer(stranger)}, want: codes.PermissionDenied},
+		{name: "auth disabled", noAuth: true, method: getMethod, want: codes.OK, wantTenant: models.DefaultTenantID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &authenticator{
+				keys:     fakeKeys{"sk_reader": reader},
+				tenants:  fakeTenants{tenant, other, models.DefaultTenantID},
+				verifier: verifier,
+			}
+			if tt.noAuth {
+				a.verifier = nil
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tt.md...))
+
+			got, err := a.authorize(ctx, tt.method)
+			if code := status.Code(err); code != tt.want {
+				t.Fatalf("authorize() code = %v (%v), want %v", code, err, tt.want)
+			}
+			if err != nil || tt.wantTenant == uuid.Nil {
+				return
+			}
+			id, ok := auth.FromContext(got)
+			if !ok {
+				t.Fatal("identity is missing from the context")
+			}
+			if id.TenantID != tt.wantTenant {
+				t.Errorf("tenant = %s, want %s", id.TenantID, tt.wantTenant)
+			}
+		})
+	}
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	user := auth.Identity{Subject: "user-1", TenantID: uuid.New()}
+	addr := &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 5000}
+
+	tests := []struct {
+		name string
+		// httpKey — ключ клиента, уже израсходовавшего корзину группы subscriptions через HTTP API.
+		httpKey string
+		ctx     context.Context
+		method  string
+		want    codes.Code
+	}{
+		{
+			name:   "first call",
+			ctx:    auth.WithIdentity(context.Background(), user),
+			method: "/subscriptions.v1.SubscriptionsService/GetSubscription",
+			want:   codes.OK,
+		},
+		{
+			name:    "bucket shared with http",
+			httpKey: ratelimit.ClientKey(user, true, ""),
+			ctx:     auth.WithIdentity(context.Background(), user),
+			method:  "/subscriptions.v1.SubscriptionsService/GetSubscription",
+			want:    codes.ResourceExhausted,
+		},
+		{
+			name:    "other group",
+			httpKey: ratelimit.ClientKey(user, true, ""),
+			ctx:     auth.WithIdentity(context.Background(), user),
+			method:  "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+			want:    codes.OK,
+		},
+		{
+			name:    "anonymous by peer address",
+			httpKey: ratelimit.ClientKey(auth.Identity{}, false, "203.0.113.7"),
+			ctx:     peer.NewContext(context.Background(), &peer.Peer{Addr: addr}),
+			method:  "/subscriptions.v1.SubscriptionsService/ListSubscriptions",
+			want:    codes.ResourceExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := ratelimit.NewGroups(config.RateLimitConfig{
+				Enabled: true,
+				Default: config.RateLimit{Rate: 0.001, Burst: 1},
+			})
+			if tt.httpKey != "" {
+				limits.Limiter("subscriptions").Allow(tt.httpKey)
+			}
+
+			r := &rateLimiter{limits: limits}
+			if got := status.Code(r.allow(tt.ctx, tt.method)); got != tt.want {
+				t.Errorf("allow() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	r := &rateLimiter{limits: ratelimit.NewGroups(config.RateLimitConfig{})}
+	for range 3 {
+		if err := r.allow(context.Background(), "/subscriptions.v1.SubscriptionsService/GetSubscription"); err != nil {
+			t.Fatalf("allow() error = %v, want nil", err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	subscriptionsv1 "online_subscription_service/api/subscriptions/v1"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/lib/ratelimit"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server — gRPC-сервер API подписок.
+// Работает на отдельном порту (grpc.port) того же хоста, что и HTTP-сервер.
+type Server struct {
+	srv  *grpcgo.Server
+	addr string
+}
+
+// New — конструктор gRPC-сервера.
+// Подключает перехватчики логирования, восстановления после паники, аутентификации
+// и ограничения частоты вызовов (limits — ограничители, общие с HTTP API),
+// трассировку OpenTelemetry (если она включена), сервис подписок и reflection.
+// verifier может быть nil, если аутентификация отключена в конфигурации.
+func New(cfg *config.Config, subs SubsService, keys apiKeyAuthenticator, tenants tenantResolver, verifier *auth.Verifier, limits *ratelimit.Groups) *Server {
+	authn := &authenticator{keys: keys, tenants: tenants, verifier: verifier}
+	limiter := &rateLimiter{limits: limits}
+
+	opts := []grpcgo.ServerOption{
+		grpcgo.ChainUnaryInterceptor(logUnary, recoverUnary, authn.unary, limiter.unary),
+		grpcgo.ChainStreamInterceptor(logStream, recoverStream, authn.stream, limiter.stream),
+	}
+	if cfg.Tracing.Enabled {
+		opts = append(opts, grpcgo.StatsHandler(otelgrpc.NewServerHandler()))
+	}
+
+	srv := grpcgo.NewServer(opts...)
+	subscriptionsv1.RegisterSubscriptionsServiceServer(srv, &subscriptionsServer{subs: subs})
+	if cfg.GRPC.Reflection {
+		reflection.Register(srv)
+	}
+
+	return &Server{
+		srv:  srv,
+		addr: fmt.Sprintf("%s:%d", cfg.Host, cfg.GRPC.Port),
+	}
+}
+
+// Start — открывает порт и запускает обработку запросов в фоне.
+// Ошибка открытия порта возвращается сразу; ошибка, из-за которой сервер
+// перестал обслуживать запросы не по команде Stop, передаётся в onError.
+func (s *Server) Start(onError func(error)) error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, grpcgo.ErrServerStopped) {
+			onError(fmt.Errorf("grpc server on %s: %w", s.addr, err))
+		}
+	}()
+
+	return nil
+}
+
+// Stop — корректно завершает работу сервера: перестаёт принимать новые вызовы
+// и дожидается завершения текущих, включая потоковые. Если контекст истекает
+// раньше, оставшиеся вызовы прерываются.
+func (s *Server) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		return fmt.Errorf("failed to shutdown grpc server: %w", ctx.Err())
+	}
+}
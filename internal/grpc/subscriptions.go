@@ -0,0 +1,247 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	subscriptionsv1 "online_subscription_service/api/subscriptions/v1"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/services"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SubsService — операции с подписками, которые предоставляет gRPC API.
+// Совпадает с subscriptions.Service HTTP-обработчиков; реализуется services.SubsService.
+type SubsService interface {
+	AddSubscription(ctx context.Context, sub models.SubsDTO) (uuid.UUID, error)
+	GetSubscription(ctx context.Context, uuid uuid.UUID) (models.Subs, error)
+	EditSubscription(ctx context.Context, uuid uuid.UUID, sub models.SubsUpdateDTO) error
+	GetAllSubscriptions(ctx context.Context, filter models.SubsFilter) ([]models.Subs, error)
+	GetPriceWithPeriod(ctx context.Context, from, to time.Time, userID uuid.UUID, name string) (int, error)
+	RemoveSubscription(ctx context.Context, uuid uuid.UUID) error
+}
+
+// methodScopes — разрешения API-ключей, необходимые для вызова методов;
+// совпадают с разрешениями соответствующих маршрутов HTTP API.
+var methodScopes = map[string]string{
+	subscriptionsv1.SubscriptionsService_AddSubscription_FullMethodName:     auth.ScopeSubscriptionsWrite,
+	subscriptionsv1.SubscriptionsService_GetSubscription_FullMethodName:     auth.ScopeSubscriptionsRead,
+	subscriptionsv1.SubscriptionsService_EditSubscription_FullMethodName:    auth.ScopeSubscriptionsWrite,
+	subscriptionsv1.SubscriptionsService_ListSubscriptions_FullMethodName:   auth.ScopeSubscriptionsRead,
+	subscriptionsv1.SubscriptionsService_StreamSubscriptions_FullMethodName: auth.ScopeSubscriptionsRead,
+	subscriptionsv1.SubscriptionsService_GetPrice_FullMethodName:            auth.ScopeReportsRead,
+	subscriptionsv1.SubscriptionsService_RemoveSubscription_FullMethodName:  auth.ScopeSubscriptionsWrite,
+}
+
+// subscriptionsServer — реализация subscriptions.v1.SubscriptionsService поверх SubsService.
+type subscriptionsServer struct {
+	subscriptionsv1.UnimplementedSubscriptionsServiceServer
+	subs SubsService
+}
+
+// AddSubscription — создаёт подписку и возвращает её UUID.
+func (s *subscriptionsServer) AddSubscription(ctx context.Context, req *subscriptionsv1.AddSubscriptionRequest) (*subscriptionsv1.AddSubscriptionResponse, error) {
+	r := models.AddSubRequest{Name: req.GetServiceName(), Price: int(req.GetPrice())}
+	if req.GetUserId() != "" {
+		userID, err := parseID("user_id", req.GetUserId())
+		if err != nil {
+			return nil, err
+		}
+		r.UserID = userID
+	}
+
+	id, err := s.subs.AddSubscription(ctx, *r.ToSubsDTO())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &subscriptionsv1.AddSubscriptionResponse{Id: id.String()}, nil
+}
+
+// GetSubscription — возвращает подписку по UUID.
+func (s *subscriptionsServer) GetSubscription(ctx context.Context, req *subscriptionsv1.GetSubscriptionRequest) (*subscriptionsv1.GetSubscriptionResponse, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.subs.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &subscriptionsv1.GetSubscriptionResponse{Subscription: toProto(sub)}, nil
+}
+
+// EditSubscription — изменяет заданные в запросе поля подписки.
+func (s *subscriptionsServer) EditSubscription(ctx context.Context, req *subscriptionsv1.EditSubscriptionRequest) (*subscriptionsv1.EditSubscriptionResponse, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	var upd models.SubsUpdateDTO
+	if req.ServiceName != nil {
+		upd.Name = req.ServiceName
+	}
+	if req.Price != nil {
+		price := int(req.GetPrice())
+		upd.Price = &price
+	}
+	if req.UserId != nil {
+		userID, err := parseID("user_id", req.GetUserId())
+		if err != nil {
+			return nil, err
+		}
+		upd.UserID = &userID
+	}
+	if req.StartDate != nil {
+		start := req.GetStartDate().AsTime()
+		upd.StartDate = &start
+	}
+	if req.EndDate != nil {
+		end := req.GetEndDate().AsTime()
+		upd.EndDate = &end
+	}
+
+	if err := s.subs.EditSubscription(ctx, id, upd); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &subscriptionsv1.EditSubscriptionResponse{}, nil
+}
+
+// ListSubscriptions — возвращает страницу подписок.
+func (s *subscriptionsServer) ListSubscriptions(ctx context.Context, req *subscriptionsv1.ListSubscriptionsRequest) (*subscriptionsv1.ListSubscriptionsResponse, error) {
+	subs, err := s.subs.GetAllSubscriptions(ctx, models.SubsFilter{Page: models.Page{Limit: int(req.GetLimit()), Offset: int(req.GetOffset())}})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &subscriptionsv1.ListSubscriptionsResponse{
+		Subscriptions: make([]*subscriptionsv1.Subscription, 0, len(subs)),
+	}
+	for _, sub := range subs {
+		resp.Subscriptions = append(resp.Subscriptions, toProto(sub))
+	}
+
+	return resp, nil
+}
+
+// StreamSubscriptions — передаёт все подписки потоком, читая их из сервиса страницами,
+// чтобы не держать весь список в памяти.
+func (s *subscriptionsServer) StreamSubscriptions(req *subscriptionsv1.StreamSubscriptionsRequest, stream subscriptionsv1.SubscriptionsService_StreamSubscriptionsServer) error {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 || pageSize > models.MaxPageLimit {
+		pageSize = models.MaxPageLimit
+	}
+
+	ctx := stream.Context()
+	for offset := 0; ; offset += pageSize {
+		subs, err := s.subs.GetAllSubscriptions(ctx, models.SubsFilter{Page: models.Page{Limit: pageSize, Offset: offset}})
+		if err != nil {
+			return statusError(err)
+		}
+
+		for _, sub := range subs {
+			if err := stream.Send(toProto(sub)); err != nil {
+				return err
+			}
+		}
+
+		if len(subs) < pageSize {
+			return nil
+		}
+	}
+}
+
+// GetPrice — рассчитывает стоимость подписки пользователя за период.
+func (s *subscriptionsServer) GetPrice(ctx context.Context, req *subscriptionsv1.GetPriceRequest) (*subscriptionsv1.GetPriceResponse, error) {
+	if req.From == nil || req.To == nil {
+		return nil, status.Error(codes.InvalidArgument, "from and to are required")
+	}
+	userID, err := parseID("user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetServiceName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_name is required")
+	}
+
+	from := req.GetFrom().AsTime().Truncate(24 * time.Hour)
+	to := req.GetTo().AsTime().Truncate(24 * time.Hour)
+
+	price, err := s.subs.GetPriceWithPeriod(ctx, from, to, userID, req.GetServiceName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &subscriptionsv1.GetPriceResponse{Price: int64(price)}, nil
+}
+
+// RemoveSubscription — удаляет подписку.
+func (s *subscriptionsServer) RemoveSubscription(ctx context.Context, req *subscriptionsv1.RemoveSubscriptionRequest) (*subscriptionsv1.RemoveSubscriptionResponse, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.subs.RemoveSubscription(ctx, id); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &subscriptionsv1.RemoveSubscriptionResponse{}, nil
+}
+
+// toProto — конвертирует подписку в сообщение gRPC API.
+func toProto(sub models.Subs) *subscriptionsv1.Subscription {
+	msg := &subscriptionsv1.Subscription{
+		Id:          sub.ID.String(),
+		ServiceName: sub.Name,
+		Price:       int64(sub.Price),
+		UserId:      sub.UserID.String(),
+		StartDate:   timestamppb.New(sub.StartDate),
+	}
+	if sub.EndDate != nil {
+		msg.EndDate = timestamppb.New(*sub.EndDate)
+	}
+	return msg
+}
+
+// parseID — разбирает UUID из поля запроса field.
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s: %v", field, err)
+	}
+	return id, nil
+}
+
+// statusError — подбирает код gRPC для ошибки сервисного слоя
+// по тем же правилам, что и response.Status для HTTP API.
+func statusError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, services.ErrUnauthenticated), errors.Is(err, services.ErrInvalidAPIKey):
+		code = codes.Unauthenticated
+	case errors.Is(err, services.ErrQuotaExceeded):
+		code = codes.ResourceExhausted
+	case errors.Is(err, services.ErrForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, services.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, services.ErrInvalidInput):
+		code = codes.InvalidArgument
+	}
+
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/services"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{err: context.Canceled, want: codes.Canceled},
+		{err: fmt.Errorf("read: %w", context.DeadlineExceeded), want: codes.DeadlineExceeded},
+		{err: services.ErrUnauthenticated, want: codes.Unauthenticated},
+		{err: services.ErrInvalidAPIKey, want: codes.Unauthenticated},
+		{err: fmt.Errorf("%w: limit is 3", services.ErrQuotaExceeded), want: codes.ResourceExhausted},
+		{err: services.ErrForbidden, want: codes.PermissionDenied},
+		{err: services.ErrNotFound, want: codes.NotFound},
+		{err: fmt.Errorf("%w: price must not be negative", services.ErrInvalidInput), want: codes.InvalidArgument},
+		{err: errors.New("error getting subscription"), want: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			err := statusError(tt.err)
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("statusError() = %v, not a status", err)
+			}
+			if st.Code() != tt.want || st.Message() != tt.err.Error() {
+				t.Errorf("statusError() = %v %q, want %v %q", st.Code(), st.Message(), tt.want, tt.err.Error())
+			}
+		})
+	}
+}
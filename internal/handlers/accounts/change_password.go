@@ -0,0 +1,40 @@
+package accounts
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// changePassword — HTTP-обработчик смены пароля текущего пользователя.
+// После смены пароля все refresh-токены пользователя отзываются.
+//
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change password of the current user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param        request body models.ChangePasswordRequest true "Old and new password"
+// @Success      200 {object} StatusResponse
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse "Unauthorized"
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /auth/password [put]
+func (h *Handlers) changePassword(c echo.Context) error {
+	r := new(models.ChangePasswordRequest)
+	ctx := c.Request().Context()
+
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	if err := h.usersService.ChangePassword(ctx, *r); err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, StatusResponse{Status: "Ok"})
+}
@@ -0,0 +1,52 @@
+package accounts
+
+import (
+	"context"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Service — интерфейс локальных учётных записей.
+// Определяет регистрацию, вход, обновление токенов, выход и смену пароля.
+type Service interface {
+	Register(ctx context.Context, req models.RegisterRequest) (models.User, error)
+	Login(ctx context.Context, req models.LoginRequest) (models.TokenPair, error)
+	Refresh(ctx context.Context, req models.RefreshRequest) (models.TokenPair, error)
+	Logout(ctx context.Context, req models.RefreshRequest) error
+	ChangePassword(ctx context.Context, req models.ChangePasswordRequest) error
+}
+
+// Handlers — HTTP-обработчики локальных учётных записей.
+// Содержит группу маршрутов Echo и ссылку на сервис учётных записей.
+type Handlers struct {
+	e            *echo.Group
+	usersService *services.UsersService
+}
+
+// StatusResponse — ответ без данных, подтверждающий успешное выполнение.
+type StatusResponse struct {
+	Status string `json:"status"`
+}
+
+// New — конструктор HTTP-обработчиков учётных записей.
+func New(
+	e *echo.Group,
+	usersService *services.UsersService,
+) *Handlers {
+	return &Handlers{
+		e:            e,
+		usersService: usersService,
+	}
+}
+
+// Setup — регистрирует маршруты Echo для учётных записей.
+// Все маршруты, кроме смены пароля, должны быть перечислены в auth.public_paths.
+func (h *Handlers) Setup() {
+	h.e.POST("/register", h.register)
+	h.e.POST("/login", h.login)
+	h.e.POST("/refresh", h.refresh)
+	h.e.POST("/logout", h.logout)
+	h.e.PUT("/password", h.changePassword)
+}
@@ -0,0 +1,40 @@
+package accounts
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// login — HTTP-обработчик входа по email и паролю.
+// Возвращает access-токен и refresh-токен.
+//
+// Login godoc
+// @Summary Login
+// @Description Login with email and password. Emails are unique per tenant: tenant_id selects the tenant, the default tenant is used when it is omitted
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param        request body models.LoginRequest true "Credentials"
+// @Success      200 {object} models.TokenPair
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse "Invalid credentials"
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /auth/login [post]
+func (h *Handlers) login(c echo.Context) error {
+	r := new(models.LoginRequest)
+	ctx := c.Request().Context()
+
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	tokens, err := h.usersService.Login(ctx, *r)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, tokens)
+}
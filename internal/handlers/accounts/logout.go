@@ -0,0 +1,38 @@
+package accounts
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// logout — HTTP-обработчик выхода: отзывает переданный refresh-токен.
+// Выданный ранее access-токен действует до истечения своего срока.
+//
+// Logout godoc
+// @Summary Logout
+// @Description Revoke refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param        request body models.RefreshRequest true "Refresh token"
+// @Success      200 {object} StatusResponse
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /auth/logout [post]
+func (h *Handlers) logout(c echo.Context) error {
+	r := new(models.RefreshRequest)
+	ctx := c.Request().Context()
+
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	if err := h.usersService.Logout(ctx, *r); err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, StatusResponse{Status: "Ok"})
+}
@@ -0,0 +1,40 @@
+package accounts
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// refresh — HTTP-обработчик обновления токенов.
+// Переданный refresh-токен отзывается, взамен выдаётся новая пара токенов.
+//
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Exchange refresh token for a new token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param        request body models.RefreshRequest true "Refresh token"
+// @Success      200 {object} models.TokenPair
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse "Invalid refresh token"
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /auth/refresh [post]
+func (h *Handlers) refresh(c echo.Context) error {
+	r := new(models.RefreshRequest)
+	ctx := c.Request().Context()
+
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	tokens, err := h.usersService.Refresh(ctx, *r)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, tokens)
+}
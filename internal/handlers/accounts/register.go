@@ -0,0 +1,41 @@
+package accounts
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// register — HTTP-обработчик регистрации локального пользователя в организации по умолчанию.
+// Пользователь регистрируется без ролей; администратора создаёт команда create-admin.
+//
+// Register godoc
+// @Summary Register
+// @Description Register local user account
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param        request body models.RegisterRequest true "Credentials"
+// @Success      201 {object} models.User
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      403 {object} models.ErrorResponse "Registration disabled"
+// @Failure      409 {object} models.ErrorResponse "User already exists"
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /auth/register [post]
+func (h *Handlers) register(c echo.Context) error {
+	r := new(models.RegisterRequest)
+	ctx := c.Request().Context()
+
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	user, err := h.usersService.Register(ctx, *r)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, user)
+}
@@ -0,0 +1,42 @@
+package apikeys
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// createAPIKey — HTTP-обработчик для создания API-ключа.
+// Возвращает созданный ключ вместе с его значением, которое больше нигде не отображается.
+//
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Create API key with scopes (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param        request body models.CreateAPIKeyRequest true "API key data"
+// @Success      201 {object} models.CreatedAPIKey
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse "Unauthorized"
+// @Failure      403 {object} models.ErrorResponse "Forbidden"
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /admin/api-keys [post]
+func (h *Handlers) createAPIKey(c echo.Context) error {
+	r := new(models.CreateAPIKeyRequest)
+	ctx := c.Request().Context()
+
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	key, err := h.keysService.CreateKey(ctx, *r)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, key)
+}
@@ -0,0 +1,32 @@
+package apikeys
+
+import (
+	"net/http"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// getAPIKeys — HTTP-обработчик для получения списка API-ключей.
+// Значения ключей не возвращаются, только их префиксы.
+//
+// @Summary     List API keys
+// @Description List API keys (admin only)
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {array} models.APIKey
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /admin/api-keys [get]
+func (h *Handlers) getAPIKeys(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	keys, err := h.keysService.ListKeys(ctx)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, keys)
+}
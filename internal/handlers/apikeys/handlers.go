@@ -0,0 +1,43 @@
+package apikeys
+
+import (
+	"context"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Service — интерфейс управления API-ключами.
+// Определяет операции создания, получения списка и отзыва ключей.
+type Service interface {
+	CreateKey(ctx context.Context, req models.CreateAPIKeyRequest) (models.CreatedAPIKey, error)
+	ListKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeKey(ctx context.Context, id uuid.UUID) error
+}
+
+// Handlers — HTTP-обработчики для управления API-ключами.
+// Содержит группу маршрутов Echo и ссылку на сервис API-ключей.
+type Handlers struct {
+	e           *echo.Group
+	keysService *services.APIKeysService
+}
+
+// New — конструктор HTTP-обработчиков API-ключей.
+func New(
+	e *echo.Group,
+	keysService *services.APIKeysService,
+) *Handlers {
+	return &Handlers{
+		e:           e,
+		keysService: keysService,
+	}
+}
+
+// Setup — регистрирует маршруты Echo для управления API-ключами.
+func (h *Handlers) Setup() {
+	h.e.POST("", h.createAPIKey)
+	h.e.GET("", h.getAPIKeys)
+	h.e.DELETE("/:id", h.revokeAPIKey)
+}
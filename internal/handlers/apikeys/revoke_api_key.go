@@ -0,0 +1,44 @@
+package apikeys
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type RevokeAPIKeyResponse struct {
+	Status string `json:"status"`
+}
+
+// revokeAPIKey — HTTP-обработчик для отзыва API-ключа по ID.
+//
+// @Summary     Revoke API key
+// @Description Revoke API key (admin only)
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "API key ID" format(uuid)
+// @Success     200 {object} RevokeAPIKeyResponse
+// @Failure     400 {object} models.ErrorResponse "Invalid ID parameter"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     404 {object} models.ErrorResponse "Not found"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /admin/api-keys/{id} [delete]
+func (h *Handlers) revokeAPIKey(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	if err := h.keysService.RevokeKey(ctx, id); err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, RevokeAPIKeyResponse{Status: "Ok"})
+}
@@ -0,0 +1,34 @@
+package graph
+
+import (
+	"online_subscription_service/internal/graph"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/labstack/echo/v4"
+)
+
+// Handlers — HTTP-обработчик GraphQL API.
+// Содержит группу маршрутов Echo, схему и сервис подписок для загрузчиков запроса.
+type Handlers struct {
+	e      *echo.Group
+	schema *graphql.Schema
+	subs   graph.SubsService
+}
+
+// New — конструктор HTTP-обработчика GraphQL API.
+func New(
+	e *echo.Group,
+	schema *graphql.Schema,
+	subs graph.SubsService,
+) *Handlers {
+	return &Handlers{
+		e:      e,
+		schema: schema,
+		subs:   subs,
+	}
+}
+
+// Setup — регистрирует маршрут GraphQL API (POST с JSON-телом).
+func (h *Handlers) Setup() {
+	h.e.POST("", h.query)
+}
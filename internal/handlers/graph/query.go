@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/graph"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Request — запрос GraphQL.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// query — HTTP-обработчик запросов GraphQL.
+// Для каждого запроса создаёт загрузчики, которые собирают обращения вложенных полей
+// к хранилищу в пакеты. Ошибки выполнения возвращаются в поле errors с кодом 200,
+// как принято в GraphQL; 400 — только для неразборчивого HTTP-запроса.
+//
+// GraphQL godoc
+// @Summary Execute GraphQL query
+// @Description Execute GraphQL query or mutation over subscriptions, users and aggregates
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param       request body Request true "GraphQL request"
+// @Success     200 {object} map[string]interface{} "GraphQL response with data and errors"
+// @Failure     400 {object} models.ErrorResponse "Invalid request"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Router      /graphql [post]
+func (h *Handlers) query(c echo.Context) error {
+	var req Request
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid graphql request: " + err.Error()})
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "query is required"})
+	}
+
+	ctx := graph.WithLoaders(c.Request().Context(), h.subs)
+
+	return c.JSON(http.StatusOK, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
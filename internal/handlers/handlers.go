@@ -1,33 +1,114 @@
 package handlers
 
 import (
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/handlers/accounts"
+	"online_subscription_service/internal/handlers/apikeys"
+	graphHandlers "online_subscription_service/internal/handlers/graph"
+	healthHandlers "online_subscription_service/internal/handlers/health"
+	"online_subscription_service/internal/handlers/integrations"
+	"online_subscription_service/internal/handlers/middleware"
 	"online_subscription_service/internal/handlers/subscriptions"
+	"online_subscription_service/internal/handlers/tenants"
+	"online_subscription_service/internal/handlers/webhooks"
+	"online_subscription_service/internal/health"
+	"online_subscription_service/internal/lib/ratelimit"
+	"online_subscription_service/internal/metrics"
 	"online_subscription_service/internal/services"
+	"slices"
 
+	"github.com/graph-gophers/graphql-go"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
 // Handlers — основной контейнер для HTTP-обработчиков приложения.
 // Содержит экземпляр Echo для регистрации маршрутов и middleware.
 type Handlers struct {
-	e *echo.Echo
+	e   *echo.Echo
+	cfg *config.Config
+}
+
+// Services — набор сервисов, к которым обращаются HTTP-обработчики.
+type Services struct {
+	Subscriptions *services.SubsService
+	Imports       *services.ImportService
+	Journal       *services.JournalService
+	APIKeys       *services.APIKeysService
+	Users         *services.UsersService // nil, если локальные учётные записи отключены
+	Tenants       *services.TenantsService
+	Webhooks      *services.WebhooksService     // nil, если вебхуки отключены
+	Integrations  *services.IntegrationsService // nil, если интеграции с провайдерами отключены
+	Health        *health.Checker
+	GraphQL       *graphql.Schema
+	RateLimits    *ratelimit.Groups // общие с gRPC-API ограничители частоты запросов
 }
 
 // New — конструктор Handlers.
-// Принимает экземпляр Echo и возвращает инициализированный Handlers.
-func New(e *echo.Echo) *Handlers {
-	return &Handlers{e: e}
+// Принимает экземпляр Echo и конфигурацию, возвращает инициализированный Handlers.
+func New(e *echo.Echo, cfg *config.Config) *Handlers {
+	return &Handlers{e: e, cfg: cfg}
 }
 
 // SetUpHandlers — настраивает все HTTP-эндпоинты и middleware приложения.
-func (h *Handlers) SetUpHandlers(subscriptionsService *services.SubsService) {
+// verifier может быть nil, если аутентификация отключена в конфигурации.
+func (h *Handlers) SetUpHandlers(svc Services, verifier *auth.Verifier) {
 	// Восстанавливает приложение после паники и логирует ошибки
-	h.e.Use(middleware.Recover())
+	h.e.Use(echoMiddleware.Recover())
+
+	// Начинает спан запроса, продолжая трассу из заголовка traceparent (W3C Trace Context)
+	if h.cfg.Tracing.Enabled {
+		h.e.Use(otelecho.Middleware(h.cfg.Tracing.ServiceName, otelecho.WithSkipper(func(c echo.Context) bool {
+			return slices.Contains([]string{h.cfg.Metrics.Path, "/healthz", "/readyz"}, c.Path())
+		})))
+	}
+
+	// Назначает запросу X-Request-ID и создаёт логгер запроса
+	h.e.Use(middleware.RequestID())
+
+	// Собирает метрики HTTP-запросов и отдаёт их на основном сервере,
+	// если для метрик не выделен отдельный порт
+	if h.cfg.Metrics.Enabled {
+		h.e.Use(middleware.Metrics())
+		if h.cfg.Metrics.Port == 0 {
+			h.e.GET(h.cfg.Metrics.Path, echo.WrapHandler(metrics.Handler()))
+		}
+	}
 
 	// Включает логгирование всех HTTP-запросов
-	h.e.Use(middleware.RequestLogger())
+	h.e.Use(middleware.AccessLog())
+
+	// Ограничивает частоту запросов с одного IP-адреса до проверки учётных данных:
+	// перебор API-ключей и токенов и публичные маршруты тоже попадают под лимит
+	h.e.Use(middleware.RateLimitIP(svc.RateLimits, "ip"))
+
+	// Аутентифицирует сервисы по заголовку X-API-Key
+	h.e.Use(middleware.APIKey(svc.APIKeys))
+
+	// Проверяет bearer-токен у всех запросов, кроме публичных путей, проверок состояния
+	// и вебхуков провайдеров, которые проверяются по подписи.
+	// Без аутентификации все запросы выполняются с правами администратора.
+	if verifier != nil {
+		publicPaths := append(slices.Clone(h.cfg.Auth.PublicPaths), "/healthz", "/readyz")
+		if svc.Integrations != nil {
+			publicPaths = append(publicPaths, "/api/v1/integrations/")
+		}
+		h.e.Use(middleware.Authenticate(verifier, publicPaths))
+	} else {
+		h.e.Use(middleware.Anonymous())
+	}
+
+	// Определяет организацию запроса по токену, API-ключу или заголовку X-Tenant-ID
+	h.e.Use(middleware.Tenant(svc.Tenants))
+
+	// Добавляет в логгер запроса пользователя и организацию
+	h.e.Use(middleware.LogIdentity())
+
+	// Проверки состояния для Docker и Kubernetes (/healthz, /readyz)
+	healthHandlers.New(h.e, svc.Health).Setup()
 
 	// Swagger (обычно без versioning)
 	h.e.GET("/swagger/*", echoSwagger.WrapHandler)
@@ -35,7 +116,44 @@ func (h *Handlers) SetUpHandlers(subscriptionsService *services.SubsService) {
 	// Группа всех API-эндпоинтов с префиксом /api/v1
 	api := h.e.Group("/api/v1")
 
-	// Группа эндпоинтов для подписок (/api/v1/subscriptions)
-	subs := api.Group("/subscriptions")
-	subscriptions.New(subs, subscriptionsService).Setup()
+	// Ограничение частоты запросов считается отдельно для каждой группы маршрутов
+	rateLimit := func(group string) echo.MiddlewareFunc {
+		return middleware.RateLimit(svc.RateLimits, group)
+	}
+
+	// Группа эндпоинтов для подписок (/api/v1/subscriptions).
+	// Разрешения API-ключей проверяются на уровне маршрутов: чтение, запись и отчёты.
+	subs := api.Group("/subscriptions", rateLimit("subscriptions"))
+	subscriptions.New(subs, svc.Subscriptions, svc.Imports, svc.Journal, svc.Integrations).Setup(
+		middleware.RequireScope(auth.ScopeSubscriptionsRead),
+		middleware.RequireScope(auth.ScopeSubscriptionsWrite),
+		middleware.RequireScope(auth.ScopeReportsRead),
+	)
+
+	// GraphQL API (/api/v1/graphql). Разрешения API-ключей проверяются в резолверах,
+	// так как один запрос может и читать, и изменять данные.
+	graphHandlers.New(api.Group("/graphql", rateLimit("graphql")), svc.GraphQL, svc.Subscriptions).Setup()
+
+	// Группа эндпоинтов локальных учётных записей (/api/v1/auth)
+	if svc.Users != nil {
+		accounts.New(api.Group("/auth", rateLimit("auth")), svc.Users).Setup()
+	}
+
+	// Группа эндпоинтов организаций (/api/v1/tenants)
+	tenants.New(api.Group("/tenants", rateLimit("tenants")), svc.Tenants, svc.Users).Setup()
+
+	// Группа эндпоинтов вебхуков (/api/v1/webhooks). API-ключу нужно разрешение webhooks:manage.
+	if svc.Webhooks != nil {
+		hooks := api.Group("/webhooks", rateLimit("webhooks"), middleware.RequireScope(auth.ScopeWebhooksManage))
+		webhooks.New(hooks, svc.Webhooks).Setup()
+	}
+
+	// Вебхуки провайдеров подписок (/api/v1/integrations/:provider/webhook)
+	if svc.Integrations != nil {
+		integrations.New(api.Group("/integrations", rateLimit("integrations")), svc.Integrations, h.cfg.Integrations.MaxBodySize).Setup()
+	}
+
+	// Группа административных эндпоинтов (/api/v1/admin), доступна только администраторам
+	admin := api.Group("/admin", rateLimit("admin"))
+	apikeys.New(admin.Group("/api-keys"), svc.APIKeys).Setup()
 }
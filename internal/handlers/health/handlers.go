@@ -0,0 +1,38 @@
+package health
+
+import (
+	"context"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/health"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Checker — интерфейс проверки готовности сервиса.
+type Checker interface {
+	Ready(ctx context.Context) (models.HealthReport, bool)
+}
+
+// Handlers — HTTP-обработчики проверок состояния для Docker и Kubernetes.
+// Маршруты регистрируются в корне сервера, вне /api/v1, и не требуют аутентификации.
+type Handlers struct {
+	e       *echo.Echo
+	checker *health.Checker
+}
+
+// New — конструктор HTTP-обработчиков проверок состояния.
+func New(
+	e *echo.Echo,
+	checker *health.Checker,
+) *Handlers {
+	return &Handlers{
+		e:       e,
+		checker: checker,
+	}
+}
+
+// Setup — регистрирует маршруты /healthz и /readyz.
+func (h *Handlers) Setup() {
+	h.e.GET("/healthz", h.healthz)
+	h.e.GET("/readyz", h.readyz)
+}
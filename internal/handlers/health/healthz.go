@@ -0,0 +1,14 @@
+package health
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// healthz — HTTP-обработчик проверки живости (liveness).
+// Отвечает 200, пока процесс способен обрабатывать запросы; зависимости не проверяются.
+func (h *Handlers) healthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, models.HealthReport{Status: models.HealthStatusOK})
+}
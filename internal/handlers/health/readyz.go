@@ -0,0 +1,20 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// readyz — HTTP-обработчик проверки готовности (readiness).
+// Выполняет все зарегистрированные проверки и возвращает отчёт с результатом
+// и длительностью каждой из них: 200, если сервис готов, иначе 503.
+// Подробности ошибок проверок в ответ не попадают (см. health.Checker).
+func (h *Handlers) readyz(c echo.Context) error {
+	report, ready := h.checker.Ready(c.Request().Context())
+	if !ready {
+		return c.JSON(http.StatusServiceUnavailable, report)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,42 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Service — интерфейс приёма вебхуков провайдеров подписок.
+type Service interface {
+	HandleWebhook(ctx context.Context, provider string, header http.Header, body []byte) (models.InboundWebhookResult, error)
+}
+
+// Handlers — HTTP-обработчики вебхуков провайдеров подписок.
+// Содержит группу маршрутов Echo, ссылку на сервис интеграций и ограничение размера тела вебхука.
+type Handlers struct {
+	e                   *echo.Group
+	integrationsService *services.IntegrationsService
+	maxBodySize         int64
+}
+
+// New — конструктор HTTP-обработчиков вебхуков провайдеров.
+func New(
+	e *echo.Group,
+	integrationsService *services.IntegrationsService,
+	maxBodySize int64,
+) *Handlers {
+	return &Handlers{
+		e:                   e,
+		integrationsService: integrationsService,
+		maxBodySize:         maxBodySize,
+	}
+}
+
+// Setup — регистрирует маршруты Echo для приёма вебхуков провайдеров.
+// Запросы аутентифицируются подписью провайдера, а не токеном или API-ключом.
+func (h *Handlers) Setup() {
+	h.e.POST("/:provider/webhook", h.receiveWebhook)
+}
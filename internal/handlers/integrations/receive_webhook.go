@@ -0,0 +1,61 @@
+package integrations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// receiveWebhook — HTTP-обработчик вебхука провайдера подписок.
+//
+// Поведение:
+//   - Проверяет подпись тела запроса секретом провайдера
+//   - Применяет события к подпискам, связанным с идентификаторами подписок у провайдера
+//   - Повторно присланные события пропускаются (status: duplicate)
+//   - Если хотя бы одно событие не обработано (status: failed), возвращает 500,
+//     чтобы провайдер прислал вебхук повторно; уже обработанные события при этом пропускаются
+//
+// @Summary     Receive provider webhook
+// @Description Apply subscription events (renewed, cancelled, price_changed) sent by a billing provider. Authenticated by the provider signature.
+// @Tags        integrations
+// @Accept      json
+// @Produce     json
+// @Param       provider path string true "Provider name from configuration"
+// @Param       request body object true "Provider-specific event payload"
+// @Success     200 {object} models.InboundWebhookResult
+// @Failure     400 {object} models.ErrorResponse "Invalid payload"
+// @Failure     401 {object} models.ErrorResponse "Invalid signature"
+// @Failure     404 {object} models.ErrorResponse "Unknown provider"
+// @Failure     413 {object} models.ErrorResponse "Payload too large"
+// @Failure     500 {object} models.InboundWebhookResult "Some events failed and must be retried"
+// @Router      /integrations/{provider}/webhook [post]
+func (h *Handlers) receiveWebhook(c echo.Context) error {
+	req := c.Request()
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Response(), req.Body, h.maxBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{Error: fmt.Sprintf("body must not exceed %d bytes", tooLarge.Limit)})
+		}
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	result, err := h.integrationsService.HandleWebhook(req.Context(), c.Param("provider"), req.Header, body)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	for _, e := range result.Events {
+		if e.Status == models.InboundFailed {
+			return c.JSON(http.StatusInternalServerError, result)
+		}
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
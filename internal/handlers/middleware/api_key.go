@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+	"online_subscription_service/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderAPIKey — заголовок, в котором передаётся API-ключ.
+const HeaderAPIKey = "X-API-Key"
+
+// apiKeyAuthenticator — проверяет значение API-ключа.
+type apiKeyAuthenticator interface {
+	Authenticate(ctx context.Context, raw string) (auth.Identity, error)
+}
+
+// APIKey — middleware аутентификации по заголовку X-API-Key.
+// Если заголовок присутствует, ключ проверяется и личность сервиса кладётся в контекст,
+// а невалидный ключ приводит к ответу 401. Запросы без заголовка передаются дальше
+// для проверки bearer-токена.
+func APIKey(keys apiKeyAuthenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			raw := c.Request().Header.Get(HeaderAPIKey)
+			if raw == "" {
+				return next(c)
+			}
+
+			req := c.Request()
+
+			id, err := keys.Authenticate(req.Context(), raw)
+			if errors.Is(err, services.ErrInvalidAPIKey) {
+				return c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: err.Error()})
+			}
+			if err != nil {
+				return response.Error(c, err)
+			}
+
+			c.SetRequest(req.WithContext(auth.WithIdentity(req.Context(), id)))
+
+			return next(c)
+		}
+	}
+}
+
+// RequireScope — middleware, пропускающий только запросы с указанным разрешением.
+// Разрешения ограничивают API-ключи; для пользователей проверка всегда успешна
+// (см. auth.Identity.HasScope).
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id, ok := auth.FromContext(c.Request().Context())
+			if !ok {
+				return unauthorized(c, "authentication required")
+			}
+
+			if !id.HasScope(scope) {
+				return c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "missing scope " + scope})
+			}
+
+			return next(c)
+		}
+	}
+}
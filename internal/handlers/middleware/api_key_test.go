@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/services"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// fakeKeys — проверка API-ключей по заранее известным значениям.
+type fakeKeys map[string]auth.Identity
+
+func (f fakeKeys) Authenticate(_ context.Context, raw string) (auth.Identity, error) {
+	if raw == "broken" {
+		return auth.Identity{}, errors.New("error check api key")
+	}
+	id, ok := f[raw]
+	if !ok {
+		return auth.Identity{}, services.ErrInvalidAPIKey
+	}
+	return id, nil
+}
+
+func TestAPIKey(t *testing.T) {
+	key := auth.Identity{Subject: "api_key", TenantID: uuid.New(), KeyID: uuid.New(), Scopes: []string{auth.ScopeSubscriptionsRead}}
+
+	tests := []struct {
+		name      string
+		header    string
+		want      int
+		wantKeyID uuid.UUID
+	}{
+		{name: "valid key", header: "oss_valid", want: http.StatusOK, wantKeyID: key.KeyID},
+		{name: "no header is left to bearer auth", want: http.StatusOK},
+		{name: "invalid key", header: "oss_invalid", want: http.StatusUnauthorized},
+		{name: "storage failure", header: "broken", want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got uuid.UUID
+			e := echo.New()
+			e.Use(APIKey(fakeKeys{"oss_valid": key}))
+			e.GET("/", func(c echo.Context) error {
+				id, _ := auth.FromContext(c.Request().Context())
+				got = id.KeyID
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderAPIKey, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got != tt.wantKeyID {
+				t.Errorf("identity key = %s, want %s", got, tt.wantKeyID)
+			}
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *auth.Identity
+		want     int
+	}{
+		{name: "key with scope", identity: &auth.Identity{TenantID: uuid.New(), KeyID: uuid.New(), Scopes: []string{auth.ScopeReportsRead, auth.ScopeSubscriptionsWrite}}, want: http.StatusOK},
+		{name: "key without scope", identity: &auth.Identity{TenantID: uuid.New(), KeyID: uuid.New(), Scopes: []string{auth.ScopeSubscriptionsRead}}, want: http.StatusForbidden},
+		{name: "key without scopes", identity: &auth.Identity{TenantID: uuid.New(), KeyID: uuid.New()}, want: http.StatusForbidden},
+		{name: "user is not limited by scopes", identity: &auth.Identity{TenantID: uuid.New(), UserID: uuid.New()}, want: http.StatusOK},
+		{name: "anonymous", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.POST("/", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, RequireScope(auth.ScopeSubscriptionsWrite))
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.identity != nil {
+				req = req.WithContext(auth.WithIdentity(req.Context(), *tt.identity))
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
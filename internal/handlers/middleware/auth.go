@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/logger"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Authenticate — middleware аутентификации по bearer-токену (JWT).
+// Запросы к путям из publicPaths и запросы, уже аутентифицированные другим
+// способом (например, API-ключом), пропускаются без проверки. Для остальных
+// запросов токен из заголовка Authorization проверяется через verifier, а
+// личность вызывающей стороны кладётся в контекст запроса (см. auth.FromContext).
+// При отсутствии или невалидности токена возвращается 401.
+func Authenticate(verifier *auth.Verifier, publicPaths []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			if _, ok := auth.FromContext(req.Context()); ok || isPublicPath(req.URL.Path, publicPaths) {
+				return next(c)
+			}
+
+			token, ok := bearerToken(req.Header.Get(echo.HeaderAuthorization))
+			if !ok {
+				return unauthorized(c, "missing bearer token")
+			}
+
+			id, err := verifier.Verify(token)
+			if err != nil {
+				logger.FromContext(req.Context()).Warn("rejected token", slog.String("error", err.Error()))
+				return unauthorized(c, "invalid token")
+			}
+
+			c.SetRequest(req.WithContext(auth.WithIdentity(req.Context(), id)))
+
+			return next(c)
+		}
+	}
+}
+
+// Anonymous — middleware для режима с отключённой аутентификацией.
+// Кладёт в контекст каждого запроса без API-ключа личность auth.Anonymous.
+func Anonymous() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if _, ok := auth.FromContext(req.Context()); !ok {
+				c.SetRequest(req.WithContext(auth.WithIdentity(req.Context(), auth.Anonymous)))
+			}
+			return next(c)
+		}
+	}
+}
+
+// isPublicPath — проверяет, начинается ли путь с одного из публичных префиксов.
+func isPublicPath(path string, publicPaths []string) bool {
+	for _, prefix := range publicPaths {
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// bearerToken — извлекает токен из значения заголовка Authorization.
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+
+	return token, token != ""
+}
+
+// unauthorized — возвращает ответ 401 с заголовком WWW-Authenticate.
+func unauthorized(c echo.Context, msg string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="api"`)
+	return c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: msg})
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"log/slog"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/lib/logger"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxRequestIDLength — максимальная длина принимаемого от клиента X-Request-ID.
+const maxRequestIDLength = 128
+
+// RequestID — middleware, назначающий запросу идентификатор.
+// Идентификатор берётся из заголовка X-Request-ID, а если он отсутствует
+// или некорректен — генерируется. Идентификатор возвращается в ответе
+// и вместе с методом и маршрутом сохраняется в логгере запроса.
+// Если запрос трассируется, в логгер также добавляется trace_id.
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			rid := req.Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(rid) {
+				rid = uuid.NewString()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, rid)
+
+			ctx := logger.With(req.Context(),
+				slog.String("request_id", rid),
+				slog.String("method", req.Method),
+				slog.String("route", c.Path()),
+			)
+			if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+				ctx = logger.With(ctx, slog.String("trace_id", sc.TraceID().String()))
+			}
+			c.SetRequest(req.WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+// validRequestID — проверяет, что идентификатор непустой, не слишком длинный
+// и состоит только из печатных ASCII-символов без пробелов.
+func validRequestID(rid string) bool {
+	if rid == "" || len(rid) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(rid); i++ {
+		if rid[i] <= ' ' || rid[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// AccessLog — middleware, записывающий итог каждого запроса в логгер запроса:
+// статус, длительность, адрес клиента и ошибку, если она была.
+// Ошибки обработчиков передаются глобальному обработчику Echo,
+// чтобы в лог попал фактически отправленный статус.
+func AccessLog() echo.MiddlewareFunc {
+	return echoMiddleware.RequestLoggerWithConfig(echoMiddleware.RequestLoggerConfig{
+		HandleError: true,
+		LogStatus:   true,
+		LogLatency:  true,
+		LogURI:      true,
+		LogRemoteIP: true,
+		LogError:    true,
+		LogValuesFunc: func(c echo.Context, v echoMiddleware.RequestLoggerValues) error {
+			ctx := c.Request().Context()
+
+			level := slog.LevelInfo
+			switch {
+			case v.Status >= 500:
+				level = slog.LevelError
+			case v.Status >= 400:
+				level = slog.LevelWarn
+			}
+
+			attrs := []slog.Attr{
+				slog.String("uri", v.URI),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency),
+				slog.String("remote_ip", v.RemoteIP),
+			}
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("error", v.Error.Error()))
+			}
+
+			logger.FromContext(ctx).LogAttrs(ctx, level, "request completed", attrs...)
+			return nil
+		},
+	})
+}
+
+// LogIdentity — middleware, добавляющий в логгер запроса сведения о вызывающей
+// стороне: субъект, пользователя, API-ключ и организацию.
+// Должен подключаться после аутентификации и middleware Tenant.
+func LogIdentity() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			id, ok := auth.FromContext(req.Context())
+			if !ok {
+				return next(c)
+			}
+
+			attrs := []any{
+				slog.String("subject", id.Subject),
+				slog.String("tenant_id", id.TenantID.String()),
+			}
+			if id.UserID != uuid.Nil {
+				attrs = append(attrs, slog.String("user_id", id.UserID.String()))
+			}
+			if id.IsAPIKey() {
+				attrs = append(attrs, slog.String("api_key_id", id.KeyID.String()))
+			}
+
+			c.SetRequest(req.WithContext(logger.With(req.Context(), attrs...)))
+
+			return next(c)
+		}
+	}
+}
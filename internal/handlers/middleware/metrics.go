@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"online_subscription_service/internal/metrics"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// unmatchedRoute — значение метки route для запросов, не совпавших ни с одним маршрутом.
+const unmatchedRoute = "unmatched"
+
+// Metrics — middleware, собирающий количество и длительность HTTP-запросов
+// по методу, шаблону маршрута и статусу ответа.
+// Должен подключаться до AccessLog, чтобы учитывать статус, выставленный обработчиком ошибок.
+func Metrics() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = unmatchedRoute
+			}
+
+			labels := []string{c.Request().Method, route, strconv.Itoa(c.Response().Status)}
+			metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+			metrics.HTTPDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/ratelimit"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Заголовки ограничения частоты запросов (draft-ietf-httpapi-ratelimit-headers).
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+)
+
+// RateLimit — middleware ограничения частоты запросов для группы маршрутов group.
+// Лимит группы берётся из limits и считается отдельно для каждого API-ключа, пользователя
+// или, для неаутентифицированных запросов, IP-адреса.
+// Каждый ответ содержит заголовки RateLimit-*, при превышении лимита возвращается 429
+// с заголовком Retry-After.
+func RateLimit(limits *ratelimit.Groups, group string) echo.MiddlewareFunc {
+	return rateLimit(limits.Limiter(group), clientKey)
+}
+
+// RateLimitIP — middleware ограничения частоты запросов с одного IP-адреса по лимиту
+// группы group. Подключается до проверки API-ключей и токенов, поэтому ограничивает
+// и запросы с неверными учётными данными, и публичные маршруты вроде /readyz.
+func RateLimitIP(limits *ratelimit.Groups, group string) echo.MiddlewareFunc {
+	return rateLimit(limits.Limiter(group), func(c echo.Context) string {
+		return ratelimit.ClientKey(auth.Identity{}, false, c.RealIP())
+	})
+}
+
+// rateLimit — middleware, считающее запросы в limiter по ключу key(c); nil-ограничитель
+// (ограничение выключено) пропускает все запросы.
+func rateLimit(limiter *ratelimit.Limiter, key func(c echo.Context) string) echo.MiddlewareFunc {
+	if limiter == nil {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			res := limiter.Allow(key(c))
+
+			h := c.Response().Header()
+			h.Set(HeaderRateLimitLimit, strconv.Itoa(res.Limit))
+			h.Set(HeaderRateLimitRemaining, strconv.Itoa(res.Remaining))
+			h.Set(HeaderRateLimitReset, ratelimit.Seconds(res.Reset))
+
+			if !res.Allowed {
+				h.Set(echo.HeaderRetryAfter, ratelimit.Seconds(res.RetryAfter))
+				return c.JSON(http.StatusTooManyRequests, models.ErrorResponse{Error: "rate limit exceeded"})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// clientKey — ключ клиента для подсчёта запросов.
+func clientKey(c echo.Context) string {
+	id, ok := auth.FromContext(c.Request().Context())
+	return ratelimit.ClientKey(id, ok, c.RealIP())
+}
+
+// IPExtractor — способ определения IP-адреса клиента для echo.Echo.IPExtractor.
+// Адрес из X-Forwarded-For учитывается, только если запрос пришёл от прокси из
+// trustedProxies (подсети CIDR или отдельные адреса); без них используется адрес
+// соединения, и заголовки, присланные клиентом, игнорируются.
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// По умолчанию echo доверяет loopback и частным сетям; доверяются только заданные прокси.
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range trustedProxies {
+		if ip := net.ParseIP(proxy); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			options = append(options, echo.TrustIPRange(&net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}))
+			continue
+		}
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
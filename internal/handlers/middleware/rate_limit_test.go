@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/lib/ratelimit"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func TestIPExtractor(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		xff        string
+		want       string
+	}{
+		{name: "no proxies ignores header", remoteAddr: "203.0.113.7:5000", xff: "198.51.100.1", want: "203.0.113.7"},
+		{name: "no proxies ignores header from private network", remoteAddr: "10.0.0.5:5000", xff: "198.51.100.1", want: "10.0.0.5"},
+		{name: "trusted cidr", trusted: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.5:5000", xff: "198.51.100.1", want: "198.51.100.1"},
+		{name: "trusted address", trusted: []string{"10.0.0.5"}, remoteAddr: "10.0.0.5:5000", xff: "198.51.100.1", want: "198.51.100.1"},
+		{name: "untrusted peer", trusted: []string{"10.0.0.0/8"}, remoteAddr: "203.0.113.7:5000", xff: "198.51.100.1", want: "203.0.113.7"},
+		{name: "private peer not in list", trusted: []string{"10.0.0.0/8"}, remoteAddr: "192.168.1.2:5000", xff: "198.51.100.1", want: "192.168.1.2"},
+		{name: "spoofed hop before proxy", trusted: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.5:5000", xff: "1.2.3.4, 198.51.100.1", want: "198.51.100.1"},
+		{name: "trusted ipv6", trusted: []string{"fd00::/8"}, remoteAddr: "[fd00::1]:5000", xff: "2001:db8::1", want: "2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extract, err := IPExtractor(tt.trusted)
+			if err != nil {
+				t.Fatalf("IPExtractor() error = %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set(echo.HeaderXForwardedFor, tt.xff)
+
+			if got := extract(req); got != tt.want {
+				t.Errorf("IP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPExtractorInvalidProxy(t *testing.T) {
+	if _, err := IPExtractor([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("IPExtractor() error = nil, want error for invalid CIDR")
+	}
+}
+
+func TestRateLimitIP(t *testing.T) {
+	key := auth.Identity{Subject: "key", KeyID: uuid.New(), TenantID: uuid.New()}
+	user := auth.Identity{Subject: "user", UserID: uuid.New(), TenantID: uuid.New()}
+
+	type request struct {
+		ip       string
+		identity *auth.Identity
+		want     int
+	}
+	tests := []struct {
+		name     string
+		enabled  bool
+		requests []request
+	}{
+		{
+			name:    "bucket is shared by all credentials from one address",
+			enabled: true,
+			requests: []request{
+				{ip: "203.0.113.7", want: http.StatusOK},
+				{ip: "203.0.113.7", identity: &key, want: http.StatusOK},
+				{ip: "203.0.113.7", identity: &user, want: http.StatusTooManyRequests},
+			},
+		},
+		{
+			name:    "addresses are counted separately",
+			enabled: true,
+			requests: []request{
+				{ip: "203.0.113.7", want: http.StatusOK},
+				{ip: "203.0.113.7", want: http.StatusOK},
+				{ip: "198.51.100.1", want: http.StatusOK},
+				{ip: "203.0.113.7", want: http.StatusTooManyRequests},
+			},
+		},
+		{
+			name: "disabled",
+			requests: []request{
+				{ip: "203.0.113.7", want: http.StatusOK},
+				{ip: "203.0.113.7", want: http.StatusOK},
+				{ip: "203.0.113.7", want: http.StatusOK},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := ratelimit.NewGroups(config.RateLimitConfig{
+				Enabled: tt.enabled,
+				Groups:  map[string]config.RateLimit{"ip": {Rate: 0.001, Burst: 2}},
+			})
+
+			e := echo.New()
+			e.IPExtractor = echo.ExtractIPDirect()
+			e.Use(RateLimitIP(limits, "ip"))
+			e.GET("/readyz", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+			for i, r := range tt.requests {
+				req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+				req.RemoteAddr = r.ip + ":5000"
+				if r.identity != nil {
+					req = req.WithContext(auth.WithIdentity(req.Context(), *r.identity))
+				}
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+
+				if rec.Code != r.want {
+					t.Fatalf("request %d: status = %d, want %d", i, rec.Code, r.want)
+				}
+				if r.want == http.StatusTooManyRequests && rec.Header().Get(echo.HeaderRetryAfter) == "" {
+					t.Errorf("request %d: Retry-After header is missing", i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+	"online_subscription_service/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderTenantID — заголовок, в котором можно указать организацию.
+const HeaderTenantID = "X-Tenant-ID"
+
+// tenantResolver — определяет организацию запроса и проверяет её существование.
+type tenantResolver interface {
+	ResolveTenant(ctx context.Context, id auth.Identity, requested uuid.UUID) (auth.Identity, error)
+}
+
+// Tenant — middleware, определяющий организацию аутентифицированного запроса
+// по токену, API-ключу или заголовку X-Tenant-ID (см. auth.ResolveTenant).
+// Запрос чужой или несуществующей организации отклоняется с кодом 403; выбрать
+// организацию заголовком может только администратор платформы.
+//
+// Запросы без личности (публичные пути) передаются дальше без изменений.
+func Tenant(tenants tenantResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			id, ok := auth.FromContext(req.Context())
+			if !ok {
+				return next(c)
+			}
+
+			var headerTenant uuid.UUID
+			if raw := req.Header.Get(HeaderTenantID); raw != "" {
+				parsed, err := uuid.Parse(raw)
+				if err != nil {
+					return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid " + HeaderTenantID + " header"})
+				}
+				headerTenant = parsed
+			}
+
+			id, err := tenants.ResolveTenant(req.Context(), id, headerTenant)
+			if errors.Is(err, auth.ErrForeignTenant) || errors.Is(err, services.ErrTenantNotFound) {
+				return c.JSON(http.StatusForbidden, models.ErrorResponse{Error: err.Error()})
+			}
+			if err != nil {
+				return response.Error(c, err)
+			}
+
+			c.SetRequest(req.WithContext(auth.WithIdentity(req.Context(), id)))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Error — возвращает JSON с ошибкой сервисного слоя и соответствующим ей HTTP-статусом.
+// Неизвестные ошибки отдаются с кодом 500.
+func Error(c echo.Context, err error) error {
+	return c.JSON(Status(err), models.ErrorResponse{Error: err.Error()})
+}
+
+// Status — подбирает HTTP-статус для ошибки сервисного слоя.
+func Status(err error) int {
+	switch {
+	case errors.Is(err, services.ErrUnauthenticated), errors.Is(err, services.ErrInvalidAPIKey),
+		errors.Is(err, services.ErrInvalidCredentials), errors.Is(err, services.ErrInvalidRefreshToken),
+		errors.Is(err, services.ErrInvalidSignature):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrRegistrationDisabled),
+		errors.Is(err, services.ErrQuotaExceeded):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrUserExists), errors.Is(err, services.ErrTenantExists),
+		errors.Is(err, services.ErrLinkExists):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrNotFound), errors.Is(err, services.ErrAPIKeyNotFound),
+		errors.Is(err, services.ErrTenantNotFound), errors.Is(err, services.ErrImportNotFound),
+		errors.Is(err, services.ErrWebhookNotFound), errors.Is(err, services.ErrDeliveryNotFound),
+		errors.Is(err, services.ErrProviderNotFound), errors.Is(err, services.ErrLinkNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -3,6 +3,7 @@ package subscriptions
 import (
 	"net/http"
 	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
 
 	"github.com/labstack/echo/v4"
 )
@@ -21,9 +22,13 @@ type AddSubscriptionResponse struct {
 // @Tags subscriptions
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param        request body models.AddSubRequest true "Subscription data"
 // @Success      201 {object} AddSubscriptionResponse
 // @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse "Unauthorized"
+// @Failure      403 {object} models.ErrorResponse "Forbidden"
 // @Failure      500 {object} models.ErrorResponse
 // @Router       /subscriptions [post]
 func (h *Handlers) addSubscription(c echo.Context) error {
@@ -36,7 +41,7 @@ func (h *Handlers) addSubscription(c echo.Context) error {
 
 	id, err := h.subsService.AddSubscription(ctx, *r.ToSubsDTO())
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return response.Error(c, err)
 	}
 
 	return c.JSON(http.StatusCreated, AddSubscriptionResponse{ID: id.String()})
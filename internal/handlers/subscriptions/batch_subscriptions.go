@@ -0,0 +1,63 @@
+package subscriptions
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// batchSubscriptions — HTTP-обработчик пакетного создания, изменения и удаления подписок.
+//
+// Тело запроса: режим (atomic или best_effort) и массив операций create, update и delete.
+//
+// Поведение:
+//   - atomic: все операции в одной транзакции; при первой ошибке изменения откатываются,
+//     и ответ получает HTTP-статус этой ошибки с итогами всех операций
+//   - best_effort: каждая операция в своей транзакции, ответ 200 с итогом каждой операции
+//   - Итог операции содержит статус (ok, failed, rolled_back, skipped), а для ошибки —
+//     её HTTP-статус и текст
+//
+// @Summary     Batch subscriptions
+// @Description Create, update and delete subscriptions in one transaction or in best-effort mode
+// @Tags        subscriptions
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       request body models.BatchRequest true "Batch operations"
+// @Success     200 {object} models.BatchResult
+// @Failure     400 {object} models.BatchResult "Invalid request or operation"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.BatchResult "Forbidden operation or quota exceeded"
+// @Failure     404 {object} models.BatchResult "Subscription not found"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /subscriptions/batch [post]
+func (h *Handlers) batchSubscriptions(c echo.Context) error {
+	var r models.BatchRequest
+	if err := c.Bind(&r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	result, err := h.subsService.BatchSubscriptions(ctx, r)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	status := http.StatusOK
+	for i := range result.Results {
+		res := &result.Results[i]
+		if res.Err == nil {
+			continue
+		}
+		res.Code = response.Status(res.Err)
+		if result.Mode == models.BatchAtomic {
+			status = res.Code
+		}
+	}
+
+	return c.JSON(status, result)
+}
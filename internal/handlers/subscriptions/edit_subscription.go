@@ -3,6 +3,7 @@ package subscriptions
 import (
 	"net/http"
 	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -26,10 +27,15 @@ type EditSubscriptionResponse struct {
 // @Tags subscriptions
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param        id path string true "Subscription ID" format(uuid)
 // @Param        request body models.EditSubRequest true "Subscription data"
 // @Success      200 {object} EditSubscriptionResponse
 // @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse "Unauthorized"
+// @Failure      403 {object} models.ErrorResponse "Forbidden"
+// @Failure      404 {object} models.ErrorResponse "Not found"
 // @Failure      500 {object} models.ErrorResponse
 // @Router       /subscriptions/{id} [patch]
 func (h *Handlers) editSubscription(c echo.Context) error {
@@ -54,7 +60,7 @@ func (h *Handlers) editSubscription(c echo.Context) error {
 
 	err = h.subsService.EditSubscription(ctx, uuid, *r.ToSubsUpdateDTO())
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return response.Error(c, err)
 	}
 
 	return c.JSON(http.StatusOK, EditSubscriptionResponse{Status: "Ok"})
@@ -0,0 +1,151 @@
+package subscriptions
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/lib/spreadsheet"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// exportColumns — колонки выгрузки подписок и значения, которые в них записываются.
+var exportColumns = map[string]func(sub models.Subs) any{
+	"id":           func(sub models.Subs) any { return sub.ID.String() },
+	"service_name": func(sub models.Subs) any { return sub.Name },
+	"price":        func(sub models.Subs) any { return sub.Price },
+	"user_id":      func(sub models.Subs) any { return sub.UserID.String() },
+	"start_date":   func(sub models.Subs) any { return sub.StartDate },
+	"end_date":     func(sub models.Subs) any { return sub.EndDate },
+}
+
+// defaultExportColumns — колонки выгрузки по умолчанию в порядке вывода.
+var defaultExportColumns = []string{"id", "service_name", "price", "user_id", "start_date", "end_date"}
+
+// exportSubscriptions — HTTP-обработчик потоковой выгрузки подписок в файл.
+//
+// Параметры запроса:
+//   - format: csv (по умолчанию), ndjson или xlsx.
+//   - columns: колонки через запятую (по умолчанию все в порядке id, service_name,
+//     price, user_id, start_date, end_date).
+//   - ids, user_id, service_name, limit, offset: тот же фильтр, что у списка подписок;
+//     limit не ограничен.
+//
+// Поведение:
+//   - Подписки читаются из курсора БД и пишутся в ответ по мере чтения, не накапливаясь в памяти
+//   - Даты выводятся в формате YYYY-MM-DD
+//   - Строки, похожие на формулы, в CSV и XLSX экранируются апострофом
+//   - Если выгрузка прервалась после начала ответа, соединение разрывается,
+//     чтобы клиент не принял неполный файл за полный
+//
+// @Summary     Export subscriptions
+// @Description Stream subscriptions matching the list filter as CSV, JSON Lines or XLSX
+// @Tags        subscriptions
+// @Produce     text/csv
+// @Produce     application/x-ndjson
+// @Produce     application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       format query string false "File format" Enums(csv, ndjson, xlsx) default(csv)
+// @Param       columns query string false "Comma-separated columns: id, service_name, price, user_id, start_date, end_date"
+// @Param       ids query string false "Comma-separated subscription IDs"
+// @Param       user_id query string false "User ID" format(uuid)
+// @Param       service_name query string false "Service name"
+// @Param       limit query int false "Maximum number of subscriptions (0 or omitted — no limit)" minimum(0)
+// @Param       offset query int false "Number of subscriptions to skip" minimum(0)
+// @Success     200 {file} file
+// @Failure     400 {object} models.ErrorResponse "Invalid request parameters"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /subscriptions/export [get]
+func (h *Handlers) exportSubscriptions(c echo.Context) error {
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "" {
+		format = spreadsheet.FormatCSV
+	}
+	if !slices.Contains([]string{spreadsheet.FormatCSV, spreadsheet.FormatNDJSON, spreadsheet.FormatXLSX}, format) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "format must be csv, ndjson or xlsx"})
+	}
+
+	columns, err := parseExportColumns(c.QueryParam("columns"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	filter, err := parseSubsFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	resp := c.Response()
+
+	// Заголовки и файл начинают писаться только с первой подпиской (или в конце,
+	// если подписок нет), чтобы ошибки прав и фильтра вернулись обычным JSON-ответом.
+	var w spreadsheet.Writer
+	start := func() error {
+		if w != nil {
+			return nil
+		}
+		resp.Header().Set(echo.HeaderContentType, spreadsheet.ContentType(format))
+		resp.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="subscriptions.%s"`, format))
+
+		var err error
+		w, err = spreadsheet.NewWriter(resp, format, columns)
+		return err
+	}
+
+	row := make([]any, len(columns))
+	err = h.subsService.ExportSubscriptions(ctx, filter, func(sub models.Subs) error {
+		if err := start(); err != nil {
+			return err
+		}
+		for i, column := range columns {
+			row[i] = exportColumns[column](sub)
+		}
+		return w.Write(row)
+	})
+	if err == nil {
+		if err = start(); err == nil {
+			err = w.Close()
+		}
+	}
+
+	if err != nil {
+		if !resp.Committed {
+			resp.Header().Del(echo.HeaderContentDisposition)
+			return response.Error(c, err)
+		}
+		logger.FromContext(ctx).Error("export interrupted", slog.String("error", err.Error()))
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil
+}
+
+// parseExportColumns — разбирает список колонок выгрузки; пустой список означает все колонки.
+func parseExportColumns(s string) ([]string, error) {
+	if s == "" {
+		return defaultExportColumns, nil
+	}
+
+	var columns []string
+	for _, column := range strings.Split(s, ",") {
+		column = strings.TrimSpace(column)
+		if _, ok := exportColumns[column]; !ok {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		if slices.Contains(columns, column) {
+			return nil, fmt.Errorf("duplicate column %q", column)
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
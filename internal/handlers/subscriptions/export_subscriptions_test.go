@@ -0,0 +1,203 @@
+package subscriptions
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/spreadsheet"
+	"online_subscription_service/internal/services"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// fakeService — сервис подписок, выгружающий заранее заданные подписки
+// или возвращающий ошибку до первой из них.
+type fakeService struct {
+	Service
+	subs []models.Subs
+	err  error
+}
+
+func (f *fakeService) ExportSubscriptions(_ context.Context, _ models.SubsFilter, fn func(models.Subs) error) error {
+	if f.err != nil {
+		return f.err
+	}
+	for _, sub := range f.subs {
+		if err := fn(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestParseExportColumns(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr string
+	}{
+		{in: "", want: defaultExportColumns},
+		{in: "price, service_name", want: []string{"price", "service_name"}},
+		{in: "end_date", want: []string{"end_date"}},
+		{in: "id,name", wantErr: `unknown column "name"`},
+		{in: "id,", wantErr: `unknown column ""`},
+		{in: "id,price,id", wantErr: `duplicate column "id"`},
+	}
+
+	for _, tt := range tests {
+		got, err := parseExportColumns(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("parseExportColumns(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("parseExportColumns(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestExportSubscriptions(t *testing.T) {
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sub := func(name string, price int) models.Subs {
+		return models.Subs{ID: uuid.New(), Name: name, Price: price, UserID: uuid.New(), StartDate: start}
+	}
+	subs := []models.Subs{
+		sub("Netflix", 400),
+		sub(`=HYPERLINK("http://evil.example","x")`, 100),
+		sub("+7 900", 0),
+		sub("-cmd", -1),
+		sub("@SUM(A1)", 1),
+		sub("\tTab", 2),
+		sub("Yandex-Plus=1", 3),
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		svc        *fakeService
+		wantStatus int
+		wantType   string
+		wantBody   string // для CSV и NDJSON — тело ответа целиком, для JSON — подстрока
+		wantXLSX   [][]string
+		wantAttach bool
+	}{
+		{
+			name:       "csv escapes formulas",
+			query:      "columns=service_name,price",
+			svc:        &fakeService{subs: subs},
+			wantStatus: http.StatusOK,
+			wantType:   "text/csv",
+			wantBody: "service_name,price\nNetflix,400\n\"'=HYPERLINK(\"\"http://evil.example\"\",\"\"x\"\")\",100\n" +
+				"'+7 900,0\n'-cmd,-1\n'@SUM(A1),1\n'\tTab,2\nYandex-Plus=1,3\n",
+			wantAttach: true,
+		},
+		{
+			name:       "xlsx escapes formulas",
+			query:      "format=xlsx&columns=service_name,start_date",
+			svc:        &fakeService{subs: subs[1:3]},
+			wantStatus: http.StatusOK,
+			wantType:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			wantXLSX: [][]string{
+				{"service_name", "start_date"},
+				{`'=HYPERLINK("http://evil.example","x")`, "45658"},
+				{"'+7 900", "45658"},
+			},
+			wantAttach: true,
+		},
+		{
+			name:       "ndjson keeps values as is",
+			query:      "format=ndjson&columns=service_name",
+			svc:        &fakeService{subs: subs[2:3]},
+			wantStatus: http.StatusOK,
+			wantType:   "application/x-ndjson",
+			wantBody:   "{\"service_name\":\"+7 900\"}\n",
+			wantAttach: true,
+		},
+		{
+			name:       "no subscriptions writes header",
+			query:      "columns=id,price",
+			svc:        &fakeService{},
+			wantStatus: http.StatusOK,
+			wantType:   "text/csv",
+			wantBody:   "id,price\n",
+			wantAttach: true,
+		},
+		{
+			name:       "error before first row returns json",
+			svc:        &fakeService{err: services.ErrForbidden},
+			wantStatus: http.StatusForbidden,
+			wantType:   echo.MIMEApplicationJSON,
+			wantBody:   services.ErrForbidden.Error(),
+		},
+		{
+			name:       "unknown column",
+			query:      "columns=id,secret",
+			svc:        &fakeService{subs: subs},
+			wantStatus: http.StatusBadRequest,
+			wantType:   echo.MIMEApplicationJSON,
+			wantBody:   `unknown column \"secret\"`,
+		},
+		{
+			name:       "unknown format",
+			query:      "format=pdf",
+			svc:        &fakeService{subs: subs},
+			wantStatus: http.StatusBadRequest,
+			wantType:   echo.MIMEApplicationJSON,
+			wantBody:   "format must be csv, ndjson or xlsx",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handlers{subsService: tt.svc}
+			req := httptest.NewRequest(http.MethodGet, "/subscriptions/export?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			if err := h.exportSubscriptions(echo.New().NewContext(req, rec)); err != nil {
+				t.Fatalf("exportSubscriptions() error = %v", err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(got, tt.wantType) {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if got := rec.Header().Get(echo.HeaderContentDisposition) != ""; got != tt.wantAttach {
+				t.Errorf("Content-Disposition present = %v, want %v", got, tt.wantAttach)
+			}
+
+			switch {
+			case tt.wantXLSX != nil:
+				rows, err := spreadsheet.Read(rec.Body, spreadsheet.FormatXLSX, spreadsheet.Options{})
+				if err != nil {
+					t.Fatalf("failed to read xlsx: %v", err)
+				}
+				if len(rows) != len(tt.wantXLSX) {
+					t.Fatalf("xlsx rows = %v, want %v", rows, tt.wantXLSX)
+				}
+				for i, row := range rows {
+					if !slices.Equal(row.Cells, tt.wantXLSX[i]) {
+						t.Errorf("xlsx row %d = %q, want %q", i+1, row.Cells, tt.wantXLSX[i])
+					}
+				}
+			case tt.wantType == echo.MIMEApplicationJSON:
+				if !strings.Contains(rec.Body.String(), tt.wantBody) {
+					t.Errorf("body = %s, want it to contain %s", rec.Body.String(), tt.wantBody)
+				}
+			default:
+				if got := rec.Body.String(); got != tt.wantBody {
+					t.Errorf("body = %q, want %q", got, tt.wantBody)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package subscriptions
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// getImport — HTTP-обработчик для получения состояния и прогресса задачи импорта.
+//
+// GetImport godoc
+// @Summary     Get import
+// @Description Get status, progress and row errors of a subscriptions import
+// @Tags        subscriptions
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       id path string true "Import ID" format(uuid)
+// @Success     200 {object} models.ImportJob
+// @Failure     400 {object} models.ErrorResponse
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     404 {object} models.ErrorResponse "Not found"
+// @Failure     500 {object} models.ErrorResponse
+// @Router      /subscriptions/import/{id} [get]
+func (h *Handlers) getImport(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid import id"})
+	}
+
+	ctx := c.Request().Context()
+
+	job, err := h.importService.GetImport(ctx, id)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
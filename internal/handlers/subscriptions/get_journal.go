@@ -0,0 +1,161 @@
+package subscriptions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+	"online_subscription_service/internal/lib/ledger"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// journalExtensions — расширения файлов журнала по форматам.
+var journalExtensions = map[string]string{
+	ledger.FormatLedger:    "ledger",
+	ledger.FormatHledger:   "journal",
+	ledger.FormatBeancount: "beancount",
+}
+
+// getJournal — HTTP-обработчик выгрузки начислений по подпискам в журнал
+// простой текстовой бухгалтерии.
+//
+// Параметры запроса:
+//   - from, to: первый и последний день периода (формат "YYYY-MM-DD").
+//   - format: ledger (по умолчанию), hledger или beancount.
+//   - user_id: UUID пользователя (по умолчанию — вызывающий пользователь).
+//   - service_name: название услуги (по умолчанию — все услуги).
+//   - account: счёт расходов услуги в виде "<услуга>=<счёт>", можно указать несколько раз;
+//     дополняет и переопределяет счета из настроек ledger.accounts.
+//   - payment_account, currency: счёт оплаты и валюта вместо заданных в настройках.
+//   - open: добавить объявления счетов (директивы open в beancount, account в ledger и hledger).
+//
+// Поведение:
+//   - Учитываются те же подписки, что и в стоимости за период (/subscriptions/price)
+//   - Цена подписки начисляется в первый день каждого месячного расчётного периода,
+//     отсчитываемого от даты начала подписки, если этот день входит в период выгрузки
+//
+// @Summary     Export journal
+// @Description Export recurring subscription charges as ledger, hledger or beancount journal entries
+// @Tags        subscriptions
+// @Produce     plain
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       from query string true "First day of the period" format(date) example(2025-01-01)
+// @Param       to query string true "Last day of the period" format(date) example(2025-12-31)
+// @Param       format query string false "Journal format" Enums(ledger, hledger, beancount) default(ledger)
+// @Param       user_id query string false "User ID" format(uuid)
+// @Param       service_name query string false "Service name"
+// @Param       account query []string false "Expense account of a service as service=account" collectionFormat(multi)
+// @Param       payment_account query string false "Account the subscriptions are paid from" example(Liabilities:CreditCard)
+// @Param       currency query string false "Currency" example(RUB)
+// @Param       open query bool false "Declare accounts before the entries"
+// @Success     200 {string} string "Journal"
+// @Failure     400 {object} models.ErrorResponse "Invalid request parameters"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /subscriptions/journal [get]
+func (h *Handlers) getJournal(c echo.Context) error {
+	opts, err := parseJournalOptions(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "" {
+		format = ledger.FormatLedger
+	}
+	if !ledger.ValidFormat(format) {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "format must be ledger, hledger or beancount"})
+	}
+
+	var open bool
+	if v := c.QueryParam("open"); v != "" {
+		if open, err = strconv.ParseBool(v); err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid open"})
+		}
+	}
+
+	ctx := c.Request().Context()
+
+	entries, err := h.journalService.GetJournal(ctx, opts)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	txs := make([]ledger.Transaction, 0, len(entries))
+	for _, e := range entries {
+		txs = append(txs, ledger.Transaction{
+			Date:      e.Date,
+			Payee:     e.ServiceName,
+			Narration: fmt.Sprintf("subscription %s..%s", e.Date.Format("2006-01-02"), e.PeriodEnd.Format("2006-01-02")),
+			Meta:      []ledger.Meta{{Key: "subscription_id", Value: e.SubscriptionID.String()}},
+			Account:   e.Account,
+			Counter:   e.PaymentAccount,
+			Amount:    e.Amount,
+			Currency:  e.Currency,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := ledger.Write(&buf, format, txs, ledger.Options{Open: open}); err != nil {
+		return response.Error(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="subscriptions.%s"`, journalExtensions[format]))
+	return c.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, buf.Bytes())
+}
+
+// parseJournalOptions — разбирает период, пользователя, услугу и счета выгрузки журнала.
+func parseJournalOptions(c echo.Context) (models.JournalOptions, error) {
+	var opts models.JournalOptions
+
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &opts.From}, {"to", &opts.To}} {
+		v := c.QueryParam(p.name)
+		if v == "" {
+			return opts, fmt.Errorf("param %s is required", p.name)
+		}
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s date", p.name)
+		}
+		*p.dst = date
+	}
+
+	if v := c.QueryParam("user_id"); v != "" {
+		userID, err := uuid.Parse(v)
+		if err != nil {
+			return opts, errors.New("invalid user_id")
+		}
+		opts.UserID = userID
+	}
+
+	opts.ServiceName = c.QueryParam("service_name")
+	opts.PaymentAccount = c.QueryParam("payment_account")
+	opts.Currency = c.QueryParam("currency")
+
+	for _, v := range c.QueryParams()["account"] {
+		// Название счёта не содержит "=", а название услуги может.
+		i := strings.LastIndex(v, "=")
+		if i <= 0 {
+			return opts, errors.New("account must be in format service=account")
+		}
+		if opts.Accounts == nil {
+			opts.Accounts = make(map[string]string)
+		}
+		opts.Accounts[v[:i]] = v[i+1:]
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,42 @@
+package subscriptions
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// getLinks — HTTP-обработчик для получения связей подписки с провайдерами.
+//
+// @Summary     List subscription links
+// @Description Get provider subscription IDs linked to the subscription
+// @Tags        subscriptions
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       id path string true "Subscription ID" format(uuid)
+// @Success     200 {array} models.SubscriptionLink
+// @Failure     400 {object} models.ErrorResponse "Invalid ID parameter"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     404 {object} models.ErrorResponse "Not found"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /subscriptions/{id}/links [get]
+func (h *Handlers) getLinks(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	links, err := h.integrationsService.ListLinks(ctx, id)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, links)
+}
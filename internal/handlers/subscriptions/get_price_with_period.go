@@ -3,6 +3,7 @@ package subscriptions
 import (
 	"net/http"
 	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,21 +22,29 @@ type GetPriceWithPeriodResponse struct {
 //   - user_id: UUID пользователя.
 //   - service_name: название услуги.
 //
+// Цена подписки месячная и списывается в первый день каждого расчётного периода, отсчитываемого
+// от даты начала подписки; стоимость — сумма списаний, пришедшихся на [from, to], пока подписка
+// действует. Это те же начисления, что выгружает /subscriptions/journal.
+//
 // Валидирует входные параметры, парсит даты и UUID, вызывает сервисный слой.
 // Возвращает JSON с рассчитанной ценой или ошибку.
 //
 // GetPriceWithPeriod godoc
 // @Summary Get price
-// @Description Get price with period from service
+// @Description Sum of monthly charges of the user's subscriptions to the service that fall within [from, to]. A charge is made on the start day of every monthly billing period counted from the subscription start date while the subscription is active; the same charges are exported by GET /subscriptions/journal.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param       from query string true "Start date" format(date) example(2025-01-01)
 // @Param       to query string true "End date" format(date) example(2025-01-31)
 // @Param       user_id query string true "User ID" format(uuid) example(550e8400-e29b-41d4-a716-446655440000)
 // @Param       service_name query string true "Service name" example("premium")
 // @Success     200 {object} GetPriceWithPeriodResponse
 // @Failure     400 {object} models.ErrorResponse "Invalid request parameters"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
 // @Failure     500 {object} models.ErrorResponse "Internal server error"
 // @Router      /subscriptions/price [get]
 func (h *Handlers) getPriceWithPeriod(c echo.Context) error {
@@ -78,7 +87,7 @@ func (h *Handlers) getPriceWithPeriod(c echo.Context) error {
 
 	price, err := h.subsService.GetPriceWithPeriod(ctx, from, to, userID, serviceName)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return response.Error(c, err)
 	}
 
 	return c.JSON(http.StatusOK, GetPriceWithPeriodResponse{Price: price})
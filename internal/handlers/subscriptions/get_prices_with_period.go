@@ -0,0 +1,73 @@
+package subscriptions
+
+import (
+	"fmt"
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// getPricesWithPeriod — HTTP-обработчик для получения стоимости подписок на услугу
+// за период сразу для нескольких пользователей.
+//
+// Тело запроса:
+//   - from, to: начало и конец периода (формат "YYYY-MM-DD").
+//   - service_name: название услуги.
+//   - user_ids: UUID пользователей (от 1 до 1000).
+//
+// Поведение:
+//   - Стоимость считается так же, как в /subscriptions/price; подписки всех пользователей читаются одним запросом к БД
+//   - В ответе есть все запрошенные пользователи; без подписок в периоде стоимость равна 0
+//
+// @Summary     Get prices
+// @Description Get prices with period for many users at once, calculated as in GET /subscriptions/price
+// @Tags        subscriptions
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       request body models.BulkPriceRequest true "Period, service and users"
+// @Success     200 {object} models.BulkPriceResponse
+// @Failure     400 {object} models.ErrorResponse "Invalid request parameters"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /subscriptions/prices [post]
+func (h *Handlers) getPricesWithPeriod(c echo.Context) error {
+	var r models.BulkPriceRequest
+	if err := c.Bind(&r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	from, err := time.Parse("2006-01-02", r.From)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid from date"})
+	}
+
+	to, err := time.Parse("2006-01-02", r.To)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid to date"})
+	}
+
+	if r.ServiceName == "" {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "service_name is required"})
+	}
+
+	if len(r.UserIDs) == 0 || len(r.UserIDs) > models.MaxPageLimit {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: fmt.Sprintf("user_ids must contain from 1 to %d users", models.MaxPageLimit),
+		})
+	}
+
+	ctx := c.Request().Context()
+
+	prices, err := h.subsService.GetPricesWithPeriod(ctx, from, to, r.UserIDs, r.ServiceName)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, models.BulkPriceResponse{Prices: prices})
+}
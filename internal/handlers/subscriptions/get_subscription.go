@@ -3,6 +3,7 @@ package subscriptions
 import (
 	"net/http"
 	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -21,9 +22,14 @@ import (
 // @Tags subscriptions
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param        id path string true "Subscription ID" format(uuid)
 // @Success      200 {object} models.Subs
 // @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse "Unauthorized"
+// @Failure      403 {object} models.ErrorResponse "Forbidden"
+// @Failure      404 {object} models.ErrorResponse "Not found"
 // @Failure      500 {object} models.ErrorResponse
 // @Router       /subscriptions/{id} [get]
 func (h *Handlers) getSubscription(c echo.Context) error {
@@ -42,7 +48,7 @@ func (h *Handlers) getSubscription(c echo.Context) error {
 
 	sub, err := h.subsService.GetSubscription(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return response.Error(c, err)
 	}
 
 	return c.JSON(http.StatusOK, sub)
@@ -1,33 +1,103 @@
 package subscriptions
 
 import (
+	"errors"
 	"net/http"
 	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+	"strconv"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
 // getSubscriptions — HTTP-обработчик для получения списка подписок.
 //
+// Параметры запроса:
+//   - ids: UUID подписок через запятую (необязательный, не больше 1000).
+//   - user_id: UUID пользователя (необязательный).
+//   - service_name: точное название сервиса (необязательный).
+//   - limit: размер страницы (необязательный, по умолчанию 100, не больше 1000;
+//     при поиске по ids — все запрошенные подписки).
+//   - offset: число пропускаемых подписок (необязательный).
+//
 // Поведение:
-//   - Вызывает сервисный слой для получения всех подписок
-//   - Возвращает массив подписок
+//   - Вызывает сервисный слой для получения страницы подписок
+//   - Возвращает массив подписок, упорядоченный по дате начала
 //
 // @Summary     Get subscriptions
 // @Description Get subscriptions from service
 // @Tags        subscriptions
 // @Accept		json
 // @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       ids query string false "Comma-separated subscription IDs (at most 1000)"
+// @Param       user_id query string false "User ID" format(uuid)
+// @Param       service_name query string false "Service name"
+// @Param       limit query int false "Page size (0 or omitted — 100, or all requested ids)" minimum(0) maximum(1000)
+// @Param       offset query int false "Number of subscriptions to skip" minimum(0)
 // @Success     200 {array} models.Subs
+// @Failure     400 {object} models.ErrorResponse "Invalid request parameters"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
 // @Failure     500 {object} models.ErrorResponse "Internal server error"
 // @Router      /subscriptions [get]
 func (h *Handlers) getSubscriptions(c echo.Context) error {
+	filter, err := parseSubsFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
 	ctx := c.Request().Context()
 
-	subs, err := h.subsService.GetAllSubscriptions(ctx)
+	subs, err := h.subsService.GetAllSubscriptions(ctx, filter)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "param ID is required"})
+		return response.Error(c, err)
 	}
 
 	return c.JSON(http.StatusOK, subs)
 }
+
+// parseSubsFilter — разбирает фильтр списка подписок из параметров запроса
+// ids, user_id, service_name, limit и offset.
+func parseSubsFilter(c echo.Context) (models.SubsFilter, error) {
+	filter := models.SubsFilter{ServiceName: c.QueryParam("service_name")}
+
+	if s := c.QueryParam("ids"); s != "" {
+		for _, v := range strings.Split(s, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(v))
+			if err != nil {
+				return filter, errors.New("invalid ids")
+			}
+			filter.IDs = append(filter.IDs, id)
+		}
+	}
+
+	if s := c.QueryParam("user_id"); s != "" {
+		userID, err := uuid.Parse(s)
+		if err != nil {
+			return filter, errors.New("invalid user_id")
+		}
+		filter.UserID = &userID
+	}
+
+	if s := c.QueryParam("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil {
+			return filter, errors.New("invalid limit")
+		}
+		filter.Limit = limit
+	}
+
+	if s := c.QueryParam("offset"); s != "" {
+		offset, err := strconv.Atoi(s)
+		if err != nil {
+			return filter, errors.New("invalid offset")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
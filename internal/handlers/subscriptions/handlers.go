@@ -12,41 +12,70 @@ import (
 
 // Service — интерфейс для работы с подписками через HTTP или другие слои.
 // Определяет основные операции: создание, чтение, обновление, получение всех подписок,
-// получение цены с периодом и удаление подписки.
+// получение цены с периодом, удаление подписки и пакетное выполнение этих операций.
 type Service interface {
 	AddSubscription(ctx context.Context, sub models.SubsDTO) (uuid.UUID, error)
 	GetSubscription(ctx context.Context, uuid uuid.UUID) (models.Subs, error)
 	EditSubscription(ctx context.Context, uuid uuid.UUID, sub models.SubsUpdateDTO) error
-	GetAllSubscriptions(ctx context.Context) ([]models.Subs, error)
+	GetAllSubscriptions(ctx context.Context, filter models.SubsFilter) ([]models.Subs, error)
+	ExportSubscriptions(ctx context.Context, filter models.SubsFilter, fn func(models.Subs) error) error
 	GetPriceWithPeriod(ctx context.Context, from, to time.Time, userID uuid.UUID, name string) (int, error)
+	GetPricesWithPeriod(ctx context.Context, from, to time.Time, userIDs []uuid.UUID, name string) (map[uuid.UUID]int, error)
 	RemoveSubscription(ctx context.Context, uuid uuid.UUID) error
+	BatchSubscriptions(ctx context.Context, req models.BatchRequest) (models.BatchResult, error)
 }
 
 // Handlers — HTTP-обработчики для работы с подписками.
-// Содержит группу маршрутов Echo и ссылку на сервис подписок.
+// Содержит группу маршрутов Echo и ссылки на сервисы подписок, их импорта, выгрузки журнала
+// и связей с провайдерами.
 type Handlers struct {
-	e           *echo.Group
-	subsService *services.SubsService
+	e                   *echo.Group
+	subsService         Service
+	importService       *services.ImportService
+	journalService      *services.JournalService
+	integrationsService *services.IntegrationsService // nil, если интеграции с провайдерами отключены
 }
 
 // New — конструктор HTTP-обработчиков.
-// Принимает Echo-группу и сервис подписок, возвращает инициализированный Handlers.
+// Принимает Echo-группу, сервисы подписок, импорта, журнала и интеграций с провайдерами,
+// возвращает инициализированный Handlers.
 func New(
 	e *echo.Group,
 	subsService *services.SubsService,
+	importService *services.ImportService,
+	journalService *services.JournalService,
+	integrationsService *services.IntegrationsService,
 ) *Handlers {
 	return &Handlers{
-		e:           e,
-		subsService: subsService,
+		e:                   e,
+		subsService:         subsService,
+		importService:       importService,
+		journalService:      journalService,
+		integrationsService: integrationsService,
 	}
 }
 
 // Setup — регистрирует маршруты Echo для работы с подписками.
-func (h *Handlers) Setup() {
-	h.e.POST("", h.addSubscription)
-	h.e.GET("/:id", h.getSubscription)
-	h.e.PATCH("/:id", h.editSubscription)
-	h.e.DELETE("/:id", h.removeSubscription)
-	h.e.GET("", h.getSubscriptions)
-	h.e.GET("/price", h.getPriceWithPeriod)
+// read, write и reports — middleware проверки разрешений на чтение, изменение подписок
+// и получение отчётов (расчёт стоимости и журнал начислений).
+func (h *Handlers) Setup(read, write, reports echo.MiddlewareFunc) {
+	h.e.POST("", h.addSubscription, write)
+	h.e.POST("/batch", h.batchSubscriptions, write)
+	h.e.GET("/:id", h.getSubscription, read)
+	h.e.PATCH("/:id", h.editSubscription, write)
+	h.e.DELETE("/:id", h.removeSubscription, write)
+	h.e.GET("", h.getSubscriptions, read)
+	h.e.POST("/search", h.searchSubscriptions, read)
+	h.e.GET("/price", h.getPriceWithPeriod, reports)
+	h.e.POST("/prices", h.getPricesWithPeriod, reports)
+	h.e.GET("/journal", h.getJournal, reports)
+	h.e.GET("/export", h.exportSubscriptions, read)
+	h.e.POST("/import", h.importSubscriptions, write)
+	h.e.GET("/import/:id", h.getImport, read)
+
+	if h.integrationsService != nil {
+		h.e.GET("/:id/links", h.getLinks, read)
+		h.e.PUT("/:id/links/:provider", h.linkSubscription, write)
+		h.e.DELETE("/:id/links/:provider", h.unlinkSubscription, write)
+	}
 }
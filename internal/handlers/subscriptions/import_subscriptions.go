@@ -0,0 +1,107 @@
+package subscriptions
+
+import (
+	"encoding/json"
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+	"online_subscription_service/internal/lib/spreadsheet"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+)
+
+// importSubscriptions — HTTP-обработчик импорта подписок из CSV- или XLSX-файла.
+//
+// Поля формы (или параметры запроса):
+//   - file: файл с заголовком в первой строке (обязательный).
+//   - format: csv или xlsx (по умолчанию — по расширению файла).
+//   - mapping: JSON с названиями колонок для полей, например {"service_name": "Сервис"}.
+//   - date_format: формат дат, например MM-YYYY (по умолчанию распознаются MM-YYYY,
+//     YYYY-MM-DD, DD.MM.YYYY, MM.YYYY и YYYY-MM).
+//   - delimiter: разделитель полей CSV (по умолчанию запятая).
+//   - dry_run: только проверить строки и вернуть ошибки.
+//
+// Поведение:
+//   - Небольшие файлы импортируются сразу: возвращается завершённая задача (200)
+//   - Большие файлы импортируются в фоне: возвращается задача в состоянии pending (202)
+//     и заголовок Location с адресом, по которому можно узнать её прогресс
+//   - Если хотя бы одна строка с ошибкой, не импортируется ни одна
+//
+// @Summary     Import subscriptions
+// @Description Import subscriptions from a CSV or XLSX file in a single transaction
+// @Tags        subscriptions
+// @Accept      mpfd
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       file formData file true "CSV or XLSX file with a header row"
+// @Param       format formData string false "File format (default — by file extension)" Enums(csv, xlsx)
+// @Param       mapping formData string false "Column names for fields as JSON, e.g. {\"service_name\": \"Service\"}"
+// @Param       date_format formData string false "Date format, e.g. MM-YYYY or DD.MM.YYYY"
+// @Param       delimiter formData string false "CSV field delimiter (default ,)"
+// @Param       dry_run formData bool false "Only validate rows"
+// @Success     200 {object} models.ImportJob "Import finished"
+// @Success     202 {object} models.ImportJob "Import started in background"
+// @Failure     400 {object} models.ErrorResponse "Invalid file or parameters"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /subscriptions/import [post]
+func (h *Handlers) importSubscriptions(c echo.Context) error {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "file is required"})
+	}
+
+	opts := models.ImportOptions{
+		Format:     c.FormValue("format"),
+		DateFormat: c.FormValue("date_format"),
+	}
+	if opts.Format == "" {
+		opts.Format = spreadsheet.FormatFromName(fh.Filename)
+	}
+
+	if s := c.FormValue("mapping"); s != "" {
+		if err := json.Unmarshal([]byte(s), &opts.Mapping); err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid mapping"})
+		}
+	}
+
+	if s := c.FormValue("delimiter"); s != "" {
+		r, size := utf8.DecodeRuneInString(s)
+		if r == utf8.RuneError || size != len(s) {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "delimiter must be a single character"})
+		}
+		opts.Delimiter = r
+	}
+
+	if s := c.FormValue("dry_run"); s != "" {
+		dryRun, err := strconv.ParseBool(s)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid dry_run"})
+		}
+		opts.DryRun = dryRun
+	}
+
+	file, err := fh.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "failed to read file"})
+	}
+	defer file.Close()
+
+	ctx := c.Request().Context()
+
+	job, err := h.importService.ImportSubscriptions(ctx, file, opts)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	if job.FinishedAt == nil {
+		c.Response().Header().Set(echo.HeaderLocation, c.Request().URL.Path+"/"+job.ID.String())
+		return c.JSON(http.StatusAccepted, job)
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,56 @@
+package subscriptions
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// linkSubscription — HTTP-обработчик для связывания подписки с её идентификатором у провайдера.
+//
+// Поведение:
+//   - События провайдера с этим идентификатором подписки применяются к подписке
+//   - Прежний идентификатор подписки у того же провайдера заменяется
+//   - Если идентификатор уже связан с другой подпиской, возвращает 409
+//
+// @Summary     Link subscription to provider
+// @Description Link subscription to its ID at a billing provider so that provider webhooks update it
+// @Tags        subscriptions
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       id path string true "Subscription ID" format(uuid)
+// @Param       provider path string true "Provider name from configuration"
+// @Param       request body models.LinkSubscriptionRequest true "Provider subscription ID"
+// @Success     200 {object} models.SubscriptionLink
+// @Failure     400 {object} models.ErrorResponse "Invalid request"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     404 {object} models.ErrorResponse "Subscription or provider not found"
+// @Failure     409 {object} models.ErrorResponse "Provider subscription ID is linked to another subscription"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /subscriptions/{id}/links/{provider} [put]
+func (h *Handlers) linkSubscription(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	var req models.LinkSubscriptionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	link, err := h.integrationsService.LinkSubscription(ctx, id, c.Param("provider"), req.ExternalID)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, link)
+}
@@ -3,6 +3,7 @@ package subscriptions
 import (
 	"net/http"
 	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -25,9 +26,14 @@ type DeleteSubscriptionResponse struct {
 // @Tags        subscriptions
 // @Accept		json
 // @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
 // @Param       id path string true "Subscription ID" format(uuid)
 // @Success     200 {string} DeleteSubscriptionResponse
 // @Failure     400 {object} models.ErrorResponse "Invalid ID parameter"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     404 {object} models.ErrorResponse "Not found"
 // @Failure     500 {object} models.ErrorResponse "Internal server error"
 // @Router      /subscriptions/{id} [delete]
 func (h *Handlers) removeSubscription(c echo.Context) error {
@@ -45,7 +51,7 @@ func (h *Handlers) removeSubscription(c echo.Context) error {
 	ctx := c.Request().Context()
 
 	if err := h.subsService.RemoveSubscription(ctx, id); err != nil {
-		return c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return response.Error(c, err)
 	}
 
 	return c.JSON(http.StatusOK, DeleteSubscriptionResponse{Status: "Ok"})
@@ -0,0 +1,55 @@
+package subscriptions
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// searchSubscriptions — HTTP-обработчик поиска подписок по условиям из тела запроса.
+//
+// Тело запроса:
+//   - ids: UUID подписок (не больше 1000).
+//   - user_ids: UUID пользователей.
+//   - service_name, limit, offset: как у списка подписок.
+//
+// Поведение:
+//   - Выбирает подписки одним запросом к БД, как GET /subscriptions, но списки UUID
+//     не ограничены длиной URL
+//   - Чужие подписки из списка ids обычному пользователю не возвращаются
+//
+// @Summary     Search subscriptions
+// @Description Get many subscriptions by IDs and other conditions in one request
+// @Tags        subscriptions
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       request body models.SubsSearchRequest true "Search conditions"
+// @Success     200 {array} models.Subs
+// @Failure     400 {object} models.ErrorResponse "Invalid request parameters"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /subscriptions/search [post]
+func (h *Handlers) searchSubscriptions(c echo.Context) error {
+	var r models.SubsSearchRequest
+	if err := c.Bind(&r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	subs, err := h.subsService.GetAllSubscriptions(ctx, r.ToSubsFilter())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	if subs == nil {
+		subs = []models.Subs{}
+	}
+
+	return c.JSON(http.StatusOK, subs)
+}
@@ -0,0 +1,46 @@
+package subscriptions
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type UnlinkSubscriptionResponse struct {
+	Status string `json:"status"`
+}
+
+// unlinkSubscription — HTTP-обработчик для удаления связи подписки с провайдером.
+//
+// @Summary     Unlink subscription from provider
+// @Description Remove the link so that provider webhooks no longer update the subscription
+// @Tags        subscriptions
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       id path string true "Subscription ID" format(uuid)
+// @Param       provider path string true "Provider name from configuration"
+// @Success     200 {object} UnlinkSubscriptionResponse
+// @Failure     400 {object} models.ErrorResponse "Invalid ID parameter"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     404 {object} models.ErrorResponse "Not found"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /subscriptions/{id}/links/{provider} [delete]
+func (h *Handlers) unlinkSubscription(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	if err := h.integrationsService.UnlinkSubscription(ctx, id, c.Param("provider")); err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, UnlinkSubscriptionResponse{Status: "Ok"})
+}
@@ -0,0 +1,42 @@
+package tenants
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// createTenant — HTTP-обработчик для создания организации.
+//
+// CreateTenant godoc
+// @Summary Create tenant
+// @Description Create tenant (platform admin only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param        request body models.CreateTenantRequest true "Tenant data"
+// @Success      201 {object} models.Tenant
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse "Unauthorized"
+// @Failure      403 {object} models.ErrorResponse "Forbidden"
+// @Failure      409 {object} models.ErrorResponse "Tenant already exists"
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /tenants [post]
+func (h *Handlers) createTenant(c echo.Context) error {
+	r := new(models.CreateTenantRequest)
+	ctx := c.Request().Context()
+
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	tenant, err := h.tenantsService.CreateTenant(ctx, *r)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, tenant)
+}
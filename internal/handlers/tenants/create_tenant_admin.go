@@ -0,0 +1,49 @@
+package tenants
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// createTenantAdmin — HTTP-обработчик для создания учётной записи администратора организации.
+//
+// @Summary     Create tenant admin
+// @Description Create local admin account in tenant (platform admin only)
+// @Tags        tenants
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Tenant ID" format(uuid)
+// @Param       request body models.CreateTenantAdminRequest true "Credentials"
+// @Success     201 {object} models.User
+// @Failure     400 {object} models.ErrorResponse "Invalid request"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     404 {object} models.ErrorResponse "Tenant not found"
+// @Failure     409 {object} models.ErrorResponse "User already exists"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /tenants/{id}/admins [post]
+func (h *Handlers) createTenantAdmin(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	r := new(models.CreateTenantAdminRequest)
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	user, err := h.usersService.CreateTenantAdmin(ctx, id, *r)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, user)
+}
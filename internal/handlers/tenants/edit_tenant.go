@@ -0,0 +1,54 @@
+package tenants
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type EditTenantResponse struct {
+	Status string `json:"status"`
+}
+
+// editTenant — HTTP-обработчик для изменения настроек организации.
+//
+// EditTenant godoc
+// @Summary Edit tenant
+// @Description Edit tenant settings (tenant or platform admin)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param        id path string true "Tenant ID" format(uuid)
+// @Param        request body models.EditTenantRequest true "Tenant settings"
+// @Success      200 {object} EditTenantResponse
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse "Unauthorized"
+// @Failure      403 {object} models.ErrorResponse "Forbidden"
+// @Failure      404 {object} models.ErrorResponse "Not found"
+// @Failure      409 {object} models.ErrorResponse "Tenant already exists"
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /tenants/{id} [patch]
+func (h *Handlers) editTenant(c echo.Context) error {
+	r := new(models.EditTenantRequest)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	if err := h.tenantsService.EditTenant(ctx, id, *r); err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, EditTenantResponse{Status: "Ok"})
+}
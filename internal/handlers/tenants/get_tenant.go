@@ -0,0 +1,41 @@
+package tenants
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// getTenant — HTTP-обработчик для получения организации и её настроек.
+//
+// @Summary     Get tenant
+// @Description Get tenant settings
+// @Tags        tenants
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Tenant ID" format(uuid)
+// @Success     200 {object} models.Tenant
+// @Failure     400 {object} models.ErrorResponse "Invalid ID parameter"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     404 {object} models.ErrorResponse "Not found"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /tenants/{id} [get]
+func (h *Handlers) getTenant(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	tenant, err := h.tenantsService.GetTenant(ctx, id)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, tenant)
+}
@@ -0,0 +1,31 @@
+package tenants
+
+import (
+	"net/http"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// getTenants — HTTP-обработчик для получения списка организаций.
+// Администратор платформы получает все организации, остальные — только свою.
+//
+// @Summary     List tenants
+// @Description List tenants visible to the caller
+// @Tags        tenants
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {array} models.Tenant
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /tenants [get]
+func (h *Handlers) getTenants(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenants, err := h.tenantsService.ListTenants(ctx)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, tenants)
+}
@@ -0,0 +1,52 @@
+package tenants
+
+import (
+	"context"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Service — интерфейс управления организациями.
+// Определяет создание, получение списка, чтение и изменение настроек организаций.
+type Service interface {
+	CreateTenant(ctx context.Context, req models.CreateTenantRequest) (models.Tenant, error)
+	ListTenants(ctx context.Context) ([]models.Tenant, error)
+	GetTenant(ctx context.Context, tenantID uuid.UUID) (models.Tenant, error)
+	EditTenant(ctx context.Context, tenantID uuid.UUID, req models.EditTenantRequest) error
+}
+
+// Handlers — HTTP-обработчики для управления организациями.
+// Содержит группу маршрутов Echo и ссылки на сервисы организаций и учётных записей.
+type Handlers struct {
+	e              *echo.Group
+	tenantsService *services.TenantsService
+	usersService   *services.UsersService // nil, если локальные учётные записи отключены
+}
+
+// New — конструктор HTTP-обработчиков организаций.
+func New(
+	e *echo.Group,
+	tenantsService *services.TenantsService,
+	usersService *services.UsersService,
+) *Handlers {
+	return &Handlers{
+		e:              e,
+		tenantsService: tenantsService,
+		usersService:   usersService,
+	}
+}
+
+// Setup — регистрирует маршруты Echo для управления организациями.
+func (h *Handlers) Setup() {
+	h.e.POST("", h.createTenant)
+	h.e.GET("", h.getTenants)
+	h.e.GET("/:id", h.getTenant)
+	h.e.PATCH("/:id", h.editTenant)
+
+	if h.usersService != nil {
+		h.e.POST("/:id/admins", h.createTenantAdmin)
+	}
+}
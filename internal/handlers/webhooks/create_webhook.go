@@ -0,0 +1,43 @@
+package webhooks
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// createWebhook — HTTP-обработчик для регистрации вебхука.
+// Возвращает созданный вебхук вместе с секретом подписи, который больше нигде не отображается.
+//
+// CreateWebhook godoc
+// @Summary Create webhook
+// @Description Register webhook endpoint with event type filter and signing secret (admin or webhooks:manage)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param        request body models.CreateWebhookRequest true "Webhook data"
+// @Success      201 {object} models.CreatedWebhook
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse "Unauthorized"
+// @Failure      403 {object} models.ErrorResponse "Forbidden"
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /webhooks [post]
+func (h *Handlers) createWebhook(c echo.Context) error {
+	r := new(models.CreateWebhookRequest)
+	ctx := c.Request().Context()
+
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	hook, err := h.webhooksService.CreateWebhook(ctx, *r)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, hook)
+}
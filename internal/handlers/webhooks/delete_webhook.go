@@ -0,0 +1,45 @@
+package webhooks
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type DeleteWebhookResponse struct {
+	Status string `json:"status"`
+}
+
+// deleteWebhook — HTTP-обработчик для удаления вебхука по ID вместе с журналом его доставок.
+//
+// @Summary     Delete webhook
+// @Description Delete webhook and its delivery log (admin or webhooks:manage)
+// @Tags        webhooks
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       id path string true "Webhook ID" format(uuid)
+// @Success     200 {object} DeleteWebhookResponse
+// @Failure     400 {object} models.ErrorResponse "Invalid ID parameter"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     404 {object} models.ErrorResponse "Not found"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /webhooks/{id} [delete]
+func (h *Handlers) deleteWebhook(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	if err := h.webhooksService.DeleteWebhook(ctx, id); err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DeleteWebhookResponse{Status: "Ok"})
+}
@@ -0,0 +1,51 @@
+package webhooks
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// editWebhook — HTTP-обработчик для изменения вебхука.
+// Изменяются только переданные поля; новый секрет подписи задаётся полем secret.
+//
+// EditWebhook godoc
+// @Summary Edit webhook
+// @Description Change webhook URL, event types, secret, description or state (admin or webhooks:manage)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param        id path string true "Webhook ID" format(uuid)
+// @Param        request body models.EditWebhookRequest true "Webhook fields to change"
+// @Success      200 {object} models.Webhook
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse "Unauthorized"
+// @Failure      403 {object} models.ErrorResponse "Forbidden"
+// @Failure      404 {object} models.ErrorResponse "Not found"
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /webhooks/{id} [patch]
+func (h *Handlers) editWebhook(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	r := new(models.EditWebhookRequest)
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	hook, err := h.webhooksService.EditWebhook(ctx, id, *r)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, hook)
+}
@@ -0,0 +1,84 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// getDeliveries — HTTP-обработчик для получения журнала доставок вебхука.
+//
+// Параметры запроса:
+//   - status: pending, succeeded или dead (необязательный).
+//   - limit: размер страницы (необязательный, по умолчанию 100).
+//   - offset: число пропускаемых доставок (необязательный).
+//
+// Поведение:
+//   - Возвращает доставки, начиная с самых новых, с результатом последней попытки
+//
+// @Summary     List webhook deliveries
+// @Description Get webhook delivery log (admin or webhooks:manage)
+// @Tags        webhooks
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       id path string true "Webhook ID" format(uuid)
+// @Param       status query string false "Delivery status" Enums(pending, succeeded, dead)
+// @Param       limit query int false "Page size (0 or omitted — 100)" minimum(0) maximum(1000)
+// @Param       offset query int false "Number of deliveries to skip" minimum(0)
+// @Success     200 {array} models.WebhookDelivery
+// @Failure     400 {object} models.ErrorResponse "Invalid request parameters"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     404 {object} models.ErrorResponse "Not found"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /webhooks/{id}/deliveries [get]
+func (h *Handlers) getDeliveries(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	filter, err := parseDeliveryFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	deliveries, err := h.webhooksService.ListDeliveries(ctx, id, filter)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+// parseDeliveryFilter — разбирает фильтр журнала доставок из параметров запроса
+// status, limit и offset.
+func parseDeliveryFilter(c echo.Context) (models.DeliveryFilter, error) {
+	filter := models.DeliveryFilter{Status: models.DeliveryStatus(c.QueryParam("status"))}
+
+	if s := c.QueryParam("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil {
+			return filter, errors.New("invalid limit")
+		}
+		filter.Limit = limit
+	}
+
+	if s := c.QueryParam("offset"); s != "" {
+		offset, err := strconv.Atoi(s)
+		if err != nil {
+			return filter, errors.New("invalid offset")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,33 @@
+package webhooks
+
+import (
+	"net/http"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// getWebhooks — HTTP-обработчик для получения списка вебхуков организации.
+// Секреты подписи не возвращаются.
+//
+// @Summary     List webhooks
+// @Description List webhooks (admin or webhooks:manage)
+// @Tags        webhooks
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Success     200 {array} models.Webhook
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /webhooks [get]
+func (h *Handlers) getWebhooks(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	hooks, err := h.webhooksService.ListWebhooks(ctx)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusOK, hooks)
+}
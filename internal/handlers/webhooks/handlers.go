@@ -0,0 +1,52 @@
+package webhooks
+
+import (
+	"context"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Service — интерфейс управления вебхуками.
+// Определяет регистрацию, получение, изменение и удаление вебхуков,
+// чтение журнала доставок и повторную доставку.
+type Service interface {
+	CreateWebhook(ctx context.Context, req models.CreateWebhookRequest) (models.CreatedWebhook, error)
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (models.Webhook, error)
+	EditWebhook(ctx context.Context, id uuid.UUID, req models.EditWebhookRequest) (models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, id uuid.UUID, filter models.DeliveryFilter) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id, deliveryID uuid.UUID) (models.WebhookDelivery, error)
+}
+
+// Handlers — HTTP-обработчики для управления вебхуками.
+// Содержит группу маршрутов Echo и ссылку на сервис вебхуков.
+type Handlers struct {
+	e               *echo.Group
+	webhooksService *services.WebhooksService
+}
+
+// New — конструктор HTTP-обработчиков вебхуков.
+func New(
+	e *echo.Group,
+	webhooksService *services.WebhooksService,
+) *Handlers {
+	return &Handlers{
+		e:               e,
+		webhooksService: webhooksService,
+	}
+}
+
+// Setup — регистрирует маршруты Echo для управления вебхуками.
+func (h *Handlers) Setup() {
+	h.e.POST("", h.createWebhook)
+	h.e.GET("", h.getWebhooks)
+	h.e.GET("/:id", h.getWebhook)
+	h.e.PATCH("/:id", h.editWebhook)
+	h.e.DELETE("/:id", h.deleteWebhook)
+	h.e.GET("/:id/deliveries", h.getDeliveries)
+	h.e.POST("/:id/deliveries/:delivery_id/redeliver", h.redeliver)
+}
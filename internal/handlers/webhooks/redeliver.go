@@ -0,0 +1,50 @@
+package webhooks
+
+import (
+	"net/http"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/handlers/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// redeliver — HTTP-обработчик для повторной доставки события на вебхук.
+// Доставка в любом состоянии возвращается в очередь с новым набором попыток
+// и отправляется в фоне; результат виден в журнале доставок.
+//
+// @Summary     Redeliver webhook delivery
+// @Description Queue delivery for immediate redelivery with a fresh set of attempts (admin or webhooks:manage)
+// @Tags        webhooks
+// @Produce     json
+// @Security    BearerAuth
+// @Security    APIKeyAuth
+// @Param       id path string true "Webhook ID" format(uuid)
+// @Param       delivery_id path string true "Delivery ID" format(uuid)
+// @Success     202 {object} models.WebhookDelivery
+// @Failure     400 {object} models.ErrorResponse "Invalid ID parameter"
+// @Failure     401 {object} models.ErrorResponse "Unauthorized"
+// @Failure     403 {object} models.ErrorResponse "Forbidden"
+// @Failure     404 {object} models.ErrorResponse "Not found"
+// @Failure     500 {object} models.ErrorResponse "Internal server error"
+// @Router      /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *Handlers) redeliver(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	delivery, err := h.webhooksService.Redeliver(ctx, id, deliveryID)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, delivery)
+}
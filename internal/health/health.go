@@ -0,0 +1,131 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/logger"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// checkTimeout — максимальное время выполнения одной проверки.
+const checkTimeout = 2 * time.Second
+
+// ErrShuttingDown — сервис завершает работу и больше не принимает новые запросы.
+var ErrShuttingDown = errors.New("service is shutting down")
+
+const (
+	// errCheckFailed — ошибка проверки в отчёте; подробности пишутся только в лог.
+	errCheckFailed = "check failed"
+	// errCheckTimeout — ошибка проверки, не завершившейся за отведённое время.
+	errCheckTimeout = "check timed out"
+)
+
+// CheckFunc — проверка состояния компонента; возвращает ошибку, если компонент не готов.
+type CheckFunc func(ctx context.Context) error
+
+// check — зарегистрированная проверка.
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker — набор проверок готовности сервиса.
+// Компоненты (БД, миграции, фоновые обработчики) регистрируют свои проверки,
+// а эндпоинт /readyz выполняет их параллельно и возвращает отчёт.
+// После вызова SetShuttingDown сервис считается неготовым независимо от проверок.
+// Отчёт отдаётся без аутентификации, поэтому текст ошибок проверок в него не попадает:
+// он пишется в лог, а в отчёте остаётся только признак сбоя или превышения времени.
+type Checker struct {
+	mu           sync.RWMutex
+	checks       []check
+	shuttingDown atomic.Bool
+	timeout      time.Duration
+}
+
+// New — конструктор Checker.
+func New() *Checker {
+	return &Checker{timeout: checkTimeout}
+}
+
+// Register — добавляет проверку готовности с указанным именем.
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// SetShuttingDown — переводит сервис в состояние завершения работы,
+// чтобы балансировщик перестал направлять на него новые запросы.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready — выполняет все проверки параллельно и возвращает отчёт.
+// Второе значение равно true, если сервис готов принимать запросы.
+func (c *Checker) Ready(ctx context.Context) (models.HealthReport, bool) {
+	c.mu.RLock()
+	checks := make([]check, len(c.checks))
+	copy(checks, c.checks)
+	c.mu.RUnlock()
+
+	results := make([]models.HealthCheck, len(checks))
+
+	var wg sync.WaitGroup
+	for i, ch := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, ch, c.timeout)
+		}()
+	}
+	wg.Wait()
+
+	if c.shuttingDown.Load() {
+		results = append(results, models.HealthCheck{
+			Name:   "shutdown",
+			Status: models.HealthStatusFail,
+			Error:  ErrShuttingDown.Error(),
+		})
+	}
+
+	report := models.HealthReport{Status: models.HealthStatusOK, Checks: results}
+	for _, r := range results {
+		if r.Status != models.HealthStatusOK {
+			report.Status = models.HealthStatusFail
+			break
+		}
+	}
+
+	return report, report.Status == models.HealthStatusOK
+}
+
+// run — выполняет одну проверку с ограничением по времени и замеряет её длительность.
+// Ошибка проверки логируется и заменяется в результате на errCheckFailed или errCheckTimeout.
+func run(ctx context.Context, ch check, timeout time.Duration) models.HealthCheck {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ch.fn(checkCtx)
+
+	result := models.HealthCheck{
+		Name:      ch.name,
+		Status:    models.HealthStatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		logger.FromContext(ctx).Warn("readiness check failed", slog.String("check", ch.name), slog.String("error", err.Error()))
+		result.Status = models.HealthStatusFail
+		result.Error = errCheckFailed
+		if errors.Is(err, context.DeadlineExceeded) && checkCtx.Err() != nil {
+			result.Error = errCheckTimeout
+		}
+	}
+
+	return result
+}
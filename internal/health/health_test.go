@@ -0,0 +1,122 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"online_subscription_service/internal/domain/models"
+	"testing"
+	"time"
+)
+
+func TestCheckerReady(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	failing := func(context.Context) error { return errors.New("dial tcp 10.0.0.5:5432: connection refused") }
+	// hanging — проверка, которая завершается только по истечении своего контекста.
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name         string
+		checks       map[string]CheckFunc
+		shuttingDown bool
+		wantReady    bool
+		wantChecks   map[string]string // имя проверки -> ошибка в отчёте
+	}{
+		{name: "no checks", wantReady: true, wantChecks: map[string]string{}},
+		{
+			name:       "all pass",
+			checks:     map[string]CheckFunc{"database": ok, "migrations": ok},
+			wantReady:  true,
+			wantChecks: map[string]string{"database": "", "migrations": ""},
+		},
+		{
+			name:       "failure hides details",
+			checks:     map[string]CheckFunc{"database": failing, "migrations": ok},
+			wantChecks: map[string]string{"database": errCheckFailed, "migrations": ""},
+		},
+		{
+			name:       "timeout",
+			checks:     map[string]CheckFunc{"database": hanging, "migrations": ok},
+			wantChecks: map[string]string{"database": errCheckTimeout, "migrations": ""},
+		},
+		{
+			name:         "shutting down",
+			checks:       map[string]CheckFunc{"database": ok},
+			shuttingDown: true,
+			wantChecks:   map[string]string{"database": "", "shutdown": ErrShuttingDown.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			c.timeout = 50 * time.Millisecond
+			for name, fn := range tt.checks {
+				c.Register(name, fn)
+			}
+			if tt.shuttingDown {
+				c.SetShuttingDown()
+			}
+
+			report, ready := c.Ready(context.Background())
+			if ready != tt.wantReady {
+				t.Errorf("Ready() ready = %v, want %v", ready, tt.wantReady)
+			}
+			wantStatus := models.HealthStatusFail
+			if tt.wantReady {
+				wantStatus = models.HealthStatusOK
+			}
+			if report.Status != wantStatus {
+				t.Errorf("report status = %q, want %q", report.Status, wantStatus)
+			}
+
+			if len(report.Checks) != len(tt.wantChecks) {
+				t.Fatalf("report checks = %+v, want %v", report.Checks, tt.wantChecks)
+			}
+			for _, r := range report.Checks {
+				wantErr, found := tt.wantChecks[r.Name]
+				if !found {
+					t.Errorf("unexpected check %q in report", r.Name)
+					continue
+				}
+				if r.Error != wantErr || (r.Status == models.HealthStatusOK) != (wantErr == "") {
+					t.Errorf("check %q = %+v, want error %q", r.Name, r, wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckerReadyParallel(t *testing.T) {
+	c := New()
+	c.timeout = time.Second
+
+	// Каждая проверка ждёт, пока запустятся все остальные: последовательное выполнение
+	// закончилось бы по таймауту каждой из них.
+	const n = 3
+	started := make(chan struct{}, n)
+	for range n {
+		c.Register("barrier", func(ctx context.Context) error {
+			started <- struct{}{}
+			for len(started) < n {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Millisecond):
+				}
+			}
+			return nil
+		})
+	}
+
+	begin := time.Now()
+	report, ready := c.Ready(context.Background())
+	if !ready {
+		t.Fatalf("Ready() = %+v, want all checks to pass", report)
+	}
+	if elapsed := time.Since(begin); elapsed >= c.timeout {
+		t.Errorf("Ready() took %s, want checks to run in parallel", elapsed)
+	}
+}
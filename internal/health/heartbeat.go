@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrNotRunning — фоновый обработчик не запущен или уже остановлен.
+var ErrNotRunning = errors.New("worker is not running")
+
+// Heartbeat — признак работы фонового обработчика для проверки готовности.
+// Обработчик отмечает каждый проход методом Beat; Check считает его неготовым,
+// если он не запущен или не завершал проходов без ошибки дольше maxAge —
+// например, если цикл завис или база данных недоступна.
+type Heartbeat struct {
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	running bool
+	last    time.Time // время последнего прохода без ошибки
+	lastErr error     // ошибка последнего прохода
+}
+
+// NewHeartbeat — конструктор Heartbeat.
+func NewHeartbeat(maxAge time.Duration) *Heartbeat {
+	return &Heartbeat{maxAge: maxAge, now: time.Now}
+}
+
+// Start — отмечает запуск обработчика; отсчёт maxAge начинается с запуска.
+func (h *Heartbeat) Start() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.running, h.last, h.lastErr = true, h.now(), nil
+}
+
+// Stop — отмечает остановку обработчика.
+func (h *Heartbeat) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.running = false
+}
+
+// Beat — отмечает завершение прохода обработчика с ошибкой err (nil — без ошибки).
+func (h *Heartbeat) Beat(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastErr = err
+	if err == nil {
+		h.last = h.now()
+	}
+}
+
+// Check — проверка готовности обработчика для Checker.Register.
+func (h *Heartbeat) Check(context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.running {
+		return ErrNotRunning
+	}
+
+	age := h.now().Sub(h.last)
+	if age <= h.maxAge {
+		return nil
+	}
+	if h.lastErr != nil {
+		return fmt.Errorf("no successful pass for %s: %w", age.Round(time.Second), h.lastErr)
+	}
+	return fmt.Errorf("no successful pass for %s", age.Round(time.Second))
+}
@@ -0,0 +1,94 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatCheck(t *testing.T) {
+	errDB := errors.New("database is unavailable")
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		run     func(h *Heartbeat, clock *time.Time)
+		wantErr bool
+		wantIs  error
+	}{
+		{
+			name:    "not started",
+			run:     func(*Heartbeat, *time.Time) {},
+			wantErr: true,
+			wantIs:  ErrNotRunning,
+		},
+		{
+			name: "just started",
+			run:  func(h *Heartbeat, _ *time.Time) { h.Start() },
+		},
+		{
+			name: "recent pass",
+			run: func(h *Heartbeat, clock *time.Time) {
+				h.Start()
+				*clock = clock.Add(50 * time.Second)
+				h.Beat(nil)
+				*clock = clock.Add(50 * time.Second)
+			},
+		},
+		{
+			name: "stale",
+			run: func(h *Heartbeat, clock *time.Time) {
+				h.Start()
+				*clock = clock.Add(2 * time.Minute)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failing passes",
+			run: func(h *Heartbeat, clock *time.Time) {
+				h.Start()
+				*clock = clock.Add(2 * time.Minute)
+				h.Beat(errDB)
+			},
+			wantErr: true,
+			wantIs:  errDB,
+		},
+		{
+			name: "recent error within max age",
+			run: func(h *Heartbeat, clock *time.Time) {
+				h.Start()
+				*clock = clock.Add(10 * time.Second)
+				h.Beat(errDB)
+			},
+		},
+		{
+			name: "stopped",
+			run: func(h *Heartbeat, _ *time.Time) {
+				h.Start()
+				h.Beat(nil)
+				h.Stop()
+			},
+			wantErr: true,
+			wantIs:  ErrNotRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := start
+			h := NewHeartbeat(time.Minute)
+			h.now = func() time.Time { return clock }
+
+			tt.run(h, &clock)
+
+			err := h.Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("Check() error = %v, want %v", err, tt.wantIs)
+			}
+		})
+	}
+}
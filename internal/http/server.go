@@ -2,18 +2,22 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	nethttp "net/http"
 	"online_subscription_service/internal/config"
 
 	"github.com/labstack/echo/v4"
 )
 
 // Server — структура, описывающая HTTP-сервер.
-// Включает контекст для graceful shutdown, порт, хост и экземпляр Echo.
+// Включает контекст для graceful shutdown, адрес (хост:порт) и экземпляр Echo.
 type Server struct {
-	ctx context.Context
-	cfg *config.Config
-	e   *echo.Echo
+	ctx  context.Context
+	cfg  *config.Config
+	e    *echo.Echo
+	addr string
 }
 
 func New(
@@ -22,23 +26,55 @@ func New(
 	e *echo.Echo,
 ) *Server {
 	return &Server{
-		ctx: ctx,
-		cfg: cfg,
-		e:   e,
+		ctx:  ctx,
+		cfg:  cfg,
+		e:    e,
+		addr: fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
 	}
 }
 
-// MustRun — запускает HTTP-сервер и завершает приложение с фатальной ошибкой, если запуск невозможен.
-// Формирует строку адреса (хост:порт) и запускает Echo.
-func (s *Server) MustRun() {
-	host := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
-	s.e.Logger.Fatal(s.e.Start(host))
+// NewMetrics — конструктор административного сервера, отдающего метрики
+// на отдельном порту (metrics.port) того же хоста.
+func NewMetrics(
+	ctx context.Context,
+	cfg *config.Config,
+	e *echo.Echo,
+) *Server {
+	e.HideBanner = true
+
+	return &Server{
+		ctx:  ctx,
+		cfg:  cfg,
+		e:    e,
+		addr: fmt.Sprintf("%s:%d", cfg.Host, cfg.Metrics.Port),
+	}
+}
+
+// Start — открывает порт и запускает обработку запросов в фоне.
+// Ошибка открытия порта возвращается сразу; ошибка, из-за которой сервер
+// перестал обслуживать запросы не по команде Stop, передаётся в onError.
+func (s *Server) Start(onError func(error)) error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+	s.e.Listener = ln
+
+	go func() {
+		if err := s.e.Start(s.addr); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+			onError(fmt.Errorf("http server on %s: %w", s.addr, err))
+		}
+	}()
+
+	return nil
 }
 
-// Stop — корректно завершает работу сервера (graceful shutdown) с использованием контекста.
+// Stop — корректно завершает работу сервера (graceful shutdown) с использованием контекста:
+// перестаёт принимать новые подключения и дожидается завершения текущих запросов.
 // Вызывается при завершении приложения или получении сигнала завершения.
-func (s *Server) Stop(ctx context.Context) {
+func (s *Server) Stop(ctx context.Context) error {
 	if err := s.e.Shutdown(ctx); err != nil {
-		s.e.Logger.Error("Can't stop server")
+		return fmt.Errorf("failed to shutdown http server: %w", err)
 	}
+	return nil
 }
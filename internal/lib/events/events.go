@@ -0,0 +1,130 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"os"
+	"sync"
+)
+
+// Публикаторы событий.
+const (
+	PublisherNATS   = "nats"
+	PublisherFile   = "file"
+	PublisherStdout = "stdout"
+	PublisherNone   = "none" // события только доставляются на вебхуки
+)
+
+// Publisher — публикатор событий об изменении подписок.
+// Publish возвращает nil, только если событие принято получателем: иначе оно
+// будет опубликовано повторно. События одной подписки публикуются по очереди
+// в порядке записи, поэтому Publish не должен менять их порядок.
+type Publisher interface {
+	Publish(ctx context.Context, event models.Event) error
+	// Close — отправляет накопленные события и освобождает ресурсы публикатора.
+	Close(ctx context.Context) error
+}
+
+// New — создаёт публикатор по конфигурации.
+func New(cfg config.OutboxConfig) (Publisher, error) {
+	switch cfg.Publisher {
+	case PublisherNATS:
+		return NewNATS(cfg.NATS)
+	case PublisherFile:
+		return NewFile(cfg.File)
+	case PublisherStdout:
+		return NewWriter(os.Stdout), nil
+	case PublisherNone:
+		return Fanout(), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q", cfg.Publisher)
+	}
+}
+
+// fanoutPublisher — публикатор, передающий событие нескольким публикаторам по очереди.
+type fanoutPublisher []Publisher
+
+// Fanout — создаёт публикатор, публикующий событие через каждый из publishers.
+// Событие считается опубликованным, только если его приняли все публикаторы;
+// иначе оно публикуется повторно всеми, поэтому каждый из них должен распознавать повторы.
+// Без публикаторов событие считается опубликованным сразу.
+func Fanout(publishers ...Publisher) Publisher {
+	return fanoutPublisher(publishers)
+}
+
+// Publish — публикует событие через каждый публикатор, останавливаясь на первой ошибке.
+func (f fanoutPublisher) Publish(ctx context.Context, event models.Event) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close — закрывает все публикаторы и возвращает их ошибки.
+func (f fanoutPublisher) Close(ctx context.Context) error {
+	var errs []error
+	for _, p := range f {
+		errs = append(errs, p.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// writerPublisher — публикатор, записывающий события в поток по одному
+// JSON-объекту на строку (JSON Lines).
+type writerPublisher struct {
+	mu    sync.Mutex
+	enc   *json.Encoder
+	sync  func() error // сбрасывает записанное на диск, если задана
+	close func() error // закрывает поток, если задана
+}
+
+// NewWriter — создаёт публикатор, записывающий события в w.
+// w не закрывается при закрытии публикатора.
+func NewWriter(w io.Writer) Publisher {
+	return &writerPublisher{enc: json.NewEncoder(w)}
+}
+
+// NewFile — создаёт публикатор, дописывающий события в файл path в формате JSON Lines.
+// Каждое событие сбрасывается на диск до возврата из Publish, чтобы не потеряться при сбое.
+func NewFile(path string) (Publisher, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events file: %w", err)
+	}
+	return &writerPublisher{enc: json.NewEncoder(f), sync: f.Sync, close: f.Close}, nil
+}
+
+// Publish — записывает событие одной строкой.
+func (p *writerPublisher) Publish(_ context.Context, event models.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.enc.Encode(event); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	if p.sync != nil {
+		if err := p.sync(); err != nil {
+			return fmt.Errorf("failed to sync events file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Close — закрывает файл событий; поток, переданный в NewWriter, не закрывается.
+func (p *writerPublisher) Close(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.close == nil {
+		return nil
+	}
+	return p.close()
+}
@@ -0,0 +1,104 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// natsPublisher — публикатор событий в NATS.
+type natsPublisher struct {
+	nc      *nats.Conn
+	js      jetstream.JetStream // nil — публикация без JetStream
+	prefix  string
+	timeout time.Duration
+}
+
+// NewNATS — подключается к NATS и создаёт публикатор событий.
+// Если сервер недоступен при запуске, подключение повторяется в фоне,
+// а публикации до подключения завершаются ошибкой и повторяются позже.
+//
+// Без JetStream событие считается опубликованным, когда сервер подтвердил получение
+// всех отправленных ему сообщений (PING/PONG), но не хранит его для отсутствующих
+// подписчиков. С JetStream событие сохраняется в потоке, захватывающем subject событий,
+// а повторно опубликованное событие отбрасывается по заголовку Nats-Msg-Id
+// в пределах окна дедупликации потока.
+func NewNATS(cfg config.NATSConfig) (Publisher, error) {
+	nc, err := nats.Connect(cfg.URL,
+		nats.Name("online_subscription_service outbox"),
+		nats.Timeout(cfg.Timeout),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+
+	p := &natsPublisher{nc: nc, prefix: cfg.SubjectPrefix, timeout: cfg.Timeout}
+	if cfg.JetStream {
+		if p.js, err = jetstream.New(nc); err != nil {
+			nc.Close()
+			return nil, fmt.Errorf("failed to init jetstream: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+// subject — subject, в который публикуется событие типа eventType.
+func subject(prefix string, eventType models.EventType) string {
+	if prefix == "" {
+		return string(eventType)
+	}
+	return prefix + "." + string(eventType)
+}
+
+// Publish — публикует событие и ждёт подтверждения сервера.
+func (p *natsPublisher) Publish(ctx context.Context, event models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	msg := nats.NewMsg(subject(p.prefix, event.Type))
+	msg.Header.Set(jetstream.MsgIDHeader, event.ID.String())
+	msg.Header.Set("Content-Type", "application/json")
+	msg.Data = data
+
+	// Пока подключение не установлено, сообщение с заголовками нельзя даже поставить в очередь.
+	if !p.nc.IsConnected() {
+		return fmt.Errorf("failed to publish event: nats connection is %s", p.nc.Status())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	if p.js != nil {
+		if _, err := p.js.PublishMsg(ctx, msg); err != nil {
+			return fmt.Errorf("failed to publish event to jetstream: %w", err)
+		}
+		return nil
+	}
+
+	if err := p.nc.PublishMsg(msg); err != nil {
+		return fmt.Errorf("failed to publish event to nats: %w", err)
+	}
+	if err := p.nc.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush nats connection: %w", err)
+	}
+
+	return nil
+}
+
+// Close — закрывает подключение к NATS. Публикация синхронная,
+// поэтому к моменту закрытия неотправленных событий нет.
+func (p *natsPublisher) Close(context.Context) error {
+	p.nc.Close()
+	return nil
+}
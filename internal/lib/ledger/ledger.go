@@ -0,0 +1,222 @@
+// Package ledger формирует журналы простой текстовой бухгалтерии
+// в форматах ledger, hledger и beancount.
+package ledger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Форматы журнала. Журналы ledger и hledger отличаются только заголовком транзакции:
+// hledger отделяет пояснение от получателя символом "|", ledger записывает его в комментарий.
+const (
+	FormatLedger    = "ledger"
+	FormatHledger   = "hledger"
+	FormatBeancount = "beancount"
+)
+
+// ErrUnsupportedFormat — формат журнала не поддерживается.
+var ErrUnsupportedFormat = errors.New("unsupported journal format")
+
+// rootAccounts — корневые счета, с которых должно начинаться название счёта в beancount.
+var rootAccounts = map[string]bool{"Assets": true, "Liabilities": true, "Equity": true, "Income": true, "Expenses": true}
+
+// currencyPattern — допустимые обозначения валюты: заглавные латинские буквы (RUB, USD).
+var currencyPattern = regexp.MustCompile(`^[A-Z]{2,24}$`)
+
+// Meta — пара ключ-значение, записываемая в метаданные транзакции.
+// Ключ должен начинаться со строчной латинской буквы.
+type Meta struct {
+	Key   string
+	Value string
+}
+
+// Transaction — транзакция журнала из двух проводок: Amount списывается
+// на счёт Account с балансирующего счёта Counter.
+type Transaction struct {
+	Date      time.Time
+	Payee     string
+	Narration string
+	Meta      []Meta
+	Account   string
+	Counter   string
+	Amount    int
+	Currency  string
+}
+
+// Options — параметры записи журнала.
+type Options struct {
+	// Open добавляет перед транзакциями объявления всех счетов журнала:
+	// директивы open в beancount (датой первой транзакции) и account в ledger и hledger.
+	Open bool
+}
+
+// ValidFormat — сообщает, поддерживается ли формат журнала.
+func ValidFormat(format string) bool {
+	switch format {
+	case FormatLedger, FormatHledger, FormatBeancount:
+		return true
+	}
+	return false
+}
+
+// ValidAccount — проверяет название счёта: компоненты через двоеточие, первый из них —
+// Assets, Liabilities, Equity, Income или Expenses, остальные начинаются с заглавной
+// буквы или цифры и состоят из букв, цифр и дефисов. Такие названия понимают все три формата.
+func ValidAccount(account string) bool {
+	parts := strings.Split(account, ":")
+	if len(parts) < 2 || !rootAccounts[parts[0]] {
+		return false
+	}
+	for _, part := range parts[1:] {
+		if !validComponent(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidCurrency — проверяет обозначение валюты.
+func ValidCurrency(currency string) bool {
+	return currencyPattern.MatchString(currency)
+}
+
+// SubAccount — подсчёт parent с названием name, приведённым к допустимому виду:
+// пробелы и прочие символы заменяются дефисами, первая буква делается заглавной
+// ("yandex plus" → "Yandex-Plus").
+func SubAccount(parent, name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	component := strings.Join(words, "-")
+
+	runes := []rune(component)
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	component = string(runes)
+	switch {
+	case component == "":
+		component = "Unknown"
+	case !validComponent(component):
+		// Письменности без заглавных букв: компонент должен начинаться с заглавной.
+		component = "X-" + component
+	}
+
+	return parent + ":" + component
+}
+
+// Write — записывает транзакции в журнал формата format в порядке дат.
+// Транзакции с одинаковой датой остаются в переданном порядке.
+func Write(w io.Writer, format string, txs []Transaction, opts Options) error {
+	if !ValidFormat(format) {
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+
+	sorted := make([]Transaction, len(txs))
+	copy(sorted, txs)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	bw := bufio.NewWriter(w)
+
+	if opts.Open && len(sorted) > 0 {
+		writeOpen(bw, format, sorted)
+	}
+
+	for i, tx := range sorted {
+		if i > 0 || opts.Open {
+			bw.WriteString("\n")
+		}
+		if format == FormatBeancount {
+			writeBeancount(bw, tx)
+		} else {
+			writeLedger(bw, format, tx)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// writeOpen — записывает объявления счетов журнала в порядке их первого появления.
+func writeOpen(w *bufio.Writer, format string, txs []Transaction) {
+	seen := make(map[string]bool)
+	for _, tx := range txs {
+		for _, account := range []string{tx.Account, tx.Counter} {
+			if seen[account] {
+				continue
+			}
+			seen[account] = true
+			if format == FormatBeancount {
+				fmt.Fprintf(w, "%s open %s\n", date(txs[0].Date), account)
+			} else {
+				fmt.Fprintf(w, "account %s\n", account)
+			}
+		}
+	}
+}
+
+// writeLedger — записывает транзакцию в синтаксисе ledger или hledger: получатель и пояснение
+// в заголовке, метаданные — тегами в комментариях, сумма балансирующей проводки выводится автоматически.
+func writeLedger(w *bufio.Writer, format string, tx Transaction) {
+	header := ledgerText(tx.Payee)
+	switch {
+	case tx.Narration == "":
+	case format == FormatHledger:
+		header += " | " + ledgerText(tx.Narration)
+	default:
+		header += "  ; " + ledgerText(tx.Narration)
+	}
+	fmt.Fprintf(w, "%s * %s\n", date(tx.Date), header)
+	for _, m := range tx.Meta {
+		fmt.Fprintf(w, "    ; %s: %s\n", m.Key, ledgerText(m.Value))
+	}
+	fmt.Fprintf(w, "    %s  %d %s\n", tx.Account, tx.Amount, tx.Currency)
+	fmt.Fprintf(w, "    %s\n", tx.Counter)
+}
+
+// writeBeancount — записывает транзакцию в синтаксисе beancount с явными суммами обеих проводок.
+func writeBeancount(w *bufio.Writer, tx Transaction) {
+	fmt.Fprintf(w, "%s * %s %s\n", date(tx.Date), beancountString(tx.Payee), beancountString(tx.Narration))
+	for _, m := range tx.Meta {
+		fmt.Fprintf(w, "  %s: %s\n", m.Key, beancountString(m.Value))
+	}
+	fmt.Fprintf(w, "  %s  %d %s\n", tx.Account, tx.Amount, tx.Currency)
+	fmt.Fprintf(w, "  %s  %d %s\n", tx.Counter, -tx.Amount, tx.Currency)
+}
+
+// validComponent — проверяет компонент названия счёта после корневого.
+func validComponent(part string) bool {
+	for i, r := range part {
+		switch {
+		case i == 0 && !unicode.IsUpper(r) && !unicode.IsDigit(r):
+			return false
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-':
+			return false
+		}
+	}
+	return part != ""
+}
+
+// ledgerText — текст в одну строку без точки с запятой, с которой в ledger и hledger
+// начинается комментарий, и без "|", которым hledger отделяет пояснение.
+func ledgerText(s string) string {
+	return strings.Join(strings.Fields(strings.NewReplacer(";", ",", "|", "/").Replace(s)), " ")
+}
+
+// beancountString — строка beancount в кавычках.
+func beancountString(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// date — дата транзакции в формате YYYY-MM-DD.
+func date(t time.Time) string {
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"online_subscription_service/internal/config"
+)
+
+// Форматы вывода логов.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// New — создаёт логгер по конфигурации: уровень (debug, info, warn, error)
+// и формат вывода (text или json). Логи пишутся в stdout.
+func New(cfg config.LogConfig) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLevel(cfg.Level)}
+
+	var handler slog.Handler
+	if strings.EqualFold(cfg.Format, FormatJSON) {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	return slog.New(handler)
+}
+
+// parseLevel — разбирает уровень логирования; неизвестные значения трактуются как info.
+func parseLevel(level string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+type loggerKey struct{}
+
+// WithContext — возвращает копию контекста с сохранённым логгером.
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext — извлекает логгер запроса из контекста.
+// Если логгер не сохранён, возвращается глобальный slog.Default().
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// With — добавляет атрибуты к логгеру из контекста и возвращает обновлённый контекст.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(args...))
+}
@@ -0,0 +1,287 @@
+package providers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Значения по умолчанию для адаптера generic.
+const (
+	defaultSignatureHeader = "X-Webhook-Signature"
+	defaultSignaturePrefix = "sha256="
+	defaultTolerance       = 5 * time.Minute
+)
+
+// dateLayouts — форматы дат, которые принимает адаптер generic.
+var dateLayouts = []string{time.RFC3339, time.DateOnly, "01-2006"}
+
+// Generic — адаптер провайдера с настраиваемым сопоставлением полей JSON-тела
+// и подписью HMAC-SHA256. Подходит для провайдеров без собственного адаптера
+// и для локальной проверки интеграции.
+type Generic struct {
+	secret          []byte
+	signatureHeader string
+	signaturePrefix string
+	timestampHeader string
+	tolerance       time.Duration
+	mapping         config.ProviderMapping
+}
+
+// NewGeneric — конструктор адаптера generic. Незаданные поля конфигурации
+// заменяются значениями по умолчанию.
+func NewGeneric(secret string, cfg config.ProviderConfig) (*Generic, error) {
+	g := &Generic{
+		secret:          []byte(secret),
+		signatureHeader: cfg.SignatureHeader,
+		signaturePrefix: cfg.SignaturePrefix,
+		timestampHeader: cfg.TimestampHeader,
+		tolerance:       cfg.Tolerance,
+		mapping:         cfg.Mapping,
+	}
+	if g.signatureHeader == "" {
+		g.signatureHeader = defaultSignatureHeader
+	}
+	if g.signaturePrefix == "" {
+		g.signaturePrefix = defaultSignaturePrefix
+	}
+	if g.tolerance <= 0 {
+		g.tolerance = defaultTolerance
+	}
+
+	m := &g.mapping
+	for _, f := range []struct {
+		path *string
+		def  string
+	}{
+		{&m.EventID, "id"},
+		{&m.EventType, "type"},
+		{&m.SubscriptionRef, "subscription_id"},
+		{&m.Price, "price"},
+		{&m.EndDate, "end_date"},
+		{&m.OccurredAt, "occurred_at"},
+	} {
+		if *f.path == "" {
+			*f.path = f.def
+		}
+	}
+
+	for external, t := range m.Types {
+		switch models.ProviderEventType(t) {
+		case models.ProviderRenewed, models.ProviderCancelled, models.ProviderPriceChanged:
+		default:
+			return nil, fmt.Errorf("mapping.types: %q maps to unknown event type %q", external, t)
+		}
+	}
+
+	return g, nil
+}
+
+// Verify — сравнивает подпись из заголовка с HMAC-SHA256 тела запроса. Если настроен
+// заголовок времени отправки, подписывается строка "<timestamp>.<тело>", а запрос,
+// отправленный раньше или позже допустимого расхождения, отклоняется.
+func (g *Generic) Verify(header http.Header, body []byte) error {
+	signature, ok := strings.CutPrefix(header.Get(g.signatureHeader), g.signaturePrefix)
+	if !ok || signature == "" {
+		return fmt.Errorf("%w: missing %s header", ErrInvalidSignature, g.signatureHeader)
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+
+	mac := hmac.New(sha256.New, g.secret)
+	if g.timestampHeader != "" {
+		ts := header.Get(g.timestampHeader)
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: missing or malformed %s header", ErrInvalidSignature, g.timestampHeader)
+		}
+		if d := time.Since(time.Unix(sec, 0)); d > g.tolerance || d < -g.tolerance {
+			return fmt.Errorf("%w: timestamp is outside the allowed tolerance", ErrInvalidSignature)
+		}
+		mac.Write([]byte(ts))
+		mac.Write([]byte("."))
+	}
+	mac.Write(body)
+
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// Parse — разбирает тело вебхука: одно событие или массив событий по пути mapping.events.
+// Типы событий переводятся по mapping.types; тип, которого там нет, сохраняется как есть.
+func (g *Generic) Parse(body []byte) ([]models.ProviderEvent, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var root any
+	if err := dec.Decode(&root); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	items := []any{root}
+	if g.mapping.Events != "" {
+		v, ok := lookup(root, g.mapping.Events)
+		list, isList := v.([]any)
+		if !ok || !isList {
+			return nil, fmt.Errorf("%w: %s must be an array", ErrInvalidPayload, g.mapping.Events)
+		}
+		items = list
+	}
+
+	events := make([]models.ProviderEvent, 0, len(items))
+	for i, item := range items {
+		e, err := g.parseEvent(item)
+		if err != nil {
+			return nil, fmt.Errorf("%w: event %d: %v", ErrInvalidPayload, i, err)
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// parseEvent — извлекает поля одного события по путям сопоставления.
+func (g *Generic) parseEvent(item any) (models.ProviderEvent, error) {
+	var (
+		e   models.ProviderEvent
+		err error
+	)
+
+	if e.ID, err = requiredString(item, g.mapping.EventID); err != nil {
+		return e, err
+	}
+	rawType, err := requiredString(item, g.mapping.EventType)
+	if err != nil {
+		return e, err
+	}
+	e.Type = models.ProviderEventType(rawType)
+	if t, ok := g.mapping.Types[rawType]; ok {
+		e.Type = models.ProviderEventType(t)
+	}
+	if e.ExternalID, err = requiredString(item, g.mapping.SubscriptionRef); err != nil {
+		return e, err
+	}
+
+	if v, ok := lookup(item, g.mapping.Price); ok && v != nil {
+		price, err := integer(v)
+		if err != nil {
+			return e, fmt.Errorf("%s: %v", g.mapping.Price, err)
+		}
+		e.Price = &price
+	}
+	if e.EndDate, err = optionalTime(item, g.mapping.EndDate); err != nil {
+		return e, err
+	}
+	if e.OccurredAt, err = optionalTime(item, g.mapping.OccurredAt); err != nil {
+		return e, err
+	}
+
+	return e, nil
+}
+
+// lookup — возвращает значение по пути через точку; сегмент пути, применённый
+// к массиву, считается индексом элемента.
+func lookup(v any, path string) (any, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// requiredString — возвращает непустую строку или число по пути в виде строки.
+func requiredString(item any, path string) (string, error) {
+	v, ok := lookup(item, path)
+	if !ok || v == nil {
+		return "", fmt.Errorf("%s is required", path)
+	}
+
+	var s string
+	switch value := v.(type) {
+	case string:
+		s = strings.TrimSpace(value)
+	case json.Number:
+		s = value.String()
+	default:
+		return "", fmt.Errorf("%s must be a string or a number", path)
+	}
+	if s == "" {
+		return "", fmt.Errorf("%s is required", path)
+	}
+	return s, nil
+}
+
+// integer — возвращает целое число из JSON-числа или строки с числом.
+func integer(v any) (int, error) {
+	var s string
+	switch value := v.(type) {
+	case json.Number:
+		s = value.String()
+	case string:
+		s = strings.TrimSpace(value)
+	default:
+		return 0, errors.New("must be an integer")
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < math.MinInt32 || n > math.MaxInt32 {
+		return 0, errors.New("must be an integer")
+	}
+	return int(n), nil
+}
+
+// optionalTime — возвращает время по пути: строку в формате RFC 3339, YYYY-MM-DD
+// или MM-YYYY либо число секунд Unix. Отсутствующее или null значение — nil.
+func optionalTime(item any, path string) (*time.Time, error) {
+	v, ok := lookup(item, path)
+	if !ok || v == nil {
+		return nil, nil
+	}
+
+	switch value := v.(type) {
+	case json.Number:
+		sec, err := value.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("%s must be unix seconds", path)
+		}
+		t := time.Unix(sec, 0).UTC()
+		return &t, nil
+	case string:
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+				t = t.UTC()
+				return &t, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s must be a date in RFC 3339, YYYY-MM-DD or MM-YYYY format or unix seconds", path)
+}
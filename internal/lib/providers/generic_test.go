@@ -0,0 +1,151 @@
+package providers
+
+import (
+	"errors"
+	"net/http"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/lib/webhook"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGenericVerify(t *testing.T) {
+	const secret = "provider-secret"
+	body := []byte(`{"id":"evt_1","type":"renewed","subscription_id":"sub_1"}`)
+	now := time.Now()
+
+	// Подпись в формате исходящих вебхуков сервиса: "sha256=" + HMAC от "<timestamp>.<тело>".
+	signed := func(ts time.Time) http.Header {
+		return http.Header{
+			"X-Webhook-Signature": {webhook.Sign(secret, ts, body)},
+			"X-Webhook-Timestamp": {strconv.FormatInt(ts.Unix(), 10)},
+		}
+	}
+	// bodySignature — HMAC-SHA256 тела без метки времени.
+	const bodySignature = "442866b2c80c0fea9aaea790c23a7428aba2b50da5eb65416e628de0a8887815"
+	withTimestamp := config.ProviderConfig{TimestampHeader: "X-Webhook-Timestamp", Tolerance: 5 * time.Minute}
+
+	tests := []struct {
+		name    string
+		cfg     config.ProviderConfig
+		header  http.Header
+		body    []byte
+		wantErr bool
+	}{
+		{
+			name:   "valid signature with timestamp",
+			cfg:    withTimestamp,
+			header: signed(now),
+		},
+		{
+			name:   "timestamp within tolerance",
+			cfg:    withTimestamp,
+			header: signed(now.Add(-4 * time.Minute)),
+		},
+		{
+			name:    "timestamp too old",
+			cfg:     withTimestamp,
+			header:  signed(now.Add(-6 * time.Minute)),
+			wantErr: true,
+		},
+		{
+			name:    "timestamp in the future",
+			cfg:     withTimestamp,
+			header:  signed(now.Add(6 * time.Minute)),
+			wantErr: true,
+		},
+		{
+			name: "timestamp changed after signing",
+			cfg:  withTimestamp,
+			header: http.Header{
+				"X-Webhook-Signature": {webhook.Sign(secret, now.Add(-time.Minute), body)},
+				"X-Webhook-Timestamp": {strconv.FormatInt(now.Unix(), 10)},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "body changed after signing",
+			cfg:     withTimestamp,
+			header:  signed(now),
+			body:    []byte(`{"id":"evt_1","type":"cancelled","subscription_id":"sub_1"}`),
+			wantErr: true,
+		},
+		{
+			name:    "missing timestamp",
+			cfg:     withTimestamp,
+			header:  http.Header{"X-Webhook-Signature": {webhook.Sign(secret, now, body)}},
+			wantErr: true,
+		},
+		{
+			name: "malformed timestamp",
+			cfg:  withTimestamp,
+			header: http.Header{
+				"X-Webhook-Signature": {webhook.Sign(secret, now, body)},
+				"X-Webhook-Timestamp": {"yesterday"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "missing signature",
+			cfg:     withTimestamp,
+			header:  http.Header{"X-Webhook-Timestamp": {strconv.FormatInt(now.Unix(), 10)}},
+			wantErr: true,
+		},
+		{
+			name: "signature without prefix",
+			cfg:  withTimestamp,
+			header: http.Header{
+				"X-Webhook-Signature": {webhook.Sign(secret, now, body)[len("sha256="):]},
+				"X-Webhook-Timestamp": {strconv.FormatInt(now.Unix(), 10)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed signature",
+			cfg:  withTimestamp,
+			header: http.Header{
+				"X-Webhook-Signature": {"sha256=not-hex"},
+				"X-Webhook-Timestamp": {strconv.FormatInt(now.Unix(), 10)},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "body signed without timestamp",
+			cfg:    config.ProviderConfig{},
+			header: http.Header{"X-Webhook-Signature": {"sha256=" + bodySignature}},
+		},
+		{
+			name:    "custom prefix mismatch",
+			cfg:     config.ProviderConfig{SignatureHeader: "Stripe-Signature", SignaturePrefix: "v1="},
+			header:  http.Header{"Stripe-Signature": {"sha256=" + bodySignature}},
+			wantErr: true,
+		},
+		{
+			name:   "custom header and prefix",
+			cfg:    config.ProviderConfig{SignatureHeader: "Stripe-Signature", SignaturePrefix: "v1="},
+			header: http.Header{"Stripe-Signature": {"v1=" + bodySignature}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGeneric(secret, tt.cfg)
+			if err != nil {
+				t.Fatalf("NewGeneric() error = %v", err)
+			}
+
+			b := body
+			if tt.body != nil {
+				b = tt.body
+			}
+			err = g.Verify(tt.header, b)
+			switch {
+			case tt.wantErr && !errors.Is(err, ErrInvalidSignature):
+				t.Errorf("Verify() error = %v, want ErrInvalidSignature", err)
+			case !tt.wantErr && err != nil:
+				t.Errorf("Verify() error = %v, want nil", err)
+			}
+		})
+	}
+}
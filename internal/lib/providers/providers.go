@@ -0,0 +1,58 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"os"
+)
+
+// Адаптеры провайдеров.
+const (
+	AdapterGeneric = "generic"
+)
+
+var (
+	// ErrInvalidSignature — подпись вебхука отсутствует, неверна или устарела.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInvalidPayload — тело вебхука не удалось разобрать в события.
+	ErrInvalidPayload = errors.New("invalid webhook payload")
+)
+
+// Adapter — адаптер провайдера подписок, проверяющий и разбирающий его вебхуки.
+type Adapter interface {
+	// Verify — проверяет подпись вебхука; возвращает ErrInvalidSignature, если она неверна.
+	Verify(header http.Header, body []byte) error
+	// Parse — разбирает тело вебхука в события; возвращает ErrInvalidPayload,
+	// если тело не соответствует формату провайдера.
+	Parse(body []byte) ([]models.ProviderEvent, error)
+}
+
+// New — создаёт адаптер провайдера по конфигурации.
+func New(name string, cfg config.ProviderConfig) (Adapter, error) {
+	secret := cfg.Secret
+	if cfg.SecretEnv != "" {
+		secret = os.Getenv(cfg.SecretEnv)
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("provider %q: signing secret is empty", name)
+	}
+
+	var (
+		adapter Adapter
+		err     error
+	)
+	switch cfg.Adapter {
+	case AdapterGeneric, "":
+		adapter, err = NewGeneric(secret, cfg)
+	default:
+		err = fmt.Errorf("unknown adapter %q", cfg.Adapter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("provider %q: %w", name, err)
+	}
+
+	return adapter, nil
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"math"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Groups — ограничители частоты запросов групп маршрутов. Один экземпляр используется
+// HTTP- и gRPC-API, поэтому запросы клиента к одной группе через оба API расходуют
+// общую корзину.
+type Groups struct {
+	cfg config.RateLimitConfig
+
+	mu       sync.Mutex
+	limiters map[string]*Limiter
+}
+
+// NewGroups — конструктор Groups. Лимит группы берётся из cfg.Groups (или cfg.Default).
+func NewGroups(cfg config.RateLimitConfig) *Groups {
+	return &Groups{
+		cfg:      cfg,
+		limiters: make(map[string]*Limiter),
+	}
+}
+
+// Limiter — возвращает ограничитель группы group, создавая его при первом обращении;
+// nil, если ограничение частоты запросов отключено.
+func (g *Groups) Limiter(group string) *Limiter {
+	if !g.cfg.Enabled {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if l, ok := g.limiters[group]; ok {
+		return l
+	}
+
+	limit, ok := g.cfg.Groups[group]
+	if !ok || limit.Rate <= 0 {
+		limit = g.cfg.Default
+	}
+
+	l := New(limit.Rate, limit.Burst)
+	g.limiters[group] = l
+	return l
+}
+
+// ClientKey — ключ клиента для подсчёта запросов: API-ключ, пользователь организации
+// или, для неаутентифицированных запросов, IP-адрес ip.
+func ClientKey(id auth.Identity, ok bool, ip string) string {
+	switch {
+	case ok && id.IsAPIKey():
+		return "key:" + id.KeyID.String()
+	case ok && id.Subject != auth.Anonymous.Subject:
+		return "user:" + id.TenantID.String() + ":" + id.Subject
+	default:
+		return "ip:" + ip
+	}
+}
+
+// Seconds — округляет длительность вверх до целого числа секунд для заголовков
+// RateLimit-Reset и Retry-After.
+func Seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
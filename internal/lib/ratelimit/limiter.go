@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval — как часто из памяти удаляются заполненные (неактивные) корзины.
+const sweepInterval = time.Minute
+
+// Result — результат проверки лимита для одного запроса.
+type Result struct {
+	Allowed    bool          // запрос разрешён
+	Limit      int           // ёмкость корзины
+	Remaining  int           // оставшиеся токены после запроса
+	Reset      time.Duration // время до полного восстановления корзины
+	RetryAfter time.Duration // время до появления следующего токена (если запрос отклонён)
+}
+
+// bucket — состояние корзины токенов одного клиента.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter — ограничитель частоты запросов по алгоритму token bucket.
+// Для каждого ключа (клиента) хранится отдельная корзина ёмкостью burst,
+// которая пополняется со скоростью rate токенов в секунду.
+type Limiter struct {
+	rate  float64
+	burst int
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// New — конструктор Limiter.
+// rate — скорость пополнения (токенов в секунду), burst — ёмкость корзины.
+func New(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{
+		rate:    rate,
+		burst:   burst,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow — списывает токен из корзины клиента key, если он есть.
+func (l *Limiter) Allow(key string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = l.refill(b, now)
+	b.last = now
+
+	res := Result{Limit: l.burst}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = l.duration(1 - b.tokens)
+	}
+
+	res.Remaining = int(math.Floor(b.tokens))
+	res.Reset = l.duration(float64(l.burst) - b.tokens)
+
+	return res
+}
+
+// refill — возвращает количество токенов в корзине на момент now.
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	elapsed := now.Sub(b.last).Seconds()
+	return math.Min(float64(l.burst), b.tokens+elapsed*l.rate)
+}
+
+// duration — время, за которое в корзине накопится указанное количество токенов.
+func (l *Limiter) duration(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	if l.rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// sweep — удаляет корзины, которые успели полностью восстановиться:
+// их состояние не отличается от новой корзины, поэтому хранить их не нужно.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= float64(l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
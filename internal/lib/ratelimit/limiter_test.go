@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// fakeClock — часы ограничителя, которые двигаются только вручную.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestLimiter(rate float64, burst int) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	l := New(rate, burst)
+	l.now = clock.Now
+	return l, clock
+}
+
+func TestLimiterAllow(t *testing.T) {
+	type step struct {
+		advance time.Duration // сдвиг часов перед запросом
+		key     string
+		want    Result
+	}
+
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		steps []step
+	}{
+		{
+			name: "burst then refill",
+			rate: 2, burst: 3,
+			steps: []step{
+				{key: "a", want: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: 500 * time.Millisecond}},
+				{key: "a", want: Result{Allowed: true, Limit: 3, Remaining: 1, Reset: time.Second}},
+				{key: "a", want: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 1500 * time.Millisecond}},
+				{key: "a", want: Result{Limit: 3, Reset: 1500 * time.Millisecond, RetryAfter: 500 * time.Millisecond}},
+				{advance: 250 * time.Millisecond, key: "a", want: Result{Limit: 3, Reset: 1250 * time.Millisecond, RetryAfter: 250 * time.Millisecond}},
+				{advance: 250 * time.Millisecond, key: "a", want: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 1500 * time.Millisecond}},
+			},
+		},
+		{
+			name: "refill is capped by burst",
+			rate: 2, burst: 3,
+			steps: []step{
+				{key: "a", want: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: 500 * time.Millisecond}},
+				{advance: time.Hour, key: "a", want: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: 500 * time.Millisecond}},
+			},
+		},
+		{
+			name: "keys have separate buckets",
+			rate: 1, burst: 1,
+			steps: []step{
+				{key: "a", want: Result{Allowed: true, Limit: 1, Remaining: 0, Reset: time.Second}},
+				{key: "a", want: Result{Limit: 1, Reset: time.Second, RetryAfter: time.Second}},
+				{key: "b", want: Result{Allowed: true, Limit: 1, Remaining: 0, Reset: time.Second}},
+			},
+		},
+		{
+			name: "burst below one allows one request",
+			rate: 1, burst: 0,
+			steps: []step{
+				{key: "a", want: Result{Allowed: true, Limit: 1, Remaining: 0, Reset: time.Second}},
+				{key: "a", want: Result{Limit: 1, Reset: time.Second, RetryAfter: time.Second}},
+			},
+		},
+		{
+			name: "zero rate never refills",
+			rate: 0, burst: 1,
+			steps: []step{
+				{key: "a", want: Result{Allowed: true, Limit: 1, Remaining: 0, Reset: math.MaxInt64}},
+				{advance: time.Hour, key: "a", want: Result{Limit: 1, Reset: math.MaxInt64, RetryAfter: math.MaxInt64}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, clock := newTestLimiter(tt.rate, tt.burst)
+
+			for i, s := range tt.steps {
+				clock.now = clock.now.Add(s.advance)
+				if got := l.Allow(s.key); got != s.want {
+					t.Errorf("step %d: Allow(%q) = %+v, want %+v", i, s.key, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     float64
+		advance  time.Duration
+		wantKept bool
+	}{
+		{name: "refilled bucket is removed", rate: 1, advance: sweepInterval},
+		{name: "not swept before interval", rate: 1, advance: sweepInterval - time.Second, wantKept: true},
+		{name: "partially refilled bucket is kept", rate: 0.01, advance: sweepInterval, wantKept: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, clock := newTestLimiter(tt.rate, 3)
+			for range 3 {
+				l.Allow("a")
+			}
+
+			clock.now = clock.now.Add(tt.advance)
+			l.Allow("b")
+
+			if _, kept := l.buckets["a"]; kept != tt.wantKept {
+				t.Errorf("bucket kept = %v, want %v", kept, tt.wantKept)
+			}
+			if _, ok := l.buckets["b"]; !ok {
+				t.Error("bucket of the current request was removed")
+			}
+		})
+	}
+}
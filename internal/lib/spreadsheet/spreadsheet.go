@@ -0,0 +1,160 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Поддерживаемые форматы файлов.
+const (
+	FormatCSV    = "csv"
+	FormatXLSX   = "xlsx"
+	FormatNDJSON = "ndjson" // JSON Lines, только для записи
+)
+
+// ErrUnsupportedFormat — формат файла не поддерживается.
+var ErrUnsupportedFormat = errors.New("unsupported file format")
+
+// utf8BOM — метка порядка байтов, с которой Excel сохраняет CSV в UTF-8.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Row — непустая строка таблицы.
+type Row struct {
+	Number int      // номер строки в файле, начиная с 1
+	Cells  []string // значения ячеек
+}
+
+// Options — параметры чтения файла.
+type Options struct {
+	Delimiter rune   // разделитель полей CSV (по умолчанию запятая)
+	Sheet     string // лист XLSX (по умолчанию первый)
+}
+
+// Read — читает все строки таблицы из CSV- или XLSX-файла.
+// Строки могут содержать разное количество ячеек; полностью пустые строки пропускаются,
+// но номера остальных строк соответствуют их положению в файле.
+// Ячейки XLSX возвращаются без форматирования: даты — числом дней в формате Excel.
+func Read(r io.Reader, format string, opts Options) ([]Row, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return readCSV(r, opts)
+	case FormatXLSX:
+		return readXLSX(r, opts)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// FormatFromName — определяет формат файла по расширению имени.
+// Возвращает пустую строку, если расширение неизвестно.
+func FormatFromName(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".csv"):
+		return FormatCSV
+	case strings.HasSuffix(name, ".xlsx"):
+		return FormatXLSX
+	default:
+		return ""
+	}
+}
+
+// readCSV — читает CSV в UTF-8, пропуская BOM.
+func readCSV(r io.Reader, opts Options) ([]Row, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if !utf8.Valid(data) {
+		return nil, errors.New("csv file must be in UTF-8")
+	}
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	if opts.Delimiter != 0 {
+		cr.Comma = opts.Delimiter
+	}
+
+	var rows []Row
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse csv: %w", err)
+		}
+		if !blank(record) {
+			line, _ := cr.FieldPos(0)
+			rows = append(rows, Row{Number: line, Cells: record})
+		}
+	}
+
+	return rows, nil
+}
+
+// readXLSX — читает лист XLSX-книги.
+func readXLSX(r io.Reader, opts Options) ([]Row, error) {
+	f, err := excelize.OpenReader(r, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open xlsx: %w", err)
+	}
+	defer f.Close()
+
+	sheet := opts.Sheet
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+
+	all, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xlsx sheet %q: %w", sheet, err)
+	}
+
+	var rows []Row
+	for i, record := range all {
+		if !blank(record) {
+			rows = append(rows, Row{Number: i + 1, Cells: record})
+		}
+	}
+
+	return rows, nil
+}
+
+// excelEpoch — нулевой день дат Excel: 30.12.1899 с учётом ошибки Excel,
+// считающего 1900 год високосным.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// ExcelDate — разбирает дату, сохранённую в ячейке XLSX числом дней от начала
+// эпохи Excel. Время суток отбрасывается.
+func ExcelDate(value string) (time.Time, bool) {
+	days, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || days < 1 || days > maxExcelDay {
+		return time.Time{}, false
+	}
+	return excelEpoch.AddDate(0, 0, int(days)), true
+}
+
+// maxExcelDay — последний день, который может храниться в ячейке Excel (31.12.9999).
+const maxExcelDay = 2958465
+
+// blank — проверяет, что в строке нет ни одной непустой ячейки.
+func blank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,252 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Writer — построчная запись таблицы. В CSV и XLSX первой строкой записывается
+// заголовок с названиями колонок, в JSON Lines они становятся ключами объектов.
+// Ничего не пишется в io.Writer до первого вызова Write или Close.
+type Writer interface {
+	// Write — записывает строку. Значения — string, int, time.Time, *time.Time или nil;
+	// даты записываются в формате YYYY-MM-DD (в XLSX — ячейками с датой).
+	// Строки, которые табличный редактор принял бы за формулу, в CSV и XLSX
+	// записываются с апострофом в начале (см. escapeFormula).
+	Write(values []any) error
+	// Close — дописывает буферизованные данные. io.Writer не закрывается.
+	Close() error
+}
+
+// NewWriter — создаёт Writer формата format (csv, ndjson или xlsx) с колонками columns.
+func NewWriter(w io.Writer, format string, columns []string) (Writer, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w), columns: columns}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w), columns: columns}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// ContentType — MIME-тип файла формата format.
+func ContentType(format string) string {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// csvWriter — запись CSV с заголовком.
+type csvWriter struct {
+	w       *csv.Writer
+	columns []string
+	started bool
+}
+
+func (cw *csvWriter) Write(values []any) error {
+	if err := cw.start(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			v = escapeFormula(s)
+		}
+		record[i] = text(v)
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *csvWriter) Close() error {
+	if err := cw.start(); err != nil {
+		return err
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// start — записывает заголовок перед первой строкой.
+func (cw *csvWriter) start() error {
+	if cw.started {
+		return nil
+	}
+	cw.started = true
+	return cw.w.Write(cw.columns)
+}
+
+// ndjsonWriter — запись JSON Lines: по объекту на строку с ключами в порядке колонок.
+type ndjsonWriter struct {
+	w       *bufio.Writer
+	columns []string
+}
+
+func (nw *ndjsonWriter) Write(values []any) error {
+	nw.w.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			nw.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(nw.columns[i])
+		nw.w.Write(key)
+		nw.w.WriteByte(':')
+
+		value, err := json.Marshal(jsonValue(v))
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", nw.columns[i], err)
+		}
+		nw.w.Write(value)
+	}
+	nw.w.WriteByte('}')
+	return nw.w.WriteByte('\n')
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nw.w.Flush()
+}
+
+// xlsxWriter — запись первого листа XLSX-книги. Строки пишутся потоково во
+// временный буфер excelize, а сама книга выводится в io.Writer при Close,
+// так как XLSX — zip-архив, который нельзя отдавать по частям.
+type xlsxWriter struct {
+	w         io.Writer
+	f         *excelize.File
+	sw        *excelize.StreamWriter
+	dateStyle int
+	row       int
+}
+
+// xlsxSheet — название листа выгрузки.
+const xlsxSheet = "Sheet1"
+
+func newXLSXWriter(w io.Writer, columns []string) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+
+	dateFormat := "yyyy-mm-dd"
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to create xlsx date style: %w", err)
+	}
+
+	sw, err := f.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to create xlsx stream: %w", err)
+	}
+
+	xw := &xlsxWriter{w: w, f: f, sw: sw, dateStyle: dateStyle}
+
+	header := make([]any, len(columns))
+	for i, c := range columns {
+		header[i] = c
+	}
+	if err := xw.setRow(header); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return xw, nil
+}
+
+func (xw *xlsxWriter) Write(values []any) error {
+	row := make([]any, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case string:
+			row[i] = escapeFormula(v)
+		case time.Time:
+			row[i] = excelize.Cell{StyleID: xw.dateStyle, Value: v}
+		case *time.Time:
+			if v != nil {
+				row[i] = excelize.Cell{StyleID: xw.dateStyle, Value: *v}
+			}
+		default:
+			row[i] = v
+		}
+	}
+	return xw.setRow(row)
+}
+
+func (xw *xlsxWriter) setRow(values []any) error {
+	xw.row++
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	if err := xw.sw.SetRow(cell, values); err != nil {
+		return fmt.Errorf("failed to write xlsx row: %w", err)
+	}
+	return nil
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.f.Close()
+
+	if err := xw.sw.Flush(); err != nil {
+		return fmt.Errorf("failed to flush xlsx: %w", err)
+	}
+	if err := xw.f.Write(xw.w); err != nil {
+		return fmt.Errorf("failed to write xlsx: %w", err)
+	}
+	return nil
+}
+
+// formulaPrefixes — символы, с которых Excel и LibreOffice начинают формулу.
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula — добавляет апостроф перед строкой, которую табличный редактор
+// выполнил бы как формулу (CSV/formula injection), например "=HYPERLINK(...)".
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune(formulaPrefixes, rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// text — строковое представление значения ячейки CSV.
+func text(v any) string {
+	switch v := jsonValue(v).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// jsonValue — приводит даты к строкам YYYY-MM-DD, пустые даты — к nil.
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		return v.Format(time.DateOnly)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.Format(time.DateOnly)
+	default:
+		return v
+	}
+}
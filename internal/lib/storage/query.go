@@ -11,10 +11,11 @@ import (
 
 // BuildUpdateQuery — строит SQL-запрос для обновления подписки.
 // - serviceID: UUID подписки для WHERE условия.
+// - tenantID: UUID организации, которой должна принадлежать подписка.
 // - sub: DTO с полями, которые нужно обновить.
 // Возвращает строку SQL-запроса и срез аргументов для Exec.
 // Игнорирует поля с nil значением, если все поля nil — возвращает пустую строку и nil args.
-func BuildUpdateQuery(serviceID uuid.UUID, sub models.SubsUpdateDTO) (string, []any) {
+func BuildUpdateQuery(serviceID, tenantID uuid.UUID, sub models.SubsUpdateDTO) (string, []any) {
 	fields := map[string]any{
 		"name":       sub.Name,
 		"price":      sub.Price,
@@ -40,9 +41,9 @@ func BuildUpdateQuery(serviceID uuid.UUID, sub models.SubsUpdateDTO) (string, []
 		return "", nil
 	}
 
-	query := fmt.Sprintf("update services set %s where id=$%d", strings.Join(set, ", "), idx)
+	query := fmt.Sprintf("update services set %s where id=$%d and tenant_id=$%d", strings.Join(set, ", "), idx, idx+1)
 
-	args = append(args, serviceID)
+	args = append(args, serviceID, tenantID)
 
 	return query, args
 }
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Заголовки запроса, с которым событие доставляется на вебхук.
+const (
+	HeaderSignature  = "X-Webhook-Signature"   // sha256=<hex HMAC-SHA256 от "<timestamp>.<тело>">
+	HeaderTimestamp  = "X-Webhook-Timestamp"   // время отправки в секундах Unix
+	HeaderEventID    = "X-Webhook-Event-Id"    // ID события для распознавания повторов
+	HeaderEventType  = "X-Webhook-Event-Type"  // тип события
+	HeaderDeliveryID = "X-Webhook-Delivery-Id" // ID доставки в журнале доставок
+)
+
+// signaturePrefix — префикс подписи, указывающий алгоритм.
+const signaturePrefix = "sha256="
+
+// ErrForbiddenAddress — адрес получателя находится во внутренней сети.
+var ErrForbiddenAddress = errors.New("webhook address is not allowed")
+
+// deniedPrefixes — сети, в которые не доставляются события без allowPrivate: внутренние,
+// служебные и зарезервированные диапазоны IANA. Список задан явно, а не методами net.IP,
+// потому что те не охватывают, например, CGNAT (100.64.0.0/10), где находятся метаданные
+// некоторых облаков (100.100.100.200), и NAT64 (64:ff9b::/96), через который доступны
+// IPv4-адреса внутренней сети.
+var deniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // «эта» сеть
+	netip.MustParsePrefix("10.0.0.0/8"),      // частная сеть
+	netip.MustParsePrefix("100.64.0.0/10"),   // CGNAT
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link-local, метаданные облаков
+	netip.MustParsePrefix("172.16.0.0/12"),   // частная сеть
+	netip.MustParsePrefix("192.0.0.0/24"),    // служебные назначения IETF
+	netip.MustParsePrefix("192.0.2.0/24"),    // документация (TEST-NET-1)
+	netip.MustParsePrefix("192.168.0.0/16"),  // частная сеть
+	netip.MustParsePrefix("198.18.0.0/15"),   // тестирование производительности
+	netip.MustParsePrefix("198.51.100.0/24"), // документация (TEST-NET-2)
+	netip.MustParsePrefix("203.0.113.0/24"),  // документация (TEST-NET-3)
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // зарезервировано, broadcast
+	netip.MustParsePrefix("::/128"),          // неуказанный адрес
+	netip.MustParsePrefix("::1/128"),         // loopback
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // локальный NAT64
+	netip.MustParsePrefix("100::/64"),        // discard
+	netip.MustParsePrefix("2001:db8::/32"),   // документация
+	netip.MustParsePrefix("2002::/16"),       // 6to4, в адресе может быть внутренний IPv4
+	netip.MustParsePrefix("fc00::/7"),        // unique local
+	netip.MustParsePrefix("fe80::/10"),       // link-local
+	netip.MustParsePrefix("ff00::/8"),        // multicast
+}
+
+// Sign — подписывает тело запроса секретом: HMAC-SHA256 от строки "<timestamp>.<body>",
+// где timestamp — время отправки в секундах Unix. Метка времени входит в подпись,
+// чтобы получатель мог отбросить перехваченный и повторно отправленный запрос.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewClient — создаёт HTTP-клиент для доставки событий с ограничением времени запроса.
+// Перенаправления не выполняются, а ответ 3xx считается неудачной доставкой.
+// Если allowPrivate равен false, подключение к адресам из deniedPrefixes запрещено;
+// адрес проверяется после разрешения имени, при каждом подключении.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil || !publicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// Через прокси проверялся бы адрес прокси, а не получателя.
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// publicIP — проверяет, что адрес не входит в deniedPrefixes. IPv4-адреса в форме
+// ::ffff:a.b.c.d проверяются как IPv4.
+func publicIP(ip netip.Addr) bool {
+	ip = ip.Unmap().WithZone("")
+	for _, prefix := range deniedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return ip.IsValid()
+}
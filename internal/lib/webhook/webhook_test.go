@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		ts     time.Time
+		body   string
+		want   string
+	}{
+		{
+			name:   "event body",
+			secret: "secret",
+			ts:     ts,
+			body:   `{"id":"evt_1"}`,
+			want:   "sha256=af784f27423c462e20039559cd4264140f7b7ed4c9090e26fd663faa5eeb8dda",
+		},
+		{
+			name:   "empty body",
+			secret: "secret",
+			ts:     ts,
+			want:   "sha256=4bc5f74d868b97888288889c5d9d65df02526f94c1592a79fdf4fe8b26e311e5",
+		},
+		{
+			name:   "other secret",
+			secret: "other",
+			ts:     ts,
+			body:   `{"id":"evt_1"}`,
+			want:   "sha256=e12ef238930e9a9dcbebaf3147df8d7a19ab1524ac7be39f4f8d50cb628f0ab5",
+		},
+		{
+			name:   "timestamp in another time zone",
+			secret: "secret",
+			ts:     ts.In(time.FixedZone("MSK", 3*60*60)),
+			body:   `{"id":"evt_1"}`,
+			want:   "sha256=af784f27423c462e20039559cd4264140f7b7ed4c9090e26fd663faa5eeb8dda",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.ts, []byte(tt.body)); got != tt.want {
+				t.Errorf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "8.8.8.8", want: true},
+		{ip: "93.184.216.34", want: true},
+		{ip: "100.63.255.255", want: true},
+		{ip: "100.128.0.0", want: true},
+		{ip: "198.20.0.1", want: true},
+		{ip: "2606:4700:4700::1111", want: true},
+		{ip: "::ffff:8.8.8.8", want: true},
+
+		{ip: "0.0.0.0"},
+		{ip: "0.1.2.3"},
+		{ip: "10.1.2.3"},
+		{ip: "100.64.0.1"},
+		{ip: "100.100.100.200"},
+		{ip: "100.127.255.255"},
+		{ip: "127.0.0.1"},
+		{ip: "169.254.169.254"},
+		{ip: "172.16.0.1"},
+		{ip: "172.31.255.255"},
+		{ip: "192.0.0.170"},
+		{ip: "192.0.2.1"},
+		{ip: "192.168.1.1"},
+		{ip: "198.18.0.1"},
+		{ip: "198.19.255.255"},
+		{ip: "198.51.100.1"},
+		{ip: "203.0.113.1"},
+		{ip: "224.0.0.1"},
+		{ip: "240.0.0.1"},
+		{ip: "255.255.255.255"},
+		{ip: "::"},
+		{ip: "::1"},
+		{ip: "::ffff:127.0.0.1"},
+		{ip: "::ffff:169.254.169.254"},
+		{ip: "64:ff9b::a9fe:a9fe"},
+		{ip: "64:ff9b:1::1"},
+		{ip: "100::1"},
+		{ip: "2001:db8::1"},
+		{ip: "2002:a00:1::1"},
+		{ip: "fc00::1"},
+		{ip: "fd12:3456::1"},
+		{ip: "fe80::1"},
+		{ip: "fe80::1%eth0"},
+		{ip: "ff02::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := publicIP(netip.MustParseAddr(tt.ip)); got != tt.want {
+				t.Errorf("publicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name         string
+		allowPrivate bool
+		path         string
+		wantStatus   int
+		wantErr      error
+	}{
+		{name: "loopback is denied", path: "/", wantErr: ErrForbiddenAddress},
+		{name: "loopback is allowed for local debugging", allowPrivate: true, path: "/", wantStatus: http.StatusNoContent},
+		{name: "redirect is not followed", allowPrivate: true, path: "/redirect", wantStatus: http.StatusFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewClient(time.Second, tt.allowPrivate).Get(srv.URL + tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Get() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"online_subscription_service/internal/domain/models"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// statsTimeout — максимальное время расчёта бизнес-метрик при одном опросе.
+const statsTimeout = 5 * time.Second
+
+// statsProvider — источник сводных показателей подписок.
+type statsProvider interface {
+	ReadSubscriptionStats(ctx context.Context) ([]models.SubsStats, error)
+}
+
+// businessCollector — рассчитывает бизнес-метрики по данным БД в момент опроса.
+type businessCollector struct {
+	stats statsProvider
+
+	active       *prometheus.Desc
+	monthlySpend *prometheus.Desc
+	up           *prometheus.Desc
+}
+
+// RegisterBusiness — регистрирует бизнес-метрики: количество действующих подписок
+// и их суммарную месячную стоимость в разрезе организаций.
+func RegisterBusiness(stats statsProvider) {
+	Registry.MustRegister(&businessCollector{
+		stats: stats,
+		active: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "active_subscriptions"),
+			"Количество действующих подписок.", []string{"tenant_id"}, nil),
+		monthlySpend: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "monthly_spend"),
+			"Суммарная месячная стоимость действующих подписок.", []string{"tenant_id"}, nil),
+		up: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "business_metrics_up"),
+			"1, если бизнес-метрики удалось рассчитать при последнем опросе.", nil, nil),
+	})
+}
+
+// Describe — реализует prometheus.Collector.
+func (c *businessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.active
+	ch <- c.monthlySpend
+	ch <- c.up
+}
+
+// Collect — реализует prometheus.Collector.
+// При ошибке чтения из БД отдаётся только метрика business_metrics_up со значением 0.
+func (c *businessCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), statsTimeout)
+	defer cancel()
+
+	stats, err := c.stats.ReadSubscriptionStats(ctx)
+	if err != nil {
+		slog.Error("failed to collect business metrics", slog.String("error", err.Error()))
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 0)
+		return
+	}
+
+	for _, st := range stats {
+		tenant := st.TenantID.String()
+		ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, float64(st.Active), tenant)
+		ch <- prometheus.MustNewConstMetric(c.monthlySpend, prometheus.GaugeValue, float64(st.MonthlySpend), tenant)
+	}
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 1)
+}
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace — общий префикс имён метрик сервиса.
+const namespace = "subscriptions_service"
+
+// Registry — реестр метрик сервиса.
+// Помимо метрик сервиса содержит стандартные метрики среды выполнения Go и процесса.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// HTTPRequests — количество обработанных HTTP-запросов по маршруту и статусу.
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Количество обработанных HTTP-запросов.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPDuration — длительность обработки HTTP-запросов по маршруту и статусу.
+	HTTPDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Длительность обработки HTTP-запросов в секундах.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// QueryDuration — длительность запросов к БД по хранилищу и методу.
+	QueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Длительность запросов к базе данных в секундах.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"storage", "method"})
+
+	// QueryErrors — количество запросов к БД, завершившихся ошибкой.
+	QueryErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "Количество запросов к базе данных, завершившихся ошибкой.",
+	}, []string{"storage", "method"})
+
+	// OutboxPublished — количество опубликованных событий по типу.
+	OutboxPublished = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "published_total",
+		Help:      "Количество опубликованных событий об изменении подписок.",
+	}, []string{"type"})
+
+	// OutboxPublishErrors — количество неудачных попыток публикации событий по типу.
+	OutboxPublishErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "publish_errors_total",
+		Help:      "Количество неудачных попыток публикации событий об изменении подписок.",
+	}, []string{"type"})
+
+	// WebhookDeliveries — количество попыток доставки событий на вебхуки по результату:
+	// succeeded, failed (будет повторена) или dead (попытки исчерпаны).
+	WebhookDeliveries = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Количество попыток доставки событий на вебхуки.",
+	}, []string{"result"})
+
+	// IntegrationEvents — количество событий провайдеров подписок по провайдеру и результату
+	// обработки: applied, unchanged, ignored, failed или duplicate.
+	IntegrationEvents = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "integration",
+		Name:      "events_total",
+		Help:      "Количество принятых событий провайдеров подписок.",
+	}, []string{"provider", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler — HTTP-обработчик, отдающий метрики в текстовом формате Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector — собирает статистику пула подключений pgxpool в момент опроса.
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired        *prometheus.Desc
+	idle            *prometheus.Desc
+	constructing    *prometheus.Desc
+	total           *prometheus.Desc
+	max             *prometheus.Desc
+	acquires        *prometheus.Desc
+	emptyAcquires   *prometheus.Desc
+	canceled        *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	emptyWait       *prometheus.Desc
+}
+
+// RegisterPool — регистрирует метрики пула подключений к БД.
+func RegisterPool(pool *pgxpool.Pool) {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+
+	Registry.MustRegister(&poolCollector{
+		pool:            pool,
+		acquired:        desc("acquired_connections", "Количество занятых подключений."),
+		idle:            desc("idle_connections", "Количество свободных подключений."),
+		constructing:    desc("constructing_connections", "Количество устанавливаемых подключений."),
+		total:           desc("total_connections", "Общее количество подключений в пуле."),
+		max:             desc("max_connections", "Максимальный размер пула."),
+		acquires:        desc("acquires_total", "Количество успешных получений подключения из пула."),
+		emptyAcquires:   desc("empty_acquires_total", "Количество получений подключения, которым пришлось ждать."),
+		canceled:        desc("canceled_acquires_total", "Количество получений подключения, отменённых контекстом."),
+		acquireDuration: desc("acquire_duration_seconds_total", "Суммарное время получения подключений в секундах."),
+		emptyWait:       desc("empty_acquire_wait_seconds_total", "Суммарное время ожидания свободного подключения в секундах."),
+	})
+}
+
+// Describe — реализует prometheus.Collector.
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+// Collect — реализует prometheus.Collector.
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.constructing, prometheus.GaugeValue, float64(s.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceled, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyWait, prometheus.CounterValue, s.EmptyAcquireWaitTime().Seconds())
+}
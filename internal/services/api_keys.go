@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/storage"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyPrefix — префикс значения API-ключа, позволяющий отличить его от других секретов.
+const apiKeyPrefix = "oss_"
+
+// apiKeysStorage — отвечает за хранение API-ключей.
+type apiKeysStorage interface {
+	CreateAPIKey(ctx context.Context, key models.APIKeyDTO) (models.APIKeyDTO, error)
+	ReadAPIKeys(ctx context.Context, tenantID uuid.UUID) ([]models.APIKeyDTO, error)
+	ReadAPIKeyByHash(ctx context.Context, hash string) (models.APIKeyDTO, error)
+	RevokeAPIKey(ctx context.Context, tenantID, id uuid.UUID) error
+}
+
+// APIKeysService — сервисный слой для управления API-ключами и их проверки.
+// Управлять ключами может только администратор, ключи создаются в его организации.
+type APIKeysService struct {
+	keys apiKeysStorage
+}
+
+// NewAPIKeysService — конструктор сервиса API-ключей.
+func NewAPIKeysService(keysStorage *storage.APIKeysStorage) *APIKeysService {
+	return &APIKeysService{
+		keys: keysStorage,
+	}
+}
+
+// CreateKey — создаёт API-ключ с указанными разрешениями и необязательным сроком действия.
+// Возвращает ключ вместе с его значением; повторно получить значение нельзя.
+func (s *APIKeysService) CreateKey(ctx context.Context, req models.CreateAPIKeyRequest) (models.CreatedAPIKey, error) {
+	logger.FromContext(ctx).Info("start creating api key")
+	id, err := requireAdmin(ctx)
+	if err != nil {
+		return models.CreatedAPIKey{}, err
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return models.CreatedAPIKey{}, fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+
+	if len(req.Scopes) == 0 {
+		return models.CreatedAPIKey{}, fmt.Errorf("%w: at least one scope is required", ErrInvalidInput)
+	}
+
+	for _, scope := range req.Scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			return models.CreatedAPIKey{}, fmt.Errorf("%w: unknown scope %q", ErrInvalidInput, scope)
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return models.CreatedAPIKey{}, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidInput)
+	}
+
+	raw, err := generateToken(apiKeyPrefix)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.CreatedAPIKey{}, errors.New("error create api key")
+	}
+
+	key, err := s.keys.CreateAPIKey(ctx, models.APIKeyDTO{
+		TenantID:  id.TenantID,
+		Name:      req.Name,
+		Prefix:    raw[:len(apiKeyPrefix)+6],
+		KeyHash:   hashToken(raw),
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.CreatedAPIKey{}, errors.New("error create api key")
+	}
+
+	return models.CreatedAPIKey{APIKey: key.ToAPIKey(), Key: raw}, nil
+}
+
+// ListKeys — возвращает все API-ключи без их значений.
+func (s *APIKeysService) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	logger.FromContext(ctx).Info("start listing api keys")
+	keys := make([]models.APIKey, 0)
+
+	id, err := requireAdmin(ctx)
+	if err != nil {
+		return keys, err
+	}
+
+	slice, err := s.keys.ReadAPIKeys(ctx, id.TenantID)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return keys, errors.New("error list api keys")
+	}
+
+	for _, v := range slice {
+		keys = append(keys, v.ToAPIKey())
+	}
+
+	return keys, nil
+}
+
+// RevokeKey — отзывает API-ключ по UUID.
+func (s *APIKeysService) RevokeKey(ctx context.Context, keyID uuid.UUID) error {
+	logger.FromContext(ctx).Info("start revoking api key")
+	id, err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.keys.RevokeAPIKey(ctx, id.TenantID, keyID); err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return errors.New("error revoke api key")
+	}
+
+	return nil
+}
+
+// Authenticate — проверяет значение API-ключа и возвращает соответствующую личность.
+// Возвращает ErrInvalidAPIKey, если ключ не найден, отозван или истёк.
+func (s *APIKeysService) Authenticate(ctx context.Context, raw string) (auth.Identity, error) {
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		return auth.Identity{}, ErrInvalidAPIKey
+	}
+
+	key, err := s.keys.ReadAPIKeyByHash(ctx, hashToken(raw))
+	if errors.Is(err, storage.ErrAPIKeyNotFound) {
+		return auth.Identity{}, ErrInvalidAPIKey
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return auth.Identity{}, errors.New("error check api key")
+	}
+
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now())) {
+		return auth.Identity{}, ErrInvalidAPIKey
+	}
+
+	return auth.Identity{
+		Subject:  "api_key:" + key.ID.String(),
+		TenantID: key.TenantID,
+		KeyID:    key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
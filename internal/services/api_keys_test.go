@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/storage"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeAPIKeysStorage — хранилище API-ключей в памяти, считающее поиски по хэшу.
+type fakeAPIKeysStorage struct {
+	apiKeysStorage
+	keys    []models.APIKeyDTO
+	lookups int
+	err     error
+}
+
+func (f *fakeAPIKeysStorage) CreateAPIKey(_ context.Context, key models.APIKeyDTO) (models.APIKeyDTO, error) {
+	key.ID = uuid.New()
+	key.CreatedAt = time.Now()
+	f.keys = append(f.keys, key)
+	return key, nil
+}
+
+func (f *fakeAPIKeysStorage) ReadAPIKeyByHash(_ context.Context, hash string) (models.APIKeyDTO, error) {
+	f.lookups++
+	if f.err != nil {
+		return models.APIKeyDTO{}, f.err
+	}
+	for _, key := range f.keys {
+		if key.KeyHash == hash {
+			return key, nil
+		}
+	}
+	return models.APIKeyDTO{}, storage.ErrAPIKeyNotFound
+}
+
+func TestAPIKeysServiceAuthenticate(t *testing.T) {
+	tenant := uuid.New()
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	scopes := []string{auth.ScopeSubscriptionsRead, auth.ScopeReportsRead}
+
+	tests := []struct {
+		name        string
+		raw         string
+		key         *models.APIKeyDTO // ключ в хранилище с хэшем raw
+		storageErr  error
+		wantErr     error
+		wantLookups int
+	}{
+		{name: "valid", raw: apiKeyPrefix + "valid", key: &models.APIKeyDTO{Scopes: scopes}, wantLookups: 1},
+		{name: "not expired yet", raw: apiKeyPrefix + "future", key: &models.APIKeyDTO{Scopes: scopes, ExpiresAt: &future}, wantLookups: 1},
+		{name: "revoked", raw: apiKeyPrefix + "revoked", key: &models.APIKeyDTO{Scopes: scopes, RevokedAt: &past}, wantErr: ErrInvalidAPIKey, wantLookups: 1},
+		{name: "expired", raw: apiKeyPrefix + "expired", key: &models.APIKeyDTO{Scopes: scopes, ExpiresAt: &past}, wantErr: ErrInvalidAPIKey, wantLookups: 1},
+		{name: "unknown", raw: apiKeyPrefix + "unknown", wantErr: ErrInvalidAPIKey, wantLookups: 1},
+		{name: "foreign prefix is not looked up", raw: "ossr_refresh-token", wantErr: ErrInvalidAPIKey},
+		{name: "empty", raw: "", wantErr: ErrInvalidAPIKey},
+		{name: "storage failure", raw: apiKeyPrefix + "valid", storageErr: errors.New("connection refused"), wantLookups: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeAPIKeysStorage{err: tt.storageErr}
+			if tt.key != nil {
+				key := *tt.key
+				key.ID, key.TenantID, key.KeyHash = uuid.New(), tenant, hashToken(tt.raw)
+				store.keys = append(store.keys, key)
+			}
+			s := &APIKeysService{keys: store}
+
+			id, err := s.Authenticate(context.Background(), tt.raw)
+			if store.lookups != tt.wantLookups {
+				t.Errorf("storage lookups = %d, want %d", store.lookups, tt.wantLookups)
+			}
+			if tt.storageErr != nil {
+				// Сбой хранилища не выдаётся за невалидный ключ и не раскрывает подробностей.
+				if err == nil || errors.Is(err, ErrInvalidAPIKey) || strings.Contains(err.Error(), tt.storageErr.Error()) {
+					t.Errorf("Authenticate() error = %v, want generic error", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			key := store.keys[0]
+			if id.KeyID != key.ID || id.TenantID != tenant || id.Subject != "api_key:"+key.ID.String() {
+				t.Errorf("Authenticate() = %+v, want identity of key %s in %s", id, key.ID, tenant)
+			}
+			if !id.IsAPIKey() || id.UserID != uuid.Nil || len(id.Roles) != 0 {
+				t.Errorf("Authenticate() = %+v, want api key identity without user and roles", id)
+			}
+			if !slices.Equal(id.Scopes, scopes) || !id.HasScope(auth.ScopeReportsRead) || id.HasScope(auth.ScopeSubscriptionsWrite) {
+				t.Errorf("Authenticate() scopes = %v, want %v", id.Scopes, scopes)
+			}
+		})
+	}
+}
+
+func TestAPIKeysServiceCreateKey(t *testing.T) {
+	tenant := uuid.New()
+	store := &fakeAPIKeysStorage{}
+	s := &APIKeysService{keys: store}
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{TenantID: tenant, UserID: uuid.New(), Roles: []string{auth.RoleAdmin}})
+
+	created, err := s.CreateKey(ctx, models.CreateAPIKeyRequest{Name: "billing", Scopes: []string{auth.ScopeSubscriptionsRead}})
+	if err != nil {
+		t.Fatalf("CreateKey() error = %v", err)
+	}
+	if !strings.HasPrefix(created.Key, apiKeyPrefix) || !strings.HasPrefix(created.Key, store.keys[0].Prefix) {
+		t.Errorf("CreateKey() key = %q, stored prefix %q, want %q prefix", created.Key, store.keys[0].Prefix, apiKeyPrefix)
+	}
+	if store.keys[0].KeyHash == created.Key || store.keys[0].TenantID != tenant {
+		t.Errorf("stored key = %+v, want hashed key of %s", store.keys[0], tenant)
+	}
+
+	// Созданный ключ сразу проходит проверку с теми же разрешениями.
+	id, err := s.Authenticate(context.Background(), created.Key)
+	if err != nil || id.KeyID != created.ID || !slices.Equal(id.Scopes, []string{auth.ScopeSubscriptionsRead}) {
+		t.Errorf("Authenticate() = %+v, %v, want key %s with its scopes", id, err, created.ID)
+	}
+
+	invalid := []models.CreateAPIKeyRequest{
+		{Scopes: []string{auth.ScopeSubscriptionsRead}},
+		{Name: "no scopes"},
+		{Name: "unknown scope", Scopes: []string{"subscriptions:admin"}},
+		{Name: "expired", Scopes: []string{auth.ScopeSubscriptionsRead}, ExpiresAt: new(time.Time)},
+	}
+	for _, req := range invalid {
+		if _, err := s.CreateKey(ctx, req); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("CreateKey(%+v) error = %v, want ErrInvalidInput", req, err)
+		}
+	}
+
+	user := auth.WithIdentity(context.Background(), auth.Identity{TenantID: tenant, UserID: uuid.New()})
+	if _, err := s.CreateKey(user, models.CreateAPIKeyRequest{Name: "billing", Scopes: []string{auth.ScopeSubscriptionsRead}}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("CreateKey() by user error = %v, want ErrForbidden", err)
+	}
+}
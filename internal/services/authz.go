@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"online_subscription_service/internal/auth"
+
+	"github.com/google/uuid"
+)
+
+// caller — возвращает личность вызывающей стороны из контекста.
+// Личность без организации считается неаутентифицированной, чтобы запрос
+// не мог обратиться к данным вне какой-либо организации.
+func caller(ctx context.Context) (auth.Identity, error) {
+	id, ok := auth.FromContext(ctx)
+	if !ok || id.TenantID == uuid.Nil {
+		return auth.Identity{}, ErrUnauthenticated
+	}
+	return id, nil
+}
+
+// authorize — проверяет, что вызывающая сторона может работать с подписками пользователя ownerID.
+// Администратор и сервисные API-ключи имеют доступ ко всем подпискам (ключи ограничены
+// только своими разрешениями), обычный пользователь — только к своим.
+// Проверка выполняется в пределах организации вызывающей стороны, которая возвращается
+// вместе с личностью и используется для всех последующих запросов к хранилищу.
+func authorize(ctx context.Context, ownerID uuid.UUID) (auth.Identity, error) {
+	id, err := caller(ctx)
+	if err != nil {
+		return id, err
+	}
+
+	if unrestricted(id) || id.UserID == ownerID {
+		return id, nil
+	}
+
+	return id, ErrForbidden
+}
+
+// authorizeUsers — проверяет, что вызывающая сторона может работать с подписками всех
+// пользователей userIDs; при доступе не ко всем возвращает ErrForbidden.
+func authorizeUsers(ctx context.Context, userIDs []uuid.UUID) (auth.Identity, error) {
+	id, err := caller(ctx)
+	if err != nil {
+		return id, err
+	}
+
+	if unrestricted(id) {
+		return id, nil
+	}
+	for _, userID := range userIDs {
+		if userID != id.UserID {
+			return id, ErrForbidden
+		}
+	}
+
+	return id, nil
+}
+
+// unrestricted — проверяет, что вызывающая сторона видит подписки всех пользователей.
+func unrestricted(id auth.Identity) bool {
+	return id.IsAdmin() || id.IsPlatformAdmin() || id.IsAPIKey()
+}
+
+// requireAdmin — проверяет, что вызывающая сторона является администратором организации.
+func requireAdmin(ctx context.Context) (auth.Identity, error) {
+	id, err := caller(ctx)
+	if err != nil {
+		return id, err
+	}
+
+	if !id.IsAdmin() && !id.IsPlatformAdmin() {
+		return id, ErrForbidden
+	}
+
+	return id, nil
+}
+
+// requireAdminOrService — проверяет, что вызывающая сторона является администратором
+// организации или сервисом с API-ключом; разрешения ключа проверяются на уровне маршрутов.
+func requireAdminOrService(ctx context.Context) (auth.Identity, error) {
+	id, err := caller(ctx)
+	if err != nil {
+		return id, err
+	}
+
+	if !id.IsAdmin() && !id.IsPlatformAdmin() && !id.IsAPIKey() {
+		return id, ErrForbidden
+	}
+
+	return id, nil
+}
+
+// requirePlatformAdmin — проверяет, что вызывающая сторона является администратором платформы.
+func requirePlatformAdmin(ctx context.Context) (auth.Identity, error) {
+	id, err := caller(ctx)
+	if err != nil {
+		return id, err
+	}
+
+	if !id.IsPlatformAdmin() {
+		return id, ErrForbidden
+	}
+
+	return id, nil
+}
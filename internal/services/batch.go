@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/lib/tracing"
+	"online_subscription_service/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+// errBatchAborted — операция пакета завершилась ошибкой, и её транзакция должна быть отменена.
+var errBatchAborted = errors.New("batch operation failed")
+
+// BatchSubscriptions — выполняет пакет операций создания, изменения и удаления подписок
+// с теми же проверками прав, квот и существования подписок, что и отдельные операции.
+//
+// В режиме atomic (по умолчанию) все операции выполняются по порядку в одной транзакции:
+// первая ошибка прекращает выполнение, и транзакция откатывается — выполненные операции
+// получают статус rolled_back, оставшиеся — skipped. В режиме best_effort каждая операция
+// выполняется в своей транзакции, и ошибка одной не влияет на остальные.
+//
+// Ошибки отдельных операций возвращаются в их результатах; ошибка метода означает,
+// что пакет отклонён целиком или его не удалось зафиксировать.
+func (s *SubsService) BatchSubscriptions(ctx context.Context, req models.BatchRequest) (_ models.BatchResult, err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.BatchSubscriptions")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start executing batch of subscriptions")
+	if _, err := caller(ctx); err != nil {
+		return models.BatchResult{}, err
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = models.BatchAtomic
+	}
+	if mode != models.BatchAtomic && mode != models.BatchBestEffort {
+		return models.BatchResult{}, fmt.Errorf("%w: mode must be atomic or best_effort", ErrInvalidInput)
+	}
+	if len(req.Operations) == 0 || len(req.Operations) > models.MaxBatchOperations {
+		return models.BatchResult{}, fmt.Errorf("%w: batch must contain from 1 to %d operations", ErrInvalidInput, models.MaxBatchOperations)
+	}
+
+	result := models.BatchResult{Mode: mode, Results: make([]models.BatchItemResult, len(req.Operations))}
+	for i, op := range req.Operations {
+		result.Results[i] = models.BatchItemResult{Index: i, Op: op.Op, Status: models.BatchItemSkipped}
+	}
+
+	if mode == models.BatchAtomic {
+		err := s.subsTx.WithinTx(ctx, func(tx *storage.SubsStorage) error {
+			txService := s.withStorage(tx)
+			for i, op := range req.Operations {
+				if !txService.applyBatchOperation(ctx, op, &result.Results[i]) {
+					return errBatchAborted
+				}
+			}
+			return nil
+		})
+		switch {
+		case err == nil:
+			result.Committed = true
+		case errors.Is(err, errBatchAborted):
+			for i := range result.Results {
+				res := &result.Results[i]
+				if res.Status != models.BatchItemOK {
+					continue
+				}
+				res.Status = models.BatchItemRolledBack
+				if res.Op == models.BatchCreate {
+					// Созданная подписка отменена вместе с транзакцией.
+					res.ID = nil
+				}
+			}
+		default:
+			logger.FromContext(ctx).Error(err.Error())
+			return models.BatchResult{}, errors.New("error executing batch")
+		}
+	} else {
+		for i, op := range req.Operations {
+			res := &result.Results[i]
+			err := s.subsTx.WithinTx(ctx, func(tx *storage.SubsStorage) error {
+				if !s.withStorage(tx).applyBatchOperation(ctx, op, res) {
+					return errBatchAborted
+				}
+				return nil
+			})
+			if err != nil && !errors.Is(err, errBatchAborted) {
+				logger.FromContext(ctx).Error(err.Error())
+				res.Status = models.BatchItemFailed
+				res.Err = errors.New("error executing batch operation")
+				res.Error = res.Err.Error()
+			}
+			if res.Status == models.BatchItemOK {
+				result.Committed = true
+			}
+		}
+	}
+
+	for _, res := range result.Results {
+		switch res.Status {
+		case models.BatchItemOK:
+			result.Succeeded++
+		case models.BatchItemFailed:
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
+
+// applyBatchOperation — выполняет операцию пакета и записывает её итог в res.
+// Возвращает false, если операция завершилась ошибкой.
+func (s *SubsService) applyBatchOperation(ctx context.Context, op models.BatchOperation, res *models.BatchItemResult) bool {
+	var err error
+
+	switch op.Op {
+	case models.BatchCreate:
+		switch {
+		case op.ID != nil:
+			err = fmt.Errorf("%w: id must not be set for create", ErrInvalidInput)
+		case op.Name == nil || *op.Name == "" || op.Price == nil:
+			err = fmt.Errorf("%w: service_name and price are required for create", ErrInvalidInput)
+		default:
+			var id uuid.UUID
+			if id, err = s.AddSubscription(ctx, op.ToSubsDTO()); err == nil {
+				res.ID = &id
+			}
+		}
+	case models.BatchUpdate:
+		update := op.ToSubsUpdateDTO()
+		switch {
+		case op.ID == nil:
+			err = fmt.Errorf("%w: id is required for update", ErrInvalidInput)
+		case update == models.SubsUpdateDTO{}:
+			err = fmt.Errorf("%w: nothing to update", ErrInvalidInput)
+		default:
+			res.ID = op.ID
+			err = s.EditSubscription(ctx, *op.ID, update)
+		}
+	case models.BatchDelete:
+		if op.ID == nil {
+			err = fmt.Errorf("%w: id is required for delete", ErrInvalidInput)
+		} else {
+			res.ID = op.ID
+			err = s.RemoveSubscription(ctx, *op.ID)
+		}
+	default:
+		err = fmt.Errorf("%w: op must be create, update or delete", ErrInvalidInput)
+	}
+
+	if err != nil {
+		res.Status = models.BatchItemFailed
+		res.Err = err
+		res.Error = err.Error()
+		return false
+	}
+
+	res.Status = models.BatchItemOK
+	return true
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"online_subscription_service/internal/domain/models"
+	"time"
+)
+
+// Стоимость подписок за период и журнал начислений считаются по одной модели: цена подписки —
+// месячная и списывается в первый день каждого расчётного периода (billingPeriods). Подписка
+// стоит за период столько, сколько её списаний пришлось на дни периода, в которые она действует.
+
+// periodCost — стоимость подписки sub за период [from, to]: цена, умноженная на число
+// списаний в периоде.
+func periodCost(sub models.SubsDTO, from, to time.Time) int {
+	return sub.Price * len(billingPeriods(sub, from, to))
+}
+
+// billingPeriods — расчётные периоды подписки, дата начала которых лежит в [from, to]
+// и не позже даты окончания подписки. Каждый период — пара из первого и последнего дня.
+// Расчётные периоды — месяцы, отсчитываемые от даты начала подписки (с 31 января следующее
+// списание — 28 или 29 февраля, затем 31 марта), а цена подписки списывается в первый
+// день каждого периода.
+func billingPeriods(sub models.SubsDTO, from, to time.Time) [][2]time.Time {
+	var periods [][2]time.Time
+
+	// Первые периоды, заведомо закончившиеся до from, пропускаются.
+	k := 0
+	if from.After(sub.StartDate) {
+		k = max((from.Year()-sub.StartDate.Year())*12+int(from.Month()-sub.StartDate.Month())-1, 0)
+	}
+
+	for ; ; k++ {
+		start := addMonths(sub.StartDate, k)
+		if start.After(to) || (sub.EndDate != nil && start.After(*sub.EndDate)) {
+			break
+		}
+		if start.Before(from) {
+			continue
+		}
+		periods = append(periods, [2]time.Time{start, addMonths(sub.StartDate, k+1).AddDate(0, 0, -1)})
+	}
+
+	return periods
+}
+
+// addMonths — дата через n месяцев после t. Если в том месяце нет такого дня,
+// берётся последний день месяца.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		n    int
+		want time.Time
+	}{
+		{t: date(2025, time.January, 15), n: 1, want: date(2025, time.February, 15)},
+		{t: date(2024, time.January, 31), n: 1, want: date(2024, time.February, 29)},
+		{t: date(2023, time.January, 31), n: 1, want: date(2023, time.February, 28)},
+		{t: date(2024, time.January, 31), n: 2, want: date(2024, time.March, 31)},
+		{t: date(2024, time.August, 31), n: 1, want: date(2024, time.September, 30)},
+		{t: date(2024, time.December, 15), n: 1, want: date(2025, time.January, 15)},
+		{t: date(2024, time.January, 31), n: 13, want: date(2025, time.February, 28)},
+		{t: date(2024, time.March, 31), n: -1, want: date(2024, time.February, 29)},
+		{t: date(2024, time.March, 31), n: 0, want: date(2024, time.March, 31)},
+	}
+
+	for _, tt := range tests {
+		if got := addMonths(tt.t, tt.n); !got.Equal(tt.want) {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", tt.t.Format(time.DateOnly), tt.n, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestBillingPeriods(t *testing.T) {
+	endDate := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name     string
+		sub      models.SubsDTO
+		from, to time.Time
+		want     [][2]time.Time
+	}{
+		{
+			name: "month ends in a leap year",
+			sub:  models.SubsDTO{StartDate: date(2024, time.January, 31)},
+			from: date(2024, time.January, 1), to: date(2024, time.April, 30),
+			want: [][2]time.Time{
+				{date(2024, time.January, 31), date(2024, time.February, 28)},
+				{date(2024, time.February, 29), date(2024, time.March, 30)},
+				{date(2024, time.March, 31), date(2024, time.April, 29)},
+				{date(2024, time.April, 30), date(2024, time.May, 30)},
+			},
+		},
+		{
+			name: "month ends in a common year",
+			sub:  models.SubsDTO{StartDate: date(2023, time.January, 31)},
+			from: date(2023, time.February, 1), to: date(2023, time.March, 31),
+			want: [][2]time.Time{
+				{date(2023, time.February, 28), date(2023, time.March, 30)},
+				{date(2023, time.March, 31), date(2023, time.April, 29)},
+			},
+		},
+		{
+			name: "from in the middle of a period",
+			sub:  models.SubsDTO{StartDate: date(2025, time.January, 15)},
+			from: date(2025, time.February, 1), to: date(2025, time.March, 31),
+			want: [][2]time.Time{
+				{date(2025, time.February, 15), date(2025, time.March, 14)},
+				{date(2025, time.March, 15), date(2025, time.April, 14)},
+			},
+		},
+		{
+			name: "from and to inside one period",
+			sub:  models.SubsDTO{StartDate: date(2025, time.January, 15)},
+			from: date(2025, time.January, 20), to: date(2025, time.February, 10),
+		},
+		{
+			name: "bounds are inclusive",
+			sub:  models.SubsDTO{StartDate: date(2025, time.January, 15)},
+			from: date(2025, time.February, 15), to: date(2025, time.March, 15),
+			want: [][2]time.Time{
+				{date(2025, time.February, 15), date(2025, time.March, 14)},
+				{date(2025, time.March, 15), date(2025, time.April, 14)},
+			},
+		},
+		{
+			name: "end date inside a period",
+			sub:  models.SubsDTO{StartDate: date(2025, time.January, 15), EndDate: endDate(date(2025, time.March, 10))},
+			from: date(2025, time.January, 1), to: date(2025, time.December, 31),
+			want: [][2]time.Time{
+				{date(2025, time.January, 15), date(2025, time.February, 14)},
+				{date(2025, time.February, 15), date(2025, time.March, 14)},
+			},
+		},
+		{
+			name: "end date on a charge day",
+			sub:  models.SubsDTO{StartDate: date(2025, time.January, 15), EndDate: endDate(date(2025, time.February, 15))},
+			from: date(2025, time.January, 1), to: date(2025, time.December, 31),
+			want: [][2]time.Time{
+				{date(2025, time.January, 15), date(2025, time.February, 14)},
+				{date(2025, time.February, 15), date(2025, time.March, 14)},
+			},
+		},
+		{
+			name: "ended before from",
+			sub:  models.SubsDTO{StartDate: date(2024, time.January, 15), EndDate: endDate(date(2024, time.June, 1))},
+			from: date(2025, time.January, 1), to: date(2025, time.December, 31),
+		},
+		{
+			name: "starts after to",
+			sub:  models.SubsDTO{StartDate: date(2026, time.January, 1)},
+			from: date(2025, time.January, 1), to: date(2025, time.December, 31),
+		},
+		{
+			name: "years after the start",
+			sub:  models.SubsDTO{StartDate: date(2020, time.January, 31)},
+			from: date(2025, time.February, 1), to: date(2025, time.February, 28),
+			want: [][2]time.Time{
+				{date(2025, time.February, 28), date(2025, time.March, 30)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := billingPeriods(tt.sub, tt.from, tt.to); !slices.Equal(got, tt.want) {
+				t.Errorf("billingPeriods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBillingPeriodsSkip — пропуск первых периодов не теряет списаний: результат
+// совпадает с перебором всех периодов с даты начала подписки.
+func TestBillingPeriodsSkip(t *testing.T) {
+	scan := func(sub models.SubsDTO, from, to time.Time) [][2]time.Time {
+		var periods [][2]time.Time
+		for k := 0; !addMonths(sub.StartDate, k).After(to); k++ {
+			if start := addMonths(sub.StartDate, k); !start.Before(from) {
+				periods = append(periods, [2]time.Time{start, addMonths(sub.StartDate, k+1).AddDate(0, 0, -1)})
+			}
+		}
+		return periods
+	}
+
+	for _, start := range []time.Time{date(2024, time.January, 1), date(2024, time.January, 15), date(2024, time.January, 31), date(2023, time.March, 31)} {
+		sub := models.SubsDTO{StartDate: start}
+		for from := start.AddDate(0, 0, -3); from.Before(start.AddDate(2, 0, 0)); from = from.AddDate(0, 0, 1) {
+			to := from.AddDate(0, 2, 0)
+			if got, want := billingPeriods(sub, from, to), scan(sub, from, to); !slices.Equal(got, want) {
+				t.Fatalf("start %s, from %s: billingPeriods() = %v, want %v", start.Format(time.DateOnly), from.Format(time.DateOnly), got, want)
+			}
+		}
+	}
+}
+
+func TestSubsServiceGetPricesWithPeriod(t *testing.T) {
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	end := date(2025, time.March, 20)
+	provider := &fakeSubsProvider{subs: []models.SubsDTO{
+		// 12 списаний за год.
+		{UserID: first, Price: 100, StartDate: date(2024, time.December, 31)},
+		// Списания 15 января, февраля и марта.
+		{UserID: first, Price: 10, StartDate: date(2025, time.January, 15), EndDate: &end},
+		// Одно списание 1 декабря.
+		{UserID: second, Price: 500, StartDate: date(2025, time.December, 1)},
+	}}
+	s := &SubsService{subsProvider: provider}
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{TenantID: uuid.New(), Roles: []string{auth.RoleAdmin}})
+	from, to := date(2025, time.January, 1), date(2025, time.December, 31)
+
+	prices, err := s.GetPricesWithPeriod(ctx, from, to, []uuid.UUID{first, second, third}, "Netflix")
+	if err != nil {
+		t.Fatalf("GetPricesWithPeriod() error = %v", err)
+	}
+	want := map[uuid.UUID]int{first: 1230, second: 500, third: 0}
+	for userID, price := range want {
+		if got, ok := prices[userID]; !ok || got != price {
+			t.Errorf("price of %s = %d (present %v), want %d", userID, got, ok, price)
+		}
+	}
+
+	// Стоимость одного пользователя равна сумме его начислений в журнале за тот же период.
+	provider.subs = provider.subs[:2]
+	price, err := s.GetPriceWithPeriod(ctx, from, to, first, "Netflix")
+	if err != nil || price != want[first] {
+		t.Errorf("GetPriceWithPeriod() = %d, %v, want %d", price, err, want[first])
+	}
+	if !slices.Equal(provider.users, []uuid.UUID{first}) {
+		t.Errorf("storage users = %v, want %v", provider.users, []uuid.UUID{first})
+	}
+
+	journal := &JournalService{subs: provider, cfg: config.LedgerConfig{
+		ExpenseAccount: "Expenses:Subscriptions", PaymentAccount: "Liabilities:CreditCard", Currency: "RUB",
+	}}
+	entries, err := journal.GetJournal(ctx, models.JournalOptions{From: from, To: to, UserID: first, ServiceName: "Netflix"})
+	if err != nil {
+		t.Fatalf("GetJournal() error = %v", err)
+	}
+	total := 0
+	for _, e := range entries {
+		total += e.Amount
+	}
+	if total != price {
+		t.Errorf("journal total = %d, want price %d", total, price)
+	}
+
+	if _, err := s.GetPriceWithPeriod(ctx, from, to, first, ""); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("GetPriceWithPeriod() without service error = %v, want ErrInvalidInput", err)
+	}
+}
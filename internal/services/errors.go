@@ -0,0 +1,47 @@
+package services
+
+import "errors"
+
+// Ошибки сервисного слоя, которые транспортный слой сопоставляет с кодами ответа.
+var (
+	// ErrUnauthenticated — в контексте нет личности вызывающей стороны.
+	ErrUnauthenticated = errors.New("authentication required")
+	// ErrForbidden — вызывающая сторона не владеет запрошенной подпиской.
+	ErrForbidden = errors.New("access denied")
+	// ErrNotFound — подписка не найдена.
+	ErrNotFound = errors.New("subscription not found")
+	// ErrInvalidInput — входные данные не прошли проверку.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrAPIKeyNotFound — API-ключ не найден.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrInvalidAPIKey — API-ключ не существует, отозван или истёк.
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrInvalidCredentials — неверный email или пароль.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrInvalidRefreshToken — refresh-токен не существует, отозван или истёк.
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrUserExists — пользователь с таким email уже зарегистрирован.
+	ErrUserExists = errors.New("user already exists")
+	// ErrRegistrationDisabled — самостоятельная регистрация отключена.
+	ErrRegistrationDisabled = errors.New("registration is disabled")
+	// ErrQuotaExceeded — превышена квота на количество подписок пользователя.
+	ErrQuotaExceeded = errors.New("subscription quota exceeded")
+	// ErrTenantNotFound — организация не найдена.
+	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrTenantExists — организация с таким названием уже существует.
+	ErrTenantExists = errors.New("tenant already exists")
+	// ErrImportNotFound — задача импорта не найдена.
+	ErrImportNotFound = errors.New("import not found")
+	// ErrWebhookNotFound — вебхук не найден.
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrDeliveryNotFound — доставка события на вебхук не найдена.
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrProviderNotFound — провайдер подписок не настроен.
+	ErrProviderNotFound = errors.New("provider not found")
+	// ErrInvalidSignature — подпись вебхука провайдера отсутствует или неверна.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrLinkNotFound — подписка не связана с провайдером.
+	ErrLinkNotFound = errors.New("subscription link not found")
+	// ErrLinkExists — идентификатор подписки у провайдера уже связан с другой подпиской.
+	ErrLinkExists = errors.New("subscription link already exists")
+)
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/storage"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// subsEventWriter — отвечает за запись событий об изменении подписок в outbox.
+type subsEventWriter interface {
+	AppendEvents(ctx context.Context, events []models.Event) error
+}
+
+// subsEvent — событие об изменении подписки, которое нужно записать в outbox.
+type subsEvent struct {
+	eventType models.EventType
+	data      models.SubsEventData
+}
+
+// createdEvent — событие о создании подписки sub.
+func createdEvent(sub models.SubsDTO) subsEvent {
+	return subsEvent{eventType: models.EventSubsCreated, data: models.SubsEventData{Subscription: sub.ToSubs()}}
+}
+
+// updatedEvents — события об изменении подписки prev на cur: subscription.updated
+// и, если изменение перевело подписку в другой статус на момент at, subscription.status_changed.
+func updatedEvents(prev, cur models.SubsDTO, at time.Time) []subsEvent {
+	previous := prev.ToSubs()
+	events := []subsEvent{{
+		eventType: models.EventSubsUpdated,
+		data:      models.SubsEventData{Subscription: cur.ToSubs(), Previous: &previous},
+	}}
+
+	if from, to := prev.Status(at), cur.Status(at); from != to {
+		events = append(events, subsEvent{
+			eventType: models.EventSubsStatusChanged,
+			data:      models.SubsEventData{Subscription: cur.ToSubs(), From: from, To: to},
+		})
+	}
+
+	return events
+}
+
+// deletedEvent — событие об удалении подписки sub.
+func deletedEvent(sub models.SubsDTO) subsEvent {
+	return subsEvent{eventType: models.EventSubsDeleted, data: models.SubsEventData{Subscription: sub.ToSubs()}}
+}
+
+// appendSubsEvents — записывает события организации tenantID через w в порядке следования.
+// Если w равен nil (outbox отключён), события не записываются.
+func appendSubsEvents(ctx context.Context, w subsEventWriter, tenantID uuid.UUID, events ...subsEvent) error {
+	if w == nil || len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	out := make([]models.Event, 0, len(events))
+	for _, e := range events {
+		data, err := json.Marshal(e.data)
+		if err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+		out = append(out, models.Event{
+			ID:             uuid.New(),
+			Type:           e.eventType,
+			TenantID:       tenantID,
+			SubscriptionID: e.data.Subscription.ID,
+			OccurredAt:     now,
+			Data:           data,
+		})
+	}
+
+	return w.AppendEvents(ctx, out)
+}
+
+// inTx — выполняет fn с копией сервиса, работающей в одной транзакции хранилища,
+// так что изменения подписок и события о них сохраняются вместе.
+// Ошибка fn возвращается как есть; ошибка начала или фиксации транзакции
+// логируется и заменяется на ошибку с текстом errMsg.
+func (s *SubsService) inTx(ctx context.Context, errMsg string, fn func(tx *SubsService) error) error {
+	var fnErr error
+	err := s.subsTx.WithinTx(ctx, func(tx *storage.SubsStorage) error {
+		fnErr = fn(s.withStorage(tx))
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"fmt"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/spreadsheet"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Поля подписки, которые заполняются из колонок файла импорта.
+const (
+	fieldServiceName = "service_name"
+	fieldPrice       = "price"
+	fieldUserID      = "user_id"
+	fieldStartDate   = "start_date"
+	fieldEndDate     = "end_date"
+)
+
+// defaultDateFormats — форматы дат, которые распознаются, если формат не указан.
+var defaultDateFormats = []string{"MM-YYYY", "YYYY-MM-DD", "DD.MM.YYYY", "MM.YYYY", "YYYY-MM"}
+
+// importParser — разбирает строки файла импорта в подписки.
+type importParser struct {
+	columns    map[string]int    // поле → индекс колонки (-1, если необязательной колонки нет)
+	names      map[string]string // поле → название колонки в файле
+	formats    []string          // форматы дат в виде MM-YYYY
+	layouts    []string          // те же форматы в виде раскладок time.Parse
+	excelDates bool              // даты могут храниться числом дней Excel
+}
+
+// newImportParser — находит колонки полей по заголовку файла и проверяет формат дат.
+// Колонки service_name, price и start_date обязательны; user_id и end_date — нет,
+// но если колонка указана в сопоставлении явно, она должна быть в файле.
+func newImportParser(header spreadsheet.Row, opts models.ImportOptions) (*importParser, error) {
+	p := &importParser{
+		columns:    make(map[string]int),
+		names:      make(map[string]string),
+		excelDates: opts.Format == spreadsheet.FormatXLSX,
+	}
+
+	index := make(map[string]int, len(header.Cells))
+	for i, cell := range header.Cells {
+		name := strings.ToLower(strings.TrimSpace(cell))
+		if _, ok := index[name]; !ok && name != "" {
+			index[name] = i
+		}
+	}
+
+	m := opts.Mapping
+	fields := []struct {
+		field, column string
+		required      bool
+	}{
+		{fieldServiceName, m.ServiceName, true},
+		{fieldPrice, m.Price, true},
+		{fieldUserID, m.UserID, false},
+		{fieldStartDate, m.StartDate, true},
+		{fieldEndDate, m.EndDate, false},
+	}
+	for _, f := range fields {
+		column := strings.TrimSpace(f.column)
+		explicit := column != ""
+		if !explicit {
+			column = f.field
+		}
+
+		i, ok := index[strings.ToLower(column)]
+		if !ok {
+			if f.required || explicit {
+				return nil, fmt.Errorf("%w: column %q for %s not found in header", ErrInvalidInput, column, f.field)
+			}
+			i = -1
+		}
+		p.columns[f.field] = i
+		p.names[f.field] = column
+	}
+
+	p.formats = defaultDateFormats
+	if opts.DateFormat != "" {
+		p.formats = []string{opts.DateFormat}
+	}
+	for _, format := range p.formats {
+		layout, err := dateLayout(format)
+		if err != nil {
+			return nil, err
+		}
+		p.layouts = append(p.layouts, layout)
+	}
+
+	return p, nil
+}
+
+// dateLayout — переводит формат даты вида MM-YYYY или DD.MM.YYYY в раскладку time.Parse.
+// Формат должен содержать год (YYYY) и месяц (MM); день (DD) необязателен.
+func dateLayout(format string) (string, error) {
+	layout := strings.NewReplacer("YYYY", "2006", "MM", "01", "DD", "02").Replace(strings.ToUpper(format))
+	if !strings.Contains(layout, "2006") || !strings.Contains(layout, "01") ||
+		strings.IndexFunc(layout, func(r rune) bool { return r >= 'A' && r <= 'Z' }) >= 0 {
+		return "", fmt.Errorf("%w: unsupported date format %q, use YYYY, MM and DD, e.g. MM-YYYY", ErrInvalidInput, format)
+	}
+	return layout, nil
+}
+
+// parse — разбирает строку файла в подписку. Возвращает все ошибки строки сразу.
+// Если колонка user_id пуста, UserID остаётся пустым и определяется вызывающей стороной.
+func (p *importParser) parse(row spreadsheet.Row) (models.SubsDTO, []models.ImportRowError) {
+	var (
+		sub  models.SubsDTO
+		errs []models.ImportRowError
+	)
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, models.ImportRowError{Row: row.Number, Column: p.names[field], Error: fmt.Sprintf(format, args...)})
+	}
+
+	sub.Name = p.cell(row, fieldServiceName)
+	if sub.Name == "" {
+		fail(fieldServiceName, "service_name is required")
+	}
+
+	price, err := strconv.Atoi(strings.Join(strings.Fields(p.cell(row, fieldPrice)), ""))
+	if err != nil || price < 0 {
+		fail(fieldPrice, "price must be a non-negative integer")
+	}
+	sub.Price = price
+
+	if v := p.cell(row, fieldUserID); v != "" {
+		userID, err := uuid.Parse(v)
+		if err != nil {
+			fail(fieldUserID, "invalid user_id")
+		}
+		sub.UserID = userID
+	}
+
+	if v := p.cell(row, fieldStartDate); v == "" {
+		fail(fieldStartDate, "start_date is required")
+	} else if start, ok := p.date(v); ok {
+		sub.StartDate = start
+	} else {
+		fail(fieldStartDate, "start_date must be in format %s", strings.Join(p.formats, ", "))
+	}
+
+	if v := p.cell(row, fieldEndDate); v != "" {
+		end, ok := p.date(v)
+		switch {
+		case !ok:
+			fail(fieldEndDate, "end_date must be in format %s", strings.Join(p.formats, ", "))
+		case !sub.StartDate.IsZero() && end.Before(sub.StartDate):
+			fail(fieldEndDate, "end_date must not be before start_date")
+		default:
+			sub.EndDate = &end
+		}
+	}
+
+	return sub, errs
+}
+
+// cell — значение колонки поля в строке без пробелов по краям.
+func (p *importParser) cell(row spreadsheet.Row, field string) string {
+	i := p.columns[field]
+	if i < 0 || i >= len(row.Cells) {
+		return ""
+	}
+	return strings.TrimSpace(row.Cells[i])
+}
+
+// date — разбирает дату в одном из форматов импорта. Даты без дня
+// (например, MM-YYYY) означают первое число месяца.
+func (p *importParser) date(v string) (time.Time, bool) {
+	for _, layout := range p.layouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	if p.excelDates {
+		return spreadsheet.ExcelDate(v)
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/spreadsheet"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestImportParserParse(t *testing.T) {
+	header := spreadsheet.Row{Number: 1, Cells: []string{"service_name", "price", "user_id", "start_date", "end_date"}}
+	userID := uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba")
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name        string
+		header      spreadsheet.Row // пустой — header
+		opts        models.ImportOptions
+		cells       []string
+		want        models.SubsDTO // для строки без ошибок
+		wantEnd     *time.Time
+		wantColumns []string // колонки с ошибками
+	}{
+		{
+			name:  "month and year",
+			cells: []string{"Yandex Plus", "400", userID.String(), "07-2025", "12-2025"},
+			want:  models.SubsDTO{Name: "Yandex Plus", Price: 400, UserID: userID, StartDate: date(2025, 7, 1)},
+			wantEnd: func() *time.Time {
+				end := date(2025, 12, 1)
+				return &end
+			}(),
+		},
+		{
+			name:  "default formats with day",
+			cells: []string{"Netflix", "799", "", "15.03.2025", "2025-06-30"},
+			want:  models.SubsDTO{Name: "Netflix", Price: 799, StartDate: date(2025, 3, 15)},
+			wantEnd: func() *time.Time {
+				end := date(2025, 6, 30)
+				return &end
+			}(),
+		},
+		{
+			name:  "price with thousands separator",
+			cells: []string{"Office", "1 200", "", "2025-01", ""},
+			want:  models.SubsDTO{Name: "Office", Price: 1200, StartDate: date(2025, 1, 1)},
+		},
+		{
+			name:  "explicit date format",
+			opts:  models.ImportOptions{DateFormat: "MM-YYYY"},
+			cells: []string{"Yandex Plus", "400", "", "01-2025", ""},
+			want:  models.SubsDTO{Name: "Yandex Plus", Price: 400, StartDate: date(2025, 1, 1)},
+		},
+		{
+			name:        "explicit date format rejects others",
+			opts:        models.ImportOptions{DateFormat: "MM-YYYY"},
+			cells:       []string{"Yandex Plus", "400", "", "2025-01-01", ""},
+			wantColumns: []string{fieldStartDate},
+		},
+		{
+			name:  "excel serial date in xlsx",
+			opts:  models.ImportOptions{Format: spreadsheet.FormatXLSX},
+			cells: []string{"Yandex Plus", "400", "", "45658", "45688.75"},
+			want:  models.SubsDTO{Name: "Yandex Plus", Price: 400, StartDate: date(2025, 1, 1)},
+			wantEnd: func() *time.Time {
+				end := date(2025, 1, 31)
+				return &end
+			}(),
+		},
+		{
+			name:        "excel serial date in csv",
+			opts:        models.ImportOptions{Format: spreadsheet.FormatCSV},
+			cells:       []string{"Yandex Plus", "400", "", "45658", ""},
+			wantColumns: []string{fieldStartDate},
+		},
+		{
+			name:        "all row errors at once",
+			cells:       []string{"", "-5", "not-a-uuid", "", "13-2025"},
+			wantColumns: []string{fieldServiceName, fieldPrice, fieldUserID, fieldStartDate, fieldEndDate},
+		},
+		{
+			name:        "end before start",
+			cells:       []string{"Yandex Plus", "400", "", "07-2025", "06-2025"},
+			wantColumns: []string{fieldEndDate},
+		},
+		{
+			name:   "mapped columns",
+			header: spreadsheet.Row{Number: 1, Cells: []string{"Начало", "Сервис", "Цена"}},
+			opts: models.ImportOptions{Mapping: models.ImportMapping{
+				ServiceName: "сервис",
+				Price:       "Цена",
+				StartDate:   "Начало",
+			}},
+			cells: []string{"03-2025", "Kinopoisk", "299"},
+			want:  models.SubsDTO{Name: "Kinopoisk", Price: 299, StartDate: date(2025, 3, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := header
+			if tt.header.Cells != nil {
+				h = tt.header
+			}
+			p, err := newImportParser(h, tt.opts)
+			if err != nil {
+				t.Fatalf("newImportParser() error = %v", err)
+			}
+
+			got, errs := p.parse(spreadsheet.Row{Number: 2, Cells: tt.cells})
+
+			var columns []string
+			for _, e := range errs {
+				if e.Row != 2 {
+					t.Errorf("error row = %d, want 2", e.Row)
+				}
+				columns = append(columns, e.Column)
+			}
+			if !slices.Equal(columns, tt.wantColumns) {
+				t.Errorf("error columns = %v, want %v (errors %+v)", columns, tt.wantColumns, errs)
+			}
+
+			// Поля строки с ошибками не используются, поэтому не сверяются.
+			if len(tt.wantColumns) > 0 {
+				return
+			}
+			if got.Name != tt.want.Name || got.Price != tt.want.Price || got.UserID != tt.want.UserID ||
+				!got.StartDate.Equal(tt.want.StartDate) {
+				t.Errorf("parse() = %+v, want %+v", got, tt.want)
+			}
+			switch {
+			case tt.wantEnd == nil && got.EndDate != nil:
+				t.Errorf("end date = %v, want nil", *got.EndDate)
+			case tt.wantEnd != nil && (got.EndDate == nil || !got.EndDate.Equal(*tt.wantEnd)):
+				t.Errorf("end date = %v, want %v", got.EndDate, *tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestNewImportParserErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		opts   models.ImportOptions
+	}{
+		{
+			name:   "required column missing",
+			header: []string{"service_name", "start_date"},
+		},
+		{
+			name:   "mapped optional column missing",
+			header: []string{"service_name", "price", "start_date"},
+			opts:   models.ImportOptions{Mapping: models.ImportMapping{EndDate: "Окончание"}},
+		},
+		{
+			name:   "date format without month",
+			header: []string{"service_name", "price", "start_date"},
+			opts:   models.ImportOptions{DateFormat: "YYYY"},
+		},
+		{
+			name:   "date format with unknown placeholder",
+			header: []string{"service_name", "price", "start_date"},
+			opts:   models.ImportOptions{DateFormat: "MM-YYYY HH"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newImportParser(spreadsheet.Row{Number: 1, Cells: tt.header}, tt.opts)
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("newImportParser() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,614 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/health"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/lib/spreadsheet"
+	"online_subscription_service/internal/lib/tracing"
+	"online_subscription_service/internal/storage"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxImportErrors — сколько ошибок в строках сохраняется в задаче импорта.
+	maxImportErrors = 1000
+	// importProgressStep — через сколько проверенных строк сохраняется прогресс задачи.
+	importProgressStep = 1000
+	// importFinishTimeout — срок сохранения итогового состояния задачи, в том числе прерванной.
+	importFinishTimeout = 5 * time.Second
+	// importHeartbeatInterval — как часто экземпляр отмечает выполняемые им задачи
+	// и завершает задачи, которые никто не отмечает.
+	importHeartbeatInterval = 30 * time.Second
+	// importStaleAfter — срок без отметки, после которого незавершённая задача считается
+	// брошенной экземпляром, завершившимся аварийно, и отмечается неудавшейся.
+	importStaleAfter = 4 * importHeartbeatInterval
+	// importStallTimeout — срок без прогресса задач, после которого обработчики импорта,
+	// если их ждут задачи в очереди, считаются зависшими.
+	importStallTimeout = 10 * time.Minute
+)
+
+// importsStore — отвечает за хранение задач импорта.
+type importsStore interface {
+	CreateImport(ctx context.Context, job models.ImportJob) (models.ImportJob, error)
+	UpdateImport(ctx context.Context, job models.ImportJob) error
+	ReadImport(ctx context.Context, tenantID, id uuid.UUID) (models.ImportJob, error)
+	TouchImports(ctx context.Context, ids []uuid.UUID) error
+	FailStaleImports(ctx context.Context, staleAfter time.Duration, reason string) (int64, error)
+}
+
+// subsImporter — отвечает за массовую запись подписок.
+type subsImporter interface {
+	CopySubscriptions(ctx context.Context, tenantID uuid.UUID, subs []models.SubsDTO, progress func(copied int)) (int64, error)
+	CountActiveSubscriptionsByUsers(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	LockUserSubscriptions(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) error
+	WithinTx(ctx context.Context, fn func(tx *storage.SubsStorage) error) error
+}
+
+// ImportService — сервис импорта подписок из CSV- и XLSX-файлов.
+// Небольшие файлы импортируются в рамках запроса, большие — фоновыми задачами,
+// число одновременно выполняемых задач ограничено. Состояние каждой задачи
+// сохраняется в хранилище, чтобы прогресс можно было запросить отдельно.
+// Незавершённые задачи экземпляр периодически отмечает в хранилище; задачи, которые
+// никто не отмечает (экземпляр завершился аварийно), отмечаются неудавшимися.
+type ImportService struct {
+	imports importsStore
+	subs    subsImporter
+	cfg     config.ImportConfig
+	quota   config.QuotaConfig
+	events  bool // записывать события о созданных подписках в outbox
+
+	workers chan struct{}
+	wg      sync.WaitGroup
+	ctx     context.Context // отменяется, если фоновые задачи не успели завершиться при остановке
+	cancel  context.CancelFunc
+
+	heartbeatStop chan struct{}
+	heartbeatDone chan struct{} // закрывается, когда цикл отметок завершился
+
+	mu           sync.Mutex
+	stopped      bool
+	active       map[uuid.UUID]struct{} // незавершённые задачи этого экземпляра
+	queued       int                    // фоновые задачи, ожидающие свободного обработчика
+	lastProgress time.Time              // последний запуск задачи обработчиком или сохранение прогресса
+}
+
+// NewImportService — конструктор сервиса импорта подписок.
+func NewImportService(importsStorage *storage.ImportsStorage, subsStorage *storage.SubsStorage, cfg config.ImportConfig, quota config.QuotaConfig, outbox config.OutboxConfig) *ImportService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ImportService{
+		imports: importsStorage,
+		subs:    subsStorage,
+		cfg:     cfg,
+		quota:   quota,
+		events:  outbox.Enabled,
+		workers: make(chan struct{}, max(cfg.Workers, 1)),
+		ctx:     ctx,
+		cancel:  cancel,
+
+		heartbeatStop: make(chan struct{}),
+		active:        make(map[uuid.UUID]struct{}),
+	}
+}
+
+// Start — отмечает неудавшимися задачи, брошенные завершившимися экземплярами сервиса,
+// и запускает в фоне отметку задач этого экземпляра.
+func (s *ImportService) Start(ctx context.Context) error {
+	s.failStale(ctx)
+
+	s.heartbeatDone = make(chan struct{})
+	go s.heartbeat()
+
+	return nil
+}
+
+// heartbeat — каждые importHeartbeatInterval отмечает незавершённые задачи этого экземпляра
+// и завершает задачи, которые не отмечались дольше importStaleAfter.
+func (s *ImportService) heartbeat() {
+	defer close(s.heartbeatDone)
+
+	ticker := time.NewTicker(importHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.heartbeatStop:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		ids := make([]uuid.UUID, 0, len(s.active))
+		for id := range s.active {
+			ids = append(ids, id)
+		}
+		s.mu.Unlock()
+
+		if len(ids) > 0 {
+			if err := s.imports.TouchImports(s.ctx, ids); err != nil && s.ctx.Err() == nil {
+				logger.FromContext(s.ctx).Error("failed to touch imports", slog.String("error", err.Error()))
+			}
+		}
+		s.failStale(s.ctx)
+	}
+}
+
+// failStale — отмечает неудавшимися задачи, которые не отмечались дольше importStaleAfter.
+func (s *ImportService) failStale(ctx context.Context) {
+	failed, err := s.imports.FailStaleImports(ctx, importStaleAfter, "import interrupted")
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.FromContext(ctx).Error("failed to fail stale imports", slog.String("error", err.Error()))
+		}
+		return
+	}
+	if failed > 0 {
+		logger.FromContext(ctx).Warn("stale imports marked as failed", slog.Int64("count", failed))
+	}
+}
+
+// ImportSubscriptions — импортирует подписки из CSV- или XLSX-файла, первая строка
+// которого — заголовок с названиями колонок. Сначала проверяются все строки: ошибки собираются по каждой строке, включая
+// права на пользователя и квоту подписок. Если ошибок нет, подписки записываются
+// одной транзакцией (вместе с событиями subscription.created, если outbox включён);
+// иначе не записывается ни одна. В режиме dry run строки
+// только проверяются.
+// Если строк не больше import.sync_rows, возвращается завершённая задача,
+// иначе — задача в состоянии pending, которая выполняется в фоне.
+func (s *ImportService) ImportSubscriptions(ctx context.Context, file io.Reader, opts models.ImportOptions) (_ models.ImportJob, err error) {
+	ctx, span := tracing.Start(ctx, "ImportService.ImportSubscriptions")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start importing subscriptions")
+	id, err := caller(ctx)
+	if err != nil {
+		return models.ImportJob{}, err
+	}
+
+	rows, err := s.readFile(file, opts)
+	if err != nil {
+		return models.ImportJob{}, err
+	}
+
+	if len(rows) < 2 {
+		return models.ImportJob{}, fmt.Errorf("%w: file must contain a header and at least one row", ErrInvalidInput)
+	}
+
+	parser, err := newImportParser(rows[0], opts)
+	if err != nil {
+		return models.ImportJob{}, err
+	}
+
+	data := rows[1:]
+	if s.cfg.MaxRows > 0 && len(data) > s.cfg.MaxRows {
+		return models.ImportJob{}, fmt.Errorf("%w: file must contain at most %d rows", ErrInvalidInput, s.cfg.MaxRows)
+	}
+
+	job, err := s.imports.CreateImport(ctx, models.ImportJob{
+		TenantID:  id.TenantID,
+		UserID:    id.UserID,
+		Status:    models.ImportPending,
+		DryRun:    opts.DryRun,
+		TotalRows: len(data),
+		Errors:    []models.ImportRowError{},
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.ImportJob{}, errors.New("error import subscriptions")
+	}
+
+	s.mu.Lock()
+	s.active[job.ID] = struct{}{}
+	s.mu.Unlock()
+
+	if len(data) <= s.cfg.SyncRows {
+		return s.run(ctx, id, job, parser, data), nil
+	}
+
+	if err := s.start(ctx, id, job, parser, data); err != nil {
+		job = s.finish(ctx, job, models.ImportFailed, err.Error())
+		return job, err
+	}
+
+	return job, nil
+}
+
+// readFile — читает строки файла, не больше import.max_file_size байт.
+func (s *ImportService) readFile(file io.Reader, opts models.ImportOptions) ([]spreadsheet.Row, error) {
+	if s.cfg.MaxFileSize > 0 {
+		file = io.LimitReader(file, s.cfg.MaxFileSize+1)
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+	if s.cfg.MaxFileSize > 0 && int64(len(data)) > s.cfg.MaxFileSize {
+		return nil, fmt.Errorf("%w: file must be at most %d bytes", ErrInvalidInput, s.cfg.MaxFileSize)
+	}
+
+	rows, err := spreadsheet.Read(bytes.NewReader(data), opts.Format, spreadsheet.Options{Delimiter: opts.Delimiter})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInput, err)
+	}
+
+	return rows, nil
+}
+
+// start — запускает задачу импорта в фоне. Задача продолжает работу после
+// завершения запроса и ждёт свободного обработчика, если все заняты.
+func (s *ImportService) start(ctx context.Context, id auth.Identity, job models.ImportJob, parser *importParser, data []spreadsheet.Row) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return errors.New("import service is stopping")
+	}
+
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(s.ctx, cancel)
+
+	// Срок ожидания обработчика отсчитывается не раньше постановки первой задачи в очередь.
+	if s.queued == 0 {
+		s.lastProgress = time.Now()
+	}
+	s.queued++
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		defer stop()
+		defer func() {
+			if r := recover(); r != nil {
+				logger.FromContext(jobCtx).Error("panic in import",
+					slog.Any("panic", r),
+					slog.String("stack", string(debug.Stack())),
+				)
+				s.finish(jobCtx, job, models.ImportFailed, "internal error")
+			}
+		}()
+
+		select {
+		case s.workers <- struct{}{}:
+			defer func() { <-s.workers }()
+			s.dequeue()
+		case <-jobCtx.Done():
+			s.dequeue()
+			s.finish(jobCtx, job, models.ImportFailed, "import interrupted")
+			return
+		}
+
+		s.run(jobCtx, id, job, parser, data)
+	}()
+
+	return nil
+}
+
+// run — выполняет задачу импорта и возвращает её итоговое состояние.
+func (s *ImportService) run(ctx context.Context, id auth.Identity, job models.ImportJob, parser *importParser, data []spreadsheet.Row) models.ImportJob {
+	ctx, span := tracing.Start(ctx, "ImportService.run")
+	var err error
+	defer tracing.End(span, &err)
+
+	ctx = logger.With(ctx, slog.String("import_id", job.ID.String()))
+	log := logger.FromContext(ctx)
+	job.Status = models.ImportRunning
+	s.save(ctx, job)
+
+	subs := make([]models.SubsDTO, 0, len(data))
+	rowNumbers := make([]int, 0, len(data))
+	for i, row := range data {
+		if err = ctx.Err(); err != nil {
+			return s.finish(ctx, job, models.ImportFailed, "import interrupted")
+		}
+
+		sub, rowErrs := parser.parse(row)
+		if len(rowErrs) == 0 {
+			rowErrs = s.authorizeRow(id, &sub, row.Number, parser.names[fieldUserID])
+		}
+		if len(rowErrs) == 0 {
+			subs = append(subs, sub)
+			rowNumbers = append(rowNumbers, row.Number)
+		}
+		addRowErrors(&job, rowErrs...)
+
+		job.ProcessedRows = i + 1
+		if job.ProcessedRows%importProgressStep == 0 {
+			s.save(ctx, job)
+		}
+	}
+
+	if err = s.checkImportQuota(ctx, id.TenantID, &job, subs, rowNumbers, parser.names[fieldUserID]); err != nil {
+		log.Error(err.Error())
+		return s.finish(ctx, job, models.ImportFailed, "error checking subscription quota")
+	}
+
+	switch {
+	case job.DryRun:
+		return s.finish(ctx, job, models.ImportSucceeded, "")
+	case job.ErrorCount > 0:
+		return s.finish(ctx, job, models.ImportFailed, fmt.Sprintf("%d rows failed validation, nothing imported", job.ErrorCount))
+	}
+
+	copied, err := s.copySubscriptions(ctx, id.TenantID, subs, func(copied int) {
+		job.ImportedRows = copied
+		s.save(ctx, job)
+	})
+	if err != nil {
+		log.Error(err.Error())
+		job.ImportedRows = 0
+		if errors.Is(err, ErrQuotaExceeded) {
+			return s.finish(ctx, job, models.ImportFailed, fmt.Sprintf("%s, nothing imported", err))
+		}
+		if ctx.Err() != nil {
+			return s.finish(ctx, job, models.ImportFailed, "import interrupted")
+		}
+		return s.finish(ctx, job, models.ImportFailed, "error writing subscriptions")
+	}
+
+	job.ImportedRows = int(copied)
+	log.Info("subscriptions imported", slog.Int("rows", job.ImportedRows))
+	return s.finish(ctx, job, models.ImportSucceeded, "")
+}
+
+// copySubscriptions — записывает подписки и, если outbox включён, события
+// subscription.created о них в одной транзакции. Квота повторно проверяется в той же
+// транзакции под блокировкой подписок пользователей: подписки, созданные после
+// предварительной проверки, могли её исчерпать.
+func (s *ImportService) copySubscriptions(ctx context.Context, tenantID uuid.UUID, subs []models.SubsDTO, progress func(copied int)) (int64, error) {
+	for i := range subs {
+		subs[i].ID = uuid.New()
+	}
+
+	var copied int64
+	err := s.subs.WithinTx(ctx, func(tx *storage.SubsStorage) error {
+		if s.quota.MaxSubscriptionsPerUser > 0 {
+			if err := tx.LockUserSubscriptions(ctx, tenantID, distinctUsers(subs)); err != nil {
+				return err
+			}
+			over, err := s.overQuota(ctx, tx, tenantID, subs)
+			if err != nil {
+				return err
+			}
+			if len(over) > 0 {
+				return fmt.Errorf("%w: limit is %d", ErrQuotaExceeded, s.quota.MaxSubscriptionsPerUser)
+			}
+		}
+
+		var err error
+		if copied, err = tx.CopySubscriptions(ctx, tenantID, subs, progress); err != nil {
+			return err
+		}
+		if !s.events {
+			return nil
+		}
+
+		events := make([]subsEvent, 0, len(subs))
+		for _, sub := range subs {
+			events = append(events, createdEvent(sub))
+		}
+		return appendSubsEvents(ctx, tx, tenantID, events...)
+	})
+
+	return copied, err
+}
+
+// authorizeRow — подставляет вызывающего пользователя в подписку без user_id
+// и проверяет, что вызывающая сторона может создавать подписки этого пользователя.
+func (s *ImportService) authorizeRow(id auth.Identity, sub *models.SubsDTO, row int, column string) []models.ImportRowError {
+	if sub.UserID == uuid.Nil {
+		if id.UserID == uuid.Nil {
+			return []models.ImportRowError{{Row: row, Column: column, Error: "user_id is required"}}
+		}
+		sub.UserID = id.UserID
+	}
+
+	if !unrestricted(id) && sub.UserID != id.UserID {
+		return []models.ImportRowError{{Row: row, Column: column, Error: ErrForbidden.Error()}}
+	}
+
+	return nil
+}
+
+// checkImportQuota — проверяет, что после импорта у пользователей не станет больше
+// действующих подписок, чем разрешено квотой. Строки сверх квоты считаются ошибочными.
+func (s *ImportService) checkImportQuota(ctx context.Context, tenantID uuid.UUID, job *models.ImportJob, subs []models.SubsDTO, rowNumbers []int, column string) error {
+	if s.quota.MaxSubscriptionsPerUser <= 0 || len(subs) == 0 {
+		return nil
+	}
+
+	over, err := s.overQuota(ctx, s.subs, tenantID, subs)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range over {
+		addRowErrors(job, models.ImportRowError{
+			Row:    rowNumbers[i],
+			Column: column,
+			Error:  fmt.Sprintf("%s: limit is %d", ErrQuotaExceeded, s.quota.MaxSubscriptionsPerUser),
+		})
+	}
+
+	return nil
+}
+
+// overQuota — возвращает индексы подписок, с которыми у их пользователей станет больше
+// действующих подписок, чем разрешено квотой.
+func (s *ImportService) overQuota(ctx context.Context, counter subsImporter, tenantID uuid.UUID, subs []models.SubsDTO) ([]int, error) {
+	counts, err := counter.CountActiveSubscriptionsByUsers(ctx, tenantID, distinctUsers(subs))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var over []int
+	for i, sub := range subs {
+		if sub.EndDate != nil && sub.EndDate.Before(now) {
+			continue
+		}
+		counts[sub.UserID]++
+		if counts[sub.UserID] > s.quota.MaxSubscriptionsPerUser {
+			over = append(over, i)
+		}
+	}
+
+	return over, nil
+}
+
+// distinctUsers — возвращает пользователей подписок без повторов в порядке первого появления.
+func distinctUsers(subs []models.SubsDTO) []uuid.UUID {
+	var userIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, sub := range subs {
+		if !seen[sub.UserID] {
+			seen[sub.UserID] = true
+			userIDs = append(userIDs, sub.UserID)
+		}
+	}
+	return userIDs
+}
+
+// addRowErrors — добавляет ошибки строк в задачу, сохраняя не больше maxImportErrors.
+func addRowErrors(job *models.ImportJob, errs ...models.ImportRowError) {
+	job.ErrorCount += len(errs)
+	if room := maxImportErrors - len(job.Errors); room > 0 {
+		job.Errors = append(job.Errors, errs[:min(room, len(errs))]...)
+	}
+}
+
+// finish — сохраняет итоговое состояние задачи. Состояние сохраняется, даже если
+// контекст задачи уже отменён, чтобы прерванная задача не осталась в состоянии running.
+func (s *ImportService) finish(ctx context.Context, job models.ImportJob, status models.ImportStatus, reason string) models.ImportJob {
+	now := time.Now()
+	job.Status = status
+	job.Error = reason
+	job.FinishedAt = &now
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), importFinishTimeout)
+	defer cancel()
+	s.save(ctx, job)
+
+	s.mu.Lock()
+	delete(s.active, job.ID)
+	s.mu.Unlock()
+
+	return job
+}
+
+// save — сохраняет прогресс задачи. Ошибка только логируется: импорт продолжается,
+// даже если прогресс не удалось сохранить.
+func (s *ImportService) save(ctx context.Context, job models.ImportJob) {
+	if err := s.imports.UpdateImport(ctx, job); err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.lastProgress = time.Now()
+	s.mu.Unlock()
+}
+
+// dequeue — отмечает, что фоновая задача перестала ждать обработчика.
+func (s *ImportService) dequeue() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queued--
+	s.lastProgress = time.Now()
+}
+
+// Check — проверка готовности обработчиков фонового импорта: сервис не остановлен,
+// и если задачи ждут свободного обработчика, выполняемые задачи продвигаются.
+func (s *ImportService) Check(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return health.ErrNotRunning
+	}
+	if s.queued > 0 && time.Since(s.lastProgress) > importStallTimeout {
+		return fmt.Errorf("import workers are stalled: %d jobs waiting, no progress for %s",
+			s.queued, time.Since(s.lastProgress).Round(time.Second))
+	}
+
+	return nil
+}
+
+// GetImport — возвращает задачу импорта по UUID.
+// Обычный пользователь видит только запущенные им задачи.
+func (s *ImportService) GetImport(ctx context.Context, importID uuid.UUID) (_ models.ImportJob, err error) {
+	ctx, span := tracing.Start(ctx, "ImportService.GetImport")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start getting import")
+	id, err := caller(ctx)
+	if err != nil {
+		return models.ImportJob{}, err
+	}
+
+	job, err := s.imports.ReadImport(ctx, id.TenantID, importID)
+	if errors.Is(err, storage.ErrImportNotFound) {
+		return models.ImportJob{}, ErrImportNotFound
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.ImportJob{}, errors.New("error get import")
+	}
+
+	if !unrestricted(id) && job.UserID != id.UserID {
+		return models.ImportJob{}, ErrImportNotFound
+	}
+
+	return job, nil
+}
+
+// Stop — перестаёт принимать фоновые импорты и ждёт завершения текущих.
+// Если срок контекста истекает раньше, текущие импорты отменяются: их транзакции
+// откатываются, а задачи сохраняются в состоянии failed.
+func (s *ImportService) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	// Задачи отмечаются, пока не завершатся, чтобы другие экземпляры не сочли их брошенными.
+	defer s.stopHeartbeat()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+		return fmt.Errorf("import jobs interrupted: %w", ctx.Err())
+	}
+}
+
+// stopHeartbeat — останавливает отметку задач и ждёт завершения её цикла.
+func (s *ImportService) stopHeartbeat() {
+	if s.heartbeatDone == nil {
+		return
+	}
+	close(s.heartbeatStop)
+	<-s.heartbeatDone
+}
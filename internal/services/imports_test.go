@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/health"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeImportsStore — хранилище задач импорта в памяти.
+type fakeImportsStore struct {
+	jobs       map[uuid.UUID]models.ImportJob
+	staleAfter time.Duration
+}
+
+func (f *fakeImportsStore) CreateImport(_ context.Context, job models.ImportJob) (models.ImportJob, error) {
+	job.ID = uuid.New()
+	f.jobs[job.ID] = job
+	return job, nil
+}
+
+func (f *fakeImportsStore) UpdateImport(_ context.Context, job models.ImportJob) error {
+	f.jobs[job.ID] = job
+	return nil
+}
+
+func (f *fakeImportsStore) ReadImport(_ context.Context, _, id uuid.UUID) (models.ImportJob, error) {
+	return f.jobs[id], nil
+}
+
+func (f *fakeImportsStore) TouchImports(context.Context, []uuid.UUID) error {
+	return nil
+}
+
+// FailStaleImports — считает брошенными все незавершённые задачи.
+func (f *fakeImportsStore) FailStaleImports(_ context.Context, staleAfter time.Duration, reason string) (int64, error) {
+	f.staleAfter = staleAfter
+	var failed int64
+	for id, job := range f.jobs {
+		if job.Status == models.ImportPending || job.Status == models.ImportRunning {
+			job.Status, job.Error = models.ImportFailed, reason
+			f.jobs[id] = job
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+func TestImportServiceCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		stopped      bool
+		queued       int
+		lastProgress time.Duration // сколько времени назад был последний прогресс
+		wantErr      bool
+	}{
+		{name: "idle", lastProgress: time.Hour},
+		{name: "queue is moving", queued: 3, lastProgress: time.Minute},
+		{name: "queue is stalled", queued: 3, lastProgress: importStallTimeout + time.Minute, wantErr: true},
+		{name: "stopped", stopped: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ImportService{stopped: tt.stopped, queued: tt.queued, lastProgress: time.Now().Add(-tt.lastProgress)}
+
+			err := s.Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.stopped && !errors.Is(err, health.ErrNotRunning) {
+				t.Errorf("Check() error = %v, want %v", err, health.ErrNotRunning)
+			}
+		})
+	}
+}
+
+func TestImportServiceFailsStaleJobsOnStart(t *testing.T) {
+	orphan := models.ImportJob{ID: uuid.New(), Status: models.ImportRunning}
+	done := models.ImportJob{ID: uuid.New(), Status: models.ImportSucceeded}
+	store := &fakeImportsStore{jobs: map[uuid.UUID]models.ImportJob{orphan.ID: orphan, done.ID: done}}
+
+	s := NewImportService(nil, nil, config.ImportConfig{}, config.QuotaConfig{}, config.OutboxConfig{})
+	s.imports = store
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if store.staleAfter != importStaleAfter {
+		t.Errorf("FailStaleImports() staleAfter = %s, want %s", store.staleAfter, importStaleAfter)
+	}
+	if got := store.jobs[orphan.ID]; got.Status != models.ImportFailed || got.Error != "import interrupted" {
+		t.Errorf("orphaned job = %s %q, want failed \"import interrupted\"", got.Status, got.Error)
+	}
+	if got := store.jobs[done.ID].Status; got != models.ImportSucceeded {
+		t.Errorf("finished job status = %s, want %s", got, models.ImportSucceeded)
+	}
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/lib/providers"
+	"online_subscription_service/internal/metrics"
+	"online_subscription_service/internal/storage"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxExternalIDLength — максимальная длина идентификатора подписки у провайдера.
+const maxExternalIDLength = 255
+
+// integrationsStorage — отвечает за связи подписок с провайдерами и журнал событий провайдеров.
+type integrationsStorage interface {
+	UpsertLink(ctx context.Context, link models.SubscriptionLink) (models.SubscriptionLink, error)
+	ReadLinks(ctx context.Context, tenantID, subscriptionID uuid.UUID) ([]models.SubscriptionLink, error)
+	ReadLinkByExternalID(ctx context.Context, tenantID uuid.UUID, provider, externalID string) (models.SubscriptionLink, error)
+	DeleteLink(ctx context.Context, tenantID, subscriptionID uuid.UUID, provider string) error
+	ClaimInboundEvent(ctx context.Context, tenantID uuid.UUID, provider string, event models.ProviderEvent, lease time.Duration) (bool, error)
+	FinishInboundEvent(ctx context.Context, provider string, result models.InboundEventResult) error
+}
+
+// subscriptionEditor — читает и изменяет подписки с проверкой прав вызывающей стороны.
+type subscriptionEditor interface {
+	GetSubscription(ctx context.Context, uuid uuid.UUID) (models.Subs, error)
+	EditSubscription(ctx context.Context, uuid uuid.UUID, sub models.SubsUpdateDTO) error
+}
+
+// boundProvider — адаптер провайдера и организация, которой принадлежат его учётные данные.
+type boundProvider struct {
+	adapter  providers.Adapter
+	tenantID uuid.UUID
+}
+
+// IntegrationsService — сервисный слой для приёма вебхуков провайдеров подписок.
+//
+// Каждый провайдер принадлежит одной организации: подписки этой организации связываются
+// с их идентификаторами у провайдера, а события провайдера
+// (продление, отмена, изменение цены) применяются к связанной подписке через SubsService,
+// поэтому изменения записываются в журнал событий так же, как изменения через API.
+// Каждое событие обрабатывается один раз: повторно присланное событие пропускается,
+// а событие, обработка которого завершилась ошибкой, обрабатывается при следующей отправке.
+type IntegrationsService struct {
+	integrations integrationsStorage
+	subs         subscriptionEditor
+	providers    map[string]boundProvider
+	lease        time.Duration
+}
+
+// NewIntegrationsService — конструктор сервиса интеграций. Создаёт адаптеры
+// всех настроенных провайдеров; возвращает ошибку, если конфигурация провайдера неверна.
+func NewIntegrationsService(integrationsStorage *storage.IntegrationsStorage, subs *SubsService, cfg config.IntegrationsConfig) (*IntegrationsService, error) {
+	bound := make(map[string]boundProvider, len(cfg.Providers))
+	for name, p := range cfg.Providers {
+		adapter, err := providers.New(name, p)
+		if err != nil {
+			return nil, err
+		}
+
+		tenantID := models.DefaultTenantID
+		if p.TenantID != "" {
+			if tenantID, err = uuid.Parse(p.TenantID); err != nil || tenantID == uuid.Nil {
+				return nil, fmt.Errorf("provider %q: invalid tenant_id %q", name, p.TenantID)
+			}
+		}
+
+		bound[name] = boundProvider{adapter: adapter, tenantID: tenantID}
+	}
+
+	return &IntegrationsService{
+		integrations: integrationsStorage,
+		subs:         subs,
+		providers:    bound,
+		lease:        cfg.ProcessingLease,
+	}, nil
+}
+
+// HandleWebhook — проверяет подпись вебхука провайдера и применяет его события к связанным
+// подпискам организации провайдера. Результат содержит исход обработки каждого события; событие, обработка которого
+// завершилась ошибкой (InboundFailed), провайдер должен прислать повторно.
+func (s *IntegrationsService) HandleWebhook(ctx context.Context, name string, header http.Header, body []byte) (models.InboundWebhookResult, error) {
+	logger.FromContext(ctx).Info("start handling provider webhook", slog.String("provider", name))
+	result := models.InboundWebhookResult{Events: make([]models.InboundEventResult, 0)}
+
+	p, ok := s.providers[name]
+	if !ok {
+		return result, ErrProviderNotFound
+	}
+
+	if err := p.adapter.Verify(header, body); err != nil {
+		logger.FromContext(ctx).Warn("rejected provider webhook", slog.String("provider", name), slog.String("error", err.Error()))
+		return result, ErrInvalidSignature
+	}
+
+	events, err := p.adapter.Parse(body)
+	if err != nil {
+		return result, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	for _, e := range events {
+		res := s.handleEvent(ctx, name, p.tenantID, e)
+		metrics.IntegrationEvents.WithLabelValues(name, string(res.Status)).Inc()
+		result.Events = append(result.Events, res)
+	}
+
+	return result, nil
+}
+
+// handleEvent — обрабатывает одно событие провайдера и сохраняет результат в журнале событий.
+func (s *IntegrationsService) handleEvent(ctx context.Context, provider string, tenantID uuid.UUID, e models.ProviderEvent) models.InboundEventResult {
+	res := models.InboundEventResult{EventID: e.ID, Type: e.Type, ExternalID: e.ExternalID}
+
+	claimed, err := s.integrations.ClaimInboundEvent(ctx, tenantID, provider, e, s.lease)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		res.Status, res.Error = models.InboundFailed, "error process event"
+		return res
+	}
+	if !claimed {
+		res.Status = models.InboundDuplicate
+		return res
+	}
+
+	link, err := s.integrations.ReadLinkByExternalID(ctx, tenantID, provider, e.ExternalID)
+	switch {
+	case errors.Is(err, storage.ErrLinkNotFound):
+		res.Status, res.Error = models.InboundIgnored, "subscription is not linked"
+	case err != nil:
+		logger.FromContext(ctx).Error(err.Error())
+		res.Status, res.Error = models.InboundFailed, "error process event"
+	default:
+		res.SubscriptionID = &link.SubscriptionID
+		res.Status, err = s.apply(ctx, provider, link, e)
+		if err != nil {
+			res.Error = err.Error()
+		}
+	}
+
+	if err := s.integrations.FinishInboundEvent(ctx, provider, res); err != nil {
+		// Событие останется в обработке и будет принято повторно по истечении lease.
+		logger.FromContext(ctx).Error(err.Error())
+	}
+
+	if res.Status == models.InboundFailed {
+		logger.FromContext(ctx).Warn("failed to apply provider event",
+			slog.String("provider", provider),
+			slog.String("event_id", e.ID),
+			slog.String("error", res.Error),
+		)
+	}
+
+	return res
+}
+
+// apply — применяет событие к связанной подписке от имени администратора её организации.
+// Возвращает результат обработки и причину, если событие не применено.
+func (s *IntegrationsService) apply(ctx context.Context, provider string, link models.SubscriptionLink, e models.ProviderEvent) (models.InboundStatus, error) {
+	ctx = auth.WithIdentity(ctx, auth.Identity{
+		Subject:  "integration:" + provider,
+		TenantID: link.TenantID,
+		Roles:    []string{auth.RoleAdmin},
+	})
+
+	sub, err := s.subs.GetSubscription(ctx, link.SubscriptionID)
+	if err != nil {
+		return applyError(err)
+	}
+
+	var update models.SubsUpdateDTO
+	switch e.Type {
+	case models.ProviderRenewed:
+		if e.EndDate == nil {
+			return models.InboundIgnored, errors.New("end date is required")
+		}
+		// Бессрочная подписка продлевается сама; дата окончания только отодвигается.
+		if sub.EndDate == nil || !e.EndDate.After(*sub.EndDate) {
+			return models.InboundUnchanged, nil
+		}
+		update.EndDate = e.EndDate
+
+	case models.ProviderCancelled:
+		end := time.Now().UTC()
+		if e.EndDate != nil {
+			end = *e.EndDate
+		} else if e.OccurredAt != nil {
+			end = *e.OccurredAt
+		}
+		if sub.EndDate != nil && !sub.EndDate.After(end) {
+			return models.InboundUnchanged, nil
+		}
+		if end.Before(sub.StartDate) {
+			end = sub.StartDate
+		}
+		update.EndDate = &end
+
+	case models.ProviderPriceChanged:
+		if e.Price == nil {
+			return models.InboundIgnored, errors.New("price is required")
+		}
+		if *e.Price < 0 {
+			return models.InboundIgnored, errors.New("price must not be negative")
+		}
+		if *e.Price == sub.Price {
+			return models.InboundUnchanged, nil
+		}
+		update.Price = e.Price
+
+	default:
+		return models.InboundIgnored, fmt.Errorf("unsupported event type %q", e.Type)
+	}
+
+	if err := s.subs.EditSubscription(ctx, link.SubscriptionID, update); err != nil {
+		return applyError(err)
+	}
+
+	return models.InboundApplied, nil
+}
+
+// applyError — сопоставляет ошибку сервиса подписок с результатом обработки события:
+// событие для удалённой подписки или с неверными данными повторять бесполезно.
+func applyError(err error) (models.InboundStatus, error) {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrInvalidInput) {
+		return models.InboundIgnored, err
+	}
+	return models.InboundFailed, err
+}
+
+// LinkSubscription — связывает подписку с её идентификатором у провайдера, заменяя
+// прежний идентификатор подписки у этого провайдера. Связывать подписку может тот,
+// кому разрешено её изменять, и только с провайдером своей организации.
+func (s *IntegrationsService) LinkSubscription(ctx context.Context, subscriptionID uuid.UUID, provider, externalID string) (models.SubscriptionLink, error) {
+	logger.FromContext(ctx).Info("start linking subscription")
+	externalID = strings.TrimSpace(externalID)
+
+	id, err := caller(ctx)
+	if err != nil {
+		return models.SubscriptionLink{}, err
+	}
+	// Провайдер другой организации неотличим от ненастроенного.
+	if p, ok := s.providers[provider]; !ok || p.tenantID != id.TenantID {
+		return models.SubscriptionLink{}, ErrProviderNotFound
+	}
+	if externalID == "" {
+		return models.SubscriptionLink{}, fmt.Errorf("%w: external_id is required", ErrInvalidInput)
+	}
+	if len(externalID) > maxExternalIDLength {
+		return models.SubscriptionLink{}, fmt.Errorf("%w: external_id must not exceed %d characters", ErrInvalidInput, maxExternalIDLength)
+	}
+
+	if _, err := s.subs.GetSubscription(ctx, subscriptionID); err != nil {
+		return models.SubscriptionLink{}, err
+	}
+
+	link, err := s.integrations.UpsertLink(ctx, models.SubscriptionLink{
+		Provider:       provider,
+		ExternalID:     externalID,
+		TenantID:       id.TenantID,
+		SubscriptionID: subscriptionID,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		switch {
+		case errors.Is(err, storage.ErrLinkExists):
+			return models.SubscriptionLink{}, ErrLinkExists
+		case errors.Is(err, storage.ErrSubsNotFound):
+			return models.SubscriptionLink{}, ErrNotFound
+		}
+		return models.SubscriptionLink{}, errors.New("error link subscription")
+	}
+
+	return link, nil
+}
+
+// ListLinks — возвращает связи подписки с провайдерами.
+func (s *IntegrationsService) ListLinks(ctx context.Context, subscriptionID uuid.UUID) ([]models.SubscriptionLink, error) {
+	logger.FromContext(ctx).Info("start listing subscription links")
+	links := make([]models.SubscriptionLink, 0)
+
+	if _, err := s.subs.GetSubscription(ctx, subscriptionID); err != nil {
+		return links, err
+	}
+	id, err := caller(ctx)
+	if err != nil {
+		return links, err
+	}
+
+	slice, err := s.integrations.ReadLinks(ctx, id.TenantID, subscriptionID)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return links, errors.New("error list subscription links")
+	}
+
+	return append(links, slice...), nil
+}
+
+// UnlinkSubscription — удаляет связь подписки с провайдером; события провайдера
+// для неё больше не применяются.
+func (s *IntegrationsService) UnlinkSubscription(ctx context.Context, subscriptionID uuid.UUID, provider string) error {
+	logger.FromContext(ctx).Info("start unlinking subscription")
+
+	if _, err := s.subs.GetSubscription(ctx, subscriptionID); err != nil {
+		return err
+	}
+	id, err := caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.integrations.DeleteLink(ctx, id.TenantID, subscriptionID, provider); err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		if errors.Is(err, storage.ErrLinkNotFound) {
+			return ErrLinkNotFound
+		}
+		return errors.New("error unlink subscription")
+	}
+
+	return nil
+}
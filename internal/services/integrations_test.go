@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeIntegrationsStorage — связи подписок в памяти; журнал событий принимает каждое событие.
+type fakeIntegrationsStorage struct {
+	links []models.SubscriptionLink
+}
+
+func (f *fakeIntegrationsStorage) UpsertLink(_ context.Context, link models.SubscriptionLink) (models.SubscriptionLink, error) {
+	for _, l := range f.links {
+		if l.TenantID == link.TenantID && l.Provider == link.Provider && l.ExternalID == link.ExternalID {
+			return link, storage.ErrLinkExists
+		}
+	}
+	f.links = append(f.links, link)
+	return link, nil
+}
+
+func (f *fakeIntegrationsStorage) ReadLinks(context.Context, uuid.UUID, uuid.UUID) ([]models.SubscriptionLink, error) {
+	return nil, nil
+}
+
+func (f *fakeIntegrationsStorage) ReadLinkByExternalID(_ context.Context, tenantID uuid.UUID, provider, externalID string) (models.SubscriptionLink, error) {
+	for _, l := range f.links {
+		if l.TenantID == tenantID && l.Provider == provider && l.ExternalID == externalID {
+			return l, nil
+		}
+	}
+	return models.SubscriptionLink{}, storage.ErrLinkNotFound
+}
+
+func (f *fakeIntegrationsStorage) DeleteLink(context.Context, uuid.UUID, uuid.UUID, string) error {
+	return nil
+}
+
+func (f *fakeIntegrationsStorage) ClaimInboundEvent(context.Context, uuid.UUID, string, models.ProviderEvent, time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeIntegrationsStorage) FinishInboundEvent(context.Context, string, models.InboundEventResult) error {
+	return nil
+}
+
+// fakeSubscriptionEditor — подписки в памяти, доступные только своей организации.
+type fakeSubscriptionEditor struct {
+	subs    map[uuid.UUID]models.Subs
+	tenants map[uuid.UUID]uuid.UUID
+}
+
+func (f *fakeSubscriptionEditor) GetSubscription(ctx context.Context, id uuid.UUID) (models.Subs, error) {
+	caller, _ := auth.FromContext(ctx)
+	sub, ok := f.subs[id]
+	if !ok || f.tenants[id] != caller.TenantID {
+		return models.Subs{}, ErrNotFound
+	}
+	return sub, nil
+}
+
+func (f *fakeSubscriptionEditor) EditSubscription(ctx context.Context, id uuid.UUID, update models.SubsUpdateDTO) error {
+	sub, err := f.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	if update.Price != nil {
+		sub.Price = *update.Price
+	}
+	f.subs[id] = sub
+	return nil
+}
+
+// staticAdapter — адаптер без проверки подписи, возвращающий заданные события.
+type staticAdapter []models.ProviderEvent
+
+func (a staticAdapter) Verify(http.Header, []byte) error { return nil }
+
+func (a staticAdapter) Parse([]byte) ([]models.ProviderEvent, error) { return a, nil }
+
+func TestIntegrationsServiceTenantBinding(t *testing.T) {
+	tenantA, tenantB := uuid.New(), uuid.New()
+	subA, subB := uuid.New(), uuid.New()
+	price := 500
+
+	editor := &fakeSubscriptionEditor{
+		subs:    map[uuid.UUID]models.Subs{subA: {Price: 100}, subB: {Price: 100}},
+		tenants: map[uuid.UUID]uuid.UUID{subA: tenantA, subB: tenantB},
+	}
+	store := &fakeIntegrationsStorage{}
+	s := &IntegrationsService{
+		integrations: store,
+		subs:         editor,
+		providers: map[string]boundProvider{
+			"a": {tenantID: tenantA, adapter: staticAdapter{{ID: "evt-1", Type: models.ProviderPriceChanged, ExternalID: "ext-1", Price: &price}}},
+			"b": {tenantID: tenantB},
+		},
+	}
+
+	adminA := auth.WithIdentity(context.Background(), auth.Identity{TenantID: tenantA, Roles: []string{auth.RoleAdmin}})
+	adminB := auth.WithIdentity(context.Background(), auth.Identity{TenantID: tenantB, Roles: []string{auth.RoleAdmin}})
+
+	// Организация B не может связать подписку с провайдером организации A.
+	if _, err := s.LinkSubscription(adminB, subB, "a", "ext-1"); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("LinkSubscription() with foreign provider error = %v, want %v", err, ErrProviderNotFound)
+	}
+	// Даже записанная в обход сервиса связь B не видна провайдеру A.
+	store.links = append(store.links, models.SubscriptionLink{Provider: "a", ExternalID: "ext-1", TenantID: tenantB, SubscriptionID: subB})
+
+	res, err := s.HandleWebhook(context.Background(), "a", nil, nil)
+	if err != nil {
+		t.Fatalf("HandleWebhook() error = %v", err)
+	}
+	if got := res.Events[0].Status; got != models.InboundIgnored {
+		t.Errorf("HandleWebhook() status for unlinked subscription = %q, want %q", got, models.InboundIgnored)
+	}
+	if editor.subs[subB].Price != 100 {
+		t.Errorf("subscription of tenant B changed by provider of tenant A")
+	}
+
+	if _, err := s.LinkSubscription(adminA, subA, "a", "ext-1"); err != nil {
+		t.Fatalf("LinkSubscription() error = %v", err)
+	}
+	res, err = s.HandleWebhook(context.Background(), "a", nil, nil)
+	if err != nil {
+		t.Fatalf("HandleWebhook() error = %v", err)
+	}
+	if got := res.Events[0].Status; got != models.InboundApplied {
+		t.Errorf("HandleWebhook() status = %q, want %q", got, models.InboundApplied)
+	}
+	if editor.subs[subA].Price != price || editor.subs[subB].Price != 100 {
+		t.Errorf("prices = %d, %d; want %d, 100", editor.subs[subA].Price, editor.subs[subB].Price, price)
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/ledger"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/lib/tracing"
+	"online_subscription_service/internal/storage"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxJournalYears — наибольшая длина периода выгрузки журнала в годах.
+const maxJournalYears = 10
+
+// subsBiller — отвечает за чтение подписок, учитываемых в стоимости за период.
+type subsBiller interface {
+	ReadSubscriptionsWithPeriod(ctx context.Context, tenantID uuid.UUID, from, to time.Time, userIDs []uuid.UUID, name string) ([]models.SubsDTO, error)
+}
+
+// JournalService — сервис выгрузки начислений по подпискам в журнал простой текстовой
+// бухгалтерии. Начисления — те же списания, из которых складывается стоимость за период
+// (SubsService.GetPriceWithPeriod, см. billingPeriods): сумма журнала по услуге равна
+// её стоимости за тот же период.
+type JournalService struct {
+	subs subsBiller
+	cfg  config.LedgerConfig
+}
+
+// NewJournalService — конструктор сервиса выгрузки журнала.
+// Возвращает ошибку, если счета или валюта в настройках заданы в недопустимом виде.
+func NewJournalService(subsStorage *storage.SubsStorage, cfg config.LedgerConfig) (*JournalService, error) {
+	if err := validateJournalAccounts(cfg.Accounts, cfg.ExpenseAccount, cfg.PaymentAccount); err != nil {
+		return nil, fmt.Errorf("invalid ledger config: %w", err)
+	}
+	if !ledger.ValidCurrency(cfg.Currency) {
+		return nil, fmt.Errorf("invalid ledger config: invalid currency %q", cfg.Currency)
+	}
+
+	return &JournalService{
+		subs: subsStorage,
+		cfg:  cfg,
+	}, nil
+}
+
+// GetJournal — возвращает начисления по подпискам пользователя за период [From, To]:
+// по одному на каждое списание из billingPeriods.
+// Пустой UserID означает вызывающего пользователя; обычный пользователь может выгрузить
+// только свои подписки. Начисления упорядочены по дате.
+func (s *JournalService) GetJournal(ctx context.Context, opts models.JournalOptions) (_ []models.JournalEntry, err error) {
+	ctx, span := tracing.Start(ctx, "JournalService.GetJournal")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start getting journal")
+	id, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.UserID == uuid.Nil {
+		if id.UserID == uuid.Nil {
+			return nil, fmt.Errorf("%w: user_id is required", ErrInvalidInput)
+		}
+		opts.UserID = id.UserID
+	}
+
+	id, err = authorize(ctx, opts.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.To.Before(opts.From) {
+		return nil, fmt.Errorf("%w: to must not be before from", ErrInvalidInput)
+	}
+	if opts.To.After(opts.From.AddDate(maxJournalYears, 0, 0)) {
+		return nil, fmt.Errorf("%w: period must not exceed %d years", ErrInvalidInput, maxJournalYears)
+	}
+
+	paymentAccount := s.cfg.PaymentAccount
+	if opts.PaymentAccount != "" {
+		paymentAccount = opts.PaymentAccount
+	}
+	currency := s.cfg.Currency
+	if opts.Currency != "" {
+		currency = opts.Currency
+	}
+	if err := validateJournalAccounts(opts.Accounts, paymentAccount); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+	if !ledger.ValidCurrency(currency) {
+		return nil, fmt.Errorf("%w: currency must be 2 to 24 capital latin letters", ErrInvalidInput)
+	}
+
+	subs, err := s.subs.ReadSubscriptionsWithPeriod(ctx, id.TenantID, opts.From, opts.To, []uuid.UUID{opts.UserID}, opts.ServiceName)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return nil, errors.New("error getting journal")
+	}
+
+	var entries []models.JournalEntry
+	for _, sub := range subs {
+		account := s.account(sub.Name, opts.Accounts)
+		for _, period := range billingPeriods(sub, opts.From, opts.To) {
+			entries = append(entries, models.JournalEntry{
+				Date:           period[0],
+				PeriodEnd:      period[1],
+				SubscriptionID: sub.ID,
+				ServiceName:    sub.Name,
+				Amount:         sub.Price,
+				Currency:       currency,
+				Account:        account,
+				PaymentAccount: paymentAccount,
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Date.Before(entries[j].Date) })
+
+	return entries, nil
+}
+
+// account — счёт расходов на услугу: из параметров запроса, из настроек
+// или подсчёт общего счёта расходов с названием услуги.
+func (s *JournalService) account(service string, accounts map[string]string) string {
+	if account, ok := accounts[service]; ok {
+		return account
+	}
+	if account, ok := s.cfg.Accounts[service]; ok {
+		return account
+	}
+	return ledger.SubAccount(s.cfg.ExpenseAccount, service)
+}
+
+// validateJournalAccounts — проверяет счета услуг и отдельные счета.
+func validateJournalAccounts(services map[string]string, accounts ...string) error {
+	for service, account := range services {
+		if !ledger.ValidAccount(account) {
+			return fmt.Errorf("invalid account %q for service %q", account, service)
+		}
+	}
+	for _, account := range accounts {
+		if !ledger.ValidAccount(account) {
+			return fmt.Errorf("invalid account %q", account)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/health"
+	"online_subscription_service/internal/lib/events"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/metrics"
+	"online_subscription_service/internal/storage"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	// outboxCleanupInterval — как часто удаляются опубликованные события старше outbox.retention.
+	outboxCleanupInterval = time.Hour
+	// outboxStaleAfter — минимальный срок без успешного прохода, после которого relay
+	// считается неготовым; не меньше десяти интервалов outbox.poll_interval.
+	outboxStaleAfter = time.Minute
+)
+
+// outboxStore — отвечает за чтение событий outbox и отметку результатов их публикации.
+type outboxStore interface {
+	WithinTx(ctx context.Context, fn func(tx *storage.OutboxStorage) error) error
+	DeletePublishedEvents(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+// outboxTx — операции с outbox в транзакции прохода relay.
+type outboxTx interface {
+	TryLockRelay(ctx context.Context) (bool, error)
+	ReadPendingEvents(ctx context.Context, limit int) ([]models.OutboxEvent, error)
+	MarkEventsPublished(ctx context.Context, sequences []int64) error
+	MarkEventFailed(ctx context.Context, sequence int64, retryIn time.Duration, reason string) error
+}
+
+// OutboxRelay — фоновый обработчик, публикующий события из outbox.
+//
+// Каждый проход выполняется в транзакции под advisory-блокировкой, поэтому при нескольких
+// экземплярах сервиса события публикует только один. События публикуются по одному
+// в порядке записи и отмечаются опубликованными при фиксации транзакции. Доставка —
+// «как минимум один раз»: если транзакция не зафиксировалась после публикации, события
+// будут опубликованы повторно с теми же ID. Первая неудачная публикация завершает проход;
+// событие откладывается с экспоненциально растущей паузой, а следующие события
+// той же подписки ждут его, так что порядок событий подписки не нарушается.
+type OutboxRelay struct {
+	outbox    outboxStore
+	publisher events.Publisher
+	cfg       config.OutboxConfig
+	heartbeat *health.Heartbeat
+
+	stop   chan struct{}
+	done   chan struct{}      // закрывается, когда фоновый цикл завершился
+	cancel context.CancelFunc // прерывает текущий проход, если срок остановки истёк
+}
+
+// NewOutboxRelay — конструктор обработчика публикации событий.
+func NewOutboxRelay(outboxStorage *storage.OutboxStorage, publisher events.Publisher, cfg config.OutboxConfig) *OutboxRelay {
+	cfg.BatchSize = max(cfg.BatchSize, 1)
+	return &OutboxRelay{
+		outbox:    outboxStorage,
+		publisher: publisher,
+		cfg:       cfg,
+		heartbeat: health.NewHeartbeat(max(outboxStaleAfter, 10*cfg.PollInterval)),
+		stop:      make(chan struct{}),
+	}
+}
+
+// Check — проверка готовности: relay запущен и успешно завершал проход недавно.
+func (r *OutboxRelay) Check(ctx context.Context) error {
+	return r.heartbeat.Check(ctx)
+}
+
+// Start — запускает публикацию событий в фоне.
+func (r *OutboxRelay) Start(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	r.heartbeat.Start()
+
+	go r.loop(ctx)
+
+	return nil
+}
+
+// loop — проверяет новые события каждые outbox.poll_interval, а если за проход
+// опубликована полная пачка, сразу выполняет следующий.
+func (r *OutboxRelay) loop(ctx context.Context) {
+	defer close(r.done)
+
+	var lastCleanup time.Time
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-timer.C:
+		}
+
+		published, err := r.safeRelay(ctx)
+		r.heartbeat.Beat(err)
+		if err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Error("failed to relay outbox events", slog.String("error", err.Error()))
+		}
+
+		if r.cfg.Retention > 0 && time.Since(lastCleanup) >= outboxCleanupInterval {
+			r.cleanup(ctx)
+			lastCleanup = time.Now()
+		}
+
+		next := r.cfg.PollInterval
+		if err == nil && published >= r.cfg.BatchSize {
+			next = 0
+		}
+		timer.Reset(next)
+	}
+}
+
+// safeRelay — выполняет проход relay; паника в публикаторе логируется как ошибка прохода.
+func (r *OutboxRelay) safeRelay(ctx context.Context) (published int, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			logger.FromContext(ctx).Error("panic in outbox relay",
+				slog.Any("panic", p),
+				slog.String("stack", string(debug.Stack())),
+			)
+			err = fmt.Errorf("panic in outbox relay: %v", p)
+		}
+	}()
+
+	return r.relay(ctx)
+}
+
+// relay — публикует до outbox.batch_size событий за одну транзакцию.
+// Возвращает количество опубликованных событий.
+func (r *OutboxRelay) relay(ctx context.Context) (int, error) {
+	var published int
+	err := r.outbox.WithinTx(ctx, func(tx *storage.OutboxStorage) error {
+		var err error
+		published, err = r.publishPending(ctx, tx)
+		return err
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return published, nil
+}
+
+// publishPending — проход relay в транзакции tx: под advisory-блокировкой публикует
+// ожидающие события до первой ошибки и отмечает результаты. Возвращает количество
+// опубликованных событий.
+func (r *OutboxRelay) publishPending(ctx context.Context, tx outboxTx) (int, error) {
+	locked, err := tx.TryLockRelay(ctx)
+	if err != nil || !locked {
+		return 0, err
+	}
+
+	pending, err := tx.ReadPendingEvents(ctx, r.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := make([]int64, 0, len(pending))
+	for _, e := range pending {
+		if err := r.publisher.Publish(ctx, e.Event); err != nil {
+			metrics.OutboxPublishErrors.WithLabelValues(string(e.Type)).Inc()
+			logger.FromContext(ctx).Warn("failed to publish event",
+				slog.String("event_id", e.ID.String()),
+				slog.String("type", string(e.Type)),
+				slog.Int("attempts", e.Attempts+1),
+				slog.String("error", err.Error()),
+			)
+			delay := retryDelay(r.cfg.RetryInitialDelay, r.cfg.RetryMaxDelay, e.Attempts)
+			if err := tx.MarkEventFailed(ctx, e.Sequence, delay, err.Error()); err != nil {
+				return 0, err
+			}
+			break
+		}
+		metrics.OutboxPublished.WithLabelValues(string(e.Type)).Inc()
+		published = append(published, e.Sequence)
+	}
+
+	if err := tx.MarkEventsPublished(ctx, published); err != nil {
+		return 0, err
+	}
+	return len(published), nil
+}
+
+// retryDelay — пауза перед повторной попыткой после attempts предыдущих неудачных
+// попыток и ещё одной текущей: initial, удваивающаяся с каждой попыткой до maxDelay.
+func retryDelay(initial, maxDelay time.Duration, attempts int) time.Duration {
+	delay := initial
+	for range attempts {
+		if delay >= maxDelay/2 {
+			return maxDelay
+		}
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// cleanup — удаляет опубликованные события старше outbox.retention.
+func (r *OutboxRelay) cleanup(ctx context.Context) {
+	deleted, err := r.outbox.DeletePublishedEvents(ctx, r.cfg.Retention)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.FromContext(ctx).Error("failed to delete published events", slog.String("error", err.Error()))
+		}
+		return
+	}
+	if deleted > 0 {
+		logger.FromContext(ctx).Info("published events deleted", slog.Int64("count", deleted))
+	}
+}
+
+// Stop — останавливает фоновую публикацию, дождавшись текущего прохода, и публикует
+// события, записанные перед остановкой, пока не истечёт срок контекста.
+// Неопубликованные события остаются в outbox и будут опубликованы после запуска.
+func (r *OutboxRelay) Stop(ctx context.Context) error {
+	if r.done == nil {
+		return nil
+	}
+
+	r.heartbeat.Stop()
+	close(r.stop)
+	select {
+	case <-r.done:
+		r.cancel()
+	case <-ctx.Done():
+		r.cancel()
+		<-r.done
+		return fmt.Errorf("outbox relay interrupted: %w", ctx.Err())
+	}
+
+	for {
+		published, err := r.relay(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to relay outbox events: %w", err)
+		}
+		if published < r.cfg.BatchSize {
+			return nil
+		}
+	}
+}
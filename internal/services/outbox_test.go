@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/events"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// fakeOutboxTx — транзакция outbox в памяти, запоминающая отметки relay.
+type fakeOutboxTx struct {
+	locked    bool
+	pending   []models.OutboxEvent
+	published []int64
+	failed    map[int64]time.Duration // событие → пауза перед повторной публикацией
+}
+
+func (tx *fakeOutboxTx) TryLockRelay(context.Context) (bool, error) {
+	return tx.locked, nil
+}
+
+func (tx *fakeOutboxTx) ReadPendingEvents(_ context.Context, limit int) ([]models.OutboxEvent, error) {
+	return tx.pending[:min(limit, len(tx.pending))], nil
+}
+
+func (tx *fakeOutboxTx) MarkEventsPublished(_ context.Context, sequences []int64) error {
+	tx.published = append(tx.published, sequences...)
+	return nil
+}
+
+func (tx *fakeOutboxTx) MarkEventFailed(_ context.Context, sequence int64, retryIn time.Duration, _ string) error {
+	if tx.failed == nil {
+		tx.failed = make(map[int64]time.Duration)
+	}
+	tx.failed[sequence] = retryIn
+	return nil
+}
+
+// runNATS — запускает встроенный сервер NATS с JetStream на свободном порту.
+func runNATS(t *testing.T) *server.Server {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server is not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+
+	return srv
+}
+
+// outboxEvents — события outbox с последовательными номерами; subs — подписка каждого события.
+func outboxEvents(subs ...uuid.UUID) []models.OutboxEvent {
+	pending := make([]models.OutboxEvent, len(subs))
+	for i, sub := range subs {
+		pending[i] = models.OutboxEvent{Event: models.Event{
+			ID:             uuid.New(),
+			Sequence:       int64(i + 1),
+			Type:           models.EventSubsUpdated,
+			SubscriptionID: sub,
+			OccurredAt:     time.Now().UTC(),
+			Data:           json.RawMessage(`{}`),
+		}}
+	}
+	return pending
+}
+
+func TestOutboxRelayPublishesToNATS(t *testing.T) {
+	srv := runNATS(t)
+
+	subA, subB := uuid.New(), uuid.New()
+	cfg := config.OutboxConfig{BatchSize: 10, RetryInitialDelay: time.Second, RetryMaxDelay: time.Minute}
+
+	tests := []struct {
+		name          string
+		jetStream     bool
+		stream        bool // создать поток, захватывающий subject событий
+		locked        bool
+		pending       []models.OutboxEvent
+		passes        int // сколько раз проход повторяется с теми же событиями
+		wantPublished []int64
+		wantFailed    map[int64]time.Duration
+		wantReceived  int // сообщений, полученных подписчиком или сохранённых в потоке
+	}{
+		{
+			name:          "publishes events in order",
+			locked:        true,
+			pending:       outboxEvents(subA, subB, subA),
+			passes:        1,
+			wantPublished: []int64{1, 2, 3},
+			wantReceived:  3,
+		},
+		{
+			name:         "relay locked by another instance",
+			pending:      outboxEvents(subA),
+			passes:       1,
+			wantReceived: 0,
+		},
+		{
+			name:          "failed publish defers event and stops pass",
+			jetStream:     true,
+			locked:        true,
+			pending:       outboxEvents(subA, subA),
+			passes:        1,
+			wantPublished: []int64{},
+			wantFailed:    map[int64]time.Duration{1: time.Second},
+			wantReceived:  0,
+		},
+		{
+			name:          "jetstream drops repeated publication",
+			jetStream:     true,
+			stream:        true,
+			locked:        true,
+			pending:       outboxEvents(subA, subB),
+			passes:        2,
+			wantPublished: []int64{1, 2, 1, 2},
+			wantReceived:  2,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			prefix := "events" + string(rune('a'+i))
+
+			nc, err := nats.Connect(srv.ClientURL())
+			if err != nil {
+				t.Fatalf("failed to connect to nats: %v", err)
+			}
+			defer nc.Close()
+
+			var stream jetstream.Stream
+			if tt.stream {
+				js, err := jetstream.New(nc)
+				if err != nil {
+					t.Fatalf("failed to init jetstream: %v", err)
+				}
+				stream, err = js.CreateStream(ctx, jetstream.StreamConfig{Name: prefix, Subjects: []string{prefix + ".>"}})
+				if err != nil {
+					t.Fatalf("failed to create stream: %v", err)
+				}
+			}
+
+			// Публикация в JetStream ждёт ответа потока, поэтому подписчик
+			// нужен только для публикации без JetStream.
+			var received *nats.Subscription
+			if !tt.jetStream {
+				if received, err = nc.SubscribeSync(prefix + ".>"); err != nil {
+					t.Fatalf("failed to subscribe: %v", err)
+				}
+				if err := nc.Flush(); err != nil {
+					t.Fatalf("failed to flush: %v", err)
+				}
+			}
+
+			publisher, err := events.NewNATS(config.NATSConfig{
+				URL:           srv.ClientURL(),
+				SubjectPrefix: prefix,
+				JetStream:     tt.jetStream,
+				Timeout:       time.Second,
+			})
+			if err != nil {
+				t.Fatalf("NewNATS() error = %v", err)
+			}
+			defer publisher.Close(ctx)
+
+			relay := &OutboxRelay{publisher: publisher, cfg: cfg}
+			tx := &fakeOutboxTx{locked: tt.locked, pending: tt.pending}
+			for range tt.passes {
+				if _, err := relay.publishPending(ctx, tx); err != nil {
+					t.Fatalf("publishPending() error = %v", err)
+				}
+			}
+
+			if !slices.Equal(tx.published, tt.wantPublished) {
+				t.Errorf("published = %v, want %v", tx.published, tt.wantPublished)
+			}
+			if len(tx.failed) != len(tt.wantFailed) {
+				t.Errorf("failed = %v, want %v", tx.failed, tt.wantFailed)
+			}
+			for seq, delay := range tt.wantFailed {
+				if got, ok := tx.failed[seq]; !ok || got != delay {
+					t.Errorf("failed[%d] = %v, want %v", seq, got, delay)
+				}
+			}
+
+			if tt.jetStream {
+				if stream == nil {
+					return
+				}
+				info, err := stream.Info(ctx)
+				if err != nil {
+					t.Fatalf("failed to get stream info: %v", err)
+				}
+				if got := int(info.State.Msgs); got != tt.wantReceived {
+					t.Errorf("stream messages = %d, want %d", got, tt.wantReceived)
+				}
+				return
+			}
+
+			// Без JetStream подписчик получает сообщения в порядке публикации.
+			for n := range tt.wantReceived {
+				msg, err := received.NextMsg(time.Second)
+				if err != nil {
+					t.Fatalf("message %d: %v", n, err)
+				}
+				var event models.Event
+				if err := json.Unmarshal(msg.Data, &event); err != nil {
+					t.Fatalf("failed to decode event: %v", err)
+				}
+				if want := tt.pending[n]; event.ID != want.ID || event.Sequence != want.Sequence {
+					t.Errorf("message %d = event %d (%s), want %d (%s)", n, event.Sequence, event.ID, want.Sequence, want.ID)
+				}
+				if got := msg.Header.Get(jetstream.MsgIDHeader); got != tt.pending[n].ID.String() {
+					t.Errorf("message %d %s = %q, want event ID", n, jetstream.MsgIDHeader, got)
+				}
+			}
+			if msg, err := received.NextMsg(100 * time.Millisecond); !errors.Is(err, nats.ErrTimeout) {
+				t.Errorf("unexpected message %v (error %v)", msg, err)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: time.Second},
+		{attempts: 1, want: 2 * time.Second},
+		{attempts: 3, want: 8 * time.Second},
+		{attempts: 5, want: 30 * time.Second},
+		{attempts: 100, want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(time.Second, 30*time.Second, tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(attempts=%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -3,8 +3,12 @@ package services
 import (
 	"context"
 	"errors"
-	"log/slog"
+	"fmt"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
 	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/lib/tracing"
 	"online_subscription_service/internal/storage"
 	"time"
 
@@ -13,86 +17,327 @@ import (
 
 // subsSaver — отвечает за создание подписок.
 type subsSaver interface {
-	CreateSubscription(ctx context.Context, sub models.SubsDTO) (uuid.UUID, error)
+	CreateSubscription(ctx context.Context, tenantID uuid.UUID, sub models.SubsDTO) (uuid.UUID, error)
+	CountActiveSubscriptions(ctx context.Context, tenantID, userID uuid.UUID) (int, error)
+	LockUserSubscriptions(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) error
 }
 
 // subsProvider — отвечает за чтение и обновление подписок.
 type subsProvider interface {
-	ReadSubscription(ctx context.Context, uuid uuid.UUID) (models.SubsDTO, error)
-	UpdateSubscription(ctx context.Context, uuid uuid.UUID, sub models.SubsUpdateDTO) error
-	ReadAllSubscriptions(ctx context.Context) ([]models.SubsDTO, error)
-	ReadPriceWithPeriod(ctx context.Context, from, to time.Time, userID uuid.UUID, name string) (int, error)
+	ReadSubscription(ctx context.Context, tenantID, uuid uuid.UUID) (models.SubsDTO, error)
+	ReadSubscriptionForUpdate(ctx context.Context, tenantID, uuid uuid.UUID) (models.SubsDTO, error)
+	UpdateSubscription(ctx context.Context, tenantID, uuid uuid.UUID, sub models.SubsUpdateDTO) error
+	ReadAllSubscriptions(ctx context.Context, tenantID uuid.UUID, filter models.SubsFilter) ([]models.SubsDTO, error)
+	StreamSubscriptions(ctx context.Context, tenantID uuid.UUID, filter models.SubsFilter, fn func(models.SubsDTO) error) error
+	ReadSubscriptionsWithPeriod(ctx context.Context, tenantID uuid.UUID, from, to time.Time, userIDs []uuid.UUID, name string) ([]models.SubsDTO, error)
+	ReadSubscriptionsByUsers(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID, name string, page models.Page) ([]models.SubsDTO, error)
+	ReadSubscriptionsSummary(ctx context.Context, tenantID uuid.UUID, filter models.SubsFilter) (models.SubsSummary, error)
+	ReadSubscriptionsSummaryByUsers(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]models.SubsSummary, error)
 }
 
 // subsRemover — отвечает за удаление подписок.
 type subsRemover interface {
-	DeleteSubscriptions(ctx context.Context, uuid uuid.UUID) error
+	DeleteSubscriptions(ctx context.Context, tenantID, uuid uuid.UUID) error
+}
+
+// subsTransactor — отвечает за выполнение нескольких операций с подписками в одной транзакции.
+type subsTransactor interface {
+	WithinTx(ctx context.Context, fn func(tx *storage.SubsStorage) error) error
 }
 
 // SubsService — сервисный слой для работы с подписками.
 // Объединяет возможности создания, чтения/обновления и удаления подписок через соответствующие интерфейсы.
+// Все операции выполняются в пределах организации вызывающей стороны.
+// Если outbox включён, каждое изменение подписки записывает событие о нём
+// в той же транзакции, что и само изменение.
 type SubsService struct {
 	subsSaver    subsSaver
 	subsProvider subsProvider
 	subsRemover  subsRemover
+	subsTx       subsTransactor
+	subsEvents   subsEventWriter // nil — события не записываются
+	quota        config.QuotaConfig
 }
 
 // NewSubsService — конструктор сервиса подписок.
-// Принимает хранилище подписок и возвращает инициализированный экземпляр SubsService,
-// реализующий все операции через соответствующие интерфейсы.
-func NewSubsService(subsStorage *storage.SubsStorage) *SubsService {
-	return &SubsService{
+// Принимает хранилище подписок, квоты и настройки outbox, возвращает инициализированный
+// экземпляр SubsService, реализующий все операции через соответствующие интерфейсы.
+func NewSubsService(subsStorage *storage.SubsStorage, quota config.QuotaConfig, outbox config.OutboxConfig) *SubsService {
+	s := &SubsService{
 		subsSaver:    subsStorage,
 		subsProvider: subsStorage,
 		subsRemover:  subsStorage,
+		subsTx:       subsStorage,
+		quota:        quota,
+	}
+	if outbox.Enabled {
+		s.subsEvents = subsStorage
+	}
+	return s
+}
+
+// withStorage — копия сервиса, работающая с хранилищем tx (например, полученным в WithinTx).
+func (s *SubsService) withStorage(tx *storage.SubsStorage) *SubsService {
+	txService := &SubsService{
+		subsSaver:    tx,
+		subsProvider: tx,
+		subsRemover:  tx,
+		subsTx:       tx,
+		quota:        s.quota,
 	}
+	if s.subsEvents != nil {
+		txService.subsEvents = tx
+	}
+	return txService
 }
 
 // AddSubscription — добавляет новую подписку через интерфейс subsSaver.
+// Если user_id не указан, подписка создаётся для вызывающего пользователя;
+// создать подписку для другого пользователя может только администратор.
+// Количество действующих подписок пользователя ограничено квотой.
+// Подписка и событие subscription.created сохраняются в одной транзакции.
 // Возвращает UUID созданной подписки и ошибку, если она произошла.
-func (s *SubsService) AddSubscription(ctx context.Context, sub models.SubsDTO) (uuid.UUID, error) {
-	slog.Info("start adding subscription")
-	uuid, err := s.subsSaver.CreateSubscription(ctx, sub)
+func (s *SubsService) AddSubscription(ctx context.Context, sub models.SubsDTO) (_ uuid.UUID, err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.AddSubscription")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start adding subscription")
+	id, err := caller(ctx)
 	if err != nil {
-		slog.Error(err.Error())
-		return uuid, errors.New("error add new subscription")
+		return uuid.UUID{}, err
+	}
+
+	if sub.UserID == uuid.Nil {
+		if id.UserID == uuid.Nil {
+			return uuid.UUID{}, fmt.Errorf("%w: user_id is required", ErrInvalidInput)
+		}
+		sub.UserID = id.UserID
+	}
+
+	if _, err := authorize(ctx, sub.UserID); err != nil {
+		return uuid.UUID{}, err
 	}
-	return uuid, nil
+
+	err = s.inTx(ctx, "error add new subscription", func(tx *SubsService) error {
+		if err := tx.checkQuota(ctx, id.TenantID, sub.UserID); err != nil {
+			return err
+		}
+
+		subID, err := tx.subsSaver.CreateSubscription(ctx, id.TenantID, sub)
+		if err != nil {
+			logger.FromContext(ctx).Error(err.Error())
+			return errors.New("error add new subscription")
+		}
+		sub.ID = subID
+
+		if err := appendSubsEvents(ctx, tx.subsEvents, id.TenantID, createdEvent(sub)); err != nil {
+			logger.FromContext(ctx).Error(err.Error())
+			return errors.New("error add new subscription")
+		}
+		return nil
+	})
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	return sub.ID, nil
+}
+
+// checkQuota — проверяет, что у пользователя есть место для ещё одной действующей подписки.
+// Вызывается в транзакции, в которой создаётся или изменяется подписка: до её завершения другие транзакции
+// не могут проверить квоту того же пользователя, поэтому параллельные запросы её не превысят.
+func (s *SubsService) checkQuota(ctx context.Context, tenantID, userID uuid.UUID) error {
+	if s.quota.MaxSubscriptionsPerUser <= 0 {
+		return nil
+	}
+
+	if err := s.subsSaver.LockUserSubscriptions(ctx, tenantID, []uuid.UUID{userID}); err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return errors.New("error check subscription quota")
+	}
+
+	count, err := s.subsSaver.CountActiveSubscriptions(ctx, tenantID, userID)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return errors.New("error check subscription quota")
+	}
+
+	if count >= s.quota.MaxSubscriptionsPerUser {
+		return fmt.Errorf("%w: limit is %d", ErrQuotaExceeded, s.quota.MaxSubscriptionsPerUser)
+	}
+
+	return nil
+}
+
+// quotaTarget — пользователь, квоту которого нужно проверить перед изменением подписки prev
+// на update в момент at. Подписка занимает место в квоте, пока не закончилась (как в
+// CountActiveSubscriptions); проверка нужна, если после изменения подписка действует, а до
+// него не действовала или принадлежала другому пользователю. Сама изменяемая подписка в
+// этом случае ещё не учтена в квоте пользователя.
+func quotaTarget(prev models.SubsDTO, update models.SubsUpdateDTO, at time.Time) (uuid.UUID, bool) {
+	next := prev
+	if update.UserID != nil {
+		next.UserID = *update.UserID
+	}
+	if update.StartDate != nil {
+		next.StartDate = *update.StartDate
+	}
+	if update.EndDate != nil {
+		next.EndDate = update.EndDate
+	}
+
+	if next.Status(at) == models.SubsEnded {
+		return uuid.Nil, false
+	}
+	return next.UserID, next.UserID != prev.UserID || prev.Status(at) == models.SubsEnded
 }
 
 // GetSubscription — возвращает информацию о конкретной подписке по UUID.
 // Вызывает метод subsProvider.ReadSubscription и конвертирует результат в модель Subs.
-func (s *SubsService) GetSubscription(ctx context.Context, uuid uuid.UUID) (models.Subs, error) {
-	slog.Info("start getting subscription")
-	sub, err := s.subsProvider.ReadSubscription(ctx, uuid)
+func (s *SubsService) GetSubscription(ctx context.Context, uuid uuid.UUID) (_ models.Subs, err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.GetSubscription")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start getting subscription")
+	sub, _, err := s.ownedSubscription(ctx, uuid)
 	if err != nil {
-		slog.Error(err.Error())
-		return models.Subs{}, errors.New("error get subscription")
+		return models.Subs{}, err
 	}
 
 	return sub.ToSubs(), nil
 }
 
+// ownedSubscription — читает подписку в организации вызывающей стороны и проверяет,
+// что вызывающая сторона имеет к ней доступ. Возвращает подписку и личность вызывающей стороны.
+func (s *SubsService) ownedSubscription(ctx context.Context, uuid uuid.UUID) (models.SubsDTO, auth.Identity, error) {
+	return s.readOwnedSubscription(ctx, uuid, s.subsProvider.ReadSubscription)
+}
+
+// lockedSubscription — то же, что ownedSubscription, но блокирует подписку до конца
+// транзакции, чтобы события о параллельных изменениях одной подписки
+// записывались в порядке этих изменений. Вызывается на копии сервиса из inTx.
+func (s *SubsService) lockedSubscription(ctx context.Context, uuid uuid.UUID) (models.SubsDTO, auth.Identity, error) {
+	return s.readOwnedSubscription(ctx, uuid, s.subsProvider.ReadSubscriptionForUpdate)
+}
+
+// readOwnedSubscription — читает подписку функцией read и проверяет доступ к ней.
+func (s *SubsService) readOwnedSubscription(ctx context.Context, uuid uuid.UUID,
+	read func(ctx context.Context, tenantID, uuid uuid.UUID) (models.SubsDTO, error)) (models.SubsDTO, auth.Identity, error) {
+	id, err := caller(ctx)
+	if err != nil {
+		return models.SubsDTO{}, id, err
+	}
+
+	sub, err := read(ctx, id.TenantID, uuid)
+	if errors.Is(err, storage.ErrSubsNotFound) {
+		return models.SubsDTO{}, id, ErrNotFound
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.SubsDTO{}, id, errors.New("error get subscription")
+	}
+
+	if _, err := authorize(ctx, sub.UserID); err != nil {
+		return models.SubsDTO{}, id, err
+	}
+
+	return sub, id, nil
+}
+
 // EditSubscription — обновляет данные существующей подписки.
 // Вызывает метод subsProvider.UpdateSubscription с переданным UUID и DTO обновления.
-func (s *SubsService) EditSubscription(ctx context.Context, uuid uuid.UUID, sub models.SubsUpdateDTO) error {
-	slog.Info("start editting subscription")
-	if err := s.subsProvider.UpdateSubscription(ctx, uuid, sub); err != nil {
-		slog.Error(err.Error())
+// Передать подписку другому пользователю может только администратор.
+// Если изменение делает подписку действующей или передаёт действующую подписку другому
+// пользователю, проверяется квота этого пользователя.
+// Изменение и события subscription.updated и, если подписка перешла в другой статус,
+// subscription.status_changed сохраняются в одной транзакции.
+func (s *SubsService) EditSubscription(ctx context.Context, uuid uuid.UUID, sub models.SubsUpdateDTO) (err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.EditSubscription")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start editting subscription")
+	return s.inTx(ctx, "error edit subscription", func(tx *SubsService) error {
+		return tx.editSubscription(ctx, uuid, sub)
+	})
+}
+
+// editSubscription — проверяет права, квоту и изменяет подписку.
+// Вызывается на копии сервиса из inTx.
+func (s *SubsService) editSubscription(ctx context.Context, uuid uuid.UUID, sub models.SubsUpdateDTO) error {
+	prev, id, err := s.lockedSubscription(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	if sub.UserID != nil {
+		if _, err := authorize(ctx, *sub.UserID); err != nil {
+			return err
+		}
+	}
+
+	if userID, ok := quotaTarget(prev, sub, time.Now()); ok {
+		if err := s.checkQuota(ctx, id.TenantID, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.subsProvider.UpdateSubscription(ctx, id.TenantID, uuid, sub); err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		if errors.Is(err, storage.ErrSubsNotFound) {
+			return ErrNotFound
+		}
+		return errors.New("error edit subscription")
+	}
+
+	if s.subsEvents == nil {
+		return nil
+	}
+
+	cur, err := s.subsProvider.ReadSubscription(ctx, id.TenantID, uuid)
+	if err == nil {
+		err = appendSubsEvents(ctx, s.subsEvents, id.TenantID, updatedEvents(prev, cur, time.Now())...)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
 		return errors.New("error edit subscription")
 	}
 	return nil
 }
 
-// GetAllSubscriptions — возвращает список всех подписок.
+// GetAllSubscriptions — возвращает страницу списка подписок, подходящих под фильтр.
+// Без limit возвращается DefaultPageLimit подписок, а при поиске по UUID — все запрошенные.
+// Администратор получает все подписки, обычный пользователь — только свои:
+// фильтр по другим пользователям для него приводит к ErrForbidden, а чужие подписки
+// из списка UUID в результат не попадают.
 // Читает данные через subsProvider.ReadAllSubscriptions и конвертирует каждую запись в модель Subs.
-func (s *SubsService) GetAllSubscriptions(ctx context.Context) ([]models.Subs, error) {
-	slog.Info("start getting all subscriptions")
+func (s *SubsService) GetAllSubscriptions(ctx context.Context, filter models.SubsFilter) (_ []models.Subs, err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.GetAllSubscriptions")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start getting all subscriptions")
 	var subs []models.Subs
 
-	slice, err := s.subsProvider.ReadAllSubscriptions(ctx)
+	id, err := caller(ctx)
+	if err != nil {
+		return subs, err
+	}
+
+	if filter.Limit < 0 || filter.Limit > models.MaxPageLimit || filter.Offset < 0 {
+		return subs, fmt.Errorf("%w: limit must be between 0 and %d, offset must not be negative", ErrInvalidInput, models.MaxPageLimit)
+	}
+	if len(filter.IDs) > models.MaxPageLimit {
+		return subs, fmt.Errorf("%w: at most %d ids can be requested at once", ErrInvalidInput, models.MaxPageLimit)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = max(models.DefaultPageLimit, len(filter.IDs))
+	}
+
+	if err := restrictFilter(id, &filter); err != nil {
+		return subs, err
+	}
+
+	slice, err := s.subsProvider.ReadAllSubscriptions(ctx, id.TenantID, filter)
 	if err != nil {
-		slog.Error(err.Error())
+		logger.FromContext(ctx).Error(err.Error())
 		return subs, errors.New("error geting all subscriptions")
 	}
 
@@ -103,25 +348,272 @@ func (s *SubsService) GetAllSubscriptions(ctx context.Context) ([]models.Subs, e
 	return subs, nil
 }
 
+// ExportSubscriptions — передаёт fn все подписки, подходящие под фильтр, по одной,
+// читая их из хранилища потоком. Права те же, что у GetAllSubscriptions, но размер
+// выборки не ограничен MaxPageLimit. Права проверяются до первого вызова fn.
+// Ошибка fn прекращает выгрузку и возвращается как есть.
+func (s *SubsService) ExportSubscriptions(ctx context.Context, filter models.SubsFilter, fn func(models.Subs) error) (err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.ExportSubscriptions")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start exporting subscriptions")
+	id, err := caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return fmt.Errorf("%w: limit and offset must not be negative", ErrInvalidInput)
+	}
+
+	if err := restrictFilter(id, &filter); err != nil {
+		return err
+	}
+
+	var fnErr error
+	err = s.subsProvider.StreamSubscriptions(ctx, id.TenantID, filter, func(sub models.SubsDTO) error {
+		fnErr = fn(sub.ToSubs())
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return errors.New("error exporting subscriptions")
+	}
+
+	return nil
+}
+
+// restrictFilter — ограничивает фильтр списка подписками, доступными вызывающей стороне.
+// Обычный пользователь видит только свои подписки: фильтр по другим пользователям
+// для него приводит к ErrForbidden.
+func restrictFilter(id auth.Identity, filter *models.SubsFilter) error {
+	if unrestricted(id) {
+		return nil
+	}
+
+	if filter.UserID != nil && *filter.UserID != id.UserID {
+		return ErrForbidden
+	}
+	for _, userID := range filter.UserIDs {
+		if userID != id.UserID {
+			return ErrForbidden
+		}
+	}
+	filter.UserID = &id.UserID
+
+	return nil
+}
+
 // GetPriceWithPeriod — возвращает стоимость подписки за указанный период для конкретного пользователя и услуги.
 // Параметры: начало и конец периода, UUID пользователя, название услуги.
-// Вызывает subsProvider.ReadPriceWithPeriod для вычисления цены.
-func (s *SubsService) GetPriceWithPeriod(ctx context.Context, from, to time.Time, userID uuid.UUID, name string) (int, error) {
-	slog.Info("start getting price with period")
-	price, err := s.subsProvider.ReadPriceWithPeriod(ctx, from, to, userID, name)
+// Стоимость — сумма списаний по подпискам в периоде по той же модели, что и журнал
+// начислений (см. periodCost).
+func (s *SubsService) GetPriceWithPeriod(ctx context.Context, from, to time.Time, userID uuid.UUID, name string) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.GetPriceWithPeriod")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start getting price with period")
+	id, err := authorize(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if name == "" {
+		return 0, fmt.Errorf("%w: service_name is required", ErrInvalidInput)
+	}
+
+	subs, err := s.subsProvider.ReadSubscriptionsWithPeriod(ctx, id.TenantID, from, to, []uuid.UUID{userID}, name)
 	if err != nil {
-		slog.Error(err.Error())
+		logger.FromContext(ctx).Error(err.Error())
 		return 0, errors.New("error getting price with period")
 	}
+
+	price := 0
+	for _, sub := range subs {
+		price += periodCost(sub, from, to)
+	}
 	return price, nil
 }
 
+// GetPricesWithPeriod — возвращает стоимость подписок на услугу за период для нескольких
+// пользователей, прочитав их подписки одним запросом к хранилищу. Стоимость считается
+// так же, как в GetPriceWithPeriod.
+// В результате есть все запрошенные пользователи; без подписок в периоде стоимость равна 0.
+// Обычный пользователь может запросить стоимость только своих подписок.
+func (s *SubsService) GetPricesWithPeriod(ctx context.Context, from, to time.Time, userIDs []uuid.UUID, name string) (_ map[uuid.UUID]int, err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.GetPricesWithPeriod")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start getting prices with period")
+	id, err := authorizeUsers(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("%w: service_name is required", ErrInvalidInput)
+	}
+
+	prices := make(map[uuid.UUID]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return prices, nil
+	}
+
+	subs, err := s.subsProvider.ReadSubscriptionsWithPeriod(ctx, id.TenantID, from, to, userIDs, name)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return nil, errors.New("error getting prices with period")
+	}
+
+	for _, userID := range userIDs {
+		prices[userID] = 0
+	}
+	for _, sub := range subs {
+		prices[sub.UserID] += periodCost(sub, from, to)
+	}
+
+	return prices, nil
+}
+
+// GetSubscriptionsByUsers — возвращает страницу подписок каждого из пользователей userIDs
+// (не больше MaxPageLimit пользователей), прочитанную одним запросом к хранилищу.
+// page ограничивает подписки каждого пользователя отдельно; нулевой Limit — DefaultPageLimit.
+// Обычный пользователь может запросить только свои подписки.
+func (s *SubsService) GetSubscriptionsByUsers(ctx context.Context, userIDs []uuid.UUID, name string, page models.Page) (_ map[uuid.UUID][]models.Subs, err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.GetSubscriptionsByUsers")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start getting subscriptions by users")
+	id, err := authorizeUsers(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.Limit < 0 || page.Limit > models.MaxPageLimit || page.Offset < 0 {
+		return nil, fmt.Errorf("%w: limit must be between 0 and %d, offset must not be negative", ErrInvalidInput, models.MaxPageLimit)
+	}
+	if len(userIDs) > models.MaxPageLimit {
+		return nil, fmt.Errorf("%w: at most %d users can be requested at once", ErrInvalidInput, models.MaxPageLimit)
+	}
+	if page.Limit == 0 {
+		page.Limit = models.DefaultPageLimit
+	}
+
+	subs := make(map[uuid.UUID][]models.Subs, len(userIDs))
+	if len(userIDs) == 0 {
+		return subs, nil
+	}
+
+	found, err := s.subsProvider.ReadSubscriptionsByUsers(ctx, id.TenantID, userIDs, name, page)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return nil, errors.New("error getting subscriptions by users")
+	}
+
+	for _, sub := range found {
+		subs[sub.UserID] = append(subs[sub.UserID], sub.ToSubs())
+	}
+
+	return subs, nil
+}
+
+// GetSubscriptionsSummary — возвращает сводку по подпискам, подходящим под фильтр,
+// посчитанную в хранилище без чтения самих подписок. Права те же, что у GetAllSubscriptions.
+func (s *SubsService) GetSubscriptionsSummary(ctx context.Context, filter models.SubsFilter) (_ models.SubsSummary, err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.GetSubscriptionsSummary")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start getting subscriptions summary")
+	id, err := caller(ctx)
+	if err != nil {
+		return models.SubsSummary{}, err
+	}
+
+	if len(filter.IDs) > models.MaxPageLimit {
+		return models.SubsSummary{}, fmt.Errorf("%w: at most %d ids can be requested at once", ErrInvalidInput, models.MaxPageLimit)
+	}
+
+	if err := restrictFilter(id, &filter); err != nil {
+		return models.SubsSummary{}, err
+	}
+
+	summary, err := s.subsProvider.ReadSubscriptionsSummary(ctx, id.TenantID, filter)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.SubsSummary{}, errors.New("error getting subscriptions summary")
+	}
+
+	return summary, nil
+}
+
+// GetSubscriptionsSummaries — возвращает сводку по подпискам каждого из пользователей
+// userIDs (не больше MaxPageLimit), посчитанную одним запросом к хранилищу.
+// В результате есть все запрошенные пользователи. Обычный пользователь может
+// запросить только свою сводку.
+func (s *SubsService) GetSubscriptionsSummaries(ctx context.Context, userIDs []uuid.UUID) (_ map[uuid.UUID]models.SubsSummary, err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.GetSubscriptionsSummaries")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start getting subscriptions summaries")
+	id, err := authorizeUsers(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userIDs) > models.MaxPageLimit {
+		return nil, fmt.Errorf("%w: at most %d users can be requested at once", ErrInvalidInput, models.MaxPageLimit)
+	}
+
+	summaries := make(map[uuid.UUID]models.SubsSummary, len(userIDs))
+	if len(userIDs) == 0 {
+		return summaries, nil
+	}
+
+	found, err := s.subsProvider.ReadSubscriptionsSummaryByUsers(ctx, id.TenantID, userIDs)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return nil, errors.New("error getting subscriptions summaries")
+	}
+
+	for _, userID := range userIDs {
+		summaries[userID] = found[userID]
+	}
+
+	return summaries, nil
+}
+
 // RemoveSubscription — удаляет подписку по UUID.
 // Вызывает метод subsRemover.DeleteSubscriptions для удаления записи.
-func (s *SubsService) RemoveSubscription(ctx context.Context, uuid uuid.UUID) error {
-	slog.Info("start deleting subscription")
-	if err := s.subsRemover.DeleteSubscriptions(ctx, uuid); err != nil {
-		slog.Error(err.Error())
+// Удаление и событие subscription.deleted сохраняются в одной транзакции.
+func (s *SubsService) RemoveSubscription(ctx context.Context, uuid uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "SubsService.RemoveSubscription")
+	defer tracing.End(span, &err)
+
+	logger.FromContext(ctx).Info("start deleting subscription")
+	return s.inTx(ctx, "error deleting subscription", func(tx *SubsService) error {
+		return tx.removeSubscription(ctx, uuid)
+	})
+}
+
+// removeSubscription — проверяет права и удаляет подписку.
+// Вызывается на копии сервиса из inTx.
+func (s *SubsService) removeSubscription(ctx context.Context, uuid uuid.UUID) error {
+	sub, id, err := s.lockedSubscription(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	if err := s.subsRemover.DeleteSubscriptions(ctx, id.TenantID, uuid); err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		if errors.Is(err, storage.ErrSubsNotFound) {
+			return ErrNotFound
+		}
+		return errors.New("error deleting subscription")
+	}
+
+	if err := appendSubsEvents(ctx, s.subsEvents, id.TenantID, deletedEvent(sub)); err != nil {
+		logger.FromContext(ctx).Error(err.Error())
 		return errors.New("error deleting subscription")
 	}
 	return nil
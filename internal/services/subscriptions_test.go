@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/storage"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeSubsSaver — считает действующие подписки и записывает порядок вызовов.
+type fakeSubsSaver struct {
+	active int
+	calls  []string
+}
+
+func (f *fakeSubsSaver) CreateSubscription(context.Context, uuid.UUID, models.SubsDTO) (uuid.UUID, error) {
+	f.calls = append(f.calls, "create")
+	return uuid.New(), nil
+}
+
+func (f *fakeSubsSaver) CountActiveSubscriptions(context.Context, uuid.UUID, uuid.UUID) (int, error) {
+	f.calls = append(f.calls, "count")
+	return f.active, nil
+}
+
+func (f *fakeSubsSaver) LockUserSubscriptions(context.Context, uuid.UUID, []uuid.UUID) error {
+	f.calls = append(f.calls, "lock")
+	return nil
+}
+
+// fakeSubsProvider — хранилище подписок в памяти, запоминающее фильтр последнего чтения
+// списка, пользователей последнего чтения за период и изменённые подписки.
+type fakeSubsProvider struct {
+	subsProvider
+	subs    []models.SubsDTO
+	filter  models.SubsFilter
+	users   []uuid.UUID
+	updated []uuid.UUID
+}
+
+func (f *fakeSubsProvider) ReadSubscription(_ context.Context, _, id uuid.UUID) (models.SubsDTO, error) {
+	for _, sub := range f.subs {
+		if sub.ID == id {
+			return sub, nil
+		}
+	}
+	return models.SubsDTO{}, storage.ErrSubsNotFound
+}
+
+func (f *fakeSubsProvider) ReadSubscriptionForUpdate(ctx context.Context, tenantID, id uuid.UUID) (models.SubsDTO, error) {
+	return f.ReadSubscription(ctx, tenantID, id)
+}
+
+func (f *fakeSubsProvider) UpdateSubscription(_ context.Context, _, id uuid.UUID, _ models.SubsUpdateDTO) error {
+	f.updated = append(f.updated, id)
+	return nil
+}
+
+func (f *fakeSubsProvider) ReadAllSubscriptions(_ context.Context, _ uuid.UUID, filter models.SubsFilter) ([]models.SubsDTO, error) {
+	f.filter = filter
+	return f.subs, nil
+}
+
+func (f *fakeSubsProvider) ReadSubscriptionsWithPeriod(_ context.Context, _ uuid.UUID, _, _ time.Time, userIDs []uuid.UUID, _ string) ([]models.SubsDTO, error) {
+	f.users = userIDs
+	return f.subs, nil
+}
+
+// fakeSubsRemover — запоминает удалённые подписки.
+type fakeSubsRemover struct {
+	deleted []uuid.UUID
+}
+
+func (f *fakeSubsRemover) DeleteSubscriptions(_ context.Context, _, id uuid.UUID) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func TestSubsServiceGetAllSubscriptionsPage(t *testing.T) {
+	ids := make([]uuid.UUID, 300)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	tests := []struct {
+		name      string
+		filter    models.SubsFilter
+		wantLimit int
+		wantErr   error
+	}{
+		{name: "default page", wantLimit: models.DefaultPageLimit},
+		{name: "explicit page", filter: models.SubsFilter{Page: models.Page{Limit: 20, Offset: 40}}, wantLimit: 20},
+		{name: "maximum page", filter: models.SubsFilter{Page: models.Page{Limit: models.MaxPageLimit}}, wantLimit: models.MaxPageLimit},
+		{name: "few ids use default page", filter: models.SubsFilter{IDs: ids[:5]}, wantLimit: models.DefaultPageLimit},
+		{name: "many ids return all of them", filter: models.SubsFilter{IDs: ids}, wantLimit: len(ids)},
+		{name: "limit above maximum", filter: models.SubsFilter{Page: models.Page{Limit: models.MaxPageLimit + 1}}, wantErr: ErrInvalidInput},
+		{name: "negative limit", filter: models.SubsFilter{Page: models.Page{Limit: -1}}, wantErr: ErrInvalidInput},
+		{name: "negative offset", filter: models.SubsFilter{Page: models.Page{Offset: -1}}, wantErr: ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeSubsProvider{}
+			s := &SubsService{subsProvider: provider}
+			ctx := auth.WithIdentity(context.Background(), auth.Identity{TenantID: uuid.New(), Roles: []string{auth.RoleAdmin}})
+
+			_, err := s.GetAllSubscriptions(ctx, tt.filter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetAllSubscriptions() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (provider.filter.Limit != tt.wantLimit || provider.filter.Offset != tt.filter.Offset) {
+				t.Errorf("storage page = %+v, want limit %d offset %d", provider.filter.Page, tt.wantLimit, tt.filter.Offset)
+			}
+		})
+	}
+}
+
+func TestSubsServiceCheckQuota(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		active    int
+		wantErr   error
+		wantCalls []string
+	}{
+		{name: "no quota", limit: 0, active: 100},
+		{name: "below limit", limit: 3, active: 2, wantCalls: []string{"lock", "count"}},
+		{name: "limit reached", limit: 3, active: 3, wantErr: ErrQuotaExceeded, wantCalls: []string{"lock", "count"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saver := &fakeSubsSaver{active: tt.active}
+			s := &SubsService{subsSaver: saver, quota: config.QuotaConfig{MaxSubscriptionsPerUser: tt.limit}}
+
+			err := s.checkQuota(context.Background(), uuid.New(), uuid.New())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkQuota() error = %v, want %v", err, tt.wantErr)
+			}
+			// Подписки считаются только под блокировкой пользователя.
+			if !slices.Equal(saver.calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", saver.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestQuotaTarget(t *testing.T) {
+	at := time.Date(2025, time.March, 15, 12, 0, 0, 0, time.UTC)
+	owner, other := uuid.New(), uuid.New()
+	past := at.AddDate(0, -1, 0)
+	future := at.AddDate(0, 1, 0)
+
+	active := models.SubsDTO{UserID: owner, StartDate: at.AddDate(-1, 0, 0)}
+	ended := models.SubsDTO{UserID: owner, StartDate: at.AddDate(-1, 0, 0), EndDate: &past}
+	scheduled := models.SubsDTO{UserID: owner, StartDate: future}
+
+	tests := []struct {
+		name     string
+		prev     models.SubsDTO
+		update   models.SubsUpdateDTO
+		wantUser uuid.UUID
+		wantOK   bool
+	}{
+		{name: "active stays active", prev: active, update: models.SubsUpdateDTO{EndDate: &future}},
+		{name: "active ends", prev: active, update: models.SubsUpdateDTO{EndDate: &past}},
+		{name: "ended is reopened", prev: ended, update: models.SubsUpdateDTO{EndDate: &future}, wantUser: owner, wantOK: true},
+		{name: "ended stays ended", prev: ended, update: models.SubsUpdateDTO{Price: new(int)}},
+		{name: "active moves to another user", prev: active, update: models.SubsUpdateDTO{UserID: &other}, wantUser: other, wantOK: true},
+		{name: "scheduled moves to another user", prev: scheduled, update: models.SubsUpdateDTO{UserID: &other}, wantUser: other, wantOK: true},
+		{name: "ended moves to another user", prev: ended, update: models.SubsUpdateDTO{UserID: &other}},
+		{name: "ended is reopened for another user", prev: ended, update: models.SubsUpdateDTO{UserID: &other, EndDate: &future}, wantUser: other, wantOK: true},
+		{name: "same user is not a transfer", prev: active, update: models.SubsUpdateDTO{UserID: &owner}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, ok := quotaTarget(tt.prev, tt.update, at)
+			if ok != tt.wantOK || (ok && user != tt.wantUser) {
+				t.Errorf("quotaTarget() = %v, %v, want %v, %v", user, ok, tt.wantUser, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	tenant, owner, other := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name             string
+		caller           *auth.Identity
+		wantErr          error
+		wantUnrestricted bool
+	}{
+		{name: "owner", caller: &auth.Identity{TenantID: tenant, UserID: owner}},
+		{name: "other user", caller: &auth.Identity{TenantID: tenant, UserID: other}, wantErr: ErrForbidden},
+		{name: "admin", caller: &auth.Identity{TenantID: tenant, UserID: other, Roles: []string{auth.RoleAdmin}}, wantUnrestricted: true},
+		{name: "platform admin", caller: &auth.Identity{TenantID: tenant, UserID: other, Roles: []string{auth.RolePlatformAdmin}}, wantUnrestricted: true},
+		{name: "api key", caller: &auth.Identity{TenantID: tenant, KeyID: uuid.New(), Scopes: []string{auth.ScopeSubscriptionsRead}}, wantUnrestricted: true},
+		{name: "no identity", wantErr: ErrUnauthenticated},
+		{name: "no tenant", caller: &auth.Identity{UserID: owner, Roles: []string{auth.RoleAdmin}}, wantErr: ErrUnauthenticated, wantUnrestricted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.caller != nil {
+				ctx = auth.WithIdentity(ctx, *tt.caller)
+				if got := unrestricted(*tt.caller); got != tt.wantUnrestricted {
+					t.Errorf("unrestricted() = %v, want %v", got, tt.wantUnrestricted)
+				}
+			}
+
+			id, err := authorize(ctx, owner)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("authorize() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && id.TenantID != tenant {
+				t.Errorf("authorize() tenant = %s, want %s", id.TenantID, tenant)
+			}
+		})
+	}
+}
+
+func TestRestrictFilter(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	user := auth.Identity{TenantID: uuid.New(), UserID: owner}
+	admin := auth.Identity{TenantID: uuid.New(), UserID: owner, Roles: []string{auth.RoleAdmin}}
+	apiKey := auth.Identity{TenantID: uuid.New(), KeyID: uuid.New()}
+
+	tests := []struct {
+		name       string
+		caller     auth.Identity
+		filter     models.SubsFilter
+		wantUserID *uuid.UUID
+		wantErr    error
+	}{
+		{name: "user without filter sees own subscriptions", caller: user, wantUserID: &owner},
+		{name: "user filters by self", caller: user, filter: models.SubsFilter{UserID: &owner}, wantUserID: &owner},
+		{name: "user filters by self in list", caller: user, filter: models.SubsFilter{UserIDs: []uuid.UUID{owner}}, wantUserID: &owner},
+		{name: "user filters by other user", caller: user, filter: models.SubsFilter{UserID: &other}, wantErr: ErrForbidden},
+		{name: "user lists other user", caller: user, filter: models.SubsFilter{UserIDs: []uuid.UUID{owner, other}}, wantErr: ErrForbidden},
+		{name: "admin filter is kept", caller: admin, filter: models.SubsFilter{UserIDs: []uuid.UUID{other}}},
+		{name: "admin sees everyone", caller: admin},
+		{name: "api key filter is kept", caller: apiKey, filter: models.SubsFilter{UserID: &other}, wantUserID: &other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			err := restrictFilter(tt.caller, &filter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("restrictFilter() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (filter.UserID == nil) != (tt.wantUserID == nil) || (filter.UserID != nil && *filter.UserID != *tt.wantUserID) {
+				t.Errorf("filter user = %v, want %v", filter.UserID, tt.wantUserID)
+			}
+			if !slices.Equal(filter.UserIDs, tt.filter.UserIDs) {
+				t.Errorf("filter users = %v, want %v", filter.UserIDs, tt.filter.UserIDs)
+			}
+		})
+	}
+}
+
+func TestSubsServiceOwnership(t *testing.T) {
+	tenant, owner, other := uuid.New(), uuid.New(), uuid.New()
+	sub := models.SubsDTO{ID: uuid.New(), UserID: owner, Name: "Netflix", Price: 100, StartDate: date(2025, time.January, 1)}
+	price := 200
+
+	callers := map[string]auth.Identity{
+		"owner":      {TenantID: tenant, UserID: owner},
+		"other user": {TenantID: tenant, UserID: other},
+		"admin":      {TenantID: tenant, UserID: other, Roles: []string{auth.RoleAdmin}},
+		"api key":    {TenantID: tenant, KeyID: uuid.New(), Scopes: []string{auth.ScopeSubscriptionsRead, auth.ScopeSubscriptionsWrite}},
+	}
+
+	// Операции возвращают ошибку и проверяют, что хранилище вызвано (или не вызвано) как ожидается.
+	ops := map[string]func(t *testing.T, s *SubsService, provider *fakeSubsProvider, remover *fakeSubsRemover, ctx context.Context, allowed bool) error{
+		"get": func(_ *testing.T, s *SubsService, _ *fakeSubsProvider, _ *fakeSubsRemover, ctx context.Context, _ bool) error {
+			_, err := s.GetSubscription(ctx, sub.ID)
+			return err
+		},
+		"edit": func(t *testing.T, s *SubsService, provider *fakeSubsProvider, _ *fakeSubsRemover, ctx context.Context, allowed bool) error {
+			err := s.editSubscription(ctx, sub.ID, models.SubsUpdateDTO{Price: &price})
+			if got := len(provider.updated) == 1; got != allowed {
+				t.Errorf("subscription updated = %v, want %v", got, allowed)
+			}
+			return err
+		},
+		"delete": func(t *testing.T, s *SubsService, _ *fakeSubsProvider, remover *fakeSubsRemover, ctx context.Context, allowed bool) error {
+			err := s.removeSubscription(ctx, sub.ID)
+			if got := len(remover.deleted) == 1; got != allowed {
+				t.Errorf("subscription deleted = %v, want %v", got, allowed)
+			}
+			return err
+		},
+		"list": func(t *testing.T, s *SubsService, provider *fakeSubsProvider, _ *fakeSubsRemover, ctx context.Context, _ bool) error {
+			_, err := s.GetAllSubscriptions(ctx, models.SubsFilter{UserID: &owner})
+			if err == nil && (provider.filter.UserID == nil || *provider.filter.UserID != owner) {
+				t.Errorf("storage filter user = %v, want %s", provider.filter.UserID, owner)
+			}
+			return err
+		},
+		"price": func(t *testing.T, s *SubsService, provider *fakeSubsProvider, _ *fakeSubsRemover, ctx context.Context, allowed bool) error {
+			_, err := s.GetPriceWithPeriod(ctx, date(2025, time.January, 1), date(2025, time.December, 31), owner, "Netflix")
+			if got := provider.users != nil; got != allowed {
+				t.Errorf("storage read = %v, want %v", got, allowed)
+			}
+			return err
+		},
+	}
+
+	tests := []struct {
+		caller  string
+		op      string
+		wantErr error
+	}{
+		{caller: "owner", op: "get"},
+		{caller: "owner", op: "edit"},
+		{caller: "owner", op: "delete"},
+		{caller: "owner", op: "list"},
+		{caller: "owner", op: "price"},
+		{caller: "other user", op: "get", wantErr: ErrForbidden},
+		{caller: "other user", op: "edit", wantErr: ErrForbidden},
+		{caller: "other user", op: "delete", wantErr: ErrForbidden},
+		{caller: "other user", op: "list", wantErr: ErrForbidden},
+		{caller: "other user", op: "price", wantErr: ErrForbidden},
+		{caller: "admin", op: "get"},
+		{caller: "admin", op: "edit"},
+		{caller: "admin", op: "delete"},
+		{caller: "admin", op: "list"},
+		{caller: "admin", op: "price"},
+		{caller: "api key", op: "get"},
+		{caller: "api key", op: "edit"},
+		{caller: "api key", op: "delete"},
+		{caller: "api key", op: "list"},
+		{caller: "api key", op: "price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.caller+" "+tt.op, func(t *testing.T) {
+			provider := &fakeSubsProvider{subs: []models.SubsDTO{sub}}
+			remover := &fakeSubsRemover{}
+			s := &SubsService{subsProvider: provider, subsRemover: remover}
+			ctx := auth.WithIdentity(context.Background(), callers[tt.caller])
+
+			err := ops[tt.op](t, s, provider, remover, ctx, tt.wantErr == nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s error = %v, want %v", tt.op, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSubsServiceEditTransfer(t *testing.T) {
+	tenant, owner, other := uuid.New(), uuid.New(), uuid.New()
+	sub := models.SubsDTO{ID: uuid.New(), UserID: owner, StartDate: date(2025, time.January, 1)}
+
+	tests := []struct {
+		name    string
+		caller  auth.Identity
+		wantErr error
+	}{
+		{name: "owner", caller: auth.Identity{TenantID: tenant, UserID: owner}, wantErr: ErrForbidden},
+		{name: "admin", caller: auth.Identity{TenantID: tenant, UserID: owner, Roles: []string{auth.RoleAdmin}}},
+		{name: "api key", caller: auth.Identity{TenantID: tenant, KeyID: uuid.New()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeSubsProvider{subs: []models.SubsDTO{sub}}
+			s := &SubsService{subsProvider: provider}
+			ctx := auth.WithIdentity(context.Background(), tt.caller)
+
+			// Передать подписку другому пользователю может только администратор или сервис.
+			err := s.editSubscription(ctx, sub.ID, models.SubsUpdateDTO{UserID: &other})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("editSubscription() error = %v, want %v", err, tt.wantErr)
+			}
+			if updated := len(provider.updated) == 1; updated != (tt.wantErr == nil) {
+				t.Errorf("subscription updated = %v, want %v", updated, tt.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/storage"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// defaultCurrency — валюта новой организации, если она не указана.
+const defaultCurrency = "RUB"
+
+// currencyCode — формат кода валюты ISO 4217.
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// tenantsStorage — отвечает за хранение организаций.
+type tenantsStorage interface {
+	CreateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error)
+	ReadTenant(ctx context.Context, id uuid.UUID) (models.Tenant, error)
+	ReadTenants(ctx context.Context) ([]models.Tenant, error)
+	UpdateTenant(ctx context.Context, id uuid.UUID, req models.EditTenantRequest) error
+}
+
+// TenantsService — сервисный слой для управления организациями и их настройками.
+// Создавать организации и видеть чужие организации может только администратор платформы,
+// изменять настройки — также администратор самой организации.
+type TenantsService struct {
+	tenants tenantsStorage
+	known   sync.Map // UUID существующих организаций; организации не удаляются, поэтому кэш не устаревает
+}
+
+// NewTenantsService — конструктор сервиса организаций.
+func NewTenantsService(tenantsStorage *storage.TenantsStorage) *TenantsService {
+	return &TenantsService{
+		tenants: tenantsStorage,
+	}
+}
+
+// ResolveTenant — определяет организацию запроса по правилам auth.ResolveTenant и проверяет,
+// что она существует. Для несуществующей организации возвращает ErrTenantNotFound.
+func (s *TenantsService) ResolveTenant(ctx context.Context, id auth.Identity, requested uuid.UUID) (auth.Identity, error) {
+	id, err := auth.ResolveTenant(id, requested)
+	if err != nil {
+		return id, err
+	}
+
+	if _, ok := s.known.Load(id.TenantID); ok {
+		return id, nil
+	}
+
+	_, err = s.tenants.ReadTenant(ctx, id.TenantID)
+	if errors.Is(err, storage.ErrTenantNotFound) {
+		return auth.Identity{}, ErrTenantNotFound
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return auth.Identity{}, errors.New("error resolve tenant")
+	}
+	s.known.Store(id.TenantID, struct{}{})
+
+	return id, nil
+}
+
+// CreateTenant — создаёт новую организацию.
+func (s *TenantsService) CreateTenant(ctx context.Context, req models.CreateTenantRequest) (models.Tenant, error) {
+	logger.FromContext(ctx).Info("start creating tenant")
+	if _, err := requirePlatformAdmin(ctx); err != nil {
+		return models.Tenant{}, err
+	}
+
+	tenant := models.Tenant{
+		Name:            strings.TrimSpace(req.Name),
+		DefaultCurrency: strings.ToUpper(strings.TrimSpace(req.DefaultCurrency)),
+	}
+
+	if tenant.Name == "" {
+		return models.Tenant{}, fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+
+	if tenant.DefaultCurrency == "" {
+		tenant.DefaultCurrency = defaultCurrency
+	}
+
+	if !currencyCode.MatchString(tenant.DefaultCurrency) {
+		return models.Tenant{}, fmt.Errorf("%w: default_currency must be an ISO 4217 code", ErrInvalidInput)
+	}
+
+	tenant, err := s.tenants.CreateTenant(ctx, tenant)
+	if errors.Is(err, storage.ErrTenantExists) {
+		return models.Tenant{}, ErrTenantExists
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.Tenant{}, errors.New("error create tenant")
+	}
+
+	return tenant, nil
+}
+
+// ListTenants — возвращает организации, доступные вызывающей стороне:
+// администратору платформы — все, остальным — только собственную.
+func (s *TenantsService) ListTenants(ctx context.Context) ([]models.Tenant, error) {
+	logger.FromContext(ctx).Info("start listing tenants")
+	tenants := make([]models.Tenant, 0)
+
+	id, err := caller(ctx)
+	if err != nil {
+		return tenants, err
+	}
+
+	if !id.IsPlatformAdmin() {
+		tenant, err := s.GetTenant(ctx, id.TenantID)
+		if err != nil {
+			return tenants, err
+		}
+		return append(tenants, tenant), nil
+	}
+
+	slice, err := s.tenants.ReadTenants(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return tenants, errors.New("error list tenants")
+	}
+
+	return append(tenants, slice...), nil
+}
+
+// GetTenant — возвращает организацию и её настройки.
+func (s *TenantsService) GetTenant(ctx context.Context, tenantID uuid.UUID) (models.Tenant, error) {
+	logger.FromContext(ctx).Info("start getting tenant")
+	id, err := caller(ctx)
+	if err != nil {
+		return models.Tenant{}, err
+	}
+
+	if !id.IsPlatformAdmin() && id.TenantID != tenantID {
+		return models.Tenant{}, ErrForbidden
+	}
+
+	tenant, err := s.tenants.ReadTenant(ctx, tenantID)
+	if errors.Is(err, storage.ErrTenantNotFound) {
+		return models.Tenant{}, ErrTenantNotFound
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.Tenant{}, errors.New("error get tenant")
+	}
+
+	return tenant, nil
+}
+
+// EditTenant — изменяет название и настройки организации.
+func (s *TenantsService) EditTenant(ctx context.Context, tenantID uuid.UUID, req models.EditTenantRequest) error {
+	logger.FromContext(ctx).Info("start editing tenant")
+	id, err := caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !id.IsPlatformAdmin() && (!id.IsAdmin() || id.TenantID != tenantID) {
+		return ErrForbidden
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return fmt.Errorf("%w: name must not be empty", ErrInvalidInput)
+		}
+		req.Name = &name
+	}
+
+	if req.DefaultCurrency != nil {
+		currency := strings.ToUpper(strings.TrimSpace(*req.DefaultCurrency))
+		if !currencyCode.MatchString(currency) {
+			return fmt.Errorf("%w: default_currency must be an ISO 4217 code", ErrInvalidInput)
+		}
+		req.DefaultCurrency = &currency
+	}
+
+	err = s.tenants.UpdateTenant(ctx, tenantID, req)
+	if errors.Is(err, storage.ErrTenantNotFound) {
+		return ErrTenantNotFound
+	}
+	if errors.Is(err, storage.ErrTenantExists) {
+		return ErrTenantExists
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return errors.New("error edit tenant")
+	}
+
+	return nil
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/storage"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// refreshTokenPrefix — префикс значения refresh-токена.
+	refreshTokenPrefix = "ossr_"
+	// minPasswordLength — минимальная длина пароля.
+	minPasswordLength = 8
+	// maxPasswordLength — максимальная длина пароля, которую учитывает bcrypt.
+	maxPasswordLength = 72
+)
+
+// usersStorage — отвечает за хранение учётных записей и refresh-токенов.
+type usersStorage interface {
+	CreateUser(ctx context.Context, user models.UserDTO) (models.UserDTO, error)
+	ReadUserByEmail(ctx context.Context, tenantID uuid.UUID, email string) (models.UserDTO, error)
+	ReadUser(ctx context.Context, id uuid.UUID) (models.UserDTO, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	CreateRefreshToken(ctx context.Context, token models.RefreshTokenDTO) (uuid.UUID, error)
+	ReadRefreshToken(ctx context.Context, hash string) (models.RefreshTokenDTO, error)
+	RevokeRefreshToken(ctx context.Context, id uuid.UUID, replacedBy *uuid.UUID) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+}
+
+// UsersService — сервисный слой локальных учётных записей.
+// Регистрирует пользователей, проверяет пароли и выдаёт пары токенов:
+// короткоживущий access-токен (JWT) и refresh-токен, который хранится на сервере
+// и заменяется новым при каждом обновлении.
+type UsersService struct {
+	users             usersStorage
+	issuer            *auth.Issuer
+	refreshTTL        time.Duration
+	allowRegistration bool
+	dummyHash         []byte
+}
+
+// NewUsersService — конструктор сервиса учётных записей.
+func NewUsersService(usersStorage *storage.UsersStorage, issuer *auth.Issuer, cfg config.AuthConfig) *UsersService {
+	// Хэш для сравнения при входе несуществующего пользователя,
+	// чтобы время ответа не выдавало наличие учётной записи.
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+	return &UsersService{
+		users:             usersStorage,
+		issuer:            issuer,
+		refreshTTL:        cfg.RefreshTokenTTL,
+		allowRegistration: cfg.AllowRegistration,
+		dummyHash:         dummyHash,
+	}
+}
+
+// Register — регистрирует нового пользователя в организации по умолчанию.
+// Зарегистрированный пользователь не получает ролей: первого администратора создаёт
+// оператор командой create-admin (CreateAdmin), администраторов других организаций —
+// администратор платформы (CreateTenantAdmin).
+func (s *UsersService) Register(ctx context.Context, req models.RegisterRequest) (models.User, error) {
+	logger.FromContext(ctx).Info("start registering user")
+	if !s.allowRegistration {
+		return models.User{}, ErrRegistrationDisabled
+	}
+
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.users.CreateUser(ctx, models.UserDTO{TenantID: models.DefaultTenantID, Email: email, PasswordHash: hash})
+	if errors.Is(err, storage.ErrUserExists) {
+		return models.User{}, ErrUserExists
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.User{}, errors.New("error register user")
+	}
+
+	return user.ToUser(), nil
+}
+
+// CreateTenantAdmin — создаёт учётную запись администратора организации.
+// Доступно только администратору платформы; работает и при выключенной самостоятельной регистрации.
+func (s *UsersService) CreateTenantAdmin(ctx context.Context, tenantID uuid.UUID, req models.CreateTenantAdminRequest) (models.User, error) {
+	logger.FromContext(ctx).Info("start creating tenant admin")
+	if _, err := requirePlatformAdmin(ctx); err != nil {
+		return models.User{}, err
+	}
+
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return s.createAdmin(ctx, tenantID, email, hash, []string{auth.RoleAdmin})
+}
+
+// CreateAdmin — создаёт учётную запись администратора организации без проверки
+// вызывающей стороны. Используется командой create-admin для создания первого
+// администратора, пока в системе нет ни одной учётной записи с правами.
+// Если platform установлен, учётная запись получает ещё и роль администратора платформы.
+func (s *UsersService) CreateAdmin(ctx context.Context, tenantID uuid.UUID, req models.CreateTenantAdminRequest, platform bool) (models.User, error) {
+	logger.FromContext(ctx).Info("start creating admin")
+
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	roles := []string{auth.RoleAdmin}
+	if platform {
+		roles = append(roles, auth.RolePlatformAdmin)
+	}
+
+	return s.createAdmin(ctx, tenantID, email, hash, roles)
+}
+
+// createAdmin — сохраняет учётную запись администратора с указанными ролями.
+func (s *UsersService) createAdmin(ctx context.Context, tenantID uuid.UUID, email, hash string, roles []string) (models.User, error) {
+	user, err := s.users.CreateUser(ctx, models.UserDTO{TenantID: tenantID, Email: email, PasswordHash: hash, Roles: roles})
+	if errors.Is(err, storage.ErrUserExists) {
+		return models.User{}, ErrUserExists
+	}
+	if errors.Is(err, storage.ErrTenantNotFound) {
+		return models.User{}, ErrTenantNotFound
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.User{}, errors.New("error create admin")
+	}
+
+	return user.ToUser(), nil
+}
+
+// Login — проверяет email и пароль и выдаёт пару токенов.
+// Email уникален только в пределах организации, поэтому учётная запись ищется
+// в организации из запроса, а если она не указана — в организации по умолчанию.
+func (s *UsersService) Login(ctx context.Context, req models.LoginRequest) (models.TokenPair, error) {
+	logger.FromContext(ctx).Info("start login")
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	tenantID := models.DefaultTenantID
+	if req.TenantID != nil {
+		tenantID = *req.TenantID
+	}
+
+	user, err := s.users.ReadUserByEmail(ctx, tenantID, email)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		_ = bcrypt.CompareHashAndPassword(s.dummyHash, []byte(req.Password))
+		return models.TokenPair{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.TokenPair{}, errors.New("error login")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return models.TokenPair{}, ErrInvalidCredentials
+	}
+
+	return s.issueTokens(ctx, user, nil)
+}
+
+// Refresh — обменивает refresh-токен на новую пару токенов.
+// Старый токен отзывается. Повторное использование уже отозванного токена
+// считается признаком утечки, и все токены пользователя отзываются.
+func (s *UsersService) Refresh(ctx context.Context, req models.RefreshRequest) (models.TokenPair, error) {
+	logger.FromContext(ctx).Info("start refreshing tokens")
+	token, err := s.users.ReadRefreshToken(ctx, hashToken(req.RefreshToken))
+	if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+		return models.TokenPair{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.TokenPair{}, errors.New("error refresh tokens")
+	}
+
+	if token.RevokedAt != nil {
+		logger.FromContext(ctx).Warn("refresh token reuse detected", slog.String("user_id", token.UserID.String()))
+		if err := s.users.RevokeUserRefreshTokens(ctx, token.UserID); err != nil {
+			logger.FromContext(ctx).Error(err.Error())
+		}
+		return models.TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	if !token.ExpiresAt.After(time.Now()) {
+		return models.TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	user, err := s.users.ReadUser(ctx, token.UserID)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return models.TokenPair{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.TokenPair{}, errors.New("error refresh tokens")
+	}
+
+	return s.issueTokens(ctx, user, &token.ID)
+}
+
+// Logout — отзывает refresh-токен. Отзыв неизвестного или уже отозванного токена не является ошибкой.
+func (s *UsersService) Logout(ctx context.Context, req models.RefreshRequest) error {
+	logger.FromContext(ctx).Info("start logout")
+	token, err := s.users.ReadRefreshToken(ctx, hashToken(req.RefreshToken))
+	if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+		return nil
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return errors.New("error logout")
+	}
+
+	if err := s.users.RevokeRefreshToken(ctx, token.ID, nil); err != nil && !errors.Is(err, storage.ErrRefreshTokenNotFound) {
+		logger.FromContext(ctx).Error(err.Error())
+		return errors.New("error logout")
+	}
+
+	return nil
+}
+
+// ChangePassword — меняет пароль вызывающего пользователя.
+// После смены пароля все refresh-токены пользователя отзываются.
+func (s *UsersService) ChangePassword(ctx context.Context, req models.ChangePasswordRequest) error {
+	logger.FromContext(ctx).Info("start changing password")
+	id, err := caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.users.ReadUser(ctx, id.UserID)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return ErrForbidden
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return errors.New("error change password")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.OldPassword)); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	hash, err := hashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := s.users.UpdatePassword(ctx, user.ID, hash); err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return errors.New("error change password")
+	}
+
+	if err := s.users.RevokeUserRefreshTokens(ctx, user.ID); err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return errors.New("error change password")
+	}
+
+	return nil
+}
+
+// issueTokens — выпускает access-токен и новый refresh-токен.
+// Если передан previous, он отзывается с указанием токена-замены; если предыдущий
+// токен уже был отозван параллельным запросом, новая пара не выдаётся.
+func (s *UsersService) issueTokens(ctx context.Context, user models.UserDTO, previous *uuid.UUID) (models.TokenPair, error) {
+	access, err := s.issuer.Issue(auth.Identity{UserID: user.ID, TenantID: user.TenantID, Roles: user.Roles})
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.TokenPair{}, errors.New("error issue tokens")
+	}
+
+	refresh, err := generateToken(refreshTokenPrefix)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.TokenPair{}, errors.New("error issue tokens")
+	}
+
+	refreshID, err := s.users.CreateRefreshToken(ctx, models.RefreshTokenDTO{
+		UserID:    user.ID,
+		TokenHash: hashToken(refresh),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.TokenPair{}, errors.New("error issue tokens")
+	}
+
+	if previous != nil {
+		err := s.users.RevokeRefreshToken(ctx, *previous, &refreshID)
+		if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+			_ = s.users.RevokeRefreshToken(ctx, refreshID, nil)
+			return models.TokenPair{}, ErrInvalidRefreshToken
+		}
+		if err != nil {
+			logger.FromContext(ctx).Error(err.Error())
+			return models.TokenPair{}, errors.New("error issue tokens")
+		}
+	}
+
+	return models.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.issuer.TTL().Seconds()),
+	}, nil
+}
+
+// normalizeEmail — проверяет адрес и приводит его к нижнему регистру.
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", fmt.Errorf("%w: invalid email", ErrInvalidInput)
+	}
+
+	return email, nil
+}
+
+// hashPassword — проверяет длину пароля и вычисляет его bcrypt-хэш.
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return "", fmt.Errorf("%w: password must be %d to %d bytes long", ErrInvalidInput, minPasswordLength, maxPasswordLength)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return string(hash), nil
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/storage"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeUsersStorage — хранилище учётных записей в памяти с ограничениями таблицы users:
+// уникальностью email в пределах организации и внешним ключом на организацию.
+type fakeUsersStorage struct {
+	usersStorage
+	users   []models.UserDTO
+	tenants []uuid.UUID
+	tokens  []models.RefreshTokenDTO
+}
+
+func (f *fakeUsersStorage) CreateUser(_ context.Context, user models.UserDTO) (models.UserDTO, error) {
+	if !slices.Contains(f.tenants, user.TenantID) {
+		return user, storage.ErrTenantNotFound
+	}
+	for _, u := range f.users {
+		if u.TenantID == user.TenantID && u.Email == user.Email {
+			return user, storage.ErrUserExists
+		}
+	}
+	if user.Roles == nil {
+		user.Roles = []string{}
+	}
+	user.ID = uuid.New()
+	f.users = append(f.users, user)
+	return user, nil
+}
+
+func (f *fakeUsersStorage) ReadUserByEmail(_ context.Context, tenantID uuid.UUID, email string) (models.UserDTO, error) {
+	for _, u := range f.users {
+		if u.TenantID == tenantID && u.Email == email {
+			return u, nil
+		}
+	}
+	return models.UserDTO{}, storage.ErrUserNotFound
+}
+
+func (f *fakeUsersStorage) CreateRefreshToken(_ context.Context, token models.RefreshTokenDTO) (uuid.UUID, error) {
+	token.ID = uuid.New()
+	f.tokens = append(f.tokens, token)
+	return token.ID, nil
+}
+
+func TestUsersServiceRegister(t *testing.T) {
+	store := &fakeUsersStorage{tenants: []uuid.UUID{models.DefaultTenantID}}
+	s := &UsersService{users: store, allowRegistration: true}
+	ctx := context.Background()
+
+	// Первый зарегистрированный пользователь не получает роль администратора.
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		user, err := s.Register(ctx, models.RegisterRequest{Email: email, Password: "password1"})
+		if err != nil {
+			t.Fatalf("Register(%s) error = %v", email, err)
+		}
+		if user.TenantID != models.DefaultTenantID || len(user.Roles) != 0 {
+			t.Errorf("Register(%s) = %+v, want plain user of the default tenant", email, user)
+		}
+	}
+
+	if _, err := s.Register(ctx, models.RegisterRequest{Email: "First@Example.com", Password: "password1"}); !errors.Is(err, ErrUserExists) {
+		t.Errorf("Register() with taken email error = %v, want %v", err, ErrUserExists)
+	}
+
+	s.allowRegistration = false
+	if _, err := s.Register(ctx, models.RegisterRequest{Email: "third@example.com", Password: "password3"}); !errors.Is(err, ErrRegistrationDisabled) {
+		t.Errorf("Register() with registration disabled error = %v, want %v", err, ErrRegistrationDisabled)
+	}
+}
+
+func TestUsersServiceCreateTenantAdmin(t *testing.T) {
+	tenant := uuid.New()
+	req := models.CreateTenantAdminRequest{Email: "admin@example.com", Password: "password1"}
+
+	tests := []struct {
+		name    string
+		caller  auth.Identity
+		tenant  uuid.UUID
+		wantErr error
+	}{
+		{name: "platform admin", caller: auth.Identity{TenantID: models.DefaultTenantID, Roles: []string{auth.RolePlatformAdmin}}, tenant: tenant},
+		{name: "tenant admin", caller: auth.Identity{TenantID: tenant, Roles: []string{auth.RoleAdmin}}, tenant: tenant, wantErr: ErrForbidden},
+		{name: "api key", caller: auth.Identity{TenantID: tenant, KeyID: uuid.New()}, tenant: tenant, wantErr: ErrForbidden},
+		{name: "unknown tenant", caller: auth.Identity{TenantID: models.DefaultTenantID, Roles: []string{auth.RolePlatformAdmin}}, tenant: uuid.New(), wantErr: ErrTenantNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeUsersStorage{tenants: []uuid.UUID{models.DefaultTenantID, tenant}}
+			store.users = []models.UserDTO{{TenantID: tenant, Email: "existing@example.com"}}
+			s := &UsersService{users: store}
+
+			user, err := s.CreateTenantAdmin(auth.WithIdentity(context.Background(), tt.caller), tt.tenant, req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateTenantAdmin() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (user.TenantID != tt.tenant || !slices.Equal(user.Roles, []string{auth.RoleAdmin})) {
+				t.Errorf("CreateTenantAdmin() = %+v, want admin of %s", user, tt.tenant)
+			}
+		})
+	}
+}
+
+func TestUsersServiceCreateAdmin(t *testing.T) {
+	tenant := uuid.New()
+
+	tests := []struct {
+		name      string
+		tenant    uuid.UUID
+		req       models.CreateTenantAdminRequest
+		platform  bool
+		wantRoles []string
+		wantErr   error
+	}{
+		{name: "default tenant", tenant: models.DefaultTenantID, req: models.CreateTenantAdminRequest{Email: "Admin@Example.com", Password: "password1"}, wantRoles: []string{auth.RoleAdmin}},
+		{name: "other tenant", tenant: tenant, req: models.CreateTenantAdminRequest{Email: "admin@example.com", Password: "password1"}, wantRoles: []string{auth.RoleAdmin}},
+		{
+			name: "platform admin", tenant: models.DefaultTenantID, req: models.CreateTenantAdminRequest{Email: "admin@example.com", Password: "password1"},
+			platform: true, wantRoles: []string{auth.RoleAdmin, auth.RolePlatformAdmin},
+		},
+		{name: "email taken in other tenant", tenant: models.DefaultTenantID, req: models.CreateTenantAdminRequest{Email: "existing@example.com", Password: "password1"}, wantRoles: []string{auth.RoleAdmin}},
+		{name: "taken email", tenant: tenant, req: models.CreateTenantAdminRequest{Email: "existing@example.com", Password: "password1"}, wantErr: ErrUserExists},
+		{name: "unknown tenant", tenant: uuid.New(), req: models.CreateTenantAdminRequest{Email: "admin@example.com", Password: "password1"}, wantErr: ErrTenantNotFound},
+		{name: "invalid email", tenant: tenant, req: models.CreateTenantAdminRequest{Email: "admin", Password: "password1"}, wantErr: ErrInvalidInput},
+		{name: "short password", tenant: tenant, req: models.CreateTenantAdminRequest{Email: "admin@example.com", Password: "short"}, wantErr: ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeUsersStorage{tenants: []uuid.UUID{models.DefaultTenantID, tenant}}
+			store.users = []models.UserDTO{{TenantID: tenant, Email: "existing@example.com"}}
+			s := &UsersService{users: store}
+
+			// Команда create-admin выполняется без вызывающей стороны в контексте.
+			user, err := s.CreateAdmin(context.Background(), tt.tenant, tt.req, tt.platform)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateAdmin() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if user.TenantID != tt.tenant || user.Email != strings.ToLower(tt.req.Email) || !slices.Equal(user.Roles, tt.wantRoles) {
+				t.Errorf("CreateAdmin() = %+v, want %s with roles %v in %s", user, strings.ToLower(tt.req.Email), tt.wantRoles, tt.tenant)
+			}
+		})
+	}
+}
+
+func TestUsersServiceLogin(t *testing.T) {
+	tenant := uuid.New()
+	defaultUser := models.UserDTO{ID: uuid.New(), TenantID: models.DefaultTenantID, Email: "user@example.com", PasswordHash: testPasswordHash(t, "password1")}
+	tenantUser := models.UserDTO{ID: uuid.New(), TenantID: tenant, Email: "user@example.com", PasswordHash: testPasswordHash(t, "password2")}
+
+	tests := []struct {
+		name     string
+		req      models.LoginRequest
+		wantUser uuid.UUID
+		wantErr  error
+	}{
+		{name: "default tenant", req: models.LoginRequest{Email: " User@Example.com ", Password: "password1"}, wantUser: defaultUser.ID},
+		{name: "explicit tenant", req: models.LoginRequest{TenantID: &tenant, Email: "user@example.com", Password: "password2"}, wantUser: tenantUser.ID},
+		{name: "password of the same email in other tenant", req: models.LoginRequest{Email: "user@example.com", Password: "password2"}, wantErr: ErrInvalidCredentials},
+		{name: "unknown email", req: models.LoginRequest{Email: "other@example.com", Password: "password1"}, wantErr: ErrInvalidCredentials},
+		{name: "unknown tenant", req: models.LoginRequest{TenantID: new(uuid.UUID), Email: "user@example.com", Password: "password1"}, wantErr: ErrInvalidCredentials},
+	}
+
+	issuer, err := auth.NewIssuer(config.AuthConfig{HMACSecret: "0123456789abcdef0123456789abcdef", AccessTokenTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("NewIssuer() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeUsersStorage{users: []models.UserDTO{defaultUser, tenantUser}}
+			s := &UsersService{users: store, issuer: issuer, refreshTTL: time.Hour, dummyHash: []byte(testPasswordHash(t, "dummy-password"))}
+
+			tokens, err := s.Login(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Login() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if tokens.AccessToken == "" || len(store.tokens) != 1 || store.tokens[0].UserID != tt.wantUser {
+				t.Errorf("Login() = %+v, refresh tokens %+v, want tokens of %s", tokens, store.tokens, tt.wantUser)
+			}
+		})
+	}
+}
+
+// testPasswordHash — bcrypt-хэш пароля с минимальной стоимостью.
+func testPasswordHash(t *testing.T, password string) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	return string(hash)
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/health"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/lib/webhook"
+	"online_subscription_service/internal/metrics"
+	"online_subscription_service/internal/storage"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// webhookLeaseMargin — запас сверх webhooks.timeout, на который откладывается следующая
+	// попытка выбранной доставки, чтобы её не выбрал другой экземпляр сервиса.
+	webhookLeaseMargin = 30 * time.Second
+	// webhookCleanupInterval — как часто удаляются доставки, завершённые раньше webhooks.retention.
+	webhookCleanupInterval = time.Hour
+	// webhookErrorBodyLimit — сколько байт ответа получателя сохраняется в журнале при неудаче.
+	webhookErrorBodyLimit = 512
+	// webhookUserAgent — User-Agent запросов доставки.
+	webhookUserAgent = "online_subscription_service-webhooks"
+	// webhookStaleAfter — минимальный срок без успешного прохода, после которого доставка
+	// считается неготовой; к нему добавляется наибольшая длительность прохода.
+	webhookStaleAfter = time.Minute
+)
+
+// deliveryStore — отвечает за очередь доставок событий на вебхуки.
+type deliveryStore interface {
+	EnqueueDeliveries(ctx context.Context, event models.Event) (int64, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.PendingDelivery, error)
+	MarkDeliverySucceeded(ctx context.Context, id uuid.UUID, attempts, responseStatus int) error
+	MarkDeliveryFailed(ctx context.Context, id uuid.UUID, attempts int, responseStatus *int, reason string, retryIn time.Duration, dead bool) error
+	DeleteFinishedDeliveries(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+// WebhookDispatcher — фоновый обработчик, доставляющий события на вебхуки организаций.
+//
+// Получает события от OutboxRelay как публикатор (events.Publisher) и ставит каждое
+// в очередь доставки на подходящие вебхуки. Доставки отправляются POST-запросами
+// с подписью HMAC-SHA256 одновременно не более чем webhooks.workers. Ответ 2xx завершает
+// доставку; иначе попытка повторяется с экспоненциально растущей паузой, а после
+// webhooks.max_attempts попыток доставка переходит в состояние dead и повторяется
+// только вручную. Доставка — «как минимум один раз», порядок доставок не гарантируется.
+type WebhookDispatcher struct {
+	deliveries deliveryStore
+	client     *http.Client
+	cfg        config.WebhooksConfig
+	heartbeat  *health.Heartbeat
+
+	stop   chan struct{}
+	done   chan struct{}      // закрывается, когда фоновый цикл завершился
+	cancel context.CancelFunc // прерывает текущие запросы, если срок остановки истёк
+}
+
+// NewWebhookDispatcher — конструктор обработчика доставки событий на вебхуки.
+func NewWebhookDispatcher(webhooksStorage *storage.WebhooksStorage, cfg config.WebhooksConfig) *WebhookDispatcher {
+	cfg.Workers = max(cfg.Workers, 1)
+	cfg.BatchSize = max(cfg.BatchSize, 1)
+	cfg.MaxAttempts = max(cfg.MaxAttempts, 1)
+	// Проход ждёт все выбранные доставки: до batch_size/workers запросов подряд на обработчик.
+	longestPass := time.Duration((cfg.BatchSize+cfg.Workers-1)/cfg.Workers) * cfg.Timeout
+	return &WebhookDispatcher{
+		deliveries: webhooksStorage,
+		client:     webhook.NewClient(cfg.Timeout, cfg.AllowPrivateNetworks),
+		cfg:        cfg,
+		heartbeat:  health.NewHeartbeat(max(webhookStaleAfter, 10*cfg.PollInterval) + longestPass),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Check — проверка готовности: доставка запущена и успешно завершала проход недавно.
+func (d *WebhookDispatcher) Check(ctx context.Context) error {
+	return d.heartbeat.Check(ctx)
+}
+
+// Publish — ставит событие в очередь доставки на включённые вебхуки его организации,
+// подписанные на тип события. Повторно полученное событие в очередь не добавляется.
+func (d *WebhookDispatcher) Publish(ctx context.Context, event models.Event) error {
+	if _, err := d.deliveries.EnqueueDeliveries(ctx, event); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Close — ничего не делает: доставка останавливается методом Stop.
+func (d *WebhookDispatcher) Close(context.Context) error {
+	return nil
+}
+
+// Start — запускает доставку событий в фоне.
+func (d *WebhookDispatcher) Start(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	d.heartbeat.Start()
+
+	go d.loop(ctx)
+
+	return nil
+}
+
+// loop — проверяет доставки, срок попытки которых наступил, каждые webhooks.poll_interval,
+// а если за проход выбрана полная пачка, сразу выполняет следующий.
+func (d *WebhookDispatcher) loop(ctx context.Context) {
+	defer close(d.done)
+
+	var lastCleanup time.Time
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-timer.C:
+		}
+
+		claimed, err := d.dispatch(ctx)
+		d.heartbeat.Beat(err)
+		if err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Error("failed to dispatch webhook deliveries", slog.String("error", err.Error()))
+		}
+
+		if d.cfg.Retention > 0 && time.Since(lastCleanup) >= webhookCleanupInterval {
+			d.cleanup(ctx)
+			lastCleanup = time.Now()
+		}
+
+		next := d.cfg.PollInterval
+		if err == nil && claimed >= d.cfg.BatchSize {
+			next = 0
+		}
+		timer.Reset(next)
+	}
+}
+
+// dispatch — выбирает до webhooks.batch_size доставок и выполняет их попытки,
+// дожидаясь завершения всех. Возвращает количество выбранных доставок.
+func (d *WebhookDispatcher) dispatch(ctx context.Context) (int, error) {
+	pending, err := d.deliveries.ClaimDeliveries(ctx, d.cfg.BatchSize, d.cfg.Timeout+webhookLeaseMargin)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, d.cfg.Workers)
+	for _, p := range pending {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			d.safeDeliver(ctx, p)
+		}()
+	}
+	wg.Wait()
+
+	return len(pending), nil
+}
+
+// safeDeliver — выполняет попытку доставки; паника логируется, а доставка
+// будет повторена по истечении срока, на который она была выбрана.
+func (d *WebhookDispatcher) safeDeliver(ctx context.Context, p models.PendingDelivery) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.FromContext(ctx).Error("panic in webhook delivery",
+				slog.String("delivery_id", p.ID.String()),
+				slog.Any("panic", r),
+				slog.String("stack", string(debug.Stack())),
+			)
+		}
+	}()
+
+	d.deliver(ctx, p)
+}
+
+// deliver — отправляет событие на вебхук и сохраняет результат попытки.
+func (d *WebhookDispatcher) deliver(ctx context.Context, p models.PendingDelivery) {
+	status, err := d.send(ctx, p)
+	if err == nil {
+		metrics.WebhookDeliveries.WithLabelValues("succeeded").Inc()
+		if err := d.deliveries.MarkDeliverySucceeded(ctx, p.ID, p.Attempts, status); err != nil {
+			logger.FromContext(ctx).Error("failed to save webhook delivery", slog.String("error", err.Error()))
+		}
+		return
+	}
+	if ctx.Err() != nil {
+		// Остановка прервала запрос: попытка повторится по истечении срока выбора доставки.
+		return
+	}
+
+	dead := p.Attempts+1 >= d.cfg.MaxAttempts
+	result := "failed"
+	if dead {
+		result = "dead"
+	}
+	metrics.WebhookDeliveries.WithLabelValues(result).Inc()
+	logger.FromContext(ctx).Warn("failed to deliver webhook",
+		slog.String("delivery_id", p.ID.String()),
+		slog.String("webhook_id", p.WebhookID.String()),
+		slog.String("event_id", p.EventID.String()),
+		slog.Int("attempts", p.Attempts+1),
+		slog.Bool("dead", dead),
+		slog.String("error", err.Error()),
+	)
+
+	var responseStatus *int
+	if status != 0 {
+		responseStatus = &status
+	}
+	delay := retryDelay(d.cfg.RetryInitialDelay, d.cfg.RetryMaxDelay, p.Attempts)
+	if err := d.deliveries.MarkDeliveryFailed(ctx, p.ID, p.Attempts, responseStatus, err.Error(), delay, dead); err != nil {
+		logger.FromContext(ctx).Error("failed to save webhook delivery", slog.String("error", err.Error()))
+	}
+}
+
+// send — отправляет подписанный POST-запрос с событием. Возвращает HTTP-статус ответа
+// (0, если ответ не получен) и ошибку, если ответ не получен или его код не 2xx.
+func (d *WebhookDispatcher) send(ctx context.Context, p models.PendingDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(p.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(webhook.HeaderEventID, p.EventID.String())
+	req.Header.Set(webhook.HeaderEventType, string(p.EventType))
+	req.Header.Set(webhook.HeaderDeliveryID, p.ID.String())
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(p.Secret, now, p.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorBodyLimit))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return resp.StatusCode, nil
+}
+
+// cleanup — удаляет доставки, завершённые раньше webhooks.retention.
+func (d *WebhookDispatcher) cleanup(ctx context.Context) {
+	deleted, err := d.deliveries.DeleteFinishedDeliveries(ctx, d.cfg.Retention)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.FromContext(ctx).Error("failed to delete finished webhook deliveries", slog.String("error", err.Error()))
+		}
+		return
+	}
+	if deleted > 0 {
+		logger.FromContext(ctx).Info("finished webhook deliveries deleted", slog.Int64("count", deleted))
+	}
+}
+
+// Stop — останавливает фоновую доставку, дождавшись текущих запросов, пока не истечёт
+// срок контекста. Прерванные и невыполненные доставки остаются в очереди
+// и будут выполнены после запуска.
+func (d *WebhookDispatcher) Stop(ctx context.Context) error {
+	if d.done == nil {
+		return nil
+	}
+
+	d.heartbeat.Stop()
+	close(d.stop)
+	select {
+	case <-d.done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-d.done
+		return fmt.Errorf("webhook delivery interrupted: %w", ctx.Err())
+	}
+}
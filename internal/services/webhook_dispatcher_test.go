@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"online_subscription_service/internal/auth"
+	"online_subscription_service/internal/config"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/webhook"
+	"online_subscription_service/internal/storage"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// failedDelivery — сохранённая неудачная попытка доставки.
+type failedDelivery struct {
+	attempts       int
+	responseStatus *int
+	retryIn        time.Duration
+	dead           bool
+}
+
+// fakeDeliveryStore — очередь доставок в памяти, запоминающая выборки и результаты попыток.
+type fakeDeliveryStore struct {
+	deliveryStore
+	pending []models.PendingDelivery
+
+	mu        sync.Mutex
+	claims    []time.Duration // срок выбора каждого вызова ClaimDeliveries
+	limits    []int
+	succeeded map[uuid.UUID]int // доставка → HTTP-статус
+	failed    map[uuid.UUID]failedDelivery
+}
+
+func (f *fakeDeliveryStore) ClaimDeliveries(_ context.Context, limit int, lease time.Duration) ([]models.PendingDelivery, error) {
+	f.claims = append(f.claims, lease)
+	f.limits = append(f.limits, limit)
+	claimed := f.pending[:min(limit, len(f.pending))]
+	f.pending = f.pending[len(claimed):]
+	return claimed, nil
+}
+
+func (f *fakeDeliveryStore) MarkDeliverySucceeded(_ context.Context, id uuid.UUID, _, responseStatus int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.succeeded == nil {
+		f.succeeded = make(map[uuid.UUID]int)
+	}
+	f.succeeded[id] = responseStatus
+	return nil
+}
+
+func (f *fakeDeliveryStore) MarkDeliveryFailed(_ context.Context, id uuid.UUID, attempts int, responseStatus *int, _ string, retryIn time.Duration, dead bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failed == nil {
+		f.failed = make(map[uuid.UUID]failedDelivery)
+	}
+	f.failed[id] = failedDelivery{attempts: attempts, responseStatus: responseStatus, retryIn: retryIn, dead: dead}
+	return nil
+}
+
+// testWebhookConfig — настройки доставки для тестов: 3 попытки с паузой от 1 до 3 секунд.
+var testWebhookConfig = config.WebhooksConfig{
+	Workers:           2,
+	BatchSize:         2,
+	Timeout:           time.Second,
+	MaxAttempts:       3,
+	RetryInitialDelay: time.Second,
+	RetryMaxDelay:     3 * time.Second,
+}
+
+func newTestDispatcher(store deliveryStore) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		deliveries: store,
+		client:     webhook.NewClient(testWebhookConfig.Timeout, true),
+		cfg:        testWebhookConfig,
+	}
+}
+
+func pendingDelivery(url string, attempts int) models.PendingDelivery {
+	return models.PendingDelivery{
+		WebhookDelivery: models.WebhookDelivery{
+			ID:        uuid.New(),
+			WebhookID: uuid.New(),
+			EventID:   uuid.New(),
+			EventType: models.EventSubsUpdated,
+			Status:    models.DeliveryPending,
+			Attempts:  attempts,
+			Payload:   []byte(`{"type":"subscription.updated"}`),
+		},
+		URL:    url,
+		Secret: "whsec_test",
+	}
+}
+
+func TestWebhookDispatcherDispatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	store := &fakeDeliveryStore{}
+	for range 3 {
+		store.pending = append(store.pending, pendingDelivery(srv.URL, 0))
+	}
+	d := newTestDispatcher(store)
+
+	for _, want := range []int{2, 1, 0} {
+		claimed, err := d.dispatch(context.Background())
+		if err != nil {
+			t.Fatalf("dispatch() error = %v", err)
+		}
+		if claimed != want {
+			t.Errorf("dispatch() claimed %d, want %d", claimed, want)
+		}
+	}
+
+	// Доставка выбирается на время запроса с запасом, чтобы её не выбрал другой экземпляр.
+	for i, lease := range store.claims {
+		if lease != testWebhookConfig.Timeout+webhookLeaseMargin || store.limits[i] != testWebhookConfig.BatchSize {
+			t.Errorf("claim %d: limit %d lease %v, want %d and %v", i, store.limits[i], lease,
+				testWebhookConfig.BatchSize, testWebhookConfig.Timeout+webhookLeaseMargin)
+		}
+	}
+	if len(store.succeeded) != 3 || len(store.failed) != 0 {
+		t.Errorf("succeeded %d, failed %d, want 3 and 0", len(store.succeeded), len(store.failed))
+	}
+}
+
+func TestWebhookDispatcherDeliver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+		if status == http.StatusFound {
+			http.Redirect(w, r, "/?status=200", status)
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	statusPtr := func(status int) *int { return &status }
+
+	tests := []struct {
+		name          string
+		url           string
+		attempts      int
+		cancel        bool
+		wantSucceeded int
+		wantFailed    *failedDelivery
+	}{
+		{name: "2xx succeeds", url: srv.URL + "/?status=204", wantSucceeded: http.StatusNoContent},
+		{
+			name:       "first failure waits initial delay",
+			url:        srv.URL + "/?status=500",
+			wantFailed: &failedDelivery{responseStatus: statusPtr(500), retryIn: time.Second},
+		},
+		{
+			name:       "delay doubles",
+			url:        srv.URL + "/?status=503",
+			attempts:   1,
+			wantFailed: &failedDelivery{attempts: 1, responseStatus: statusPtr(503), retryIn: 2 * time.Second},
+		},
+		{
+			name:       "last attempt is dead-lettered",
+			url:        srv.URL + "/?status=500",
+			attempts:   2,
+			wantFailed: &failedDelivery{attempts: 2, responseStatus: statusPtr(500), retryIn: 3 * time.Second, dead: true},
+		},
+		{
+			name:       "redirect is a failure",
+			url:        srv.URL + "/?status=302",
+			wantFailed: &failedDelivery{responseStatus: statusPtr(302), retryIn: time.Second},
+		},
+		{
+			name:       "no response",
+			url:        closed.URL,
+			wantFailed: &failedDelivery{retryIn: time.Second},
+		},
+		{name: "stopped dispatcher leaves delivery to the lease", url: srv.URL + "/?status=500", cancel: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeDeliveryStore{}
+			p := pendingDelivery(tt.url, tt.attempts)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.cancel {
+				cancel()
+			}
+			defer cancel()
+
+			newTestDispatcher(store).deliver(ctx, p)
+
+			if got := store.succeeded[p.ID]; got != tt.wantSucceeded {
+				t.Errorf("succeeded status = %d, want %d", got, tt.wantSucceeded)
+			}
+			got, failed := store.failed[p.ID]
+			if failed != (tt.wantFailed != nil) {
+				t.Fatalf("failed = %+v, want %+v", got, tt.wantFailed)
+			}
+			if !failed {
+				return
+			}
+			want := *tt.wantFailed
+			if got.attempts != want.attempts || got.retryIn != want.retryIn || got.dead != want.dead ||
+				(got.responseStatus == nil) != (want.responseStatus == nil) ||
+				(got.responseStatus != nil && *got.responseStatus != *want.responseStatus) {
+				t.Errorf("failed = %+v (status %v), want %+v (status %v)", got, got.responseStatus, want, want.responseStatus)
+			}
+		})
+	}
+}
+
+func TestWebhookDispatcherSendHeaders(t *testing.T) {
+	var (
+		header http.Header
+		body   []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	p := pendingDelivery(srv.URL, 0)
+	status, err := newTestDispatcher(&fakeDeliveryStore{}).send(context.Background(), p)
+	if err != nil || status != http.StatusAccepted {
+		t.Fatalf("send() = %d, %v, want %d", status, err, http.StatusAccepted)
+	}
+
+	if string(body) != string(p.Payload) {
+		t.Errorf("body = %s, want %s", body, p.Payload)
+	}
+
+	want := map[string]string{
+		"Content-Type":           "application/json",
+		"User-Agent":             webhookUserAgent,
+		webhook.HeaderEventID:    p.EventID.String(),
+		webhook.HeaderEventType:  string(p.EventType),
+		webhook.HeaderDeliveryID: p.ID.String(),
+	}
+	for name, value := range want {
+		if got := header.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+
+	// Получатель проверяет подпись по метке времени из заголовка и телу как есть.
+	unix, err := strconv.ParseInt(header.Get(webhook.HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("%s = %q: %v", webhook.HeaderTimestamp, header.Get(webhook.HeaderTimestamp), err)
+	}
+	if age := time.Since(time.Unix(unix, 0)); age < -time.Second || age > time.Minute {
+		t.Errorf("timestamp is %v old", age)
+	}
+	if got, want := header.Get(webhook.HeaderSignature), webhook.Sign(p.Secret, time.Unix(unix, 0), body); got != want {
+		t.Errorf("%s = %q, want %q", webhook.HeaderSignature, got, want)
+	}
+}
+
+// fakeWebhooksStorage — хранилище вебхуков, запоминающее запросы повторной доставки.
+type fakeWebhooksStorage struct {
+	webhooksStorage
+	err       error
+	redeliver []uuid.UUID // организация, вебхук и доставка последнего вызова
+}
+
+func (f *fakeWebhooksStorage) RedeliverDelivery(_ context.Context, tenantID, webhookID, id uuid.UUID) (models.WebhookDelivery, error) {
+	f.redeliver = []uuid.UUID{tenantID, webhookID, id}
+	if f.err != nil {
+		return models.WebhookDelivery{}, f.err
+	}
+	return models.WebhookDelivery{ID: id, WebhookID: webhookID, Status: models.DeliveryPending}, nil
+}
+
+func TestWebhooksServiceRedeliver(t *testing.T) {
+	tenantID := uuid.New()
+	admin := auth.Identity{Subject: "admin", UserID: uuid.New(), TenantID: tenantID, Roles: []string{auth.RoleAdmin}}
+	service := auth.Identity{Subject: "key", KeyID: uuid.New(), TenantID: tenantID, Scopes: []string{auth.ScopeWebhooksManage}}
+	user := auth.Identity{Subject: "user", UserID: uuid.New(), TenantID: tenantID}
+
+	tests := []struct {
+		name       string
+		identity   auth.Identity
+		storageErr error
+		wantErr    error
+		wantCalled bool
+	}{
+		{name: "admin", identity: admin, wantCalled: true},
+		{name: "service", identity: service, wantCalled: true},
+		{name: "user", identity: user, wantErr: ErrForbidden},
+		{name: "not found in tenant", identity: admin, storageErr: storage.ErrDeliveryNotFound, wantErr: ErrDeliveryNotFound, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeWebhooksStorage{err: tt.storageErr}
+			s := &WebhooksService{webhooks: store}
+			webhookID, deliveryID := uuid.New(), uuid.New()
+
+			delivery, err := s.Redeliver(auth.WithIdentity(context.Background(), tt.identity), webhookID, deliveryID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Redeliver() error = %v, want %v", err, tt.wantErr)
+			}
+			if (store.redeliver != nil) != tt.wantCalled {
+				t.Fatalf("storage called = %v, want %v", store.redeliver != nil, tt.wantCalled)
+			}
+			if !tt.wantCalled {
+				return
+			}
+			// Доставка ищется только в организации вызывающей стороны.
+			if store.redeliver[0] != tenantID || store.redeliver[1] != webhookID || store.redeliver[2] != deliveryID {
+				t.Errorf("storage called with %v, want %v %v %v", store.redeliver, tenantID, webhookID, deliveryID)
+			}
+			if tt.wantErr == nil && delivery.Status != models.DeliveryPending {
+				t.Errorf("status = %s, want %s", delivery.Status, models.DeliveryPending)
+			}
+		})
+	}
+}
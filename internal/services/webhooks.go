@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/storage"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// webhookSecretPrefix — префикс сгенерированного секрета подписи вебхука.
+	webhookSecretPrefix = "whsec_"
+	// minWebhookSecretLength — минимальная длина секрета, указанного клиентом.
+	minWebhookSecretLength = 16
+	// maxWebhookURLLength — максимальная длина адреса вебхука.
+	maxWebhookURLLength = 2048
+	// defaultDeliveriesLimit и maxDeliveriesLimit — размер страницы журнала доставок
+	// по умолчанию и наибольший допустимый.
+	defaultDeliveriesLimit = 100
+	maxDeliveriesLimit     = 1000
+)
+
+// webhooksStorage — отвечает за хранение вебхуков и журнала доставок.
+type webhooksStorage interface {
+	CreateWebhook(ctx context.Context, hook models.WebhookDTO) (models.WebhookDTO, error)
+	ReadWebhooks(ctx context.Context, tenantID uuid.UUID) ([]models.WebhookDTO, error)
+	ReadWebhook(ctx context.Context, tenantID, id uuid.UUID) (models.WebhookDTO, error)
+	UpdateWebhook(ctx context.Context, hook models.WebhookDTO) (models.WebhookDTO, error)
+	DeleteWebhook(ctx context.Context, tenantID, id uuid.UUID) error
+	ReadDeliveries(ctx context.Context, tenantID, webhookID uuid.UUID, filter models.DeliveryFilter) ([]models.WebhookDelivery, error)
+	RedeliverDelivery(ctx context.Context, tenantID, webhookID, id uuid.UUID) (models.WebhookDelivery, error)
+}
+
+// WebhooksService — сервисный слой для управления вебхуками и журналом доставок.
+// Управлять вебхуками может администратор организации или сервис с API-ключом
+// с разрешением webhooks:manage; вебхуки создаются в организации вызывающей стороны.
+type WebhooksService struct {
+	webhooks webhooksStorage
+}
+
+// NewWebhooksService — конструктор сервиса вебхуков.
+func NewWebhooksService(webhooksStorage *storage.WebhooksStorage) *WebhooksService {
+	return &WebhooksService{
+		webhooks: webhooksStorage,
+	}
+}
+
+// CreateWebhook — регистрирует вебхук с фильтром типов событий и секретом подписи.
+// Если секрет не указан, он генерируется. Возвращает вебхук вместе с секретом;
+// повторно получить секрет нельзя.
+func (s *WebhooksService) CreateWebhook(ctx context.Context, req models.CreateWebhookRequest) (models.CreatedWebhook, error) {
+	logger.FromContext(ctx).Info("start creating webhook")
+	id, err := requireAdminOrService(ctx)
+	if err != nil {
+		return models.CreatedWebhook{}, err
+	}
+
+	hook := models.WebhookDTO{
+		TenantID:    id.TenantID,
+		URL:         strings.TrimSpace(req.URL),
+		EventTypes:  req.EventTypes,
+		Secret:      req.Secret,
+		Description: strings.TrimSpace(req.Description),
+		Enabled:     req.Enabled == nil || *req.Enabled,
+	}
+
+	if hook.Secret == "" {
+		if hook.Secret, err = generateToken(webhookSecretPrefix); err != nil {
+			logger.FromContext(ctx).Error(err.Error())
+			return models.CreatedWebhook{}, errors.New("error create webhook")
+		}
+	}
+
+	if err := validateWebhook(&hook); err != nil {
+		return models.CreatedWebhook{}, err
+	}
+
+	hook, err = s.webhooks.CreateWebhook(ctx, hook)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return models.CreatedWebhook{}, errors.New("error create webhook")
+	}
+
+	return models.CreatedWebhook{Webhook: hook.ToWebhook(), Secret: hook.Secret}, nil
+}
+
+// ListWebhooks — возвращает все вебхуки организации без секретов.
+func (s *WebhooksService) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	logger.FromContext(ctx).Info("start listing webhooks")
+	hooks := make([]models.Webhook, 0)
+
+	id, err := requireAdminOrService(ctx)
+	if err != nil {
+		return hooks, err
+	}
+
+	slice, err := s.webhooks.ReadWebhooks(ctx, id.TenantID)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return hooks, errors.New("error list webhooks")
+	}
+
+	for _, v := range slice {
+		hooks = append(hooks, v.ToWebhook())
+	}
+
+	return hooks, nil
+}
+
+// GetWebhook — возвращает вебхук организации по UUID без секрета.
+func (s *WebhooksService) GetWebhook(ctx context.Context, webhookID uuid.UUID) (models.Webhook, error) {
+	logger.FromContext(ctx).Info("start getting webhook")
+	hook, err := s.ownedWebhook(ctx, webhookID)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	return hook.ToWebhook(), nil
+}
+
+// EditWebhook — изменяет переданные поля вебхука и возвращает его без секрета.
+func (s *WebhooksService) EditWebhook(ctx context.Context, webhookID uuid.UUID, req models.EditWebhookRequest) (models.Webhook, error) {
+	logger.FromContext(ctx).Info("start editing webhook")
+	hook, err := s.ownedWebhook(ctx, webhookID)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	if req.URL != nil {
+		hook.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.EventTypes != nil {
+		hook.EventTypes = *req.EventTypes
+	}
+	if req.Secret != nil {
+		hook.Secret = *req.Secret
+	}
+	if req.Description != nil {
+		hook.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Enabled != nil {
+		hook.Enabled = *req.Enabled
+	}
+
+	if err := validateWebhook(&hook); err != nil {
+		return models.Webhook{}, err
+	}
+
+	hook, err = s.webhooks.UpdateWebhook(ctx, hook)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		if errors.Is(err, storage.ErrWebhookNotFound) {
+			return models.Webhook{}, ErrWebhookNotFound
+		}
+		return models.Webhook{}, errors.New("error edit webhook")
+	}
+
+	return hook.ToWebhook(), nil
+}
+
+// DeleteWebhook — удаляет вебхук вместе с журналом его доставок.
+// Недоставленные события на него больше не отправляются.
+func (s *WebhooksService) DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error {
+	logger.FromContext(ctx).Info("start deleting webhook")
+	id, err := requireAdminOrService(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.webhooks.DeleteWebhook(ctx, id.TenantID, webhookID); err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		if errors.Is(err, storage.ErrWebhookNotFound) {
+			return ErrWebhookNotFound
+		}
+		return errors.New("error delete webhook")
+	}
+
+	return nil
+}
+
+// ListDeliveries — возвращает страницу журнала доставок вебхука, начиная с самых новых.
+// Без limit возвращается defaultDeliveriesLimit доставок.
+func (s *WebhooksService) ListDeliveries(ctx context.Context, webhookID uuid.UUID, filter models.DeliveryFilter) ([]models.WebhookDelivery, error) {
+	logger.FromContext(ctx).Info("start listing webhook deliveries")
+	deliveries := make([]models.WebhookDelivery, 0)
+
+	switch filter.Status {
+	case "", models.DeliveryPending, models.DeliverySucceeded, models.DeliveryDead:
+	default:
+		return deliveries, fmt.Errorf("%w: unknown delivery status %q", ErrInvalidInput, filter.Status)
+	}
+
+	if filter.Limit < 0 || filter.Limit > maxDeliveriesLimit || filter.Offset < 0 {
+		return deliveries, fmt.Errorf("%w: limit must be between 0 and %d, offset must not be negative", ErrInvalidInput, maxDeliveriesLimit)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultDeliveriesLimit
+	}
+
+	hook, err := s.ownedWebhook(ctx, webhookID)
+	if err != nil {
+		return deliveries, err
+	}
+
+	slice, err := s.webhooks.ReadDeliveries(ctx, hook.TenantID, hook.ID, filter)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		return deliveries, errors.New("error list webhook deliveries")
+	}
+
+	return append(deliveries, slice...), nil
+}
+
+// Redeliver — возвращает доставку в очередь для немедленной отправки с новым набором
+// попыток. Подходит и для исчерпавшей попытки, и для уже успешной доставки.
+func (s *WebhooksService) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (models.WebhookDelivery, error) {
+	logger.FromContext(ctx).Info("start redelivering webhook delivery")
+	id, err := requireAdminOrService(ctx)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	delivery, err := s.webhooks.RedeliverDelivery(ctx, id.TenantID, webhookID, deliveryID)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		if errors.Is(err, storage.ErrDeliveryNotFound) {
+			return models.WebhookDelivery{}, ErrDeliveryNotFound
+		}
+		return models.WebhookDelivery{}, errors.New("error redeliver webhook delivery")
+	}
+
+	return delivery, nil
+}
+
+// ownedWebhook — проверяет права вызывающей стороны и читает вебхук её организации.
+func (s *WebhooksService) ownedWebhook(ctx context.Context, webhookID uuid.UUID) (models.WebhookDTO, error) {
+	id, err := requireAdminOrService(ctx)
+	if err != nil {
+		return models.WebhookDTO{}, err
+	}
+
+	hook, err := s.webhooks.ReadWebhook(ctx, id.TenantID, webhookID)
+	if err != nil {
+		logger.FromContext(ctx).Error(err.Error())
+		if errors.Is(err, storage.ErrWebhookNotFound) {
+			return hook, ErrWebhookNotFound
+		}
+		return hook, errors.New("error get webhook")
+	}
+
+	return hook, nil
+}
+
+// validateWebhook — проверяет адрес, типы событий и секрет вебхука.
+// Повторяющиеся типы событий удаляются.
+func validateWebhook(hook *models.WebhookDTO) error {
+	if hook.URL == "" {
+		return fmt.Errorf("%w: url is required", ErrInvalidInput)
+	}
+	if len(hook.URL) > maxWebhookURLLength {
+		return fmt.Errorf("%w: url must not exceed %d characters", ErrInvalidInput, maxWebhookURLLength)
+	}
+
+	u, err := url.Parse(hook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https url", ErrInvalidInput)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: url must not contain credentials", ErrInvalidInput)
+	}
+
+	types := make([]models.EventType, 0, len(hook.EventTypes))
+	for _, t := range hook.EventTypes {
+		if !slices.Contains(models.EventTypes, t) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidInput, t)
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	hook.EventTypes = types
+
+	if len(hook.Secret) < minWebhookSecretLength {
+		return fmt.Errorf("%w: secret must be at least %d characters", ErrInvalidInput, minWebhookSecretLength)
+	}
+
+	return nil
+}
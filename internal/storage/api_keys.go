@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/storage/postgres"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrAPIKeyNotFound — API-ключ отсутствует в базе данных.
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// APIKeysStorage — хранилище API-ключей.
+// Ключ принадлежит организации и действует только в её пределах.
+type APIKeysStorage struct {
+	db *pgxpool.Pool
+}
+
+// NewAPIKeysStorage — конструктор хранилища API-ключей.
+func NewAPIKeysStorage(db *pgxpool.Pool) *APIKeysStorage {
+	return &APIKeysStorage{
+		db: db,
+	}
+}
+
+// CreateAPIKey — сохраняет новый API-ключ в таблице api_keys.
+// Возвращает UUID и дату создания ключа.
+func (s *APIKeysStorage) CreateAPIKey(ctx context.Context, key models.APIKeyDTO) (models.APIKeyDTO, error) {
+	ctx = postgres.WithOperation(ctx, "api_keys", "CreateAPIKey")
+
+	if err := requireTenant(key.TenantID); err != nil {
+		return key, err
+	}
+
+	query := "insert into api_keys (tenant_id, name, prefix, key_hash, scopes, expires_at) values ($1, $2, $3, $4, $5, $6) returning id, created_at"
+
+	err := s.db.QueryRow(ctx, query, key.TenantID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.ExpiresAt).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return key, fmt.Errorf("failed to insert api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// ReadAPIKeys — возвращает все API-ключи организации, начиная с самых новых.
+func (s *APIKeysStorage) ReadAPIKeys(ctx context.Context, tenantID uuid.UUID) ([]models.APIKeyDTO, error) {
+	ctx = postgres.WithOperation(ctx, "api_keys", "ReadAPIKeys")
+
+	var keys []models.APIKeyDTO
+
+	if err := requireTenant(tenantID); err != nil {
+		return keys, err
+	}
+
+	query := "select id, tenant_id, name, prefix, key_hash, scopes, created_at, expires_at, revoked_at from api_keys where tenant_id=$1 order by created_at desc"
+
+	rows, err := s.db.Query(ctx, query, tenantID)
+	if err != nil {
+		return keys, fmt.Errorf("failed to select api keys: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return keys, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return keys, fmt.Errorf("failed to read api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// ReadAPIKeyByHash — находит API-ключ по хэшу его значения.
+// Поиск выполняется по всем организациям: организация определяется самим ключом.
+func (s *APIKeysStorage) ReadAPIKeyByHash(ctx context.Context, hash string) (models.APIKeyDTO, error) {
+	ctx = postgres.WithOperation(ctx, "api_keys", "ReadAPIKeyByHash")
+
+	query := "select id, tenant_id, name, prefix, key_hash, scopes, created_at, expires_at, revoked_at from api_keys where key_hash=$1"
+
+	key, err := scanAPIKey(s.db.QueryRow(ctx, query, hash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return key, ErrAPIKeyNotFound
+	}
+
+	return key, err
+}
+
+// RevokeAPIKey — помечает API-ключ организации отозванным.
+// Повторный отзыв не меняет дату отзыва.
+func (s *APIKeysStorage) RevokeAPIKey(ctx context.Context, tenantID, id uuid.UUID) error {
+	ctx = postgres.WithOperation(ctx, "api_keys", "RevokeAPIKey")
+
+	if err := requireTenant(tenantID); err != nil {
+		return err
+	}
+
+	query := "update api_keys set revoked_at = coalesce(revoked_at, now()) where id=$1 and tenant_id=$2"
+
+	data, err := s.db.Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	if data.RowsAffected() == 0 {
+		return fmt.Errorf("failed to revoke api key: %w", ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
+
+// scanAPIKey — читает строку таблицы api_keys в DTO.
+func scanAPIKey(row pgx.Row) (models.APIKeyDTO, error) {
+	var key models.APIKeyDTO
+
+	err := row.Scan(&key.ID, &key.TenantID, &key.Name, &key.Prefix, &key.KeyHash, &key.Scopes, &key.CreatedAt, &key.ExpiresAt, &key.RevokedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return key, err
+	}
+	if err != nil {
+		return key, fmt.Errorf("failed to scan api key: %w", err)
+	}
+
+	return key, nil
+}
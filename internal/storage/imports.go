@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/storage/postgres"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrImportNotFound — задача импорта отсутствует в базе данных.
+var ErrImportNotFound = errors.New("import not found")
+
+// ImportsStorage — хранилище задач импорта подписок.
+// Состояние задачи хранится в БД, чтобы прогресс был виден с любого экземпляра сервиса.
+// Экземпляр, выполняющий задачу, периодически отмечает её (heartbeat_at), чтобы задачи
+// экземпляра, завершившегося аварийно, можно было отличить от выполняемых.
+type ImportsStorage struct {
+	db *pgxpool.Pool
+}
+
+// NewImportsStorage — конструктор хранилища задач импорта.
+func NewImportsStorage(db *pgxpool.Pool) *ImportsStorage {
+	return &ImportsStorage{
+		db: db,
+	}
+}
+
+// CreateImport — сохраняет новую задачу импорта в таблице imports.
+// Возвращает задачу с UUID и датой создания.
+func (s *ImportsStorage) CreateImport(ctx context.Context, job models.ImportJob) (models.ImportJob, error) {
+	ctx = postgres.WithOperation(ctx, "imports", "CreateImport")
+
+	if err := requireTenant(job.TenantID); err != nil {
+		return job, err
+	}
+
+	var userID *uuid.UUID
+	if job.UserID != uuid.Nil {
+		userID = &job.UserID
+	}
+
+	query := "insert into imports (tenant_id, user_id, status, dry_run, total_rows) values ($1, $2, $3, $4, $5) returning id, created_at"
+
+	err := s.db.QueryRow(ctx, query, job.TenantID, userID, job.Status, job.DryRun, job.TotalRows).Scan(&job.ID, &job.CreatedAt)
+	if err != nil {
+		return job, fmt.Errorf("failed to insert import: %w", err)
+	}
+
+	return job, nil
+}
+
+// UpdateImport — сохраняет состояние и прогресс задачи импорта; сохранение также
+// отмечает, что задача выполняется.
+func (s *ImportsStorage) UpdateImport(ctx context.Context, job models.ImportJob) error {
+	ctx = postgres.WithOperation(ctx, "imports", "UpdateImport")
+
+	if err := requireTenant(job.TenantID); err != nil {
+		return err
+	}
+
+	rowErrors := job.Errors
+	if rowErrors == nil {
+		rowErrors = []models.ImportRowError{}
+	}
+	encodedErrors, err := json.Marshal(rowErrors)
+	if err != nil {
+		return fmt.Errorf("failed to encode import errors: %w", err)
+	}
+
+	var jobErr *string
+	if job.Error != "" {
+		jobErr = &job.Error
+	}
+
+	query := `update imports set status = $3, processed_rows = $4, imported_rows = $5, error_count = $6,
+		errors = $7, error = $8, finished_at = $9, heartbeat_at = now()
+		where id = $1 and tenant_id = $2`
+
+	data, err := s.db.Exec(ctx, query, job.ID, job.TenantID, job.Status, job.ProcessedRows, job.ImportedRows,
+		job.ErrorCount, string(encodedErrors), jobErr, job.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update import: %w", err)
+	}
+
+	if data.RowsAffected() == 0 {
+		return fmt.Errorf("failed to update import: %w", ErrImportNotFound)
+	}
+
+	return nil
+}
+
+// ReadImport — читает задачу импорта по UUID.
+func (s *ImportsStorage) ReadImport(ctx context.Context, tenantID, id uuid.UUID) (models.ImportJob, error) {
+	ctx = postgres.WithOperation(ctx, "imports", "ReadImport")
+
+	job := models.ImportJob{TenantID: tenantID}
+
+	if err := requireTenant(tenantID); err != nil {
+		return job, err
+	}
+
+	query := `select id, user_id, status, dry_run, total_rows, processed_rows, imported_rows, error_count, errors,
+		coalesce(error, ''), created_at, finished_at from imports where id = $1 and tenant_id = $2`
+
+	var (
+		userID    *uuid.UUID
+		rowErrors []byte
+	)
+	err := s.db.QueryRow(ctx, query, id, tenantID).Scan(&job.ID, &userID, &job.Status, &job.DryRun, &job.TotalRows,
+		&job.ProcessedRows, &job.ImportedRows, &job.ErrorCount, &rowErrors, &job.Error, &job.CreatedAt, &job.FinishedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return job, ErrImportNotFound
+	}
+	if err != nil {
+		return job, fmt.Errorf("failed to select import: %w", err)
+	}
+
+	if userID != nil {
+		job.UserID = *userID
+	}
+
+	if err := json.Unmarshal(rowErrors, &job.Errors); err != nil {
+		return job, fmt.Errorf("failed to decode import errors: %w", err)
+	}
+
+	return job, nil
+}
+
+// TouchImports — отмечает, что незавершённые задачи ids всё ещё выполняются.
+// Работает с задачами всех организаций.
+func (s *ImportsStorage) TouchImports(ctx context.Context, ids []uuid.UUID) error {
+	ctx = postgres.WithOperation(ctx, "imports", "TouchImports")
+
+	query := "update imports set heartbeat_at = now() where id = any($1) and status in ('pending', 'running')"
+
+	if _, err := s.db.Exec(ctx, query, ids); err != nil {
+		return fmt.Errorf("failed to touch imports: %w", err)
+	}
+
+	return nil
+}
+
+// FailStaleImports — завершает с ошибкой reason незавершённые задачи всех организаций,
+// которые не отмечались дольше staleAfter: экземпляр, выполнявший их, завершился аварийно.
+// Возвращает количество таких задач.
+func (s *ImportsStorage) FailStaleImports(ctx context.Context, staleAfter time.Duration, reason string) (int64, error) {
+	ctx = postgres.WithOperation(ctx, "imports", "FailStaleImports")
+
+	query := `update imports set status = 'failed', error = $1, finished_at = now()
+		where status in ('pending', 'running') and heartbeat_at < now() - make_interval(secs => $2)`
+
+	data, err := s.db.Exec(ctx, query, reason, staleAfter.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail stale imports: %w", err)
+	}
+
+	return data.RowsAffected(), nil
+}
@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/storage/postgres"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrLinkNotFound — связь подписки с провайдером отсутствует в базе данных.
+	ErrLinkNotFound = errors.New("subscription link not found")
+	// ErrLinkExists — идентификатор подписки у провайдера уже связан с другой подпиской организации.
+	ErrLinkExists = errors.New("subscription link already exists")
+)
+
+// linkColumns — колонки таблицы subscription_links в порядке scanLink.
+const linkColumns = "provider, external_id, tenant_id, subscription_id, created_at"
+
+// IntegrationsStorage — хранилище связей подписок с провайдерами и журнала
+// принятых событий провайдеров. Связи принадлежат организации, и поиск связи
+// по идентификатору у провайдера ведётся только в пределах организации.
+type IntegrationsStorage struct {
+	db *pgxpool.Pool
+}
+
+// NewIntegrationsStorage — конструктор хранилища интеграций с провайдерами.
+func NewIntegrationsStorage(db *pgxpool.Pool) *IntegrationsStorage {
+	return &IntegrationsStorage{
+		db: db,
+	}
+}
+
+// UpsertLink — связывает подписку с идентификатором у провайдера; прежний идентификатор
+// подписки у того же провайдера заменяется. Если идентификатор уже связан с другой
+// подпиской той же организации, возвращает ErrLinkExists.
+func (s *IntegrationsStorage) UpsertLink(ctx context.Context, link models.SubscriptionLink) (models.SubscriptionLink, error) {
+	ctx = postgres.WithOperation(ctx, "subscription_links", "UpsertLink")
+
+	if err := requireTenant(link.TenantID); err != nil {
+		return link, err
+	}
+
+	query := `insert into subscription_links (provider, external_id, tenant_id, subscription_id) values ($1, $2, $3, $4)
+		on conflict (subscription_id, provider) do update set external_id = excluded.external_id, created_at = now()
+		returning created_at`
+
+	err := s.db.QueryRow(ctx, query, link.Provider, link.ExternalID, link.TenantID, link.SubscriptionID).Scan(&link.CreatedAt)
+	if isUniqueViolation(err) {
+		return link, fmt.Errorf("failed to insert subscription link: %w", ErrLinkExists)
+	}
+	if isForeignKeyViolation(err) {
+		return link, fmt.Errorf("failed to insert subscription link: %w", ErrSubsNotFound)
+	}
+	if err != nil {
+		return link, fmt.Errorf("failed to insert subscription link: %w", err)
+	}
+
+	return link, nil
+}
+
+// ReadLinks — возвращает связи подписки организации со всеми провайдерами.
+func (s *IntegrationsStorage) ReadLinks(ctx context.Context, tenantID, subscriptionID uuid.UUID) ([]models.SubscriptionLink, error) {
+	ctx = postgres.WithOperation(ctx, "subscription_links", "ReadLinks")
+
+	var links []models.SubscriptionLink
+
+	if err := requireTenant(tenantID); err != nil {
+		return links, err
+	}
+
+	query := "select " + linkColumns + " from subscription_links where tenant_id=$1 and subscription_id=$2 order by provider"
+
+	rows, err := s.db.Query(ctx, query, tenantID, subscriptionID)
+	if err != nil {
+		return links, fmt.Errorf("failed to select subscription links: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		link, err := scanLink(rows)
+		if err != nil {
+			return links, err
+		}
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return links, fmt.Errorf("failed to read subscription links: %w", err)
+	}
+
+	return links, nil
+}
+
+// ReadLinkByExternalID — возвращает связь организации по идентификатору подписки у провайдера.
+func (s *IntegrationsStorage) ReadLinkByExternalID(ctx context.Context, tenantID uuid.UUID, provider, externalID string) (models.SubscriptionLink, error) {
+	ctx = postgres.WithOperation(ctx, "subscription_links", "ReadLinkByExternalID")
+
+	if err := requireTenant(tenantID); err != nil {
+		return models.SubscriptionLink{}, err
+	}
+
+	query := "select " + linkColumns + " from subscription_links where tenant_id=$1 and provider=$2 and external_id=$3"
+
+	link, err := scanLink(s.db.QueryRow(ctx, query, tenantID, provider, externalID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return link, ErrLinkNotFound
+	}
+
+	return link, err
+}
+
+// DeleteLink — удаляет связь подписки организации с провайдером.
+func (s *IntegrationsStorage) DeleteLink(ctx context.Context, tenantID, subscriptionID uuid.UUID, provider string) error {
+	ctx = postgres.WithOperation(ctx, "subscription_links", "DeleteLink")
+
+	if err := requireTenant(tenantID); err != nil {
+		return err
+	}
+
+	data, err := s.db.Exec(ctx, "delete from subscription_links where tenant_id=$1 and subscription_id=$2 and provider=$3", tenantID, subscriptionID, provider)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription link: %w", err)
+	}
+
+	if data.RowsAffected() == 0 {
+		return fmt.Errorf("failed to delete subscription link: %w", ErrLinkNotFound)
+	}
+
+	return nil
+}
+
+// ClaimInboundEvent — записывает начало обработки события провайдера. Возвращает false,
+// если событие уже обработано или обрабатывается; повторно принимается событие,
+// обработка которого завершилась ошибкой или не завершилась за lease. Событие записывается
+// в организацию, которой принадлежит провайдер.
+func (s *IntegrationsStorage) ClaimInboundEvent(ctx context.Context, tenantID uuid.UUID, provider string, event models.ProviderEvent, lease time.Duration) (bool, error) {
+	ctx = postgres.WithOperation(ctx, "integration_events", "ClaimInboundEvent")
+
+	if err := requireTenant(tenantID); err != nil {
+		return false, err
+	}
+
+	query := `insert into integration_events (provider, event_id, event_type, external_id, tenant_id, status) values ($1, $2, $3, $4, $5, 'processing')
+		on conflict (provider, event_id) do update
+			set event_type = excluded.event_type, external_id = excluded.external_id, tenant_id = excluded.tenant_id,
+				subscription_id = null, status = 'processing', error = null,
+				attempts = integration_events.attempts + 1, received_at = now(), processed_at = null
+			where integration_events.status = 'failed'
+				or (integration_events.status = 'processing' and integration_events.received_at < now() - make_interval(secs => $6))
+		returning true`
+
+	var claimed bool
+	err := s.db.QueryRow(ctx, query, provider, event.ID, string(event.Type), event.ExternalID, tenantID, lease.Seconds()).Scan(&claimed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim integration event: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// FinishInboundEvent — сохраняет результат обработки события провайдера.
+func (s *IntegrationsStorage) FinishInboundEvent(ctx context.Context, provider string, result models.InboundEventResult) error {
+	ctx = postgres.WithOperation(ctx, "integration_events", "FinishInboundEvent")
+
+	var reason *string
+	if result.Error != "" {
+		reason = &result.Error
+	}
+
+	query := "update integration_events set status=$1, error=$2, subscription_id=$3, processed_at=now() where provider=$4 and event_id=$5"
+
+	if _, err := s.db.Exec(ctx, query, string(result.Status), reason, result.SubscriptionID, provider, result.EventID); err != nil {
+		return fmt.Errorf("failed to update integration event: %w", err)
+	}
+
+	return nil
+}
+
+// scanLink — читает связь подписки из строки результата запроса.
+func scanLink(row pgx.Row) (models.SubscriptionLink, error) {
+	var link models.SubscriptionLink
+
+	err := row.Scan(&link.Provider, &link.ExternalID, &link.TenantID, &link.SubscriptionID, &link.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return link, err
+	}
+	if err != nil {
+		return link, fmt.Errorf("failed to scan subscription link: %w", err)
+	}
+
+	return link, nil
+}
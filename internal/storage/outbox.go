@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/storage/postgres"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// outboxRelayLockID — ключ advisory-блокировки, которую удерживает транзакция публикации
+// событий, чтобы события публиковал только один экземпляр сервиса и их порядок сохранялся.
+const outboxRelayLockID int64 = 0x537562734f757431
+
+// outboxColumns — колонки таблицы outbox, которые заполняются при записи событий.
+var outboxColumns = []string{"event_id", "tenant_id", "subscription_id", "event_type", "payload", "created_at"}
+
+// AppendEvents — записывает события в таблицу outbox в порядке следования.
+// Вызывается на хранилище, полученном в WithinTx, чтобы события сохранялись
+// в той же транзакции, что и изменения подписок, которые они описывают.
+func (s *SubsStorage) AppendEvents(ctx context.Context, events []models.Event) error {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "AppendEvents")
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	for _, e := range events {
+		if err := requireTenant(e.TenantID); err != nil {
+			return err
+		}
+	}
+
+	_, err := s.db.CopyFrom(ctx, pgx.Identifier{"outbox"}, outboxColumns, pgx.CopyFromSlice(len(events), func(i int) ([]any, error) {
+		// Дата хранится без часового пояса, поэтому приводится к UTC.
+		e := events[i]
+		return []any{e.ID, e.TenantID, e.SubscriptionID, string(e.Type), []byte(e.Data), e.OccurredAt.UTC()}, nil
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to insert events: %w", err)
+	}
+
+	return nil
+}
+
+// OutboxStorage — хранилище событий, ожидающих публикации.
+// В отличие от остальных хранилищ работает с событиями всех организаций
+// и предназначено только для фоновой публикации событий.
+// Хранилище, полученное в WithinTx, выполняет запросы в транзакции.
+type OutboxStorage struct {
+	db dbtx // пул подключений или транзакция WithinTx
+}
+
+// NewOutboxStorage — конструктор хранилища событий.
+func NewOutboxStorage(db *pgxpool.Pool) *OutboxStorage {
+	return &OutboxStorage{
+		db: db,
+	}
+}
+
+// WithinTx — выполняет fn в одной транзакции, как SubsStorage.WithinTx.
+func (s *OutboxStorage) WithinTx(ctx context.Context, fn func(tx *OutboxStorage) error) error {
+	return withinTx(ctx, s.db, func(tx pgx.Tx) error {
+		return fn(&OutboxStorage{db: tx})
+	})
+}
+
+// TryLockRelay — пытается захватить блокировку публикации событий до конца транзакции.
+// Возвращает false, если события уже публикует другая транзакция.
+func (s *OutboxStorage) TryLockRelay(ctx context.Context) (bool, error) {
+	ctx = postgres.WithOperation(ctx, "outbox", "TryLockRelay")
+
+	var locked bool
+	if err := s.db.QueryRow(ctx, "select pg_try_advisory_xact_lock($1)", outboxRelayLockID).Scan(&locked); err != nil {
+		return false, fmt.Errorf("failed to lock outbox relay: %w", err)
+	}
+
+	return locked, nil
+}
+
+// ReadPendingEvents — возвращает до limit неопубликованных событий, срок очередной попытки
+// публикации которых наступил, в порядке записи. События подписки, более раннее событие
+// которой ждёт повторной попытки, не возвращаются, чтобы не нарушить порядок.
+func (s *OutboxStorage) ReadPendingEvents(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	ctx = postgres.WithOperation(ctx, "outbox", "ReadPendingEvents")
+
+	var events []models.OutboxEvent
+
+	query := `select o.id, o.event_id, o.tenant_id, o.subscription_id, o.event_type, o.payload, o.created_at, o.attempts
+		from outbox o
+		where o.published_at is null and o.next_attempt_at <= now()
+		and not exists (select 1 from outbox p where p.subscription_id = o.subscription_id
+			and p.published_at is null and p.id < o.id and p.next_attempt_at > now())
+		order by o.id limit $1`
+
+	rows, err := s.db.Query(ctx, query, limit)
+	if err != nil {
+		return events, fmt.Errorf("failed to select events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			e         models.OutboxEvent
+			eventType string
+		)
+		err := rows.Scan(&e.Sequence, &e.ID, &e.TenantID, &e.SubscriptionID, &eventType, &e.Data, &e.OccurredAt, &e.Attempts)
+		if err != nil {
+			return events, fmt.Errorf("failed to scan event: %w", err)
+		}
+		e.Type = models.EventType(eventType)
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return events, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkEventsPublished — отмечает события с указанными порядковыми номерами опубликованными.
+func (s *OutboxStorage) MarkEventsPublished(ctx context.Context, sequences []int64) error {
+	ctx = postgres.WithOperation(ctx, "outbox", "MarkEventsPublished")
+
+	if len(sequences) == 0 {
+		return nil
+	}
+
+	query := "update outbox set published_at = now(), last_error = null where id = any($1)"
+
+	if _, err := s.db.Exec(ctx, query, sequences); err != nil {
+		return fmt.Errorf("failed to mark events published: %w", err)
+	}
+
+	return nil
+}
+
+// MarkEventFailed — сохраняет неудачную попытку публикации события
+// и откладывает следующую на retryIn.
+func (s *OutboxStorage) MarkEventFailed(ctx context.Context, sequence int64, retryIn time.Duration, reason string) error {
+	ctx = postgres.WithOperation(ctx, "outbox", "MarkEventFailed")
+
+	query := "update outbox set attempts = attempts + 1, next_attempt_at = now() + $2::interval, last_error = $3 where id = $1"
+
+	if _, err := s.db.Exec(ctx, query, sequence, retryIn, reason); err != nil {
+		return fmt.Errorf("failed to mark event failed: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePublishedEvents — удаляет события, опубликованные больше olderThan назад.
+// Возвращает количество удалённых событий.
+func (s *OutboxStorage) DeletePublishedEvents(ctx context.Context, olderThan time.Duration) (int64, error) {
+	ctx = postgres.WithOperation(ctx, "outbox", "DeletePublishedEvents")
+
+	data, err := s.db.Exec(ctx, "delete from outbox where published_at < now() - $1::interval", olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete published events: %w", err)
+	}
+
+	return data.RowsAffected(), nil
+}
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"online_subscription_service/migrations"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratepg "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// migrationLockID — ключ advisory-блокировки, которую сервис удерживает на время миграций,
+// чтобы несколько одновременно запускаемых экземпляров не мигрировали схему параллельно.
+const migrationLockID int64 = 0x5375627353766331
+
+// MigrationStatus — состояние схемы БД.
+type MigrationStatus struct {
+	Version uint // применённая версия (0 — миграции не применялись)
+	Dirty   bool // последняя миграция завершилась с ошибкой
+	Latest  uint // последняя версия, встроенная в сервис
+}
+
+// Pending — проверяет, есть ли встроенные миграции, которые ещё не применены.
+func (s MigrationStatus) Pending() bool {
+	return s.Version < s.Latest
+}
+
+// Migrator — управляет миграциями схемы БД, встроенными в бинарный файл.
+// Каждая операция выполняется под advisory-блокировкой.
+type Migrator struct {
+	pool *pgxpool.Pool
+}
+
+// NewMigrator — конструктор Migrator.
+func NewMigrator(pool *pgxpool.Pool) *Migrator {
+	return &Migrator{pool: pool}
+}
+
+// Up — применяет все непримененные миграции.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, func(mg *migrate.Migrate) error {
+		return mg.Up()
+	})
+}
+
+// Down — откатывает указанное количество последних миграций.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("invalid number of steps %d", steps)
+	}
+	return m.run(ctx, func(mg *migrate.Migrate) error {
+		return mg.Steps(-steps)
+	})
+}
+
+// Goto — применяет или откатывает миграции до указанной версии.
+func (m *Migrator) Goto(ctx context.Context, version uint) error {
+	return m.run(ctx, func(mg *migrate.Migrate) error {
+		return mg.Migrate(version)
+	})
+}
+
+// Force — устанавливает версию схемы и снимает признак «грязной» миграции без
+// выполнения SQL. Используется после ручного исправления неудачной миграции.
+// Версия -1 означает, что миграции не применялись.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	return m.run(ctx, func(mg *migrate.Migrate) error {
+		return mg.Force(version)
+	})
+}
+
+// Status — возвращает применённую и последнюю встроенную версии схемы.
+func (m *Migrator) Status(ctx context.Context) (MigrationStatus, error) {
+	var status MigrationStatus
+
+	latest, err := LatestMigrationVersion()
+	if err != nil {
+		return status, err
+	}
+	status.Latest = latest
+
+	err = m.run(ctx, func(mg *migrate.Migrate) error {
+		version, dirty, err := mg.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		status.Version, status.Dirty = version, dirty
+		return nil
+	})
+
+	return status, err
+}
+
+// run — захватывает advisory-блокировку и выполняет операцию с миграциями.
+// Ожидание блокировки прерывается при отмене контекста.
+func (m *Migrator) run(ctx context.Context, op func(mg *migrate.Migrate) error) error {
+	ctx = WithOperation(ctx, "migrations", "Migrate")
+
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection for migrations: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "select pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer func() {
+		// Блокировка снимается и при закрытии подключения, поэтому ошибку можно не возвращать.
+		_, _ = conn.Exec(context.WithoutCancel(ctx), "select pg_advisory_unlock($1)", migrationLockID)
+	}()
+
+	db := stdlib.OpenDBFromPool(m.pool)
+	defer db.Close()
+
+	driver, err := migratepg.WithInstance(db, &migratepg.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to init migrations driver: %w", err)
+	}
+
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	mg, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		return fmt.Errorf("failed to init migrations: %w", err)
+	}
+	defer mg.Close()
+
+	if err := op(mg); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	return nil
+}
+
+// LatestMigrationVersion — возвращает номер последней встроенной миграции.
+func LatestMigrationVersion() (uint, error) {
+	entries, err := fs.ReadDir(migrations.FS, ".")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok || !strings.HasSuffix(entry.Name(), ".up.sql") {
+			continue
+		}
+
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		latest = max(latest, uint(version))
+	}
+
+	return latest, nil
+}
+
+// rowQuerier — выполняет запрос, возвращающий одну строку (например, *pgxpool.Pool).
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// MigrationCheck — возвращает проверку готовности, которая убеждается,
+// что схема БД не находится в «грязном» состоянии после неудачной миграции
+// и что применены все миграции, известные этой версии сервиса.
+func MigrationCheck(db rowQuerier, expected uint) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx = WithOperation(ctx, "migrations", "MigrationCheck")
+
+		var version int64
+		var dirty bool
+
+		err := db.QueryRow(ctx, "select version, dirty from schema_migrations limit 1").Scan(&version, &dirty)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("no migrations applied")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read migration version: %w", err)
+		}
+
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+		if uint(version) < expected {
+			return fmt.Errorf("migration version %d is behind expected %d", version, expected)
+		}
+
+		return nil
+	}
+}
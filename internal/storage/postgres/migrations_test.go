@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// fakeRow — строка schema_migrations с версией и признаком «грязной» миграции.
+type fakeRow struct {
+	version int64
+	dirty   bool
+	err     error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*int64) = r.version
+	*dest[1].(*bool) = r.dirty
+	return nil
+}
+
+// fakeRowQuerier — возвращает заранее заданную строку на любой запрос.
+type fakeRowQuerier struct {
+	row fakeRow
+}
+
+func (q fakeRowQuerier) QueryRow(context.Context, string, ...any) pgx.Row {
+	return q.row
+}
+
+func TestMigrationCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		row     fakeRow
+		wantErr string
+	}{
+		{name: "latest", row: fakeRow{version: 11}},
+		{name: "ahead of binary", row: fakeRow{version: 12}},
+		{name: "behind", row: fakeRow{version: 10}, wantErr: "migration version 10 is behind expected 11"},
+		{name: "dirty", row: fakeRow{version: 11, dirty: true}, wantErr: "migration 11 is dirty"},
+		{name: "dirty and behind", row: fakeRow{version: 10, dirty: true}, wantErr: "migration 10 is dirty"},
+		{name: "no migrations", row: fakeRow{err: pgx.ErrNoRows}, wantErr: "no migrations applied"},
+		{name: "query failure", row: fakeRow{err: errors.New("relation does not exist")}, wantErr: "failed to read migration version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MigrationCheck(fakeRowQuerier{row: tt.row}, 11)(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("MigrationCheck() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("MigrationCheck() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
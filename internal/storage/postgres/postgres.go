@@ -2,51 +2,171 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/url"
 	"online_subscription_service/internal/config"
-
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// New создает и возвращает новое подключение к пулу PostgreSQL.
-// Принимает контекст выполнения и указатель на конфигурацию приложения.
-func New(context context.Context, config *config.Config) *pgxpool.Pool {
-	dbHost := fmt.Sprintf(
-		"postgresql://%s:%s@%s:%s/%s?sslmode=%s",
-		config.DB.Username, // Имя пользователя
-		config.DB.Password, // Пароль
-		config.DB.DBHost,   // Хост базы данных
-		config.DB.DBPort,   // Порт базы данных
-		config.DB.DBName,   // Название базы данных
-		config.DB.SSLMode,
-	)
-	var conn *pgxpool.Pool
-	var err error
-
-	count := 10
-
-	for range count {
-		conn, err = pgxpool.New(context, dbHost)
-		if err == nil {
-			err = conn.Ping(context)
-		}
+// New создает пул подключений к PostgreSQL и, если включено db.auto_migrate,
+// применяет встроенные миграции. Подключение и миграции должны уложиться
+// в срок db.connect_timeout. Возвращает ошибку, если подключиться или
+// применить миграции не удалось.
+func New(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.DB.ConnectTimeout)
+	defer cancel()
 
-		if err == nil {
-			break
+	pool, err := Connect(ctx, cfg.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.DB.AutoMigrate {
+		if err := NewMigrator(pool).Up(ctx); err != nil {
+			pool.Close()
+			return nil, err
 		}
 	}
-	m, err := migrate.New("file://./migrations", dbHost)
+
+	return pool, nil
+}
+
+// Connect создает пул подключений к PostgreSQL без применения миграций.
+// Подключение повторяется с экспоненциальной задержкой и случайным разбросом,
+// пока не истечёт контекст.
+func Connect(ctx context.Context, cfg config.DBConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := poolConfig(cfg)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	return connect(ctx, poolConfig, cfg)
+}
+
+// ConnString — возвращает строку подключения к БД.
+// Если задан DSN, он возвращается без изменений; иначе строка собирается
+// из параметров конфигурации с учётом переменных окружения PG*.
+func ConnString(cfg config.DBConfig) string {
+	if cfg.DSN != "" {
+		return cfg.DSN
+	}
+
+	host := envOr("PGHOST", cfg.DBHost)
+	port := envOr("PGPORT", cfg.DBPort)
+
+	u := url.URL{
+		Scheme: "postgresql",
+		User:   url.UserPassword(envOr("PGUSER", cfg.Username), envOr("PGPASSWORD", cfg.Password)),
+		Host:   net.JoinHostPort(host, port),
+		Path:   "/" + envOr("PGDATABASE", cfg.DBName),
+	}
+	if sslMode := envOr("PGSSLMODE", cfg.SSLMode); sslMode != "" {
+		u.RawQuery = url.Values{"sslmode": {sslMode}}.Encode()
 	}
 
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		panic(err)
+	return u.String()
+}
+
+// envOr — возвращает значение переменной окружения или fallback, если она не задана.
+func envOr(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// poolConfig — разбирает строку подключения и применяет настройки пула.
+func poolConfig(cfg config.DBConfig) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(ConnString(cfg))
+	if err != nil {
+		// Ошибка разбора может содержать строку подключения вместе с паролем.
+		return nil, errors.New("failed to parse database connection string")
 	}
 
-	return conn
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+	poolConfig.MinConns = cfg.MinConns
+	if cfg.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+
+	// Логирует запросы к БД с атрибутами HTTP-запроса из контекста и собирает их метрики
+	poolConfig.ConnConfig.Tracer = queryTracer{}
+
+	return poolConfig, nil
+}
+
+// connect — создаёт пул и проверяет подключение, повторяя попытки
+// с экспоненциальной задержкой, пока не истечёт контекст.
+func connect(ctx context.Context, poolConfig *pgxpool.Config, cfg config.DBConfig) (*pgxpool.Pool, error) {
+	delay := cfg.RetryInitialDelay
+
+	for attempt := 1; ; attempt++ {
+		pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+		if err == nil {
+			err = pool.Ping(ctx)
+			if err == nil {
+				slog.Info("connected to database", slog.Int("attempt", attempt))
+				return pool, nil
+			}
+			pool.Close()
+		}
+
+		wait := jitter(delay)
+		slog.Warn("failed to connect to database",
+			slog.Int("attempt", attempt),
+			slog.Duration("retry_in", wait),
+			slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", attempt, err)
+		case <-time.After(wait):
+		}
+
+		delay = min(delay*2, cfg.RetryMaxDelay)
+	}
+}
+
+// jitter — возвращает случайную задержку в диапазоне [d/2, d],
+// чтобы несколько экземпляров сервиса не переподключались одновременно.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// Close — закрывает пул подключений, дожидаясь возврата занятых подключений,
+// но не дольше, чем позволяет контекст.
+func Close(ctx context.Context, pool *pgxpool.Pool) error {
+	done := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to close database pool: %w", ctx.Err())
+	}
 }
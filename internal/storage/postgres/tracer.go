@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"online_subscription_service/internal/lib/logger"
+	"online_subscription_service/internal/lib/tracing"
+	"online_subscription_service/internal/metrics"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// unknownOperation — имя хранилища и метода для запросов, выполненных без WithOperation.
+const unknownOperation = "unknown"
+
+type operationKey struct{}
+
+// operation — хранилище и метод, от имени которых выполняется запрос.
+type operation struct {
+	storage string
+	method  string
+}
+
+// WithOperation — возвращает копию контекста с именем хранилища и метода,
+// выполняющего запросы. Эти имена попадают в логи и метрики запросов к БД.
+func WithOperation(ctx context.Context, storage, method string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation{storage: storage, method: method})
+}
+
+// operationFromContext — извлекает хранилище и метод из контекста.
+func operationFromContext(ctx context.Context) operation {
+	if op, ok := ctx.Value(operationKey{}).(operation); ok {
+		return op
+	}
+	return operation{storage: unknownOperation, method: unknownOperation}
+}
+
+// queryTracer — трассировщик pgx, который для каждого SQL-запроса и записи по протоколу COPY
+// записывает в логгер из контекста текст запроса и длительность, обновляет метрики
+// длительности и ошибок по хранилищу и методу и создаёт спан OpenTelemetry.
+// Благодаря логгеру из контекста в логах запросов к БД присутствуют request_id,
+// пользователь и маршрут HTTP-запроса, в рамках которого они выполнялись.
+// Аргументы запросов не логируются и не попадают в спаны.
+type queryTracer struct{}
+
+type queryStartKey struct{}
+
+// queryStart — данные о начале запроса, сохраняемые в контексте.
+type queryStart struct {
+	sql   string
+	op    operation
+	start time.Time
+}
+
+// TraceQueryStart — запоминает текст и время начала запроса и начинает спан запроса.
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	sql := compactSQL(data.SQL)
+	op := operationFromContext(ctx)
+
+	ctx, _ = tracing.Start(ctx, op.storage+"."+op.method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(sqlOperation(sql)),
+			semconv.DBQueryText(sql),
+		),
+	)
+
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: sql, op: op, start: time.Now()})
+}
+
+// TraceQueryEnd — записывает в лог текст запроса, длительность и результат,
+// обновляет метрики и завершает спан запроса.
+// Успешные запросы логируются на уровне debug, ошибки — на уровне warn.
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	q, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(q.start)
+	op := q.op
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int64("db.response.rows_affected", data.CommandTag.RowsAffected()))
+	err := data.Err
+	tracing.End(span, &err)
+
+	metrics.QueryDuration.WithLabelValues(op.storage, op.method).Observe(duration.Seconds())
+
+	attrs := []slog.Attr{
+		slog.String("storage", op.storage),
+		slog.String("storage_method", op.method),
+		slog.String("sql", q.sql),
+		slog.Duration("duration", duration),
+	}
+
+	log := logger.FromContext(ctx)
+	if data.Err != nil {
+		metrics.QueryErrors.WithLabelValues(op.storage, op.method).Inc()
+
+		attrs = append(attrs, slog.String("error", data.Err.Error()))
+		log.LogAttrs(ctx, slog.LevelWarn, "query failed", attrs...)
+		return
+	}
+
+	attrs = append(attrs, slog.Int64("rows", data.CommandTag.RowsAffected()))
+	log.LogAttrs(ctx, slog.LevelDebug, "query executed", attrs...)
+}
+
+// TraceCopyFromStart — начинает спан массовой записи по протоколу COPY,
+// как для запроса COPY <таблица> (<колонки>) FROM STDIN.
+func (t queryTracer) TraceCopyFromStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	sql := fmt.Sprintf("COPY %s (%s) FROM STDIN", data.TableName.Sanitize(), strings.Join(data.ColumnNames, ", "))
+	return t.TraceQueryStart(ctx, conn, pgx.TraceQueryStartData{SQL: sql})
+}
+
+// TraceCopyFromEnd — логирует массовую запись и завершает её спан.
+func (t queryTracer) TraceCopyFromEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	t.TraceQueryEnd(ctx, conn, pgx.TraceQueryEndData{CommandTag: data.CommandTag, Err: data.Err})
+}
+
+// sqlOperation — возвращает первое ключевое слово запроса (SELECT, INSERT, ...).
+func sqlOperation(sql string) string {
+	verb, _, _ := strings.Cut(sql, " ")
+	return strings.ToUpper(verb)
+}
+
+// compactSQL — схлопывает пробельные символы, чтобы запрос умещался в одну строку лога.
+func compactSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
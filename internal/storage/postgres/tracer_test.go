@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestQueryTracerSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		run        func(ctx context.Context)
+		wantName   string
+		wantAttrs  map[attribute.Key]attribute.Value
+		wantStatus codes.Code
+	}{
+		{
+			name: "query",
+			ctx:  WithOperation(context.Background(), "subscriptions", "ReadSubscription"),
+			run: func(ctx context.Context) {
+				ctx = queryTracer{}.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT id,\n\t\tprice\n  FROM services WHERE id = $1"})
+				queryTracer{}.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+			},
+			wantName: "subscriptions.ReadSubscription",
+			wantAttrs: map[attribute.Key]attribute.Value{
+				"db.system.name":            attribute.StringValue("postgresql"),
+				"db.operation.name":         attribute.StringValue("SELECT"),
+				"db.query.text":             attribute.StringValue("SELECT id, price FROM services WHERE id = $1"),
+				"db.response.rows_affected": attribute.Int64Value(1),
+			},
+			wantStatus: codes.Unset,
+		},
+		{
+			name: "failed query",
+			ctx:  WithOperation(context.Background(), "subscriptions", "CreateSubscription"),
+			run: func(ctx context.Context) {
+				ctx = queryTracer{}.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "insert into services (id) values ($1)"})
+				queryTracer{}.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("duplicate key")})
+			},
+			wantName: "subscriptions.CreateSubscription",
+			wantAttrs: map[attribute.Key]attribute.Value{
+				"db.operation.name": attribute.StringValue("INSERT"),
+			},
+			wantStatus: codes.Error,
+		},
+		{
+			name: "copy from",
+			ctx:  WithOperation(context.Background(), "subscriptions", "CopySubscriptions"),
+			run: func(ctx context.Context) {
+				ctx = queryTracer{}.TraceCopyFromStart(ctx, nil, pgx.TraceCopyFromStartData{
+					TableName:   pgx.Identifier{"services"},
+					ColumnNames: []string{"id", "price"},
+				})
+				queryTracer{}.TraceCopyFromEnd(ctx, nil, pgx.TraceCopyFromEndData{CommandTag: pgconn.NewCommandTag("COPY 2")})
+			},
+			wantName: "subscriptions.CopySubscriptions",
+			wantAttrs: map[attribute.Key]attribute.Value{
+				"db.operation.name":         attribute.StringValue("COPY"),
+				"db.query.text":             attribute.StringValue(`COPY "services" (id, price) FROM STDIN`),
+				"db.response.rows_affected": attribute.Int64Value(2),
+			},
+			wantStatus: codes.Unset,
+		},
+		{
+			name: "query without operation",
+			ctx:  context.Background(),
+			run: func(ctx context.Context) {
+				ctx = queryTracer{}.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+				queryTracer{}.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+			},
+			wantName:   "unknown.unknown",
+			wantStatus: codes.Unset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Спан запроса продолжает трассу вызывающего кода.
+			ctx, parent := otel.Tracer("test").Start(tt.ctx, "parent")
+			tt.run(ctx)
+			parent.End()
+
+			spans := recorder.Ended()
+			if len(spans) < 2 {
+				t.Fatalf("ended spans = %d, want query and parent spans", len(spans))
+			}
+			span := spans[len(spans)-2]
+
+			if span.Name() != tt.wantName {
+				t.Errorf("span name = %q, want %q", span.Name(), tt.wantName)
+			}
+			if span.SpanKind() != trace.SpanKindClient {
+				t.Errorf("span kind = %v, want client", span.SpanKind())
+			}
+			if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("span parent = %v, want %v", span.Parent().SpanID(), parent.SpanContext().SpanID())
+			}
+			if span.Status().Code != tt.wantStatus {
+				t.Errorf("span status = %v, want %v", span.Status().Code, tt.wantStatus)
+			}
+
+			attrs := make(map[attribute.Key]attribute.Value)
+			for _, kv := range span.Attributes() {
+				attrs[kv.Key] = kv.Value
+			}
+			for key, want := range tt.wantAttrs {
+				if got, ok := attrs[key]; !ok || got != want {
+					t.Errorf("attribute %s = %v, want %v", key, got.Emit(), want.Emit())
+				}
+			}
+		})
+	}
+}
@@ -6,17 +6,35 @@ import (
 	"fmt"
 	"online_subscription_service/internal/domain/models"
 	"online_subscription_service/internal/lib/storage"
+	"online_subscription_service/internal/storage/postgres"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrSubsNotFound — подписка с указанным UUID отсутствует в базе данных.
+var ErrSubsNotFound = errors.New("subscription not found")
+
+// dbtx — методы, общие для пула подключений и транзакции, через которые хранилище выполняет запросы.
+type dbtx interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 // SubsStorage — хранилище для работы с подписками.
 // Содержит подключение к базе данных и реализует методы
 // для создания и управления записями подписок.
+// Все запросы выполняются в пределах одной организации (tenant_id).
+// Хранилище, полученное в WithinTx, выполняет запросы в транзакции.
 type SubsStorage struct {
-	db *pgxpool.Pool
+	pool *pgxpool.Pool
+	db   dbtx // пул подключений или транзакция WithinTx
 }
 
 // NewSubsStorage — конструктор хранилища подписок.
@@ -24,21 +42,58 @@ type SubsStorage struct {
 // возвращает инициализированный экземпляр SubsStorage.
 func NewSubsStorage(db *pgxpool.Pool) *SubsStorage {
 	return &SubsStorage{
-		db: db,
+		pool: db,
+		db:   db,
 	}
 }
 
+// WithinTx — выполняет fn как единицу работы: все запросы хранилища tx, переданного в fn,
+// выполняются в одной транзакции, которая фиксируется, если fn вернула nil, и откатывается иначе.
+// Вызов WithinTx на хранилище транзакции открывает точку сохранения, и ошибка fn
+// откатывает только её изменения. Ошибка fn возвращается как есть.
+func (s *SubsStorage) WithinTx(ctx context.Context, fn func(tx *SubsStorage) error) error {
+	return withinTx(ctx, s.db, func(tx pgx.Tx) error {
+		return fn(&SubsStorage{pool: s.pool, db: tx})
+	})
+}
+
+// withinTx — выполняет fn в транзакции (или точке сохранения, если db — транзакция),
+// фиксирует её, если fn вернула nil, и откатывает иначе. Ошибка fn возвращается как есть.
+func withinTx(ctx context.Context, db dbtx, fn func(tx pgx.Tx) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // CreateSubscription — создает новую подписку в таблице services.
 // На вход принимает структуру SubsDTO с данными подписки
 // (название, цена, идентификатор пользователя, дата начала и окончания).
 // Возвращает UUID созданной подписки.
 // Если при выполнении запроса произошла ошибка, возвращает её наружу.
-func (s *SubsStorage) CreateSubscription(ctx context.Context, sub models.SubsDTO) (uuid.UUID, error) {
+func (s *SubsStorage) CreateSubscription(ctx context.Context, tenantID uuid.UUID, sub models.SubsDTO) (uuid.UUID, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "CreateSubscription")
+
 	var ID uuid.UUID
 
-	query := "insert into services (name, price, user_id, start_date, end_date) values ($1, $2, $3, $4, $5) returning id"
+	if err := requireTenant(tenantID); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	query := "insert into services (tenant_id, name, price, user_id, start_date, end_date) values ($1, $2, $3, $4, $5, $6) returning id"
 
-	err := s.db.QueryRow(ctx, query, sub.Name, sub.Price, sub.UserID.String(), sub.StartDate, sub.EndDate).Scan(&ID)
+	err := s.db.QueryRow(ctx, query, tenantID, sub.Name, sub.Price, sub.UserID.String(), sub.StartDate, sub.EndDate).Scan(&ID)
 	if err != nil {
 		return uuid.UUID{}, fmt.Errorf("failed to insert sub: %w", err)
 	}
@@ -46,25 +101,130 @@ func (s *SubsStorage) CreateSubscription(ctx context.Context, sub models.SubsDTO
 	return ID, nil
 }
 
+// subsColumns — колонки таблицы services, которые заполняются при массовой записи подписок.
+var subsColumns = []string{"id", "tenant_id", "name", "price", "user_id", "start_date", "end_date"}
+
+// CopySubscriptions — записывает подписки в таблицу services одной транзакцией
+// по протоколу COPY. Если запись не удалась, не сохраняется ни одна подписка.
+// Подписки записываются с указанными UUID; подписке без UUID присваивается новый.
+// progress, если не nil, вызывается по мере передачи строк с числом уже переданных.
+// Возвращает количество записанных подписок.
+func (s *SubsStorage) CopySubscriptions(ctx context.Context, tenantID uuid.UUID, subs []models.SubsDTO, progress func(copied int)) (int64, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "CopySubscriptions")
+
+	if err := requireTenant(tenantID); err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin copy: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	src := &subsCopySource{tenantID: tenantID, subs: subs, next: -1, progress: progress}
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{"services"}, subsColumns, src)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy subs: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit copy: %w", err)
+	}
+
+	return copied, nil
+}
+
+// copyProgressStep — через сколько переданных строк CopySubscriptions сообщает о прогрессе.
+const copyProgressStep = 1000
+
+// subsCopySource — источник строк COPY из среза подписок.
+type subsCopySource struct {
+	tenantID uuid.UUID
+	subs     []models.SubsDTO
+	next     int
+	progress func(copied int)
+}
+
+func (src *subsCopySource) Next() bool {
+	src.next++
+	if src.progress != nil && src.next > 0 && (src.next%copyProgressStep == 0 || src.next == len(src.subs)) {
+		src.progress(src.next)
+	}
+	return src.next < len(src.subs)
+}
+
+func (src *subsCopySource) Values() ([]any, error) {
+	sub := src.subs[src.next]
+	id := sub.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+	return []any{id, src.tenantID, sub.Name, sub.Price, sub.UserID, sub.StartDate, sub.EndDate}, nil
+}
+
+func (src *subsCopySource) Err() error {
+	return nil
+}
+
 // ReadSubscription — читает подписку по UUID из базы данных.
 // Возвращает DTO подписки или ошибку, если запись не найдена или произошла ошибка при запросе.
-func (s *SubsStorage) ReadSubscription(ctx context.Context, uuid uuid.UUID) (models.SubsDTO, error) {
+func (s *SubsStorage) ReadSubscription(ctx context.Context, tenantID, uuid uuid.UUID) (models.SubsDTO, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "ReadSubscription")
+
 	var sub models.SubsDTO
 
-	query := "select id, name, price, user_id, start_date, end_date from services where id=$1"
+	if err := requireTenant(tenantID); err != nil {
+		return sub, err
+	}
+
+	query := "select id, name, price, user_id, start_date, end_date from services where id=$1 and tenant_id=$2"
 
-	err := s.db.QueryRow(ctx, query, uuid).Scan(&sub.ID, &sub.Name, &sub.Price, &sub.UserID, &sub.StartDate, &sub.EndDate)
+	err := s.db.QueryRow(ctx, query, uuid, tenantID).Scan(&sub.ID, &sub.Name, &sub.Price, &sub.UserID, &sub.StartDate, &sub.EndDate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sub, ErrSubsNotFound
+	}
 	if err != nil {
 		return sub, fmt.Errorf("failed to select sub: %w", err)
 	}
 	return sub, nil
 }
 
+// ReadSubscriptionForUpdate — читает подписку по UUID, как ReadSubscription, и блокирует
+// её строку до конца транзакции, так что параллельные изменения подписки выполняются по очереди.
+// Имеет смысл только для хранилища, полученного в WithinTx.
+func (s *SubsStorage) ReadSubscriptionForUpdate(ctx context.Context, tenantID, uuid uuid.UUID) (models.SubsDTO, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "ReadSubscriptionForUpdate")
+
+	var sub models.SubsDTO
+
+	if err := requireTenant(tenantID); err != nil {
+		return sub, err
+	}
+
+	query := "select id, name, price, user_id, start_date, end_date from services where id=$1 and tenant_id=$2 for update"
+
+	err := s.db.QueryRow(ctx, query, uuid, tenantID).Scan(&sub.ID, &sub.Name, &sub.Price, &sub.UserID, &sub.StartDate, &sub.EndDate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sub, ErrSubsNotFound
+	}
+	if err != nil {
+		return sub, fmt.Errorf("failed to select sub for update: %w", err)
+	}
+	return sub, nil
+}
+
 // UpdateSubscription — обновляет существующую подписку по UUID.
 // Использует BuildUpdateQuery для генерации SQL-запроса и аргументов.
 // Возвращает ошибку, если не удалось обновить запись или аргументы пусты.
-func (s *SubsStorage) UpdateSubscription(ctx context.Context, uuid uuid.UUID, sub models.SubsUpdateDTO) error {
-	query, args := storage.BuildUpdateQuery(uuid, sub)
+func (s *SubsStorage) UpdateSubscription(ctx context.Context, tenantID, uuid uuid.UUID, sub models.SubsUpdateDTO) error {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "UpdateSubscription")
+
+	if err := requireTenant(tenantID); err != nil {
+		return err
+	}
+
+	query, args := storage.BuildUpdateQuery(uuid, tenantID, sub)
 	if args == nil {
 		return errors.New("empty args for update")
 	}
@@ -75,20 +235,277 @@ func (s *SubsStorage) UpdateSubscription(ctx context.Context, uuid uuid.UUID, su
 	}
 
 	if data.RowsAffected() == 0 {
-		return fmt.Errorf("failed to update service, 0 rows affected")
+		return fmt.Errorf("failed to update service: %w", ErrSubsNotFound)
 	}
 
 	return nil
 }
 
-// ReadAllSubscriptions — возвращает все подписки из базы данных.
+// ReadAllSubscriptions — возвращает подписки из базы данных, подходящие под фильтр.
+// Подписки упорядочены по дате начала и UUID, чтобы страницы не пересекались.
 // Конвертирует каждую запись в DTO и возвращает срез подписок.
-func (s *SubsStorage) ReadAllSubscriptions(ctx context.Context) ([]models.SubsDTO, error) {
+func (s *SubsStorage) ReadAllSubscriptions(ctx context.Context, tenantID uuid.UUID, filter models.SubsFilter) ([]models.SubsDTO, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "ReadAllSubscriptions")
+
 	var subs []models.SubsDTO
 
-	query := "select * from services"
+	if err := requireTenant(tenantID); err != nil {
+		return subs, err
+	}
 
-	rows, err := s.db.Query(ctx, query)
+	query, args := subsListQuery(tenantID, filter)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return subs, fmt.Errorf("failed to select subs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sub models.SubsDTO
+		err := rows.Scan(&sub.ID, &sub.Name, &sub.Price, &sub.UserID, &sub.StartDate, &sub.EndDate)
+		if err != nil {
+			return subs, fmt.Errorf("failed to scan sub: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return subs, fmt.Errorf("failed to read subs: %w", err)
+	}
+
+	return subs, nil
+}
+
+// streamFetchSize — сколько строк StreamSubscriptions читает из курсора за один FETCH.
+const streamFetchSize = 1000
+
+// StreamSubscriptions — читает подписки, подходящие под фильтр, через серверный курсор
+// и передаёт их fn по одной, не накапливая в памяти. Порядок тот же, что в ReadAllSubscriptions.
+// Курсор открывается в отдельной транзакции только для чтения с уровнем изоляции
+// repeatable read (даже у хранилища из WithinTx), поэтому все строки читаются из одного снимка данных.
+// Ошибка fn прекращает чтение и возвращается вызывающей стороне.
+func (s *SubsStorage) StreamSubscriptions(ctx context.Context, tenantID uuid.UUID, filter models.SubsFilter, fn func(models.SubsDTO) error) error {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "StreamSubscriptions")
+
+	if err := requireTenant(tenantID); err != nil {
+		return err
+	}
+
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("failed to begin stream: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query, args := subsListQuery(tenantID, filter)
+	if _, err := tx.Exec(ctx, "declare subs_stream no scroll cursor for "+query, args...); err != nil {
+		return fmt.Errorf("failed to declare cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf("fetch forward %d from subs_stream", streamFetchSize)
+	for {
+		rows, err := tx.Query(ctx, fetch)
+		if err != nil {
+			return fmt.Errorf("failed to fetch subs: %w", err)
+		}
+
+		fetched := 0
+		for rows.Next() {
+			fetched++
+			var sub models.SubsDTO
+			if err := rows.Scan(&sub.ID, &sub.Name, &sub.Price, &sub.UserID, &sub.StartDate, &sub.EndDate); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan sub: %w", err)
+			}
+			if err := fn(sub); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read subs: %w", err)
+		}
+		if fetched < streamFetchSize {
+			break
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to close stream: %w", err)
+	}
+
+	return nil
+}
+
+// subsListQuery — запрос подписок организации, подходящих под фильтр, и его аргументы.
+// Подписки упорядочены по дате начала и UUID.
+func subsListQuery(tenantID uuid.UUID, filter models.SubsFilter) (string, []any) {
+	where, args := subsFilterCondition(tenantID, filter)
+	query := `select id, name, price, user_id, start_date, end_date from services
+		where ` + where + `
+		order by start_date, id limit $6 offset $7`
+
+	var limit *int
+	if filter.Limit > 0 {
+		limit = &filter.Limit
+	}
+
+	return query, append(args, limit, filter.Offset)
+}
+
+// subsFilterCondition — условие отбора подписок организации по фильтру (без страницы)
+// и его аргументы $1–$5.
+func subsFilterCondition(tenantID uuid.UUID, filter models.SubsFilter) (string, []any) {
+	where := `tenant_id = $1 and ($2::uuid is null or user_id = $2) and ($3::uuid[] is null or user_id = any($3))
+		and ($4 = '' or name = $4) and ($5::uuid[] is null or id = any($5))`
+
+	var userIDs []uuid.UUID
+	if len(filter.UserIDs) > 0 {
+		userIDs = filter.UserIDs
+	}
+
+	var ids []uuid.UUID
+	if len(filter.IDs) > 0 {
+		ids = filter.IDs
+	}
+
+	return where, []any{tenantID, filter.UserID, userIDs, filter.ServiceName, ids}
+}
+
+// activeToday — условие, по которому подписка считается действующей на текущую дату.
+const activeToday = "start_date <= current_date and (end_date is null or end_date >= current_date)"
+
+// ReadSubscriptionsSummary — считает количество подписок организации, подходящих под фильтр,
+// количество действующих на текущую дату и их суммарную месячную стоимость одним
+// агрегирующим запросом. Страница фильтра не учитывается.
+func (s *SubsStorage) ReadSubscriptionsSummary(ctx context.Context, tenantID uuid.UUID, filter models.SubsFilter) (models.SubsSummary, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "ReadSubscriptionsSummary")
+
+	var summary models.SubsSummary
+
+	if err := requireTenant(tenantID); err != nil {
+		return summary, err
+	}
+
+	where, args := subsFilterCondition(tenantID, filter)
+	query := `select count(*), count(*) filter (where ` + activeToday + `),
+		coalesce(sum(price) filter (where ` + activeToday + `), 0)
+		from services where ` + where
+
+	if err := s.db.QueryRow(ctx, query, args...).Scan(&summary.Count, &summary.Active, &summary.MonthlySpend); err != nil {
+		return summary, fmt.Errorf("failed to read subs summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// ReadSubscriptionsSummaryByUsers — сводка ReadSubscriptionsSummary для каждого из пользователей
+// одним сгруппированным запросом. Пользователи без подписок в результат не попадают.
+func (s *SubsStorage) ReadSubscriptionsSummaryByUsers(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]models.SubsSummary, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "ReadSubscriptionsSummaryByUsers")
+
+	if err := requireTenant(tenantID); err != nil {
+		return nil, err
+	}
+
+	query := `select user_id, count(*), count(*) filter (where ` + activeToday + `),
+		coalesce(sum(price) filter (where ` + activeToday + `), 0)
+		from services where tenant_id = $1 and user_id = any($2)
+		group by user_id`
+
+	rows, err := s.db.Query(ctx, query, tenantID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subs summary: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := make(map[uuid.UUID]models.SubsSummary, len(userIDs))
+	for rows.Next() {
+		var (
+			userID  uuid.UUID
+			summary models.SubsSummary
+		)
+		if err := rows.Scan(&userID, &summary.Count, &summary.Active, &summary.MonthlySpend); err != nil {
+			return nil, fmt.Errorf("failed to scan subs summary: %w", err)
+		}
+		summaries[userID] = summary
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read subs summary: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// ReadSubscriptionsByUsers — возвращает страницу подписок каждого из пользователей
+// одним запросом: page ограничивает подписки каждого пользователя отдельно, а не всю выборку.
+// Пустое name — подписки на все услуги. Подписки пользователя упорядочены по дате начала и UUID.
+func (s *SubsStorage) ReadSubscriptionsByUsers(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID, name string, page models.Page) ([]models.SubsDTO, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "ReadSubscriptionsByUsers")
+
+	var subs []models.SubsDTO
+
+	if err := requireTenant(tenantID); err != nil {
+		return subs, err
+	}
+
+	query := `select id, name, price, user_id, start_date, end_date from (
+			select id, name, price, user_id, start_date, end_date,
+				row_number() over (partition by user_id order by start_date, id) as n
+			from services
+			where tenant_id = $1 and user_id = any($2) and ($3 = '' or name = $3)
+		) as ranked
+		where n > $4 and n <= $4 + $5
+		order by user_id, n`
+
+	rows, err := s.db.Query(ctx, query, tenantID, userIDs, name, page.Offset, page.Limit)
+	if err != nil {
+		return subs, fmt.Errorf("failed to select subs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sub models.SubsDTO
+		err := rows.Scan(&sub.ID, &sub.Name, &sub.Price, &sub.UserID, &sub.StartDate, &sub.EndDate)
+		if err != nil {
+			return subs, fmt.Errorf("failed to scan sub: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return subs, fmt.Errorf("failed to read subs: %w", err)
+	}
+
+	return subs, nil
+}
+
+// activeInPeriod — условие, по которому подписка может учитываться в стоимости за период:
+// она начинается не позже последнего дня периода ($3) и не заканчивается раньше его начала ($4).
+const activeInPeriod = "start_date < $3::date + interval '1 day' and (end_date is null or end_date >= $4)"
+
+// ReadSubscriptionsWithPeriod — возвращает подписки пользователей userIDs, которые могут
+// учитываться в стоимости за период (условие activeInPeriod); начисления по ним считает
+// сервисный слой. Пустое name — подписки на все услуги. Подписки упорядочены по дате
+// начала и UUID.
+func (s *SubsStorage) ReadSubscriptionsWithPeriod(ctx context.Context, tenantID uuid.UUID, from, to time.Time, userIDs []uuid.UUID, name string) ([]models.SubsDTO, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "ReadSubscriptionsWithPeriod")
+
+	var subs []models.SubsDTO
+
+	if err := requireTenant(tenantID); err != nil {
+		return subs, err
+	}
+
+	query := `select id, name, price, user_id, start_date, end_date from services
+		where tenant_id = $5 and user_id = any($1) and ($2 = '' or name = $2) and ` + activeInPeriod + `
+		order by start_date, id`
+
+	rows, err := s.db.Query(ctx, query, userIDs, name, to, from, tenantID)
 	if err != nil {
 		return subs, fmt.Errorf("failed to select subs: %w", err)
 	}
@@ -103,35 +520,143 @@ func (s *SubsStorage) ReadAllSubscriptions(ctx context.Context) ([]models.SubsDT
 		subs = append(subs, sub)
 	}
 
+	if err := rows.Err(); err != nil {
+		return subs, fmt.Errorf("failed to read subs: %w", err)
+	}
+
 	return subs, nil
 }
 
-// ReadPriceWithPeriod — вычисляет суммарную стоимость подписки за указанный период для конкретного пользователя и услуги.
-// Возвращает 0, если подписок в периоде нет.
-func (s *SubsStorage) ReadPriceWithPeriod(ctx context.Context, from, to time.Time, userID uuid.UUID, name string) (int, error) {
-	var price int
-	query := "select coalesce(sum(price),0) from services where user_id = $1 and name=$2 and start_date < $3 ::date + interval '1 day'  and (end_date is null or end_date >= $4)"
+// CountActiveSubscriptions — возвращает количество действующих (не завершённых) подписок пользователя.
+func (s *SubsStorage) CountActiveSubscriptions(ctx context.Context, tenantID, userID uuid.UUID) (int, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "CountActiveSubscriptions")
 
-	err := s.db.QueryRow(ctx, query, userID, name, to, from).Scan(&price)
+	var count int
+
+	if err := requireTenant(tenantID); err != nil {
+		return 0, err
+	}
+
+	query := "select count(*) from services where tenant_id = $1 and user_id = $2 and (end_date is null or end_date >= now())"
+
+	if err := s.db.QueryRow(ctx, query, tenantID, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count subs: %w", err)
+	}
+
+	return count, nil
+}
+
+// LockUserSubscriptions — блокирует создание подписок пользователей организации до конца
+// текущей транзакции (pg_advisory_xact_lock), чтобы параллельные транзакции не превысили
+// квоту, одновременно посчитав подписки и добавив новые. Блокировки берутся в порядке
+// идентификаторов пользователей, поэтому транзакции не блокируют друг друга взаимно.
+// Вне транзакции блокировка снимается сразу после запроса.
+func (s *SubsStorage) LockUserSubscriptions(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) error {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "LockUserSubscriptions")
+
+	if err := requireTenant(tenantID); err != nil {
+		return err
+	}
+
+	query := `select pg_advisory_xact_lock(hashtextextended('subscriptions:' || $1::text || ':' || u::text, 0))
+		from unnest($2::uuid[]) as u order by u`
+
+	if _, err := s.db.Exec(ctx, query, tenantID, userIDs); err != nil {
+		return fmt.Errorf("failed to lock user subscriptions: %w", err)
+	}
+
+	return nil
+}
+
+// CountActiveSubscriptionsByUsers — возвращает количество действующих подписок
+// нескольких пользователей одним сгруппированным запросом.
+// Пользователи без действующих подписок в результат не попадают.
+func (s *SubsStorage) CountActiveSubscriptionsByUsers(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "CountActiveSubscriptionsByUsers")
+
+	if err := requireTenant(tenantID); err != nil {
+		return nil, err
+	}
+
+	query := `select user_id, count(*) from services
+		where tenant_id = $1 and user_id = any($2) and (end_date is null or end_date >= now())
+		group by user_id`
+
+	rows, err := s.db.Query(ctx, query, tenantID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count subs: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]int, len(userIDs))
+	for rows.Next() {
+		var (
+			userID uuid.UUID
+			count  int
+		)
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan subs count: %w", err)
+		}
+		counts[userID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count subs: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ReadSubscriptionStats — возвращает количество и суммарную месячную стоимость
+// действующих подписок в разрезе организаций.
+// В отличие от остальных методов выполняется по всем организациям
+// и предназначен только для служебных метрик.
+func (s *SubsStorage) ReadSubscriptionStats(ctx context.Context) ([]models.SubsStats, error) {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "ReadSubscriptionStats")
+
+	var stats []models.SubsStats
+
+	query := "select tenant_id, count(*), coalesce(sum(price),0) from services where start_date <= now() and (end_date is null or end_date >= now()) group by tenant_id"
+
+	rows, err := s.db.Query(ctx, query)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read price: %w", err)
+		return stats, fmt.Errorf("failed to select subs stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var st models.SubsStats
+		if err := rows.Scan(&st.TenantID, &st.Active, &st.MonthlySpend); err != nil {
+			return stats, fmt.Errorf("failed to scan subs stats: %w", err)
+		}
+		stats = append(stats, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("failed to read subs stats: %w", err)
 	}
 
-	return price, nil
+	return stats, nil
 }
 
 // DeleteSubscriptions — удаляет подписку по UUID из базы данных.
 // Возвращает ошибку, если запись не найдена или произошла ошибка при удалении.
-func (s *SubsStorage) DeleteSubscriptions(ctx context.Context, uuid uuid.UUID) error {
-	query := "delete from services where id=$1"
+func (s *SubsStorage) DeleteSubscriptions(ctx context.Context, tenantID, uuid uuid.UUID) error {
+	ctx = postgres.WithOperation(ctx, "subscriptions", "DeleteSubscriptions")
+
+	if err := requireTenant(tenantID); err != nil {
+		return err
+	}
+
+	query := "delete from services where id=$1 and tenant_id=$2"
 
-	data, err := s.db.Exec(ctx, query, uuid)
+	data, err := s.db.Exec(ctx, query, uuid, tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to delete service: %w", err)
 	}
 
 	if data.RowsAffected() == 0 {
-		return fmt.Errorf("failed to delete service, 0 rows affected")
+		return fmt.Errorf("failed to delete service: %w", ErrSubsNotFound)
 	}
 
 	return nil
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/storage/postgres"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrTenantNotFound — организация отсутствует в базе данных.
+	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrTenantExists — организация с таким названием уже существует.
+	ErrTenantExists = errors.New("tenant already exists")
+	// errNoTenant — запрос к данным организации без указания организации.
+	errNoTenant = errors.New("tenant id is required")
+)
+
+// requireTenant — защищает от запросов без организации, которые могли бы
+// затронуть данные всех организаций.
+func requireTenant(tenantID uuid.UUID) error {
+	if tenantID == uuid.Nil {
+		return errNoTenant
+	}
+	return nil
+}
+
+// TenantsStorage — хранилище организаций и их настроек.
+type TenantsStorage struct {
+	db *pgxpool.Pool
+}
+
+// NewTenantsStorage — конструктор хранилища организаций.
+func NewTenantsStorage(db *pgxpool.Pool) *TenantsStorage {
+	return &TenantsStorage{
+		db: db,
+	}
+}
+
+// CreateTenant — создаёт организацию в таблице tenants.
+func (s *TenantsStorage) CreateTenant(ctx context.Context, tenant models.Tenant) (models.Tenant, error) {
+	ctx = postgres.WithOperation(ctx, "tenants", "CreateTenant")
+
+	query := "insert into tenants (name, default_currency) values ($1, $2) returning id, created_at"
+
+	err := s.db.QueryRow(ctx, query, tenant.Name, tenant.DefaultCurrency).Scan(&tenant.ID, &tenant.CreatedAt)
+	if isUniqueViolation(err) {
+		return tenant, ErrTenantExists
+	}
+	if err != nil {
+		return tenant, fmt.Errorf("failed to insert tenant: %w", err)
+	}
+
+	return tenant, nil
+}
+
+// ReadTenant — возвращает организацию по UUID.
+func (s *TenantsStorage) ReadTenant(ctx context.Context, id uuid.UUID) (models.Tenant, error) {
+	ctx = postgres.WithOperation(ctx, "tenants", "ReadTenant")
+
+	var tenant models.Tenant
+
+	query := "select id, name, default_currency, created_at from tenants where id=$1"
+
+	err := s.db.QueryRow(ctx, query, id).Scan(&tenant.ID, &tenant.Name, &tenant.DefaultCurrency, &tenant.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return tenant, ErrTenantNotFound
+	}
+	if err != nil {
+		return tenant, fmt.Errorf("failed to select tenant: %w", err)
+	}
+
+	return tenant, nil
+}
+
+// ReadTenants — возвращает все организации, упорядоченные по названию.
+func (s *TenantsStorage) ReadTenants(ctx context.Context) ([]models.Tenant, error) {
+	ctx = postgres.WithOperation(ctx, "tenants", "ReadTenants")
+
+	var tenants []models.Tenant
+
+	query := "select id, name, default_currency, created_at from tenants order by name"
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return tenants, fmt.Errorf("failed to select tenants: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tenant models.Tenant
+		if err := rows.Scan(&tenant.ID, &tenant.Name, &tenant.DefaultCurrency, &tenant.CreatedAt); err != nil {
+			return tenants, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return tenants, fmt.Errorf("failed to read tenants: %w", err)
+	}
+
+	return tenants, nil
+}
+
+// UpdateTenant — обновляет настройки организации. Поля со значением nil не изменяются.
+func (s *TenantsStorage) UpdateTenant(ctx context.Context, id uuid.UUID, req models.EditTenantRequest) error {
+	ctx = postgres.WithOperation(ctx, "tenants", "UpdateTenant")
+
+	query := "update tenants set name = coalesce($1, name), default_currency = coalesce($2, default_currency) where id=$3"
+
+	data, err := s.db.Exec(ctx, query, req.Name, req.DefaultCurrency, id)
+	if isUniqueViolation(err) {
+		return ErrTenantExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update tenant: %w", err)
+	}
+
+	if data.RowsAffected() == 0 {
+		return fmt.Errorf("failed to update tenant: %w", ErrTenantNotFound)
+	}
+
+	return nil
+}
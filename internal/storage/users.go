@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/storage/postgres"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Коды ошибок PostgreSQL.
+const (
+	uniqueViolation     = "23505" // нарушение уникальности
+	foreignKeyViolation = "23503" // нарушение внешнего ключа
+)
+
+var (
+	// ErrUserNotFound — пользователь отсутствует в базе данных.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUserExists — пользователь с таким email уже зарегистрирован в организации.
+	ErrUserExists = errors.New("user already exists")
+	// ErrRefreshTokenNotFound — refresh-токен отсутствует в базе данных.
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+)
+
+// UsersStorage — хранилище локальных учётных записей и refresh-токенов.
+type UsersStorage struct {
+	db *pgxpool.Pool
+}
+
+// NewUsersStorage — конструктор хранилища учётных записей.
+func NewUsersStorage(db *pgxpool.Pool) *UsersStorage {
+	return &UsersStorage{
+		db: db,
+	}
+}
+
+// CreateUser — создаёт учётную запись в таблице users с ролями user.Roles.
+func (s *UsersStorage) CreateUser(ctx context.Context, user models.UserDTO) (models.UserDTO, error) {
+	ctx = postgres.WithOperation(ctx, "users", "CreateUser")
+
+	if err := requireTenant(user.TenantID); err != nil {
+		return user, err
+	}
+
+	query := `insert into users (tenant_id, email, password_hash, roles)
+values ($1, $2, $3, $4)
+returning id, roles, created_at`
+
+	if user.Roles == nil {
+		user.Roles = []string{}
+	}
+
+	err := s.db.QueryRow(ctx, query, user.TenantID, user.Email, user.PasswordHash, user.Roles).Scan(&user.ID, &user.Roles, &user.CreatedAt)
+	if isUniqueViolation(err) {
+		return user, ErrUserExists
+	}
+	if isForeignKeyViolation(err) {
+		return user, ErrTenantNotFound
+	}
+	if err != nil {
+		return user, fmt.Errorf("failed to insert user: %w", err)
+	}
+
+	return user, nil
+}
+
+// ReadUserByEmail — находит учётную запись организации по email.
+func (s *UsersStorage) ReadUserByEmail(ctx context.Context, tenantID uuid.UUID, email string) (models.UserDTO, error) {
+	ctx = postgres.WithOperation(ctx, "users", "ReadUserByEmail")
+
+	if err := requireTenant(tenantID); err != nil {
+		return models.UserDTO{}, err
+	}
+
+	query := "select id, tenant_id, email, password_hash, roles, created_at from users where tenant_id=$1 and email=$2"
+	return scanUser(s.db.QueryRow(ctx, query, tenantID, email))
+}
+
+// ReadUser — находит учётную запись по UUID.
+func (s *UsersStorage) ReadUser(ctx context.Context, id uuid.UUID) (models.UserDTO, error) {
+	ctx = postgres.WithOperation(ctx, "users", "ReadUser")
+
+	query := "select id, tenant_id, email, password_hash, roles, created_at from users where id=$1"
+	return scanUser(s.db.QueryRow(ctx, query, id))
+}
+
+// UpdatePassword — заменяет хэш пароля пользователя.
+func (s *UsersStorage) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	ctx = postgres.WithOperation(ctx, "users", "UpdatePassword")
+
+	query := "update users set password_hash=$1, updated_at=now() where id=$2"
+
+	data, err := s.db.Exec(ctx, query, passwordHash, id)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if data.RowsAffected() == 0 {
+		return fmt.Errorf("failed to update password: %w", ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// CreateRefreshToken — сохраняет хэш выданного refresh-токена.
+func (s *UsersStorage) CreateRefreshToken(ctx context.Context, token models.RefreshTokenDTO) (uuid.UUID, error) {
+	ctx = postgres.WithOperation(ctx, "users", "CreateRefreshToken")
+
+	var ID uuid.UUID
+
+	query := "insert into refresh_tokens (user_id, token_hash, expires_at) values ($1, $2, $3) returning id"
+
+	err := s.db.QueryRow(ctx, query, token.UserID, token.TokenHash, token.ExpiresAt).Scan(&ID)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("failed to insert refresh token: %w", err)
+	}
+
+	return ID, nil
+}
+
+// ReadRefreshToken — находит refresh-токен по хэшу его значения.
+func (s *UsersStorage) ReadRefreshToken(ctx context.Context, hash string) (models.RefreshTokenDTO, error) {
+	ctx = postgres.WithOperation(ctx, "users", "ReadRefreshToken")
+
+	var token models.RefreshTokenDTO
+
+	query := "select id, user_id, token_hash, expires_at, revoked_at from refresh_tokens where token_hash=$1"
+
+	err := s.db.QueryRow(ctx, query, hash).Scan(&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &token.RevokedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return token, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return token, fmt.Errorf("failed to select refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// RevokeRefreshToken — отзывает действующий refresh-токен.
+// replacedBy указывает на токен, выданный взамен при ротации (может быть nil).
+// Возвращает ErrRefreshTokenNotFound, если токен уже был отозван, — это позволяет
+// обнаружить повторное использование токена при параллельной ротации.
+func (s *UsersStorage) RevokeRefreshToken(ctx context.Context, id uuid.UUID, replacedBy *uuid.UUID) error {
+	ctx = postgres.WithOperation(ctx, "users", "RevokeRefreshToken")
+
+	query := "update refresh_tokens set revoked_at=now(), replaced_by=$1 where id=$2 and revoked_at is null"
+
+	data, err := s.db.Exec(ctx, query, replacedBy, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	if data.RowsAffected() == 0 {
+		return fmt.Errorf("failed to revoke refresh token: %w", ErrRefreshTokenNotFound)
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens — отзывает все действующие refresh-токены пользователя.
+func (s *UsersStorage) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	ctx = postgres.WithOperation(ctx, "users", "RevokeUserRefreshTokens")
+
+	query := "update refresh_tokens set revoked_at=now() where user_id=$1 and revoked_at is null"
+
+	if _, err := s.db.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+// scanUser — читает строку таблицы users в DTO.
+func scanUser(row pgx.Row) (models.UserDTO, error) {
+	var user models.UserDTO
+
+	err := row.Scan(&user.ID, &user.TenantID, &user.Email, &user.PasswordHash, &user.Roles, &user.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return user, ErrUserNotFound
+	}
+	if err != nil {
+		return user, fmt.Errorf("failed to select user: %w", err)
+	}
+
+	return user, nil
+}
+
+// isUniqueViolation — проверяет, что ошибка вызвана нарушением ограничения уникальности.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// isForeignKeyViolation — проверяет, что ошибка вызвана ссылкой на несуществующую запись.
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation
+}
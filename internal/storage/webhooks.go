@@ -0,0 +1,397 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"online_subscription_service/internal/domain/models"
+	"online_subscription_service/internal/storage/postgres"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrWebhookNotFound — вебхук отсутствует в базе данных.
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrDeliveryNotFound — доставка отсутствует в базе данных.
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// webhookColumns — колонки таблицы webhooks в порядке scanWebhook.
+const webhookColumns = "id, tenant_id, url, event_types, secret, description, enabled, created_at, updated_at"
+
+// deliveryColumns — колонки таблицы webhook_deliveries в порядке scanDelivery.
+const deliveryColumns = "d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.response_status, d.last_error, d.created_at, d.finished_at"
+
+// WebhooksStorage — хранилище вебхуков и журнала доставок событий на них.
+// Вебхуки и доставки принадлежат организации; методы фоновой доставки
+// (EnqueueDeliveries, ClaimDeliveries и отметка результатов) работают со всеми организациями.
+type WebhooksStorage struct {
+	db *pgxpool.Pool
+}
+
+// NewWebhooksStorage — конструктор хранилища вебхуков.
+func NewWebhooksStorage(db *pgxpool.Pool) *WebhooksStorage {
+	return &WebhooksStorage{
+		db: db,
+	}
+}
+
+// CreateWebhook — сохраняет новый вебхук в таблице webhooks.
+// Возвращает UUID и даты создания и изменения вебхука.
+func (s *WebhooksStorage) CreateWebhook(ctx context.Context, hook models.WebhookDTO) (models.WebhookDTO, error) {
+	ctx = postgres.WithOperation(ctx, "webhooks", "CreateWebhook")
+
+	if err := requireTenant(hook.TenantID); err != nil {
+		return hook, err
+	}
+
+	query := "insert into webhooks (tenant_id, url, event_types, secret, description, enabled) values ($1, $2, $3, $4, $5, $6) returning id, created_at, updated_at"
+
+	err := s.db.QueryRow(ctx, query, hook.TenantID, hook.URL, eventTypeStrings(hook.EventTypes), hook.Secret, hook.Description, hook.Enabled).
+		Scan(&hook.ID, &hook.CreatedAt, &hook.UpdatedAt)
+	if err != nil {
+		return hook, fmt.Errorf("failed to insert webhook: %w", err)
+	}
+
+	return hook, nil
+}
+
+// ReadWebhooks — возвращает все вебхуки организации, начиная с самых новых.
+func (s *WebhooksStorage) ReadWebhooks(ctx context.Context, tenantID uuid.UUID) ([]models.WebhookDTO, error) {
+	ctx = postgres.WithOperation(ctx, "webhooks", "ReadWebhooks")
+
+	var hooks []models.WebhookDTO
+
+	if err := requireTenant(tenantID); err != nil {
+		return hooks, err
+	}
+
+	query := "select " + webhookColumns + " from webhooks where tenant_id=$1 order by created_at desc"
+
+	rows, err := s.db.Query(ctx, query, tenantID)
+	if err != nil {
+		return hooks, fmt.Errorf("failed to select webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return hooks, err
+		}
+		hooks = append(hooks, hook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return hooks, fmt.Errorf("failed to read webhooks: %w", err)
+	}
+
+	return hooks, nil
+}
+
+// ReadWebhook — возвращает вебхук организации по UUID.
+func (s *WebhooksStorage) ReadWebhook(ctx context.Context, tenantID, id uuid.UUID) (models.WebhookDTO, error) {
+	ctx = postgres.WithOperation(ctx, "webhooks", "ReadWebhook")
+
+	if err := requireTenant(tenantID); err != nil {
+		return models.WebhookDTO{}, err
+	}
+
+	query := "select " + webhookColumns + " from webhooks where id=$1 and tenant_id=$2"
+
+	hook, err := scanWebhook(s.db.QueryRow(ctx, query, id, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return hook, ErrWebhookNotFound
+	}
+
+	return hook, err
+}
+
+// UpdateWebhook — сохраняет изменённые поля вебхука.
+// Возвращает вебхук с новой датой изменения.
+func (s *WebhooksStorage) UpdateWebhook(ctx context.Context, hook models.WebhookDTO) (models.WebhookDTO, error) {
+	ctx = postgres.WithOperation(ctx, "webhooks", "UpdateWebhook")
+
+	if err := requireTenant(hook.TenantID); err != nil {
+		return hook, err
+	}
+
+	query := "update webhooks set url=$1, event_types=$2, secret=$3, description=$4, enabled=$5, updated_at=now() where id=$6 and tenant_id=$7 returning updated_at"
+
+	err := s.db.QueryRow(ctx, query, hook.URL, eventTypeStrings(hook.EventTypes), hook.Secret, hook.Description, hook.Enabled, hook.ID, hook.TenantID).
+		Scan(&hook.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return hook, fmt.Errorf("failed to update webhook: %w", ErrWebhookNotFound)
+	}
+	if err != nil {
+		return hook, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return hook, nil
+}
+
+// DeleteWebhook — удаляет вебхук организации вместе с журналом его доставок.
+func (s *WebhooksStorage) DeleteWebhook(ctx context.Context, tenantID, id uuid.UUID) error {
+	ctx = postgres.WithOperation(ctx, "webhooks", "DeleteWebhook")
+
+	if err := requireTenant(tenantID); err != nil {
+		return err
+	}
+
+	data, err := s.db.Exec(ctx, "delete from webhooks where id=$1 and tenant_id=$2", id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	if data.RowsAffected() == 0 {
+		return fmt.Errorf("failed to delete webhook: %w", ErrWebhookNotFound)
+	}
+
+	return nil
+}
+
+// EnqueueDeliveries — ставит событие в очередь доставки на все включённые вебхуки
+// его организации, подписанные на тип события. Повторная постановка того же события
+// игнорируется. Возвращает количество новых доставок.
+func (s *WebhooksStorage) EnqueueDeliveries(ctx context.Context, event models.Event) (int64, error) {
+	ctx = postgres.WithOperation(ctx, "webhooks", "EnqueueDeliveries")
+
+	if err := requireTenant(event.TenantID); err != nil {
+		return 0, err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	query := `insert into webhook_deliveries (webhook_id, tenant_id, event_id, event_type, payload)
+		select id, tenant_id, $2::uuid, $3::text, $4::jsonb from webhooks
+		where tenant_id = $1 and enabled and (cardinality(event_types) = 0 or $3::text = any(event_types))
+		on conflict (webhook_id, event_id) do nothing`
+
+	data, err := s.db.Exec(ctx, query, event.TenantID, event.ID, string(event.Type), payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+
+	return data.RowsAffected(), nil
+}
+
+// ReadDeliveries — возвращает журнал доставок вебхука организации, начиная с самых новых.
+func (s *WebhooksStorage) ReadDeliveries(ctx context.Context, tenantID, webhookID uuid.UUID, filter models.DeliveryFilter) ([]models.WebhookDelivery, error) {
+	ctx = postgres.WithOperation(ctx, "webhooks", "ReadDeliveries")
+
+	var deliveries []models.WebhookDelivery
+
+	if err := requireTenant(tenantID); err != nil {
+		return deliveries, err
+	}
+
+	query := "select " + deliveryColumns + " from webhook_deliveries d where d.webhook_id=$1 and d.tenant_id=$2 and ($3::text = '' or d.status = $3::text) order by d.created_at desc, d.id"
+	args := []any{webhookID, tenantID, string(filter.Status)}
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" limit $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" offset $%d", len(args))
+	}
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return deliveries, fmt.Errorf("failed to select webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return deliveries, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return deliveries, fmt.Errorf("failed to read webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// RedeliverDelivery — возвращает доставку вебхука организации в очередь для немедленной
+// отправки с новым набором попыток, в каком бы состоянии она ни была.
+func (s *WebhooksStorage) RedeliverDelivery(ctx context.Context, tenantID, webhookID, id uuid.UUID) (models.WebhookDelivery, error) {
+	ctx = postgres.WithOperation(ctx, "webhooks", "RedeliverDelivery")
+
+	if err := requireTenant(tenantID); err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	query := `update webhook_deliveries d set status = 'pending', attempts = 0, next_attempt_at = now(), finished_at = null
+		where d.id=$1 and d.webhook_id=$2 and d.tenant_id=$3 returning ` + deliveryColumns
+
+	d, err := scanDelivery(s.db.QueryRow(ctx, query, id, webhookID, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return d, ErrDeliveryNotFound
+	}
+
+	return d, err
+}
+
+// ClaimDeliveries — выбирает до limit доставок, срок попытки которых наступил, на включённые
+// вебхуки и откладывает их следующую попытку на lease. Пока попытка выполняется, доставку
+// не выберет другой экземпляр сервиса; если экземпляр не сохранил результат, попытка
+// будет повторена по истечении lease.
+func (s *WebhooksStorage) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.PendingDelivery, error) {
+	ctx = postgres.WithOperation(ctx, "webhooks", "ClaimDeliveries")
+
+	var deliveries []models.PendingDelivery
+
+	query := `with due as (
+			select d.id from webhook_deliveries d join webhooks w on w.id = d.webhook_id
+			where d.status = 'pending' and d.next_attempt_at <= now() and w.enabled
+			order by d.next_attempt_at limit $1 for update of d skip locked
+		)
+		update webhook_deliveries d set next_attempt_at = now() + $2::interval
+		from due, webhooks w where d.id = due.id and w.id = d.webhook_id
+		returning ` + deliveryColumns + `, w.url, w.secret`
+
+	rows, err := s.db.Query(ctx, query, limit, lease)
+	if err != nil {
+		return deliveries, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d models.PendingDelivery
+		if d.WebhookDelivery, err = scanDelivery(rows, &d.URL, &d.Secret); err != nil {
+			return deliveries, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return deliveries, fmt.Errorf("failed to read webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// MarkDeliverySucceeded — сохраняет успешную попытку доставки.
+// Доставка, которую за время попытки вернули в очередь вручную, не меняется.
+func (s *WebhooksStorage) MarkDeliverySucceeded(ctx context.Context, id uuid.UUID, attempts, responseStatus int) error {
+	ctx = postgres.WithOperation(ctx, "webhooks", "MarkDeliverySucceeded")
+
+	query := `update webhook_deliveries set status = 'succeeded', attempts = attempts + 1, response_status = $3,
+		last_error = null, finished_at = now() where id = $1 and attempts = $2 and status = 'pending'`
+
+	if _, err := s.db.Exec(ctx, query, id, attempts, responseStatus); err != nil {
+		return fmt.Errorf("failed to mark webhook delivery succeeded: %w", err)
+	}
+
+	return nil
+}
+
+// MarkDeliveryFailed — сохраняет неудачную попытку доставки и откладывает следующую
+// на retryIn; если dead, доставка больше не повторяется. responseStatus равен nil,
+// если ответ не получен. Доставка, которую за время попытки вернули в очередь вручную, не меняется.
+func (s *WebhooksStorage) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, attempts int, responseStatus *int, reason string, retryIn time.Duration, dead bool) error {
+	ctx = postgres.WithOperation(ctx, "webhooks", "MarkDeliveryFailed")
+
+	query := `update webhook_deliveries set attempts = attempts + 1, response_status = $3, last_error = $4,
+		next_attempt_at = now() + $5::interval,
+		status = case when $6 then 'dead' else 'pending' end,
+		finished_at = case when $6 then now() end
+		where id = $1 and attempts = $2 and status = 'pending'`
+
+	if _, err := s.db.Exec(ctx, query, id, attempts, responseStatus, reason, retryIn, dead); err != nil {
+		return fmt.Errorf("failed to mark webhook delivery failed: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFinishedDeliveries — удаляет успешные и исчерпавшие попытки доставки,
+// завершённые больше olderThan назад. Возвращает количество удалённых доставок.
+func (s *WebhooksStorage) DeleteFinishedDeliveries(ctx context.Context, olderThan time.Duration) (int64, error) {
+	ctx = postgres.WithOperation(ctx, "webhooks", "DeleteFinishedDeliveries")
+
+	data, err := s.db.Exec(ctx, "delete from webhook_deliveries where finished_at < now() - $1::interval", olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished webhook deliveries: %w", err)
+	}
+
+	return data.RowsAffected(), nil
+}
+
+// scanWebhook — читает строку таблицы webhooks в DTO.
+func scanWebhook(row pgx.Row) (models.WebhookDTO, error) {
+	var (
+		hook       models.WebhookDTO
+		eventTypes []string
+	)
+
+	err := row.Scan(&hook.ID, &hook.TenantID, &hook.URL, &eventTypes, &hook.Secret, &hook.Description, &hook.Enabled, &hook.CreatedAt, &hook.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return hook, err
+	}
+	if err != nil {
+		return hook, fmt.Errorf("failed to scan webhook: %w", err)
+	}
+
+	hook.EventTypes = make([]models.EventType, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		hook.EventTypes = append(hook.EventTypes, models.EventType(t))
+	}
+
+	return hook, nil
+}
+
+// scanDelivery — читает строку таблицы webhook_deliveries, а следующие за ней колонки — в extra.
+// Дата следующей попытки возвращается только для ожидающих доставок.
+func scanDelivery(row pgx.Row, extra ...any) (models.WebhookDelivery, error) {
+	var (
+		d                 models.WebhookDelivery
+		eventType, status string
+		lastError         *string
+		nextAttemptAt     time.Time
+	)
+
+	dest := []any{&d.ID, &d.WebhookID, &d.EventID, &eventType, &d.Payload, &status, &d.Attempts, &nextAttemptAt,
+		&d.ResponseStatus, &lastError, &d.CreatedAt, &d.FinishedAt}
+
+	err := row.Scan(append(dest, extra...)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return d, err
+	}
+	if err != nil {
+		return d, fmt.Errorf("failed to scan webhook delivery: %w", err)
+	}
+
+	d.EventType, d.Status = models.EventType(eventType), models.DeliveryStatus(status)
+	if lastError != nil {
+		d.LastError = *lastError
+	}
+	if d.Status == models.DeliveryPending {
+		d.NextAttemptAt = &nextAttemptAt
+	}
+
+	return d, nil
+}
+
+// eventTypeStrings — типы событий в виде строк для колонки text[].
+func eventTypeStrings(types []models.EventType) []string {
+	out := make([]string, 0, len(types))
+	for _, t := range types {
+		out = append(out, string(t))
+	}
+	return out
+}
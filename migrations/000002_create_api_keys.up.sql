@@ -0,0 +1,11 @@
+create table api_keys
+(
+    id         uuid primary key default uuid_generate_v4(),  -- уникальный идентификатор ключа
+    name       text         not null,                             -- название ключа (кому выдан)
+    prefix     text         not null,                             -- начало ключа для отображения в списке
+    key_hash   text         not null unique,                      -- SHA-256 от ключа в hex
+    scopes     text[]       not null default '{}',                -- разрешения ключа
+    created_at timestamp    not null default now(),               -- дата создания
+    expires_at timestamp    null,                                 -- дата истечения срока действия
+    revoked_at timestamp    null                                  -- дата отзыва
+);
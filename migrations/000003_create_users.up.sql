@@ -0,0 +1,22 @@
+create table users
+(
+    id            uuid primary key default uuid_generate_v4(),  -- уникальный идентификатор пользователя
+    email         text         not null unique,                      -- адрес электронной почты (логин)
+    password_hash text         not null,                             -- bcrypt-хэш пароля
+    roles         text[]       not null default '{}',                -- роли пользователя
+    created_at    timestamp    not null default now(),               -- дата регистрации
+    updated_at    timestamp    not null default now()                -- дата последнего изменения
+);
+
+create table refresh_tokens
+(
+    id          uuid primary key default uuid_generate_v4(),                  -- уникальный идентификатор токена
+    user_id     uuid         not null references users (id) on delete cascade, -- владелец токена
+    token_hash  text         not null unique,                                  -- SHA-256 от токена в hex
+    created_at  timestamp    not null default now(),                           -- дата выдачи
+    expires_at  timestamp    not null,                                         -- дата истечения срока действия
+    revoked_at  timestamp    null,                                             -- дата отзыва или ротации
+    replaced_by uuid         null                                              -- токен, выданный при ротации
+);
+
+create index refresh_tokens_user_id_idx on refresh_tokens (user_id);
@@ -0,0 +1,4 @@
+alter table api_keys drop column if exists tenant_id;
+alter table users drop column if exists tenant_id;
+alter table services drop column if exists tenant_id;
+drop table if exists tenants;
\ No newline at end of file
@@ -0,0 +1,22 @@
+create table tenants
+(
+    id               uuid primary key default uuid_generate_v4(),  -- уникальный идентификатор организации
+    name             text         not null unique,                      -- название организации
+    default_currency text         not null default 'RUB',               -- валюта по умолчанию (ISO 4217)
+    created_at       timestamp    not null default now()                -- дата создания
+);
+
+-- организация по умолчанию, к которой относятся все существующие данные
+insert into tenants (id, name) values ('00000000-0000-0000-0000-000000000001', 'default');
+
+alter table services add column tenant_id uuid not null default '00000000-0000-0000-0000-000000000001' references tenants (id);
+alter table services alter column tenant_id drop default;
+create index services_tenant_id_user_id_idx on services (tenant_id, user_id);
+
+alter table users add column tenant_id uuid not null default '00000000-0000-0000-0000-000000000001' references tenants (id);
+alter table users alter column tenant_id drop default;
+create index users_tenant_id_idx on users (tenant_id);
+
+alter table api_keys add column tenant_id uuid not null default '00000000-0000-0000-0000-000000000001' references tenants (id);
+alter table api_keys alter column tenant_id drop default;
+create index api_keys_tenant_id_idx on api_keys (tenant_id);
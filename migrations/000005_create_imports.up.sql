@@ -0,0 +1,18 @@
+create table imports
+(
+    id             uuid primary key default uuid_generate_v4(),  -- уникальный идентификатор задачи импорта
+    tenant_id      uuid         not null references tenants (id),     -- организация, в которую импортируются подписки
+    user_id        uuid         null,                                 -- пользователь, запустивший импорт (null для API-ключа)
+    status         text         not null,                             -- pending, running, succeeded или failed
+    dry_run        boolean      not null default false,               -- только проверка строк без записи
+    total_rows     integer      not null default 0,                   -- строк с данными в файле
+    processed_rows integer      not null default 0,                   -- проверенных строк
+    imported_rows  integer      not null default 0,                   -- записанных подписок
+    error_count    integer      not null default 0,                   -- всего ошибок в строках
+    errors         jsonb        not null default '[]',                -- первые ошибки в строках
+    error          text         null,                                 -- причина неудачи импорта
+    created_at     timestamp    not null default now(),               -- дата создания
+    finished_at    timestamp    null                                  -- дата завершения
+);
+
+create index imports_tenant_id_idx on imports (tenant_id);
@@ -0,0 +1,19 @@
+create table outbox
+(
+    id              bigserial primary key,                                -- порядковый номер события
+    event_id        uuid         not null unique,                          -- идентификатор события для дедупликации у получателей
+    tenant_id       uuid         not null references tenants (id),         -- организация подписки
+    subscription_id uuid         not null,                                 -- подписка, к которой относится событие
+    event_type      text         not null,                                 -- subscription.created, .updated, .deleted или .status_changed
+    payload         jsonb        not null,                                 -- данные события
+    created_at      timestamp    not null default now(),                   -- дата изменения подписки
+    published_at    timestamp    null,                                     -- дата публикации (null — ещё не опубликовано)
+    attempts        integer      not null default 0,                       -- неудачных попыток публикации
+    next_attempt_at timestamp    not null default now(),                   -- не раньше этой даты событие публикуется повторно
+    last_error      text         null                                      -- причина последней неудачной попытки
+);
+
+-- неопубликованные события в порядке записи и по подпискам
+create index outbox_pending_idx on outbox (id) where published_at is null;
+create index outbox_pending_subscription_idx on outbox (subscription_id, id) where published_at is null;
+create index outbox_published_at_idx on outbox (published_at) where published_at is not null;
@@ -0,0 +1,37 @@
+create table webhooks
+(
+    id          uuid primary key default uuid_generate_v4(),  -- уникальный идентификатор вебхука
+    tenant_id   uuid         not null references tenants (id),     -- организация, события которой доставляются
+    url         text         not null,                             -- адрес, на который отправляются события
+    event_types text[]       not null default '{}',                -- доставляемые типы событий (пустой — все)
+    secret      text         not null,                             -- секрет подписи HMAC-SHA256
+    description text         not null default '',                  -- назначение вебхука
+    enabled     boolean      not null default true,                -- отключённому вебхуку события не доставляются
+    created_at  timestamp    not null default now(),               -- дата создания
+    updated_at  timestamp    not null default now()                -- дата последнего изменения
+);
+
+create index webhooks_tenant_id_idx on webhooks (tenant_id);
+
+create table webhook_deliveries
+(
+    id              uuid primary key default uuid_generate_v4(),  -- уникальный идентификатор доставки
+    webhook_id      uuid         not null references webhooks (id) on delete cascade, -- вебхук получателя
+    tenant_id       uuid         not null references tenants (id),     -- организация вебхука
+    event_id        uuid         not null,                             -- доставляемое событие
+    event_type      text         not null,                             -- тип события
+    payload         jsonb        not null,                             -- тело запроса
+    status          text         not null default 'pending',           -- pending, succeeded или dead
+    attempts        integer      not null default 0,                   -- выполненных попыток доставки
+    next_attempt_at timestamp    not null default now(),               -- не раньше этой даты выполняется попытка
+    response_status integer      null,                                 -- HTTP-статус ответа на последнюю попытку
+    last_error      text         null,                                 -- причина последней неудачной попытки
+    created_at      timestamp    not null default now(),               -- дата постановки в очередь
+    finished_at     timestamp    null,                                 -- дата успешной доставки или исчерпания попыток
+    unique (webhook_id, event_id)
+);
+
+-- ожидающие доставки по сроку попытки, журнал вебхука и завершённые доставки для очистки
+create index webhook_deliveries_pending_idx on webhook_deliveries (next_attempt_at) where status = 'pending';
+create index webhook_deliveries_webhook_idx on webhook_deliveries (webhook_id, created_at);
+create index webhook_deliveries_finished_at_idx on webhook_deliveries (finished_at) where finished_at is not null;
@@ -0,0 +1,2 @@
+drop table if exists integration_events;
+drop table if exists subscription_links;
\ No newline at end of file
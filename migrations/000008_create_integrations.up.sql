@@ -0,0 +1,26 @@
+create table subscription_links
+(
+    provider        text         not null,                             -- провайдер, присылающий события
+    external_id     text         not null,                             -- идентификатор подписки у провайдера
+    tenant_id       uuid         not null references tenants (id),     -- организация подписки
+    subscription_id uuid         not null references services (id) on delete cascade, -- связанная подписка
+    created_at      timestamp    not null default now(),               -- дата связывания
+    primary key (provider, external_id),
+    unique (subscription_id, provider)
+);
+
+create table integration_events
+(
+    provider        text         not null,                             -- провайдер, приславший событие
+    event_id        text         not null,                             -- идентификатор события у провайдера
+    event_type      text         not null,                             -- тип события после сопоставления
+    external_id     text         not null,                             -- идентификатор подписки у провайдера
+    tenant_id       uuid         null,                                 -- организация связанной подписки
+    subscription_id uuid         null,                                 -- связанная подписка
+    status          text         not null,                             -- processing, applied, unchanged, ignored или failed
+    error           text         null,                                 -- причина, по которой событие не применено
+    attempts        integer      not null default 1,                   -- полученных копий события, обработка которых начиналась
+    received_at     timestamp    not null default now(),               -- дата начала последней обработки
+    processed_at    timestamp    null,                                 -- дата завершения обработки
+    primary key (provider, event_id)
+);
@@ -0,0 +1,2 @@
+alter table subscription_links drop constraint subscription_links_pkey;
+alter table subscription_links add primary key (provider, external_id);
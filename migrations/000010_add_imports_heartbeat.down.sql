@@ -0,0 +1,3 @@
+drop index if exists imports_unfinished_idx;
+
+alter table imports drop column if exists heartbeat_at;
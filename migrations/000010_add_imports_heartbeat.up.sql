@@ -0,0 +1,3 @@
+alter table imports add column heartbeat_at timestamp not null default now(); -- последняя отметка экземпляра, выполняющего задачу
+
+create index imports_unfinished_idx on imports (heartbeat_at) where status in ('pending', 'running');
@@ -0,0 +1,2 @@
+alter table users drop constraint users_email_key;
+alter table users add constraint users_tenant_id_email_key unique (tenant_id, email);
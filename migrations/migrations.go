@@ -0,0 +1,9 @@
+// Package migrations содержит SQL-миграции схемы БД, встроенные в бинарный файл.
+package migrations
+
+import "embed"
+
+// FS — файлы миграций в формате golang-migrate (<версия>_<название>.up.sql / .down.sql).
+//
+//go:embed *.sql
+var FS embed.FS
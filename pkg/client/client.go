@@ -0,0 +1,253 @@
+// Package client — типизированный клиент HTTP API сервиса подписок.
+//
+// Пример:
+//
+//	c, err := client.New("http://subscriptions:8080/api/v1", client.WithAPIKey(key))
+//	if err != nil {
+//		return err
+//	}
+//	for sub, err := range c.Subscriptions(ctx, client.ListOptions{Limit: 100}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(sub.Name, sub.Price)
+//	}
+//
+// Ошибки API возвращаются как *Error и сопоставляются с ErrNotFound, ErrForbidden
+// и другими ошибками Err* через errors.Is.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultTimeout — таймаут HTTP-клиента по умолчанию для одной попытки запроса.
+const defaultTimeout = 30 * time.Second
+
+// Client — клиент API сервиса подписок. Безопасен для одновременного использования.
+type Client struct {
+	baseURL   *url.URL
+	http      *http.Client
+	tokens    TokenSource
+	apiKey    string
+	tenantID  uuid.UUID
+	retry     RetryPolicy
+	userAgent string
+}
+
+// New — конструктор Client.
+// baseURL — адрес API вместе с префиксом версии, например http://localhost:8080/api/v1.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base url %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:   u,
+		http:      &http.Client{Timeout: defaultTimeout},
+		retry:     DefaultRetryPolicy,
+		userAgent: "online_subscription_service-go-client",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// do — выполняет запрос с повторными попытками и декодирует JSON-ответ в out.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	resp, err := c.roundTrip(ctx, method, path, query, "application/json", payload)
+	if err != nil {
+		return err
+	}
+	return decode(resp, out)
+}
+
+// roundTrip — выполняет запрос с повторными попытками и возвращает ответ последней попытки.
+// Тело ответа закрывает вызывающая сторона.
+func (c *Client) roundTrip(ctx context.Context, method, path string, query url.Values, contentType string, payload []byte) (*http.Response, error) {
+	delay := c.retry.InitialDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, path, query, contentType, payload)
+
+		wait, retry := c.shouldRetry(method, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			drain(resp)
+		}
+
+		if wait == 0 {
+			wait = jitter(delay)
+			delay = min(delay*2, c.retry.MaxDelay)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if err == nil {
+				err = ctx.Err()
+			}
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// send — выполняет одну попытку запроса.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, contentType string, payload []byte) (*http.Response, error) {
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	switch {
+	case c.apiKey != "":
+		req.Header.Set("X-API-Key", c.apiKey)
+	case c.tokens != nil:
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.tenantID != uuid.Nil {
+		req.Header.Set("X-Tenant-ID", c.tenantID.String())
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, u.Path, err)
+	}
+
+	return resp, nil
+}
+
+// shouldRetry — решает, нужна ли ещё одна попытка, и возвращает задержку
+// из Retry-After (0 — использовать экспоненциальную задержку).
+func (c *Client) shouldRetry(method string, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= c.retry.MaxAttempts {
+		return 0, false
+	}
+
+	idempotent := method != http.MethodPost
+
+	if err != nil {
+		// Отмена контекста и ошибки получения токена повторять бессмысленно.
+		var urlErr *url.Error
+		return 0, idempotent && errors.As(err, &urlErr) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		// Запрос отклонён до обработки, поэтому его можно повторить для любого метода.
+		return retryAfter(resp.Header, c.retry.MaxDelay), true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return retryAfter(resp.Header, c.retry.MaxDelay), idempotent
+	default:
+		return 0, false
+	}
+}
+
+// retryAfter — читает задержку из заголовков Retry-After или RateLimit-Reset
+// (в секундах) и ограничивает её сверху значением limit.
+func retryAfter(h http.Header, limit time.Duration) time.Duration {
+	for _, name := range []string{"Retry-After", "RateLimit-Reset"} {
+		if secs, err := strconv.Atoi(h.Get(name)); err == nil && secs > 0 {
+			return min(time.Duration(secs)*time.Second, limit)
+		}
+	}
+	return 0
+}
+
+// decode — проверяет статус ответа и декодирует тело в out.
+func decode(resp *http.Response, out any) error {
+	defer drain(resp)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		return newError(resp, data)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// newError — собирает *Error из ответа с ошибкой и его тела data.
+func newError(resp *http.Response, data []byte) *Error {
+	apiErr := &Error{StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-ID")}
+
+	var errResp ErrorResponse
+	if json.Unmarshal(data, &errResp) == nil && errResp.Error != "" {
+		apiErr.Message = errResp.Error
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+
+	return apiErr
+}
+
+// drain — дочитывает и закрывает тело ответа, чтобы соединение вернулось в пул.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	resp.Body.Close()
+}
+
+// jitter — возвращает случайную задержку в диапазоне [d/2, d],
+// чтобы клиенты не повторяли запросы одновременно.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
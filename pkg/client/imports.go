@@ -0,0 +1,119 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ImportOptions — параметры импорта подписок из файла.
+type ImportOptions struct {
+	Format     string         // csv или xlsx; пустой — по расширению имени файла
+	Mapping    *ImportMapping // nil — колонки называются как поля (service_name, price, ...)
+	DateFormat string         // например MM-YYYY; пустой — любой поддерживаемый сервером формат
+	Delimiter  rune           // разделитель полей CSV; 0 — запятая
+	DryRun     bool           // только проверить строки, ничего не записывая
+}
+
+// ExportOptions — формат, колонки и фильтр выгрузки подписок.
+// Нулевой Filter.Limit — все подходящие подписки.
+type ExportOptions struct {
+	Format  string   // csv (по умолчанию), ndjson или xlsx
+	Columns []string // пустой — все колонки в порядке id, service_name, price, user_id, start_date, end_date
+	Filter  ListOptions
+}
+
+// ImportSubscriptions — загружает файл с подписками (POST /subscriptions/import).
+// name — имя файла, по расширению которого сервер определяет формат, если он не указан.
+//
+// Небольшие файлы импортируются сразу, и возвращается завершённая задача. Большие
+// импортируются в фоне: у задачи нет FinishedAt, а прогресс можно узнать через GetImport.
+func (c *Client) ImportSubscriptions(ctx context.Context, name string, file io.Reader, opts ImportOptions) (ImportJob, error) {
+	var job ImportJob
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	var fields [][2]string
+	if opts.Format != "" {
+		fields = append(fields, [2]string{"format", opts.Format})
+	}
+	if opts.Mapping != nil {
+		mapping, err := json.Marshal(opts.Mapping)
+		if err != nil {
+			return job, fmt.Errorf("failed to encode mapping: %w", err)
+		}
+		fields = append(fields, [2]string{"mapping", string(mapping)})
+	}
+	if opts.DateFormat != "" {
+		fields = append(fields, [2]string{"date_format", opts.DateFormat})
+	}
+	if opts.Delimiter != 0 {
+		fields = append(fields, [2]string{"delimiter", string(opts.Delimiter)})
+	}
+	if opts.DryRun {
+		fields = append(fields, [2]string{"dry_run", strconv.FormatBool(opts.DryRun)})
+	}
+	for _, field := range fields {
+		if err := w.WriteField(field[0], field[1]); err != nil {
+			return job, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	// Файл читается в память целиком, чтобы запрос можно было повторить.
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		return job, fmt.Errorf("failed to encode request: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return job, fmt.Errorf("failed to read file: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return job, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	resp, err := c.roundTrip(ctx, http.MethodPost, "/subscriptions/import", nil, w.FormDataContentType(), body.Bytes())
+	if err != nil {
+		return job, err
+	}
+	err = decode(resp, &job)
+	return job, err
+}
+
+// GetImport — возвращает состояние, прогресс и ошибки в строках задачи импорта
+// (GET /subscriptions/import/{id}).
+func (c *Client) GetImport(ctx context.Context, id uuid.UUID) (ImportJob, error) {
+	var job ImportJob
+	err := c.do(ctx, http.MethodGet, "/subscriptions/import/"+id.String(), nil, nil, &job)
+	return job, err
+}
+
+// ExportSubscriptions — выгружает подписки в файл (GET /subscriptions/export) и возвращает
+// его содержимое, которое сервер передаёт по мере чтения из БД. Тело закрывает вызывающая
+// сторона. Таймаут HTTP-клиента ограничивает и чтение тела, поэтому для больших выгрузок
+// его стоит увеличить через WithHTTPClient.
+func (c *Client) ExportSubscriptions(ctx context.Context, opts ExportOptions) (io.ReadCloser, error) {
+	query := opts.Filter.query()
+	if opts.Format != "" {
+		query.Set("format", opts.Format)
+	}
+	if len(opts.Columns) > 0 {
+		query.Set("columns", strings.Join(opts.Columns, ","))
+	}
+
+	resp, err := c.roundTrip(ctx, http.MethodGet, "/subscriptions/export", query, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, decode(resp, nil)
+	}
+	return resp.Body, nil
+}
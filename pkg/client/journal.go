@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JournalOptions — период, фильтры и формат выгрузки журнала начислений.
+type JournalOptions struct {
+	From           time.Time         // первый день периода
+	To             time.Time         // последний день периода
+	Format         string            // ledger (по умолчанию), hledger или beancount
+	UserID         uuid.UUID         // uuid.Nil — вызывающий пользователь
+	ServiceName    string            // пустое — все услуги
+	Accounts       map[string]string // счета расходов услуг в дополнение к настройкам сервера (услуга → счёт)
+	PaymentAccount string            // пустой — счёт оплаты из настроек сервера
+	Currency       string            // пустая — валюта из настроек сервера
+	Open           bool              // добавить объявления счетов перед записями
+}
+
+// Journal — возвращает начисления по подпискам за период в виде журнала
+// простой текстовой бухгалтерии (GET /subscriptions/journal).
+func (c *Client) Journal(ctx context.Context, opts JournalOptions) ([]byte, error) {
+	query := url.Values{
+		"from": {opts.From.Format(time.DateOnly)},
+		"to":   {opts.To.Format(time.DateOnly)},
+	}
+	if opts.Format != "" {
+		query.Set("format", opts.Format)
+	}
+	if opts.UserID != uuid.Nil {
+		query.Set("user_id", opts.UserID.String())
+	}
+	if opts.ServiceName != "" {
+		query.Set("service_name", opts.ServiceName)
+	}
+	services := make([]string, 0, len(opts.Accounts))
+	for service := range opts.Accounts {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	for _, service := range services {
+		query.Add("account", service+"="+opts.Accounts[service])
+	}
+	if opts.PaymentAccount != "" {
+		query.Set("payment_account", opts.PaymentAccount)
+	}
+	if opts.Currency != "" {
+		query.Set("currency", opts.Currency)
+	}
+	if opts.Open {
+		query.Set("open", strconv.FormatBool(opts.Open))
+	}
+
+	resp, err := c.roundTrip(ctx, http.MethodGet, "/subscriptions/journal", query, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, decode(resp, nil)
+	}
+	defer drain(resp)
+
+	return io.ReadAll(resp.Body)
+}
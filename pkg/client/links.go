@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// ListLinks — возвращает идентификаторы подписки у провайдеров (GET /subscriptions/{id}/links).
+func (c *Client) ListLinks(ctx context.Context, id uuid.UUID) ([]SubscriptionLink, error) {
+	var links []SubscriptionLink
+	err := c.do(ctx, http.MethodGet, "/subscriptions/"+id.String()+"/links", nil, nil, &links)
+	return links, err
+}
+
+// LinkSubscription — связывает подписку с её идентификатором externalID у провайдера,
+// чтобы вебхуки провайдера изменяли подписку (PUT /subscriptions/{id}/links/{provider}).
+// Прежний идентификатор у того же провайдера заменяется; если externalID уже связан
+// с другой подпиской, возвращается ошибка, совпадающая с ErrConflict.
+func (c *Client) LinkSubscription(ctx context.Context, id uuid.UUID, provider, externalID string) (SubscriptionLink, error) {
+	var link SubscriptionLink
+	req := LinkSubscriptionRequest{ExternalID: externalID}
+	err := c.do(ctx, http.MethodPut, "/subscriptions/"+id.String()+"/links/"+url.PathEscape(provider), nil, req, &link)
+	return link, err
+}
+
+// UnlinkSubscription — удаляет связь подписки с провайдером
+// (DELETE /subscriptions/{id}/links/{provider}).
+func (c *Client) UnlinkSubscription(ctx context.Context, id uuid.UUID, provider string) error {
+	return c.do(ctx, http.MethodDelete, "/subscriptions/"+id.String()+"/links/"+url.PathEscape(provider), nil, nil, nil)
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenSource — источник bearer-токенов; вызывается перед каждой попыткой запроса,
+// поэтому может обновлять истёкшие токены.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc — функция, реализующая TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token — вызывает f.
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// RetryPolicy — параметры повторных попыток.
+// Повторяются запросы, отклонённые ограничением частоты (429), а для идемпотентных
+// методов также сетевые ошибки и ответы 502, 503 и 504. Задержка растёт
+// экспоненциально от InitialDelay до MaxDelay; заголовок Retry-After имеет приоритет.
+type RetryPolicy struct {
+	MaxAttempts  int // общее число попыток, включая первую; 1 отключает повторы
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+// DefaultRetryPolicy — политика повторов по умолчанию.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:  3,
+	InitialDelay: 200 * time.Millisecond,
+	MaxDelay:     5 * time.Second,
+}
+
+// Option — настройка клиента.
+type Option func(*Client)
+
+// WithHTTPClient — задаёт HTTP-клиент, например с собственным транспортом или таймаутом.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithToken — аутентифицирует запросы постоянным bearer-токеном.
+func WithToken(token string) Option {
+	return WithTokenSource(TokenSourceFunc(func(context.Context) (string, error) {
+		return token, nil
+	}))
+}
+
+// WithTokenSource — аутентифицирует запросы bearer-токенами из ts.
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *Client) {
+		c.tokens = ts
+	}
+}
+
+// WithAPIKey — аутентифицирует запросы API-ключом (заголовок X-API-Key).
+// Если заданы и ключ, и токен, используется ключ.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithTenant — выполняет запросы в указанной организации (заголовок X-Tenant-ID).
+func WithTenant(tenantID uuid.UUID) Option {
+	return func(c *Client) {
+		c.tenantID = tenantID
+	}
+}
+
+// WithRetry — задаёт политику повторных попыток.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithUserAgent — задаёт заголовок User-Agent.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
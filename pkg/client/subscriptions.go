@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxBatchResponseSize — ограничение размера ответа пакетного запроса с ошибкой,
+// в котором есть итоги всех операций.
+const maxBatchResponseSize = 1 << 22
+
+// ListOptions — фильтры и страница списка подписок.
+// Нулевой Limit — размер страницы сервера по умолчанию (100, а при фильтре по IDs — все они);
+// все подписки перебирает Subscriptions.
+type ListOptions struct {
+	IDs         []uuid.UUID // только подписки с этими UUID (не больше MaxPageLimit)
+	UserID      uuid.UUID   // uuid.Nil — подписки всех доступных пользователей
+	ServiceName string
+	Limit       int
+	Offset      int
+}
+
+// ListSubscriptions — возвращает страницу подписок, доступных вызывающей стороне,
+// упорядоченных по дате начала (GET /subscriptions).
+func (c *Client) ListSubscriptions(ctx context.Context, opts ListOptions) ([]Subscription, error) {
+	var subs []Subscription
+	err := c.do(ctx, http.MethodGet, "/subscriptions", opts.query(), nil, &subs)
+	return subs, err
+}
+
+// query — параметры запроса с фильтрами и страницей списка подписок.
+func (opts ListOptions) query() url.Values {
+	query := url.Values{}
+	if len(opts.IDs) > 0 {
+		ids := make([]string, len(opts.IDs))
+		for i, id := range opts.IDs {
+			ids[i] = id.String()
+		}
+		query.Set("ids", strings.Join(ids, ","))
+	}
+	if opts.UserID != uuid.Nil {
+		query.Set("user_id", opts.UserID.String())
+	}
+	if opts.ServiceName != "" {
+		query.Set("service_name", opts.ServiceName)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	return query
+}
+
+// Subscriptions — перебирает подписки, подходящие под фильтры opts, начиная с opts.Offset
+// и запрашивая их страницами по opts.Limit (0 — MaxPageLimit).
+// Перебор останавливается после первой ошибки, которая передаётся вторым значением.
+func (c *Client) Subscriptions(ctx context.Context, opts ListOptions) iter.Seq2[Subscription, error] {
+	pageSize := opts.Limit
+	if pageSize <= 0 || pageSize > MaxPageLimit {
+		pageSize = MaxPageLimit
+	}
+
+	return func(yield func(Subscription, error) bool) {
+		for offset := opts.Offset; ; offset += pageSize {
+			page, err := c.ListSubscriptions(ctx, ListOptions{
+				IDs:         opts.IDs,
+				UserID:      opts.UserID,
+				ServiceName: opts.ServiceName,
+				Limit:       pageSize,
+				Offset:      offset,
+			})
+			if err != nil {
+				yield(Subscription{}, err)
+				return
+			}
+
+			for _, sub := range page {
+				if !yield(sub, nil) {
+					return
+				}
+			}
+
+			if len(page) < pageSize {
+				return
+			}
+		}
+	}
+}
+
+// SearchSubscriptions — возвращает подписки, подходящие под условия req, одним запросом
+// (POST /subscriptions/search). В отличие от ListSubscriptions списки UUID передаются
+// в теле запроса и не ограничены длиной URL.
+func (c *Client) SearchSubscriptions(ctx context.Context, req SearchRequest) ([]Subscription, error) {
+	var subs []Subscription
+	err := c.do(ctx, http.MethodPost, "/subscriptions/search", nil, req, &subs)
+	return subs, err
+}
+
+// GetSubscription — возвращает подписку по UUID (GET /subscriptions/{id}).
+func (c *Client) GetSubscription(ctx context.Context, id uuid.UUID) (Subscription, error) {
+	var sub Subscription
+	err := c.do(ctx, http.MethodGet, "/subscriptions/"+id.String(), nil, nil, &sub)
+	return sub, err
+}
+
+// CreateSubscription — создаёт подписку и возвращает её UUID (POST /subscriptions).
+// Если UserID не указан, подписка создаётся для вызывающего пользователя.
+func (c *Client) CreateSubscription(ctx context.Context, req CreateRequest) (uuid.UUID, error) {
+	var resp createResponse
+	err := c.do(ctx, http.MethodPost, "/subscriptions", nil, req, &resp)
+	return resp.ID, err
+}
+
+// EditSubscription — изменяет заполненные поля подписки (PATCH /subscriptions/{id}).
+func (c *Client) EditSubscription(ctx context.Context, id uuid.UUID, req EditRequest) error {
+	return c.do(ctx, http.MethodPatch, "/subscriptions/"+id.String(), nil, req, nil)
+}
+
+// DeleteSubscription — удаляет подписку (DELETE /subscriptions/{id}).
+func (c *Client) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/subscriptions/"+id.String(), nil, nil, nil)
+}
+
+// Price — возвращает стоимость подписки пользователя на сервис за период
+// (GET /subscriptions/price).
+func (c *Client) Price(ctx context.Context, req PriceRequest) (int, error) {
+	query := url.Values{
+		"from":         {req.From.Format(time.DateOnly)},
+		"to":           {req.To.Format(time.DateOnly)},
+		"user_id":      {req.UserID.String()},
+		"service_name": {req.Name},
+	}
+
+	var resp priceResponse
+	err := c.do(ctx, http.MethodGet, "/subscriptions/price", query, nil, &resp)
+	return resp.Price, err
+}
+
+// Prices — возвращает стоимость подписок на сервис за период для нескольких
+// пользователей одним запросом (POST /subscriptions/prices). В результате есть
+// все запрошенные пользователи; без подписок в периоде стоимость равна 0.
+func (c *Client) Prices(ctx context.Context, from, to time.Time, serviceName string, userIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	req := BulkPriceRequest{
+		From:        from.Format(time.DateOnly),
+		To:          to.Format(time.DateOnly),
+		ServiceName: serviceName,
+		UserIDs:     userIDs,
+	}
+
+	var resp BulkPriceResponse
+	err := c.do(ctx, http.MethodPost, "/subscriptions/prices", nil, req, &resp)
+	return resp.Prices, err
+}
+
+// BatchSubscriptions — создаёт, изменяет и удаляет подписки одним запросом
+// (POST /subscriptions/batch).
+//
+// Итог каждой операции возвращается и вместе с ошибкой: в режиме atomic при ошибке операции
+// сервер откатывает транзакцию и отвечает статусом этой ошибки, а ошибка *Error содержит
+// её текст. В режиме best_effort ошибки операций есть только в итогах.
+func (c *Client) BatchSubscriptions(ctx context.Context, req BatchRequest) (BatchResult, error) {
+	var result BatchResult
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return result, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	resp, err := c.roundTrip(ctx, http.MethodPost, "/subscriptions/batch", nil, "application/json", payload)
+	if err != nil {
+		return result, err
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		err := decode(resp, &result)
+		return result, err
+	}
+
+	defer drain(resp)
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxBatchResponseSize))
+	apiErr := newError(resp, data)
+	if json.Unmarshal(data, &result) == nil {
+		for _, res := range result.Results {
+			if res.Error != "" {
+				apiErr.Message = res.Error
+				break
+			}
+		}
+	}
+	return result, apiErr
+}
@@ -0,0 +1,416 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// swaggerSpec — части docs/swagger.json, которые сверяются с клиентом.
+type swaggerSpec struct {
+	BasePath    string                          `json:"basePath"`
+	Paths       map[string]map[string]swaggerOp `json:"paths"`
+	Definitions map[string]swaggerSchema        `json:"definitions"`
+}
+
+type swaggerOp struct {
+	Parameters []swaggerParam `json:"parameters"`
+	Responses  map[string]struct {
+		Schema *swaggerSchema `json:"schema"`
+	} `json:"responses"`
+}
+
+type swaggerParam struct {
+	Name   string         `json:"name"`
+	In     string         `json:"in"`
+	Schema *swaggerSchema `json:"schema"`
+}
+
+type swaggerSchema struct {
+	Ref        string                   `json:"$ref"`
+	Type       string                   `json:"type"`
+	Items      *swaggerSchema           `json:"items"`
+	Properties map[string]swaggerSchema `json:"properties"`
+}
+
+// recordedRequest — запрос клиента, полученный тестовым сервером.
+type recordedRequest struct {
+	method string
+	path   string   // путь без basePath
+	params []string // параметры запроса и поля формы
+}
+
+// TestClientMatchesSwagger — проверяет, что каждый метод клиента обращается к маршруту
+// из docs/swagger.json с описанными там параметрами, а поля JSON его запросов и ответов
+// совпадают с моделями swagger. Если тест упал после изменения API, обновите клиент
+// или перегенерируйте документацию (swag init).
+func TestClientMatchesSwagger(t *testing.T) {
+	spec := loadSwagger(t)
+
+	var got recordedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = recordedRequest{method: r.Method, path: strings.TrimPrefix(r.URL.Path, spec.BasePath)}
+		for name := range r.URL.Query() {
+			got.params = append(got.params, name)
+		}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("failed to parse multipart form: %v", err)
+			} else {
+				for name := range r.MultipartForm.Value {
+					got.params = append(got.params, name)
+				}
+				for name := range r.MultipartForm.File {
+					got.params = append(got.params, name)
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, "null")
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL+spec.BasePath, WithRetry(RetryPolicy{MaxAttempts: 1}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	id := uuid.New()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	list := ListOptions{IDs: []uuid.UUID{id}, UserID: id, ServiceName: "Yandex Plus", Limit: 10, Offset: 10}
+
+	tests := []struct {
+		name     string
+		call     func(ctx context.Context) error
+		request  any // тело запроса в JSON; nil — без тела
+		response any // тело успешного ответа в JSON; nil — не декодируется
+	}{
+		{
+			name: "ListSubscriptions",
+			call: func(ctx context.Context) error {
+				_, err := c.ListSubscriptions(ctx, list)
+				return err
+			},
+			response: []Subscription{},
+		},
+		{
+			name: "SearchSubscriptions",
+			call: func(ctx context.Context) error {
+				_, err := c.SearchSubscriptions(ctx, SearchRequest{})
+				return err
+			},
+			request:  SearchRequest{},
+			response: []Subscription{},
+		},
+		{
+			name: "GetSubscription",
+			call: func(ctx context.Context) error {
+				_, err := c.GetSubscription(ctx, id)
+				return err
+			},
+			response: Subscription{},
+		},
+		{
+			name: "CreateSubscription",
+			call: func(ctx context.Context) error {
+				_, err := c.CreateSubscription(ctx, CreateRequest{})
+				return err
+			},
+			request:  CreateRequest{},
+			response: createResponse{},
+		},
+		{
+			name:    "EditSubscription",
+			call:    func(ctx context.Context) error { return c.EditSubscription(ctx, id, EditRequest{}) },
+			request: EditRequest{},
+		},
+		{
+			name: "DeleteSubscription",
+			call: func(ctx context.Context) error { return c.DeleteSubscription(ctx, id) },
+		},
+		{
+			name: "Price",
+			call: func(ctx context.Context) error {
+				_, err := c.Price(ctx, PriceRequest{From: from, To: to, UserID: id, Name: "Yandex Plus"})
+				return err
+			},
+			response: priceResponse{},
+		},
+		{
+			name: "Prices",
+			call: func(ctx context.Context) error {
+				_, err := c.Prices(ctx, from, to, "Yandex Plus", []uuid.UUID{id})
+				return err
+			},
+			request:  BulkPriceRequest{},
+			response: BulkPriceResponse{},
+		},
+		{
+			name: "BatchSubscriptions",
+			call: func(ctx context.Context) error {
+				_, err := c.BatchSubscriptions(ctx, BatchRequest{Mode: BatchAtomic})
+				return err
+			},
+			request:  BatchRequest{},
+			response: BatchResult{},
+		},
+		{
+			name: "ImportSubscriptions",
+			call: func(ctx context.Context) error {
+				_, err := c.ImportSubscriptions(ctx, "subs.csv", strings.NewReader("service_name\n"), ImportOptions{
+					Format:     "csv",
+					Mapping:    &ImportMapping{ServiceName: "Сервис"},
+					DateFormat: "MM-YYYY",
+					Delimiter:  ';',
+					DryRun:     true,
+				})
+				return err
+			},
+			response: ImportJob{},
+		},
+		{
+			name: "GetImport",
+			call: func(ctx context.Context) error {
+				_, err := c.GetImport(ctx, id)
+				return err
+			},
+			response: ImportJob{},
+		},
+		{
+			name: "ExportSubscriptions",
+			call: func(ctx context.Context) error {
+				body, err := c.ExportSubscriptions(ctx, ExportOptions{Format: "csv", Columns: []string{"id"}, Filter: list})
+				if err == nil {
+					body.Close()
+				}
+				return err
+			},
+		},
+		{
+			name: "Journal",
+			call: func(ctx context.Context) error {
+				_, err := c.Journal(ctx, JournalOptions{
+					From:           from,
+					To:             to,
+					Format:         "beancount",
+					UserID:         id,
+					ServiceName:    "Yandex Plus",
+					Accounts:       map[string]string{"Yandex Plus": "Expenses:Yandex"},
+					PaymentAccount: "Assets:Bank",
+					Currency:       "RUB",
+					Open:           true,
+				})
+				return err
+			},
+		},
+		{
+			name: "ListLinks",
+			call: func(ctx context.Context) error {
+				_, err := c.ListLinks(ctx, id)
+				return err
+			},
+			response: []SubscriptionLink{},
+		},
+		{
+			name: "LinkSubscription",
+			call: func(ctx context.Context) error {
+				_, err := c.LinkSubscription(ctx, id, "stripe", "sub_1")
+				return err
+			},
+			request:  LinkSubscriptionRequest{},
+			response: SubscriptionLink{},
+		},
+		{
+			name: "UnlinkSubscription",
+			call: func(ctx context.Context) error { return c.UnlinkSubscription(ctx, id, "stripe") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = recordedRequest{}
+			if err := tt.call(context.Background()); err != nil {
+				t.Fatalf("call error = %v", err)
+			}
+
+			route, op, ok := spec.match(got.method, got.path)
+			if !ok {
+				t.Fatalf("%s %s is not described in swagger", got.method, got.path)
+			}
+
+			var body *swaggerSchema
+			for _, param := range op.Parameters {
+				if param.In == "body" {
+					body = param.Schema
+				}
+			}
+			for _, name := range got.params {
+				if !slices.ContainsFunc(op.Parameters, func(p swaggerParam) bool {
+					return p.Name == name && (p.In == "query" || p.In == "formData")
+				}) {
+					t.Errorf("%s %s: parameter %q is not described in swagger", got.method, route, name)
+				}
+			}
+
+			switch {
+			case tt.request != nil && body == nil:
+				t.Errorf("%s %s: swagger has no request body", got.method, route)
+			case tt.request != nil:
+				spec.compare(t, "request", reflect.TypeOf(tt.request), *body)
+			}
+
+			if tt.response != nil {
+				schema := op.successSchema()
+				if schema == nil {
+					t.Fatalf("%s %s: swagger has no success response schema", got.method, route)
+				}
+				spec.compare(t, "response", reflect.TypeOf(tt.response), *schema)
+			}
+		})
+	}
+}
+
+// loadSwagger — читает docs/swagger.json.
+func loadSwagger(t *testing.T) swaggerSpec {
+	t.Helper()
+
+	data, err := os.ReadFile("../../docs/swagger.json")
+	if err != nil {
+		t.Fatalf("failed to read swagger: %v", err)
+	}
+
+	var spec swaggerSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("failed to parse swagger: %v", err)
+	}
+	return spec
+}
+
+// match — находит маршрут swagger для запроса. Из подходящих шаблонов выбирается тот,
+// в котором больше совпавших постоянных сегментов (/subscriptions/import/{id},
+// а не /subscriptions/{id}/...).
+func (s swaggerSpec) match(method, path string) (string, swaggerOp, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var (
+		route string
+		op    swaggerOp
+		best  = -1
+	)
+	for tmpl, ops := range s.Paths {
+		candidate, ok := ops[strings.ToLower(method)]
+		if !ok {
+			continue
+		}
+		parts := strings.Split(strings.Trim(tmpl, "/"), "/")
+		if len(parts) != len(segments) {
+			continue
+		}
+		literals := 0
+		for i, part := range parts {
+			if strings.HasPrefix(part, "{") {
+				continue
+			}
+			if part != segments[i] {
+				literals = -1
+				break
+			}
+			literals++
+		}
+		if literals > best {
+			route, op, best = tmpl, candidate, literals
+		}
+	}
+
+	return route, op, best >= 0
+}
+
+// successSchema — схема ответа с наименьшим статусом 2xx.
+func (op swaggerOp) successSchema() *swaggerSchema {
+	for _, code := range []string{"200", "201", "202"} {
+		if resp, ok := op.Responses[code]; ok && resp.Schema != nil {
+			return resp.Schema
+		}
+	}
+	return nil
+}
+
+// resolve — раскрывает ссылку на определение swagger.
+func (s swaggerSpec) resolve(schema swaggerSchema) swaggerSchema {
+	if name, ok := strings.CutPrefix(schema.Ref, "#/definitions/"); ok {
+		return s.Definitions[name]
+	}
+	return schema
+}
+
+// compare — сверяет поля JSON типа t с объектом swagger schema, рекурсивно для вложенных
+// объектов и массивов.
+func (s swaggerSpec) compare(t *testing.T, where string, typ reflect.Type, schema swaggerSchema) {
+	t.Helper()
+
+	schema = s.resolve(schema)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ.Kind() == reflect.Slice && schema.Type == "array" && schema.Items != nil:
+		s.compare(t, where+"[]", typ.Elem(), *schema.Items)
+		return
+	case typ.Kind() != reflect.Struct || typ == reflect.TypeFor[time.Time]() || schema.Properties == nil:
+		return
+	}
+
+	fields := jsonFields(typ)
+	for name, field := range fields {
+		prop, ok := schema.Properties[name]
+		if !ok {
+			t.Errorf("%s: field %q of %s is not described in swagger", where, name, typ)
+			continue
+		}
+		s.compare(t, where+"."+name, field, prop)
+	}
+	for name := range schema.Properties {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("%s: swagger field %q is missing in %s", where, name, typ)
+		}
+	}
+}
+
+// jsonFields — поля типа-структуры по именам в JSON, включая поля встроенных структур.
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for n, f := range jsonFields(field.Type) {
+				fields[n] = f
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
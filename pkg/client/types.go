@@ -0,0 +1,68 @@
+package client
+
+import (
+	"online_subscription_service/internal/domain/models"
+
+	"github.com/google/uuid"
+)
+
+// Типы запросов и ответов совпадают с моделями сервиса, поэтому клиент
+// не может разойтись с сервером в именах полей JSON.
+type (
+	// Subscription — подписка.
+	Subscription = models.Subs
+	// CreateRequest — запрос на создание подписки.
+	CreateRequest = models.AddSubRequest
+	// EditRequest — запрос на изменение подписки; nil-поля не изменяются.
+	EditRequest = models.EditSubRequest
+	// PriceRequest — запрос стоимости подписки за период.
+	PriceRequest = models.PricePeriodRequest
+	// SearchRequest — условия поиска подписок; пустые поля не ограничивают выборку.
+	SearchRequest = models.SubsSearchRequest
+	// BulkPriceRequest — запрос стоимости подписок за период для нескольких пользователей.
+	BulkPriceRequest = models.BulkPriceRequest
+	// BulkPriceResponse — стоимость подписок за период по пользователям.
+	BulkPriceResponse = models.BulkPriceResponse
+	// BatchRequest — пакетный запрос на создание, изменение и удаление подписок.
+	BatchRequest = models.BatchRequest
+	// BatchOperation — операция пакетного запроса.
+	BatchOperation = models.BatchOperation
+	// BatchResult — итог пакетного запроса с итогом каждой операции.
+	BatchResult = models.BatchResult
+	// ImportJob — задача импорта подписок и её прогресс.
+	ImportJob = models.ImportJob
+	// ImportMapping — названия колонок файла импорта, из которых берутся поля подписки.
+	ImportMapping = models.ImportMapping
+	// SubscriptionLink — связь подписки с её идентификатором у провайдера.
+	SubscriptionLink = models.SubscriptionLink
+	// LinkSubscriptionRequest — идентификатор подписки у провайдера.
+	LinkSubscriptionRequest = models.LinkSubscriptionRequest
+	// ErrorResponse — тело ответа с ошибкой.
+	ErrorResponse = models.ErrorResponse
+)
+
+// createResponse — ответ на создание подписки.
+type createResponse struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// priceResponse — стоимость подписки за период.
+type priceResponse struct {
+	Price int `json:"price"`
+}
+
+// Режимы пакетного запроса и операции в нём.
+const (
+	BatchAtomic     = models.BatchAtomic
+	BatchBestEffort = models.BatchBestEffort
+	BatchCreate     = models.BatchCreate
+	BatchUpdate     = models.BatchUpdate
+	BatchDelete     = models.BatchDelete
+)
+
+const (
+	// MaxPageLimit — максимальный размер страницы, который принимает сервер.
+	MaxPageLimit = models.MaxPageLimit
+	// MaxBatchOperations — максимальное число операций в одном пакетном запросе.
+	MaxBatchOperations = models.MaxBatchOperations
+)